@@ -0,0 +1,26 @@
+// Package openapi embeds the OpenAPI document of the HTTP API and its docs page.
+// openapi.json is generated by cmd/openapi from the handler annotations; regenerate it with
+// go generate ./api/openapi after changing a route, an annotation, a request/response type or
+// api/proto/user.proto.
+package openapi
+
+import _ "embed"
+
+//go:generate go run ../../cmd/openapi -root ../..
+//go:generate go run ../../cmd/openapi -root ../.. -gateway -out api/openapi/openapi.gateway.json
+
+// Spec OpenAPI 3.1 document (JSON)
+//
+//go:embed openapi.json
+var Spec []byte
+
+// GatewaySpec the document served with http.user_api: gateway, where grpc-gateway serves the
+// annotated user routes with the proto JSON mapping
+//
+//go:embed openapi.gateway.json
+var GatewaySpec []byte
+
+// DocsPage self-contained HTML page rendering the document served at /openapi.json
+//
+//go:embed docs.html
+var DocsPage []byte
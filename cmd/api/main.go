@@ -13,6 +13,9 @@ import (
 	"example.com/classic/pkg/logger"
 )
 
+// @title Classic User Service API
+// @version 1.0
+// @description Multi-tenant user management service. Requests under /api/v1 are scoped to the tenant resolved from the bearer token, the tenant header or the subdomain.
 func main() {
 	ctx := context.Background()
 
@@ -30,10 +33,14 @@ func main() {
 	}
 	defer cleanupGRPC()
 
+	// Serve gRPC on the HTTP port (grpc.shared_port)
+	if grpcServer.SharedPort() {
+		httpServer.ServeGRPC(grpcServer)
+	}
+
 	// Start HTTP server
 	go func() {
-		logger.Info(ctx, "HTTP server starting", logger.F("addr", ":8080"))
-		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		if err := httpServer.Start(); err != nil && err != http.ErrServerClosed {
 			logger.Error(ctx, "HTTP server error", logger.F("error", err))
 		}
 	}()
@@ -52,12 +59,12 @@ func main() {
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	// Shutdown HTTP server
-	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+	// Shutdown HTTP server (fails /readyz and drains first)
+	if err := httpServer.Stop(shutdownCtx); err != nil {
 		logger.Error(ctx, "HTTP server shutdown error", logger.F("error", err))
 	}
 
-	// Shutdown gRPC server
+	// Shutdown gRPC server (with a shared port, after the HTTP server has drained its calls)
 	if err := grpcServer.Stop(shutdownCtx); err != nil {
 		logger.Error(ctx, "gRPC server shutdown error", logger.F("error", err))
 	}
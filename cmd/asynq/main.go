@@ -3,12 +3,14 @@ package main
 import (
 	"context"
 	"flag"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 
 	"example.com/classic/internal/config"
 	"example.com/classic/internal/job/asynq"
+	"example.com/classic/internal/wire"
 	"example.com/classic/pkg/logger"
 )
 
@@ -42,11 +44,14 @@ func main() {
 }
 
 func runWorker(ctx context.Context, cfg *config.Config, log logger.Logger) {
-	queue, err := asynq.New(cfg, log)
+	// 通过 wire 构建 worker，注册依赖应用服务的任务处理器（如批量用户操作）
+	worker, cleanup, err := wire.InitWorker(ctx)
 	if err != nil {
-		log.Error(ctx, "failed to init asynq queue", logger.F("error", err))
+		log.Error(ctx, "failed to init asynq worker", logger.F("error", err))
 		os.Exit(1)
 	}
+	defer cleanup()
+	queue := worker.Queue
 
 	go func() {
 		if err := queue.Start(ctx); err != nil {
@@ -55,10 +60,27 @@ func runWorker(ctx context.Context, cfg *config.Config, log logger.Logger) {
 		}
 	}()
 
+	// 独立暴露 worker 指标（任务处理数与耗时、连接池、运行时）
+	var metricsServer *http.Server
+	if worker.Metrics != nil && cfg.Metrics.WorkerAddress != "" {
+		mux := http.NewServeMux()
+		mux.Handle(cfg.Metrics.Path, worker.Metrics.Handler())
+		metricsServer = &http.Server{Addr: cfg.Metrics.WorkerAddress, Handler: mux}
+		go func() {
+			log.Info(ctx, "metrics server starting", logger.F("addr", cfg.Metrics.WorkerAddress))
+			if err := metricsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.Error(ctx, "metrics server error", logger.Err(err))
+			}
+		}()
+	}
+
 	log.Info(ctx, "asynq worker started")
 	waitForSignal()
 	log.Info(ctx, "asynq worker stopping...")
 	_ = queue.Stop(ctx)
+	if metricsServer != nil {
+		_ = metricsServer.Shutdown(ctx)
+	}
 	log.Info(ctx, "asynq worker stopped")
 }
 
@@ -0,0 +1,440 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Operation OpenAPI operation object
+type Operation struct {
+	Tags        []string             `json:"tags,omitempty"`
+	Summary     string               `json:"summary,omitempty"`
+	Description string               `json:"description,omitempty"`
+	OperationID string               `json:"operationId,omitempty"`
+	Parameters  []Parameter          `json:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+}
+
+// Parameter OpenAPI parameter object
+type Parameter struct {
+	Name        string `json:"name"`
+	In          string `json:"in"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+	Style       string `json:"style,omitempty"`
+	Explode     bool   `json:"explode,omitempty"`
+	Schema      Schema `json:"schema"`
+}
+
+// RequestBody OpenAPI request body object
+type RequestBody struct {
+	Description string               `json:"description,omitempty"`
+	Required    bool                 `json:"required,omitempty"`
+	Content     map[string]MediaType `json:"content"`
+}
+
+// Response OpenAPI response object
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType OpenAPI media type object
+type MediaType struct {
+	Schema Schema `json:"schema"`
+}
+
+// route an operation and where it is served
+type route struct {
+	method string
+	path   string
+	op     *Operation
+}
+
+var (
+	paramPattern    = regexp.MustCompile(`^(\S+)\s+(\S+)\s+(\S+)\s+(\S+)\s+"([^"]*)"\s*(.*)$`)
+	responsePattern = regexp.MustCompile(`^(\d+)\s+\{(\w+)\}\s+(\S+)(?:\s+"([^"]*)")?$`)
+	routerPattern   = regexp.MustCompile(`^(\S+)\s+\[(\w+)\]$`)
+	attrPattern     = regexp.MustCompile(`(\w+)\(([^)]*)\)`)
+)
+
+// parseOperations reads the swag-style annotations (@Summary, @Param, @Router ...) of the
+// functions in a package
+func (x *typeIndex) parseOperations(path string) ([]route, error) {
+	pkg, files, err := x.load(path)
+	if err != nil {
+		return nil, err
+	}
+	if pkg == nil {
+		return nil, fmt.Errorf("package %s is outside the module", path)
+	}
+
+	var routes []route
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Doc == nil {
+				continue
+			}
+			fnRoutes, err := x.parseFunc(file, pkg, fn)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", x.fset.Position(fn.Pos()), err)
+			}
+			routes = append(routes, fnRoutes...)
+		}
+	}
+	return routes, nil
+}
+
+func (x *typeIndex) parseFunc(file *ast.File, pkg *sourcePackage, fn *ast.FuncDecl) ([]route, error) {
+	op := &Operation{OperationID: operationID(fn), Responses: make(map[string]*Response)}
+	var (
+		routes   []route
+		accept   []string
+		produce  []string
+		bodyType string
+		bodyDesc string
+		bodyReq  bool
+	)
+	type pendingResponse struct {
+		code, kind, typ, desc string
+	}
+	var responses []pendingResponse
+
+	for _, comment := range fn.Doc.List {
+		line := strings.TrimSpace(strings.TrimPrefix(comment.Text, "//"))
+		if !strings.HasPrefix(line, "@") {
+			continue
+		}
+		attr, value, _ := strings.Cut(line, " ")
+		value = strings.TrimSpace(value)
+
+		switch strings.ToLower(attr) {
+		case "@summary":
+			op.Summary = value
+		case "@description":
+			op.Description = strings.TrimSpace(op.Description + " " + value)
+		case "@tags":
+			for _, tag := range strings.Split(value, ",") {
+				op.Tags = append(op.Tags, strings.TrimSpace(tag))
+			}
+		case "@accept":
+			accept = mimeTypes(value)
+		case "@produce":
+			produce = mimeTypes(value)
+		case "@param":
+			m := paramPattern.FindStringSubmatch(value)
+			if m == nil {
+				return nil, fmt.Errorf("invalid @Param %q", value)
+			}
+			name, in, typ, required, desc, attrs := m[1], m[2], m[3], m[4] == "true", m[5], m[6]
+			if in == "body" {
+				bodyType, bodyDesc, bodyReq = typ, desc, required
+				continue
+			}
+			schema, err := primitiveSchema(typ)
+			if err != nil {
+				return nil, err
+			}
+			if err := applyAttributes(schema, attrs); err != nil {
+				return nil, fmt.Errorf("@Param %s: %w", name, err)
+			}
+			op.Parameters = append(op.Parameters, Parameter{
+				Name:        name,
+				In:          in,
+				Description: desc,
+				Required:    required || in == "path",
+				Schema:      schema,
+			})
+		case "@success", "@failure":
+			m := responsePattern.FindStringSubmatch(value)
+			if m == nil {
+				return nil, fmt.Errorf("invalid %s %q", attr, value)
+			}
+			responses = append(responses, pendingResponse{code: m[1], kind: m[2], typ: m[3], desc: m[4]})
+		case "@router":
+			m := routerPattern.FindStringSubmatch(value)
+			if m == nil {
+				return nil, fmt.Errorf("invalid @Router %q", value)
+			}
+			routes = append(routes, route{method: strings.ToUpper(m[2]), path: m[1], op: op})
+		}
+	}
+	if len(routes) == 0 {
+		return nil, nil
+	}
+
+	if len(accept) == 0 {
+		accept = []string{"application/json"}
+	}
+	if len(produce) == 0 {
+		produce = []string{"application/json"}
+	}
+
+	// The request body is either a typed body parameter or a raw upload of the accepted types
+	if bodyType != "" || !acceptsJSONOnly(accept) {
+		schema := Schema{"type": "string"}
+		if bodyType != "" {
+			var err error
+			if schema, err = x.annotationSchema(file, pkg, bodyType); err != nil {
+				return nil, err
+			}
+		}
+		op.RequestBody = &RequestBody{Description: bodyDesc, Required: bodyReq || bodyType == "", Content: content(accept, schema)}
+	}
+
+	for _, r := range responses {
+		if _, ok := op.Responses[r.code]; ok {
+			continue
+		}
+		schema, err := x.responseSchema(file, pkg, r.kind, r.typ)
+		if err != nil {
+			return nil, err
+		}
+		desc := r.desc
+		if desc == "" {
+			code, _ := strconv.Atoi(r.code)
+			desc = http.StatusText(code)
+		}
+		// Errors are always JSON, whatever the operation produces on success
+		types := produce
+		if !strings.HasPrefix(r.code, "2") {
+			types = []string{"application/json"}
+		}
+		op.Responses[r.code] = &Response{Description: desc, Content: content(types, schema)}
+	}
+	if len(op.Responses) == 0 {
+		op.Responses["200"] = &Response{Description: http.StatusText(http.StatusOK)}
+	}
+
+	// An operation served at several paths keeps its ID on the first one only
+	for i := 1; i < len(routes); i++ {
+		copied := *op
+		copied.OperationID = ""
+		routes[i].op = &copied
+	}
+	return routes, nil
+}
+
+// responseSchema the schema of a response annotation ({object} T, {array} T, {file} file, {string} ...)
+func (x *typeIndex) responseSchema(file *ast.File, pkg *sourcePackage, kind, typ string) (Schema, error) {
+	switch kind {
+	case "file":
+		return Schema{"type": "string", "contentMediaType": "application/octet-stream"}, nil
+	case "array":
+		items, err := x.annotationSchema(file, pkg, typ)
+		if err != nil {
+			return nil, err
+		}
+		return Schema{"type": "array", "items": items}, nil
+	case "object", "string", "integer", "number", "boolean":
+		return x.annotationSchema(file, pkg, typ)
+	default:
+		return nil, fmt.Errorf("unsupported response kind {%s}", kind)
+	}
+}
+
+// annotationSchema parses a swag type expression: "string", "pkg.Type", "[]pkg.Type" and
+// compositions overriding fields, such as "response.Response{data=[]dto.UserDTO}"
+func (x *typeIndex) annotationSchema(file *ast.File, pkg *sourcePackage, expr string) (Schema, error) {
+	if rest, ok := strings.CutPrefix(expr, "[]"); ok {
+		items, err := x.annotationSchema(file, pkg, rest)
+		if err != nil {
+			return nil, err
+		}
+		return Schema{"type": "array", "items": items}, nil
+	}
+
+	name, fields, composed := strings.Cut(expr, "{")
+	if schema, err := primitiveSchema(name); err == nil {
+		return schema, nil
+	}
+	qualifier, typeName, ok := strings.Cut(name, ".")
+	if !ok {
+		qualifier, typeName = "", name
+	}
+	var base Schema
+	if full, ok := externalSchema(name); ok {
+		base = full
+	} else {
+		decl, err := x.lookup(file, pkg, qualifier, typeName)
+		if err != nil {
+			return nil, err
+		}
+		if decl == nil {
+			return nil, fmt.Errorf("unknown type %s", name)
+		}
+		if base, err = x.named(decl); err != nil {
+			return nil, err
+		}
+	}
+	if !composed {
+		return base, nil
+	}
+
+	fields, ok = strings.CutSuffix(fields, "}")
+	if !ok {
+		return nil, fmt.Errorf("unbalanced braces in %q", expr)
+	}
+	properties := Schema{}
+	for _, field := range splitTopLevel(fields) {
+		fieldName, fieldType, ok := strings.Cut(field, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid field override %q", field)
+		}
+		schema, err := x.annotationSchema(file, pkg, fieldType)
+		if err != nil {
+			return nil, err
+		}
+		properties[fieldName] = schema
+	}
+	return Schema{"allOf": []any{base, Schema{"type": "object", "properties": properties}}}, nil
+}
+
+// splitTopLevel splits on commas outside braces
+func splitTopLevel(s string) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i, r := range s {
+		switch r {
+		case '{':
+			depth++
+		case '}':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// primitiveSchema schemas of the swag primitive types
+func primitiveSchema(typ string) (Schema, error) {
+	switch typ {
+	case "string":
+		return Schema{"type": "string"}, nil
+	case "int", "integer":
+		return Schema{"type": "integer"}, nil
+	case "number":
+		return Schema{"type": "number"}, nil
+	case "bool", "boolean":
+		return Schema{"type": "boolean"}, nil
+	case "file":
+		return Schema{"type": "string", "contentMediaType": "application/octet-stream"}, nil
+	case "object":
+		return Schema{"type": "object"}, nil
+	}
+	return nil, fmt.Errorf("unsupported type %q", typ)
+}
+
+// applyAttributes maps parameter attributes such as default(1) and maximum(200)
+func applyAttributes(schema Schema, attrs string) error {
+	for _, m := range attrPattern.FindAllStringSubmatch(attrs, -1) {
+		name, arg := m[1], m[2]
+		switch name {
+		case "default", "minimum", "maximum", "minLength", "maxLength":
+			value, err := typedValue(schema, arg)
+			if err != nil {
+				return fmt.Errorf("%s(%s): %w", name, arg, err)
+			}
+			schema[name] = value
+		case "enums":
+			var values []any
+			for _, v := range strings.Split(arg, ",") {
+				value, err := typedValue(schema, strings.TrimSpace(v))
+				if err != nil {
+					return fmt.Errorf("enums(%s): %w", arg, err)
+				}
+				values = append(values, value)
+			}
+			schema["enum"] = values
+		case "format":
+			schema["format"] = arg
+		default:
+			return fmt.Errorf("unsupported attribute %s", name)
+		}
+	}
+	return nil
+}
+
+func typedValue(schema Schema, value string) (any, error) {
+	switch schema["type"] {
+	case "integer":
+		return strconv.ParseInt(value, 10, 64)
+	case "number":
+		return strconv.ParseFloat(value, 64)
+	case "boolean":
+		return strconv.ParseBool(value)
+	default:
+		return value, nil
+	}
+}
+
+// mimeTypes expands the swag MIME type aliases of @Accept and @Produce
+func mimeTypes(value string) []string {
+	var types []string
+	for _, t := range strings.Split(value, ",") {
+		switch t = strings.TrimSpace(t); t {
+		case "json":
+			t = "application/json"
+		case "xml":
+			t = "application/xml"
+		case "plain":
+			t = "text/plain"
+		case "html":
+			t = "text/html"
+		case "mpfd":
+			t = "multipart/form-data"
+		case "x-www-form-urlencoded":
+			t = "application/x-www-form-urlencoded"
+		}
+		types = append(types, t)
+	}
+	return types
+}
+
+func acceptsJSONOnly(types []string) bool {
+	return len(types) == 1 && types[0] == "application/json"
+}
+
+// protobufSchema protobuf bodies are the bare gRPC messages (pb.*), without the response envelope
+var protobufSchema = Schema{
+	"type":             "string",
+	"contentMediaType": "application/x-protobuf",
+	"description":      "Binary protobuf message of the gRPC API (pb.*), without the response envelope",
+}
+
+func content(types []string, schema Schema) map[string]MediaType {
+	m := make(map[string]MediaType, len(types))
+	for _, t := range types {
+		if t == "application/x-protobuf" {
+			m[t] = MediaType{Schema: protobufSchema}
+			continue
+		}
+		m[t] = MediaType{Schema: schema}
+	}
+	return m
+}
+
+// operationID names an operation after its handler: "UserHandler.Register"
+func operationID(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return fn.Name.Name
+	}
+	recv := fn.Recv.List[0].Type
+	if star, ok := recv.(*ast.StarExpr); ok {
+		recv = star.X
+	}
+	if ident, ok := recv.(*ast.Ident); ok {
+		return ident.Name + "." + fn.Name.Name
+	}
+	return fn.Name.Name
+}
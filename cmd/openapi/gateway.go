@@ -0,0 +1,243 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"example.com/classic/api/grpc/pb"
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// gatewayService the service that grpc-gateway serves on the gin router with http.user_api: gateway
+var gatewayService = pb.File_api_proto_user_proto.Services().ByName("UserService")
+
+const protobufContentType = "application/x-protobuf"
+
+var (
+	pathParamPattern = regexp.MustCompile(`\{(\w+)\}`)
+	schemaRefPattern = regexp.MustCompile(`"#/components/schemas/([^"]+)"`)
+)
+
+// applyGateway replaces the gin operations of the routes annotated with google.api.http by the
+// operations grpc-gateway serves: bodies and query parameters follow the proto JSON mapping (proto
+// field names, enum value names), responses keep the {code, msg, data} envelope. Summaries, tags and
+// error responses are taken from the gin operation of the same route, since the gateway writes
+// errors like the gin handlers.
+func applyGateway(doc *Document) error {
+	methods := gatewayService.Methods()
+	for i := 0; i < methods.Len(); i++ {
+		method := methods.Get(i)
+		rule, ok := proto.GetExtension(method.Options(), annotations.E_Http).(*annotations.HttpRule)
+		if !ok || rule == nil {
+			continue
+		}
+		verb, path := httpBinding(rule)
+		if verb == "" {
+			return fmt.Errorf("%s: unsupported http binding", method.FullName())
+		}
+		gin := doc.Paths[path][verb]
+		if gin == nil {
+			return fmt.Errorf("%s: no gin operation for %s %s", method.FullName(), strings.ToUpper(verb), path)
+		}
+		doc.Paths[path][verb] = gatewayOperation(doc, method, rule.GetBody(), path, gin)
+	}
+	pruneSchemas(doc)
+	return nil
+}
+
+// httpBinding the lower-case method and path of a rule
+func httpBinding(rule *annotations.HttpRule) (string, string) {
+	switch pattern := rule.GetPattern().(type) {
+	case *annotations.HttpRule_Get:
+		return "get", pattern.Get
+	case *annotations.HttpRule_Post:
+		return "post", pattern.Post
+	case *annotations.HttpRule_Put:
+		return "put", pattern.Put
+	case *annotations.HttpRule_Patch:
+		return "patch", pattern.Patch
+	case *annotations.HttpRule_Delete:
+		return "delete", pattern.Delete
+	default:
+		return "", ""
+	}
+}
+
+// gatewayOperation describes a gateway method; body is the body field of the rule ("*" for the whole request)
+func gatewayOperation(doc *Document, method protoreflect.MethodDescriptor, body, path string, gin *Operation) *Operation {
+	op := &Operation{
+		Tags:        gin.Tags,
+		Summary:     gin.Summary,
+		Description: gin.Description,
+		OperationID: string(method.Parent().Name()) + "." + string(method.Name()),
+		Responses:   make(map[string]*Response),
+	}
+	input := method.Input()
+
+	// 路径参数覆盖请求体中的同名字段
+	bound := make(map[protoreflect.Name]bool)
+	for _, m := range pathParamPattern.FindAllStringSubmatch(path, -1) {
+		name := protoreflect.Name(m[1])
+		bound[name] = true
+		param := Parameter{Name: m[1], In: "path", Required: true, Schema: fieldSchema(doc, input.Fields().ByName(name))}
+		for _, p := range gin.Parameters {
+			if p.In == "path" && p.Name == param.Name {
+				param.Description = p.Description
+			}
+		}
+		op.Parameters = append(op.Parameters, param)
+	}
+
+	switch body {
+	case "*":
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]MediaType{"application/json": {Schema: messageSchema(doc, input, bound)}},
+		}
+	case "":
+		// 其余标量字段 (含 map) 作为查询参数，嵌套消息不支持
+		fields := input.Fields()
+		for i := 0; i < fields.Len(); i++ {
+			field := fields.Get(i)
+			if bound[field.Name()] || (field.Kind() == protoreflect.MessageKind && !field.IsMap()) {
+				continue
+			}
+			param := Parameter{Name: string(field.Name()), In: "query", Schema: fieldSchema(doc, field)}
+			if field.IsMap() {
+				param.Style, param.Explode = "deepObject", true
+			}
+			op.Parameters = append(op.Parameters, param)
+		}
+	default:
+		field := input.Fields().ByName(protoreflect.Name(body))
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]MediaType{"application/json": {Schema: fieldSchema(doc, field)}},
+		}
+	}
+
+	op.Responses["200"] = &Response{
+		Description: "OK",
+		Content: map[string]MediaType{
+			"application/json": {Schema: Schema{"allOf": []any{
+				Schema{"$ref": "#/components/schemas/response.Response"},
+				Schema{"type": "object", "properties": map[string]any{"data": messageSchema(doc, method.Output(), nil)}},
+			}}},
+			// Accept: application/x-protobuf 返回不带统一响应格式的消息本身
+			protobufContentType: {Schema: Schema{"type": "string", "format": "binary"}},
+		},
+	}
+	for code, resp := range gin.Responses {
+		if !strings.HasPrefix(code, "2") {
+			op.Responses[code] = resp
+		}
+	}
+	return op
+}
+
+// messageSchema the reference to the component schema of a message, named "pb.<Message>";
+// fields in omit are left out (bound to path parameters)
+func messageSchema(doc *Document, msg protoreflect.MessageDescriptor, omit map[protoreflect.Name]bool) Schema {
+	if schema, ok := wellKnownSchema(msg); ok {
+		return schema
+	}
+	name := "pb." + string(msg.Name())
+	ref := Schema{"$ref": "#/components/schemas/" + name}
+	if _, ok := doc.Components.Schemas[name]; ok {
+		return ref
+	}
+
+	properties := make(map[string]any)
+	// 先占位，防止递归消息无限展开
+	doc.Components.Schemas[name] = Schema{"type": "object", "properties": properties}
+	fields := msg.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		field := fields.Get(i)
+		if !omit[field.Name()] {
+			properties[string(field.Name())] = fieldSchema(doc, field)
+		}
+	}
+	return ref
+}
+
+// fieldSchema the schema of a field in the proto JSON mapping
+func fieldSchema(doc *Document, field protoreflect.FieldDescriptor) Schema {
+	if field.IsMap() {
+		return Schema{"type": "object", "additionalProperties": fieldSchema(doc, field.MapValue())}
+	}
+	var schema Schema
+	switch field.Kind() {
+	case protoreflect.BoolKind:
+		schema = Schema{"type": "boolean"}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		schema = Schema{"type": "integer"}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		// 64 位整数编码为字符串
+		schema = Schema{"type": "string", "format": "int64"}
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		schema = Schema{"type": "number"}
+	case protoreflect.BytesKind:
+		schema = Schema{"type": "string", "format": "byte"}
+	case protoreflect.EnumKind:
+		values := field.Enum().Values()
+		enum := make([]any, values.Len())
+		for i := range enum {
+			enum[i] = string(values.Get(i).Name())
+		}
+		schema = Schema{"type": "string", "enum": enum}
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		schema = messageSchema(doc, field.Message(), nil)
+	default:
+		schema = Schema{"type": "string"}
+	}
+	if field.IsList() {
+		return Schema{"type": "array", "items": schema}
+	}
+	return schema
+}
+
+// wellKnownSchema the JSON mapping of the google.protobuf types used by the API
+func wellKnownSchema(msg protoreflect.MessageDescriptor) (Schema, bool) {
+	switch msg.FullName() {
+	case "google.protobuf.Timestamp":
+		return Schema{"type": "string", "format": "date-time"}, true
+	case "google.protobuf.Duration":
+		return Schema{"type": "string"}, true
+	case "google.protobuf.Struct":
+		return Schema{"type": "object"}, true
+	case "google.protobuf.ListValue":
+		return Schema{"type": "array"}, true
+	case "google.protobuf.Value":
+		return Schema{}, true
+	default:
+		return nil, false
+	}
+}
+
+// pruneSchemas drops the component schemas no operation refers to any more
+func pruneSchemas(doc *Document) {
+	reachable := make(map[string]bool)
+	var visit func(v any)
+	visit = func(v any) {
+		data, _ := json.Marshal(v)
+		for _, m := range schemaRefPattern.FindAllSubmatch(data, -1) {
+			name := string(m[1])
+			if !reachable[name] {
+				reachable[name] = true
+				visit(doc.Components.Schemas[name])
+			}
+		}
+	}
+	visit(doc.Paths)
+	for name := range doc.Components.Schemas {
+		if !reachable[name] {
+			delete(doc.Components.Schemas, name)
+		}
+	}
+}
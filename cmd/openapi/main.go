@@ -0,0 +1,200 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Document OpenAPI document
+type Document struct {
+	OpenAPI    string                           `json:"openapi"`
+	Info       Info                             `json:"info"`
+	Paths      map[string]map[string]*Operation `json:"paths"`
+	Components Components                       `json:"components"`
+}
+
+// Info OpenAPI info object
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// Components OpenAPI components object
+type Components struct {
+	Schemas map[string]Schema `json:"schemas"`
+}
+
+// sources where the generator reads annotations, relative to the module root
+var (
+	generalInfoFile = "cmd/api/main.go"
+	handlerPackages = []string{"internal/handler", "internal/server/http"}
+	// problemType the body of error responses negotiated with Accept: application/problem+json
+	problemPackage, problemType = "pkg/response", "Problem"
+)
+
+const problemContentType = "application/problem+json"
+
+// openapi 根据 handler 上的 swag 注解 (@Summary、@Param、@Router ...) 和请求/响应类型生成
+// OpenAPI 3.1 文档，由 api/openapi 嵌入服务。修改接口后运行 go generate ./api/openapi。
+func main() {
+	root := flag.String("root", ".", "module root")
+	out := flag.String("out", "api/openapi/openapi.json", "output file, relative to the module root")
+	check := flag.Bool("check", false, "fail if the output file is not up to date instead of writing it")
+	gateway := flag.Bool("gateway", false, "describe the user API as served by grpc-gateway (http.user_api: gateway)")
+	flag.Parse()
+
+	build := generate
+	if *gateway {
+		build = generateGateway
+	}
+	spec, err := build(*root)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "openapi:", err)
+		os.Exit(1)
+	}
+
+	path := filepath.Join(*root, *out)
+	if *check {
+		current, err := os.ReadFile(path)
+		if err != nil || !bytes.Equal(current, spec) {
+			fmt.Fprintf(os.Stderr, "openapi: %s is out of date; run go generate ./api/openapi\n", path)
+			os.Exit(1)
+		}
+		return
+	}
+	if err := os.WriteFile(path, spec, 0o644); err != nil {
+		fmt.Fprintln(os.Stderr, "openapi:", err)
+		os.Exit(1)
+	}
+}
+
+// generate builds the OpenAPI document of the module at root
+func generate(root string) ([]byte, error) {
+	doc, err := document(root)
+	if err != nil {
+		return nil, err
+	}
+	return encode(doc)
+}
+
+// generateGateway builds the OpenAPI document served with http.user_api: gateway
+func generateGateway(root string) ([]byte, error) {
+	doc, err := document(root)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyGateway(doc); err != nil {
+		return nil, err
+	}
+	return encode(doc)
+}
+
+// document collects the operations of the handler packages
+func document(root string) (*Document, error) {
+	index, err := newTypeIndex(root)
+	if err != nil {
+		return nil, err
+	}
+	info, err := generalInfo(filepath.Join(root, generalInfoFile))
+	if err != nil {
+		return nil, err
+	}
+
+	problem, err := index.problemSchema()
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &Document{
+		OpenAPI:    "3.1.0",
+		Info:       info,
+		Paths:      make(map[string]map[string]*Operation),
+		Components: Components{Schemas: index.schemas},
+	}
+	for _, dir := range handlerPackages {
+		routes, err := index.parseOperations(index.modulePath + "/" + dir)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range routes {
+			item := doc.Paths[r.path]
+			if item == nil {
+				item = make(map[string]*Operation)
+				doc.Paths[r.path] = item
+			}
+			method := strings.ToLower(r.method)
+			if _, ok := item[method]; ok {
+				return nil, fmt.Errorf("duplicate operation %s %s", r.method, r.path)
+			}
+			item[method] = r.op
+			// Every error may also be rendered as RFC 7807 problem details
+			for code, resp := range r.op.Responses {
+				if !strings.HasPrefix(code, "2") && resp.Content != nil {
+					resp.Content[problemContentType] = MediaType{Schema: problem}
+				}
+			}
+		}
+	}
+	return doc, nil
+}
+
+// encode renders the document as indented JSON
+func encode(doc *Document) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return nil, fmt.Errorf("encode spec: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// problemSchema the reference to the problem details schema
+func (x *typeIndex) problemSchema() (Schema, error) {
+	pkg, _, err := x.load(x.modulePath + "/" + problemPackage)
+	if err != nil {
+		return nil, err
+	}
+	decl, ok := pkg.types[problemType]
+	if !ok {
+		return nil, fmt.Errorf("type %s not found in %s", problemType, pkg.path)
+	}
+	return x.named(decl)
+}
+
+// generalInfo reads @title, @version and @description from the comments of the main package
+func generalInfo(path string) (Info, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), path, nil, parser.ParseComments)
+	if err != nil {
+		return Info{}, fmt.Errorf("parse general info: %w", err)
+	}
+	var info Info
+	for _, group := range file.Comments {
+		for _, comment := range group.List {
+			line := strings.TrimSpace(strings.TrimPrefix(comment.Text, "//"))
+			attr, value, _ := strings.Cut(line, " ")
+			value = strings.TrimSpace(value)
+			switch strings.ToLower(attr) {
+			case "@title":
+				info.Title = value
+			case "@version":
+				info.Version = value
+			case "@description":
+				info.Description = strings.TrimSpace(info.Description + " " + value)
+			}
+		}
+	}
+	if info.Title == "" || info.Version == "" {
+		return Info{}, fmt.Errorf("%s: @title and @version are required", path)
+	}
+	return info, nil
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate_UpToDate(t *testing.T) {
+	spec, err := generate("../..")
+	require.NoError(t, err)
+
+	current, err := os.ReadFile("../../api/openapi/openapi.json")
+	require.NoError(t, err)
+	assert.True(t, string(current) == string(spec), "api/openapi/openapi.json is out of date; run go generate ./api/openapi")
+}
+
+func TestGenerate_Schemas(t *testing.T) {
+	spec, err := generate("../..")
+	require.NoError(t, err)
+
+	var doc struct {
+		Paths      map[string]map[string]Operation `json:"paths"`
+		Components struct {
+			Schemas map[string]Schema `json:"schemas"`
+		} `json:"components"`
+	}
+	require.NoError(t, json.Unmarshal(spec, &doc))
+
+	// Binding rules become constraints, constants of named types become enums
+	create := doc.Components.Schemas["request.CreateUserRequest"]
+	assert.ElementsMatch(t, []any{"name", "email", "password"}, create["required"])
+	properties := create["properties"].(map[string]any)
+	assert.Equal(t, map[string]any{"type": "string", "format": "email"}, properties["email"])
+	assert.Equal(t, map[string]any{"type": "string", "minLength": 2.0, "maxLength": 50.0}, properties["name"])
+	status := doc.Components.Schemas["dto.TenantDTO"]["properties"].(map[string]any)["status"]
+	assert.Equal(t, map[string]any{"type": "string", "enum": []any{"active", "suspended"}}, status)
+
+	register := doc.Paths["/api/v1/users"]["post"]
+	assert.Equal(t, "UserHandler.Register", register.OperationID)
+	require.NotNil(t, register.RequestBody)
+	assert.Equal(t, "#/components/schemas/request.CreateUserRequest", register.RequestBody.Content["application/json"].Schema["$ref"])
+	// Errors may be negotiated as problem details
+	assert.Equal(t, "#/components/schemas/response.Problem", register.Responses["400"].Content[problemContentType].Schema["$ref"])
+	// Protobuf bodies are the bare gRPC messages
+	assert.Equal(t, "application/x-protobuf", register.RequestBody.Content["application/x-protobuf"].Schema["contentMediaType"])
+	assert.Equal(t, "application/x-protobuf", register.Responses["200"].Content["application/x-protobuf"].Schema["contentMediaType"])
+	assert.NotContains(t, register.Responses["400"].Content, "application/x-protobuf")
+
+	// Raw uploads are described by their media types
+	upload := doc.Paths["/api/v1/users:import"]["post"].RequestBody
+	require.NotNil(t, upload)
+	assert.Contains(t, upload.Content, "text/csv")
+	assert.Contains(t, upload.Content, "application/x-ndjson")
+
+	page := doc.Paths["/api/v1/users"]["get"].Parameters[0]
+	assert.Equal(t, "page", page.Name)
+	assert.Equal(t, Schema{"type": "integer", "default": 1.0}, page.Schema)
+}
+
+func TestGenerateGateway(t *testing.T) {
+	spec, err := generateGateway("../..")
+	require.NoError(t, err)
+
+	current, err := os.ReadFile("../../api/openapi/openapi.gateway.json")
+	require.NoError(t, err)
+	assert.True(t, string(current) == string(spec), "api/openapi/openapi.gateway.json is out of date; run go generate ./api/openapi")
+
+	var doc struct {
+		Paths      map[string]map[string]Operation `json:"paths"`
+		Components struct {
+			Schemas map[string]Schema `json:"schemas"`
+		} `json:"components"`
+	}
+	require.NoError(t, json.Unmarshal(spec, &doc))
+
+	// Annotated routes are described by the proto JSON mapping
+	register := doc.Paths["/api/v1/users"]["post"]
+	assert.Equal(t, "UserService.Register", register.OperationID)
+	assert.Equal(t, "#/components/schemas/pb.RegisterRequest", register.RequestBody.Content["application/json"].Schema["$ref"])
+	data := register.Responses["200"].Content["application/json"].Schema["allOf"].([]any)[1].(map[string]any)["properties"].(map[string]any)["data"]
+	assert.Equal(t, map[string]any{"$ref": "#/components/schemas/pb.UserResponse"}, data)
+	assert.Equal(t, "#/components/schemas/response.Problem", register.Responses["400"].Content[problemContentType].Schema["$ref"])
+
+	user := doc.Components.Schemas["pb.UserResponse"]["properties"].(map[string]any)
+	assert.Equal(t, map[string]any{"type": "string", "format": "date-time"}, user["created_at"])
+	assert.Contains(t, user["status"].(map[string]any)["enum"], "STATUS_ERASED")
+
+	// Path parameters are not repeated in the body
+	status := doc.Paths["/api/v1/users/{id}/status"]["patch"]
+	assert.Equal(t, "id", status.Parameters[0].Name)
+	assert.NotContains(t, doc.Components.Schemas["pb.ChangeStatusRequest"]["properties"], "id")
+
+	// Routes without an annotation keep the gin operation; schemas only the gin handlers used are dropped
+	assert.Equal(t, "UserHandler.Import", doc.Paths["/api/v1/users:import"]["post"].OperationID)
+	assert.NotContains(t, doc.Components.Schemas, "request.CreateUserRequest")
+}
+
+func TestSplitTopLevel(t *testing.T) {
+	assert.Equal(t, []string{"data=a.B{x=c.D,y=e.F}", "meta=g.H"}, splitTopLevel("data=a.B{x=c.D,y=e.F},meta=g.H"))
+}
@@ -0,0 +1,460 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Schema JSON Schema (OpenAPI 3.1 dialect)
+type Schema map[string]any
+
+// sourcePackage a package of the module, parsed from source
+type sourcePackage struct {
+	name  string
+	path  string
+	files []*ast.File
+	types map[string]*typeDecl
+	// enums constant values declared with each named type, in source order
+	enums map[string][]any
+}
+
+// typeDecl a type declaration and the file it is declared in (for resolving imports)
+type typeDecl struct {
+	spec *ast.TypeSpec
+	doc  string
+	file *ast.File
+	pkg  *sourcePackage
+}
+
+// typeIndex resolves types of the module's packages from source. Types are looked up by
+// syntax only, so generating the spec does not need to type-check the module.
+type typeIndex struct {
+	fset       *token.FileSet
+	root       string
+	modulePath string
+	pkgs       map[string]*sourcePackage
+	// schemas component schemas of the struct types referenced so far
+	schemas map[string]Schema
+}
+
+func newTypeIndex(root string) (*typeIndex, error) {
+	data, err := os.ReadFile(filepath.Join(root, "go.mod"))
+	if err != nil {
+		return nil, fmt.Errorf("read go.mod: %w", err)
+	}
+	var modulePath string
+	for _, line := range strings.Split(string(data), "\n") {
+		if rest, ok := strings.CutPrefix(strings.TrimSpace(line), "module "); ok {
+			modulePath = strings.TrimSpace(rest)
+			break
+		}
+	}
+	if modulePath == "" {
+		return nil, fmt.Errorf("no module path in %s", filepath.Join(root, "go.mod"))
+	}
+	return &typeIndex{
+		fset:       token.NewFileSet(),
+		root:       root,
+		modulePath: modulePath,
+		pkgs:       make(map[string]*sourcePackage),
+		schemas:    make(map[string]Schema),
+	}, nil
+}
+
+// load parses a package of the module; packages outside the module return nil
+func (x *typeIndex) load(path string) (*sourcePackage, []*ast.File, error) {
+	rel, ok := strings.CutPrefix(path, x.modulePath)
+	if !ok || (rel != "" && !strings.HasPrefix(rel, "/")) {
+		return nil, nil, nil
+	}
+	dir := filepath.Join(x.root, filepath.FromSlash(strings.TrimPrefix(rel, "/")))
+	files, err := x.parseDir(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	if pkg, ok := x.pkgs[path]; ok {
+		return pkg, files, nil
+	}
+
+	pkg := &sourcePackage{path: path, files: files, types: make(map[string]*typeDecl), enums: make(map[string][]any)}
+	for _, file := range files {
+		pkg.name = file.Name.Name
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok {
+				continue
+			}
+			switch gen.Tok {
+			case token.TYPE:
+				for _, spec := range gen.Specs {
+					ts := spec.(*ast.TypeSpec)
+					doc := ts.Doc
+					if doc == nil && len(gen.Specs) == 1 {
+						doc = gen.Doc
+					}
+					pkg.types[ts.Name.Name] = &typeDecl{spec: ts, doc: docText(doc, ts.Name.Name), file: file, pkg: pkg}
+				}
+			case token.CONST:
+				for _, spec := range gen.Specs {
+					vs := spec.(*ast.ValueSpec)
+					ident, ok := vs.Type.(*ast.Ident)
+					if !ok || len(vs.Values) != len(vs.Names) {
+						continue
+					}
+					for _, value := range vs.Values {
+						if lit, ok := value.(*ast.BasicLit); ok {
+							pkg.enums[ident.Name] = append(pkg.enums[ident.Name], literalValue(lit))
+						}
+					}
+				}
+			}
+		}
+	}
+	x.pkgs[path] = pkg
+	return pkg, files, nil
+}
+
+// parseDir parses the non-test Go files of a directory in name order
+func (x *typeIndex) parseDir(dir string) ([]*ast.File, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read package dir: %w", err)
+	}
+	var files []*ast.File
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(x.fset, filepath.Join(dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return nil, fmt.Errorf("parse %s: %w", name, err)
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// lookup finds a named type; qualifier is the package name as imported in file, or empty
+func (x *typeIndex) lookup(file *ast.File, pkg *sourcePackage, qualifier, name string) (*typeDecl, error) {
+	if qualifier != "" {
+		path, err := x.importPath(file, pkg, qualifier)
+		if err != nil {
+			return nil, err
+		}
+		if pkg, _, err = x.load(path); err != nil {
+			return nil, err
+		}
+		if pkg == nil {
+			return nil, nil
+		}
+	}
+	decl, ok := pkg.types[name]
+	if !ok {
+		return nil, fmt.Errorf("type %s not found in %s", name, pkg.path)
+	}
+	return decl, nil
+}
+
+// importPath resolves the package name of a selector against the imports of a file, then of
+// the other files of its package (annotations may name types the file does not import)
+func (x *typeIndex) importPath(file *ast.File, pkg *sourcePackage, qualifier string) (string, error) {
+	for _, f := range append([]*ast.File{file}, pkg.files...) {
+		path, err := x.fileImport(f, qualifier)
+		if path != "" || err != nil {
+			return path, err
+		}
+	}
+	return "", fmt.Errorf("package %s is not imported by %s", qualifier, pkg.path)
+}
+
+func (x *typeIndex) fileImport(file *ast.File, qualifier string) (string, error) {
+	for _, imp := range file.Imports {
+		path, _ := strconv.Unquote(imp.Path.Value)
+		if imp.Name != nil {
+			if imp.Name.Name == qualifier {
+				return path, nil
+			}
+			continue
+		}
+		pkg, _, err := x.load(path)
+		if err != nil {
+			return "", err
+		}
+		if (pkg != nil && pkg.name == qualifier) || (pkg == nil && path[strings.LastIndex(path, "/")+1:] == qualifier) {
+			return path, nil
+		}
+	}
+	return "", nil
+}
+
+// named returns the schema of a named type: a reference for structs, the inlined underlying
+// type (with the enum of its constants) otherwise
+func (x *typeIndex) named(decl *typeDecl) (Schema, error) {
+	if _, ok := decl.spec.Type.(*ast.StructType); !ok {
+		schema, err := x.expr(decl.file, decl.pkg, decl.spec.Type)
+		if err != nil {
+			return nil, err
+		}
+		if values := decl.pkg.enums[decl.spec.Name.Name]; len(values) > 0 {
+			schema["enum"] = values
+		}
+		return schema, nil
+	}
+
+	name := decl.pkg.name + "." + decl.spec.Name.Name
+	ref := Schema{"$ref": "#/components/schemas/" + name}
+	if _, ok := x.schemas[name]; ok {
+		return ref, nil
+	}
+	// Registered before the fields are resolved, so that recursive types terminate
+	x.schemas[name] = Schema{}
+	schema, err := x.structSchema(decl.file, decl.pkg, decl.spec.Type.(*ast.StructType))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	if decl.doc != "" {
+		schema["description"] = decl.doc
+	}
+	x.schemas[name] = schema
+	return ref, nil
+}
+
+// expr returns the schema of a type expression in a file
+func (x *typeIndex) expr(file *ast.File, pkg *sourcePackage, expr ast.Expr) (Schema, error) {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		if schema, ok := builtinSchema(t.Name); ok {
+			return schema, nil
+		}
+		decl, err := x.lookup(file, pkg, "", t.Name)
+		if err != nil {
+			return nil, err
+		}
+		return x.named(decl)
+	case *ast.SelectorExpr:
+		qualifier := t.X.(*ast.Ident).Name
+		if schema, ok := externalSchema(qualifier + "." + t.Sel.Name); ok {
+			return schema, nil
+		}
+		decl, err := x.lookup(file, pkg, qualifier, t.Sel.Name)
+		if err != nil {
+			return nil, err
+		}
+		if decl == nil {
+			// A type outside the module without a known mapping accepts any value
+			return Schema{}, nil
+		}
+		return x.named(decl)
+	case *ast.StarExpr:
+		return x.expr(file, pkg, t.X)
+	case *ast.ArrayType:
+		if ident, ok := t.Elt.(*ast.Ident); ok && ident.Name == "byte" {
+			return Schema{"type": "string", "contentEncoding": "base64"}, nil
+		}
+		items, err := x.expr(file, pkg, t.Elt)
+		if err != nil {
+			return nil, err
+		}
+		return Schema{"type": "array", "items": items}, nil
+	case *ast.MapType:
+		values, err := x.expr(file, pkg, t.Value)
+		if err != nil {
+			return nil, err
+		}
+		schema := Schema{"type": "object"}
+		if len(values) > 0 {
+			schema["additionalProperties"] = values
+		}
+		return schema, nil
+	case *ast.InterfaceType:
+		return Schema{}, nil
+	case *ast.StructType:
+		return x.structSchema(file, pkg, t)
+	default:
+		return nil, fmt.Errorf("unsupported type %T", expr)
+	}
+}
+
+// structSchema builds an object schema from the json and binding tags of the fields
+func (x *typeIndex) structSchema(file *ast.File, pkg *sourcePackage, st *ast.StructType) (Schema, error) {
+	properties := Schema{}
+	var required []string
+	var embedded []any
+
+	for _, field := range st.Fields.List {
+		var tag reflect.StructTag
+		if field.Tag != nil {
+			value, _ := strconv.Unquote(field.Tag.Value)
+			tag = reflect.StructTag(value)
+		}
+		jsonName, _, _ := strings.Cut(tag.Get("json"), ",")
+		if jsonName == "-" {
+			continue
+		}
+
+		schema, err := x.expr(file, pkg, field.Type)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(field.Names) == 0 && jsonName == "" {
+			// Embedded struct without a json name: its fields are promoted
+			embedded = append(embedded, schema)
+			continue
+		}
+		for _, name := range field.Names {
+			if !name.IsExported() {
+				continue
+			}
+			propName := jsonName
+			if propName == "" {
+				propName = name.Name
+			}
+			fieldSchema := Schema{}
+			for k, v := range schema {
+				fieldSchema[k] = v
+			}
+			if doc := docText(field.Doc, name.Name); doc != "" {
+				fieldSchema["description"] = doc
+			} else if doc := docText(field.Comment, ""); doc != "" {
+				fieldSchema["description"] = doc
+			}
+			if applyBinding(fieldSchema, tag.Get("binding")) {
+				required = append(required, propName)
+			}
+			properties[propName] = fieldSchema
+		}
+	}
+
+	schema := Schema{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	if len(embedded) > 0 {
+		return Schema{"allOf": append(embedded, schema)}, nil
+	}
+	return schema, nil
+}
+
+// applyBinding maps validator rules of a binding tag to schema keywords; it reports whether
+// the field is required
+func applyBinding(schema Schema, binding string) bool {
+	required := false
+	for _, rule := range strings.Split(binding, ",") {
+		name, arg, _ := strings.Cut(rule, "=")
+		switch name {
+		case "dive":
+			// The remaining rules apply to the elements
+			return required
+		case "required":
+			required = true
+		case "email":
+			schema["format"] = "email"
+		case "oneof":
+			values := make([]any, 0)
+			for _, value := range strings.Fields(arg) {
+				values = append(values, value)
+			}
+			schema["enum"] = values
+		case "min", "max":
+			n, err := strconv.Atoi(arg)
+			if err != nil {
+				continue
+			}
+			schema[lengthKeyword(schema, name)] = n
+		}
+	}
+	return required
+}
+
+// lengthKeyword the keyword of a min/max rule for the type of the schema
+func lengthKeyword(schema Schema, rule string) string {
+	var keyword string
+	switch schema["type"] {
+	case "string":
+		keyword = "Length"
+	case "array":
+		keyword = "Items"
+	case "object":
+		keyword = "Properties"
+	default:
+		if rule == "min" {
+			return "minimum"
+		}
+		return "maximum"
+	}
+	if rule == "min" {
+		return "min" + keyword
+	}
+	return "max" + keyword
+}
+
+func builtinSchema(name string) (Schema, bool) {
+	switch name {
+	case "string":
+		return Schema{"type": "string"}, true
+	case "bool":
+		return Schema{"type": "boolean"}, true
+	case "int", "int8", "int16", "int32", "uint", "uint8", "uint16", "uint32", "byte", "rune":
+		return Schema{"type": "integer"}, true
+	case "int64", "uint64":
+		return Schema{"type": "integer", "format": "int64"}, true
+	case "float32":
+		return Schema{"type": "number", "format": "float"}, true
+	case "float64":
+		return Schema{"type": "number", "format": "double"}, true
+	case "any", "error":
+		return Schema{}, true
+	}
+	return nil, false
+}
+
+// externalSchema schemas of types outside the module with a custom JSON encoding
+func externalSchema(name string) (Schema, bool) {
+	switch name {
+	case "time.Time":
+		return Schema{"type": "string", "format": "date-time"}, true
+	case "time.Duration":
+		return Schema{"type": "integer", "format": "int64"}, true
+	case "json.RawMessage":
+		return Schema{}, true
+	}
+	return nil, false
+}
+
+// docText returns a doc comment without the leading identifier ("Name does ..." -> "does ...")
+func docText(group *ast.CommentGroup, name string) string {
+	if group == nil {
+		return ""
+	}
+	text := strings.TrimSpace(group.Text())
+	if name != "" {
+		if rest, ok := strings.CutPrefix(text, name+" "); ok {
+			text = rest
+		} else if text == name {
+			text = ""
+		}
+	}
+	return strings.Join(strings.Fields(text), " ")
+}
+
+func literalValue(lit *ast.BasicLit) any {
+	switch lit.Kind {
+	case token.STRING:
+		s, _ := strconv.Unquote(lit.Value)
+		return s
+	case token.INT:
+		n, _ := strconv.ParseInt(lit.Value, 0, 64)
+		return n
+	default:
+		return lit.Value
+	}
+}
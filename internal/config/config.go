@@ -2,9 +2,12 @@ package config
 
 import (
 	"fmt"
+	"net"
+	"slices"
 	"strings"
 	"time"
 
+	"example.com/classic/pkg/i18n"
 	"github.com/spf13/viper"
 )
 
@@ -17,6 +20,16 @@ const (
 	EnvProduction  Environment = "production"
 )
 
+// UserAPI 用户 REST 接口的实现方式
+type UserAPI string
+
+const (
+	// UserAPIGin 手写的 gin 处理器
+	UserAPIGin UserAPI = "gin"
+	// UserAPIGateway grpc-gateway 在进程内将 REST 请求转给 gRPC 服务实现，接口由 proto 的 HTTP 注解定义
+	UserAPIGateway UserAPI = "gateway"
+)
+
 // HTTPConfig HTTP 服务配置
 type HTTPConfig struct {
 	Address        string        `mapstructure:"address"`
@@ -27,6 +40,78 @@ type HTTPConfig struct {
 	EnableCORS     bool          `mapstructure:"enable_cors"`
 	EnableMetrics  bool          `mapstructure:"enable_metrics"`
 	EnableHealth   bool          `mapstructure:"enable_health"`
+	// TrustedProxies 可信反向代理 (IP 或 CIDR)；只有来自它们的请求才按 X-Forwarded-For / X-Real-IP 取客户端地址，为空时不信任任何代理
+	TrustedProxies []string `mapstructure:"trusted_proxies"`
+	// ProblemDetails 错误响应统一使用 RFC 7807 application/problem+json；
+	// 关闭时客户端仍可通过 Accept: application/problem+json 按请求选择
+	ProblemDetails bool `mapstructure:"problem_details"`
+	// ProblemTypeBase 问题类型 URI 前缀，拼接业务错误码；为空时 type 为 about:blank
+	ProblemTypeBase string `mapstructure:"problem_type_base"`
+	// CORS 跨域策略 (enable_cors 开启时生效)
+	CORS CORSConfig `mapstructure:"cors"`
+	// SecurityHeaders 安全响应头
+	SecurityHeaders SecurityHeadersConfig `mapstructure:"security_headers"`
+	// UserAPI 用户接口由 gin 处理器还是 grpc-gateway 提供；流式导入导出始终由 gin 处理器提供
+	UserAPI UserAPI `mapstructure:"user_api"`
+}
+
+// CORSPolicy 跨域策略
+type CORSPolicy struct {
+	// AllowOrigins 允许的来源："*"、"https://app.example.com" 或通配子域名 "https://*.example.com"
+	AllowOrigins []string `mapstructure:"allow_origins"`
+	// AllowMethods 预检允许的方法，为空时使用 GET、POST、PUT、PATCH、DELETE、OPTIONS
+	AllowMethods []string `mapstructure:"allow_methods"`
+	// AllowHeaders 预检允许的请求头，为空时使用内置列表 (含租户头与幂等键头)；"*" 表示回显请求的头
+	AllowHeaders []string `mapstructure:"allow_headers"`
+	// ExposeHeaders 浏览器可读取的响应头，为空时使用内置列表 (追踪、限流、幂等回放)
+	ExposeHeaders []string `mapstructure:"expose_headers"`
+	// AllowCredentials 允许携带 Cookie 等凭据；不能与 "*" 来源同时使用
+	AllowCredentials bool `mapstructure:"allow_credentials"`
+	// MaxAge 预检结果缓存时间 (Access-Control-Max-Age)，0 表示不缓存
+	MaxAge time.Duration `mapstructure:"max_age"`
+}
+
+// CORSRoute 按路径前缀覆盖的跨域策略
+type CORSRoute struct {
+	Path       string `mapstructure:"path"` // 路径前缀，如 /api/v1/admin；多个匹配时取最长前缀
+	CORSPolicy `mapstructure:",squash"`
+}
+
+// CORSConfig 跨域配置：默认策略与按路由覆盖的策略 (路由策略完整替换默认策略)
+type CORSConfig struct {
+	CORSPolicy `mapstructure:",squash"`
+	Routes     []CORSRoute `mapstructure:"routes"`
+}
+
+// allOrigins 默认策略和各路由策略中配置的全部来源
+func (c CORSConfig) allOrigins() []string {
+	origins := append([]string(nil), c.AllowOrigins...)
+	for _, route := range c.Routes {
+		origins = append(origins, route.AllowOrigins...)
+	}
+	return origins
+}
+
+// policies 默认策略和各路由策略
+func (c CORSConfig) policies() []CORSPolicy {
+	policies := []CORSPolicy{c.CORSPolicy}
+	for _, route := range c.Routes {
+		policies = append(policies, route.CORSPolicy)
+	}
+	return policies
+}
+
+// SecurityHeadersConfig 安全响应头配置
+type SecurityHeadersConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// HSTSMaxAge Strict-Transport-Security 的 max-age，仅在 HTTPS 请求 (含 X-Forwarded-Proto: https) 上发送；0 表示不发送
+	HSTSMaxAge            time.Duration `mapstructure:"hsts_max_age"`
+	HSTSIncludeSubdomains bool          `mapstructure:"hsts_include_subdomains"`
+	HSTSPreload           bool          `mapstructure:"hsts_preload"`
+	ContentTypeNosniff    bool          `mapstructure:"content_type_nosniff"`    // X-Content-Type-Options: nosniff
+	FrameOptions          string        `mapstructure:"frame_options"`           // X-Frame-Options：DENY | SAMEORIGIN，为空时不发送
+	ContentSecurityPolicy string        `mapstructure:"content_security_policy"` // 为空时不发送
+	ReferrerPolicy        string        `mapstructure:"referrer_policy"`         // 为空时不发送
 }
 
 // GRPCConfig gRPC 服务配置
@@ -35,6 +120,27 @@ type GRPCConfig struct {
 	EnableReflection bool         `mapstructure:"enable_reflection"`
 	MaxRecvMsgSize  int           `mapstructure:"max_recv_msg_size"`
 	MaxSendMsgSize  int           `mapstructure:"max_send_msg_size"`
+	// SharedPort 在 HTTP 端口 (http.address) 上同时提供 gRPC，按协议与 Content-Type 分流；此时不监听 port
+	SharedPort bool `mapstructure:"shared_port"`
+}
+
+// TLSConfig HTTP 与 gRPC 服务的 TLS 配置；证书、私钥与客户端 CA 文件变更后自动重新加载
+type TLSConfig struct {
+	Enabled    bool   `mapstructure:"enabled"`
+	CertFile   string `mapstructure:"cert_file"`   // PEM 证书 (可含中间证书)
+	KeyFile    string `mapstructure:"key_file"`    // PEM 私钥
+	MinVersion string `mapstructure:"min_version"` // 1.2 或 1.3
+	// ClientCAFile 非空时启用双向 TLS：客户端必须出示由这些 CA 签发的证书
+	ClientCAFile string `mapstructure:"client_ca_file"`
+}
+
+// AdminConfig 运维调试接口 (pprof、运行时日志级别、构建信息、生效配置)
+// Address 为空时挂载在 HTTP 服务的 /admin 下，此时必须配置 Token
+type AdminConfig struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Address string `mapstructure:"address"` // 独立监听地址，如 127.0.0.1:6060
+	// Token 请求头 X-Admin-Token 的值；独立监听时可为空
+	Token string `mapstructure:"token" redact:"true"`
 }
 
 // LogConfig 日志配置
@@ -53,11 +159,11 @@ type LogConfig struct {
 // DBConfig 数据库配置
 type DBConfig struct {
 	Driver      string        `mapstructure:"driver"`
-	DSN         string        `mapstructure:"dsn"`
+	DSN         string        `mapstructure:"dsn" redact:"true"`
 	Host        string        `mapstructure:"host"`
 	Port        int           `mapstructure:"port"`
 	Username    string        `mapstructure:"username"`
-	Password    string        `mapstructure:"password"`
+	Password    string        `mapstructure:"password" redact:"true"`
 	Database    string        `mapstructure:"database"`
 	Charset     string        `mapstructure:"charset"`
 	MaxOpen     int           `mapstructure:"max_open"`
@@ -71,7 +177,7 @@ type DBConfig struct {
 type RedisConfig struct {
 	Host         string        `mapstructure:"host"`
 	Port         int           `mapstructure:"port"`
-	Password     string        `mapstructure:"password"`
+	Password     string        `mapstructure:"password" redact:"true"`
 	Database     int           `mapstructure:"database"`
 	PoolSize     int           `mapstructure:"pool_size"`
 	MinIdleConns int           `mapstructure:"min_idle_conns"`
@@ -84,7 +190,7 @@ type RedisConfig struct {
 // AsynqConfig Asynq 任务队列配置
 type AsynqConfig struct {
 	RedisAddr       string        `mapstructure:"redis_addr"`
-	RedisPassword   string        `mapstructure:"redis_password"`
+	RedisPassword   string        `mapstructure:"redis_password" redact:"true"`
 	RedisDB         int           `mapstructure:"redis_db"`
 	Concurrency     int           `mapstructure:"concurrency"`
 	Queues          []string      `mapstructure:"queues"`
@@ -103,18 +209,154 @@ type KafkaConfig struct {
 	MaxMessageBytes int           `mapstructure:"max_message_bytes"`
 }
 
+// BatchConfig 批量操作配置
+type BatchConfig struct {
+	AsyncThreshold int           `mapstructure:"async_threshold"`
+	MaxSize        int           `mapstructure:"max_size"`
+	JobRetention   time.Duration `mapstructure:"job_retention"`
+}
+
+// TenancyConfig 多租户配置
+// 租户解析顺序：JWT claim > 请求头 > 子域名 > 默认租户
+// 请求头和子域名可由客户端任意指定，因此默认开启 RequireJWT；仅在可信网关设置它们时关闭
+type TenancyConfig struct {
+	DefaultTenant string        `mapstructure:"default_tenant"` // 未解析到租户时使用；为空则必须显式指定
+	Header        string        `mapstructure:"header"`
+	BaseDomain    string        `mapstructure:"base_domain"`              // 如 example.com，则 acme.example.com 解析为 acme
+	JWTSecret     string        `mapstructure:"jwt_secret" redact:"true"` // HS256 密钥；为空时不解析 JWT
+	JWTClaim      string        `mapstructure:"jwt_claim"`
+	RequireJWT    bool          `mapstructure:"require_jwt"`               // 只信任已验证 JWT 中的租户，忽略请求头和子域名
+	AdminToken    string        `mapstructure:"admin_token" redact:"true"` // 租户管理 API 的令牌；为空时禁用
+	CacheTTL      time.Duration `mapstructure:"cache_ttl"`
+}
+
+// PIIConfig 个人数据字段加密配置
+type PIIConfig struct {
+	KeyringFile string `mapstructure:"keyring_file"` // 密钥环 JSON 文件；为空时 name/email 明文存储
+}
+
+// MetricsConfig Prometheus 指标配置
+// HTTP 服务在 http.enable_metrics 开启时于 Path 暴露指标；asynq worker 在 WorkerAddress 单独暴露
+type MetricsConfig struct {
+	Enabled        bool      `mapstructure:"enabled"` // 关闭时不采集任何指标
+	Path           string    `mapstructure:"path"`
+	WorkerAddress  string    `mapstructure:"worker_address"`  // 为空时 worker 不暴露指标
+	Namespace      string    `mapstructure:"namespace"`       // 应用指标名前缀
+	LatencyBuckets []float64 `mapstructure:"latency_buckets"` // 延迟直方图桶 (秒)；为空时使用 Prometheus 默认值
+	GoRuntime      bool      `mapstructure:"go_runtime"`      // Go 运行时与进程指标
+	DBStats        bool      `mapstructure:"db_stats"`        // 连接池指标
+}
+
+// HealthConfig 健康检查配置 (http.enable_health 开启时暴露 /livez、/readyz)
+type HealthConfig struct {
+	Timeout       time.Duration `mapstructure:"timeout"`        // 单个依赖检查的超时
+	CacheTTL      time.Duration `mapstructure:"cache_ttl"`      // 检查结果缓存时间，避免探针压垮依赖
+	ShutdownDelay time.Duration `mapstructure:"shutdown_delay"` // 优雅关闭时 /readyz 失败后继续服务的时间，供负载均衡摘除流量
+}
+
+// RateLimitRule 限流规则，每个匹配的规则独立计数
+type RateLimitRule struct {
+	Name string `mapstructure:"name"`
+	// Routes HTTP 路由模板，"POST /api/v1/users" 限定方法，"/api/v1/users/:id" 匹配所有方法，"*" 匹配所有路由
+	Routes []string `mapstructure:"routes"`
+	// Methods gRPC 完整方法名，如 "/user.UserService/Register"；"/user.UserService/*" 匹配整个服务，"*" 匹配所有
+	Methods []string      `mapstructure:"methods"`
+	Key     string        `mapstructure:"key"` // ip | user | api_key | route
+	Limit   int           `mapstructure:"limit"`
+	Period  time.Duration `mapstructure:"period"`
+	Burst   int           `mapstructure:"burst"` // 允许的突发请求数；为 0 时等于 limit
+}
+
+// RateLimitConfig 限流配置
+// 计数保存在 Redis (redis.*)；Redis 不可用时退化为进程内限流
+type RateLimitConfig struct {
+	Enabled      bool            `mapstructure:"enabled"`
+	Prefix       string          `mapstructure:"prefix"`         // Redis key 前缀
+	APIKeyHeader string          `mapstructure:"api_key_header"` // key 为 api_key 时读取的请求头 (gRPC 使用同名 metadata)
+	Rules        []RateLimitRule `mapstructure:"rules"`
+}
+
+// EventStreamConfig 领域事件 SSE 推送配置 (GET /api/v1/events/stream)
+// 事件经 Redis pub/sub 广播到所有副本，每个副本保留最近 ReplayBuffer 条事件供 Last-Event-ID 续传
+type EventStreamConfig struct {
+	Enabled      bool          `mapstructure:"enabled"`
+	Channel      string        `mapstructure:"channel"`       // Redis pub/sub 频道，事件序号保存在 <channel>:seq
+	ReplayBuffer int           `mapstructure:"replay_buffer"` // 可续传的事件数
+	Heartbeat    time.Duration `mapstructure:"heartbeat"`     // 心跳注释的间隔
+}
+
+// IdempotencyConfig 幂等键配置
+// 对携带幂等键的 POST/PUT/PATCH 请求 (gRPC 为同名 metadata)，在 Redis 中保存请求指纹与响应，重试时回放原响应
+type IdempotencyConfig struct {
+	Enabled      bool          `mapstructure:"enabled"`
+	Header       string        `mapstructure:"header"`         // 幂等键请求头
+	Prefix       string        `mapstructure:"prefix"`         // Redis key 前缀
+	TTL          time.Duration `mapstructure:"ttl"`            // 响应保存时间，超过后同一个键视为新请求
+	LockTTL      time.Duration `mapstructure:"lock_ttl"`       // 处理中锁的过期时间，进程崩溃后键在此之后可重试
+	MaxBodyBytes int           `mapstructure:"max_body_bytes"` // 请求体在内存中计算指纹的上限 (更大的写入临时文件)，也是可缓存的响应体上限
+}
+
+// RequestLimitRule 路由级的请求超时与请求体上限；为 0 的项沿用默认值
+type RequestLimitRule struct {
+	Name string `mapstructure:"name"`
+	// Routes HTTP 路由模板，写法同限流规则："POST /api/v1/users"、"/api/v1/users/:id"、"*"
+	Routes []string `mapstructure:"routes"`
+	// Methods gRPC 完整方法名，写法同限流规则："/user.UserService/ImportUsers"、"/user.UserService/*"
+	Methods      []string      `mapstructure:"methods"`
+	Timeout      time.Duration `mapstructure:"timeout"`        // 请求处理时限，作为 context deadline 传入 service 与数据库调用
+	MaxBodyBytes int64         `mapstructure:"max_body_bytes"` // 请求体 (gRPC 为请求消息) 上限
+}
+
+// RequestLimitsConfig 请求超时与请求体上限配置
+// 超时返回 504，请求体过大返回 413 (gRPC 为 DEADLINE_EXCEEDED / RESOURCE_EXHAUSTED)；按顺序取第一条匹配的规则
+type RequestLimitsConfig struct {
+	Timeout      time.Duration      `mapstructure:"timeout"`        // 默认请求处理时限，0 表示不限制
+	MaxBodyBytes int64              `mapstructure:"max_body_bytes"` // 默认请求体上限，0 表示不限制
+	Rules        []RequestLimitRule `mapstructure:"rules"`
+}
+
+// I18nConfig 消息本地化配置
+// 语言按 Accept-Language 请求头 (gRPC 为同名 metadata) 协商，无法匹配时使用 DefaultLocale
+type I18nConfig struct {
+	DefaultLocale string `mapstructure:"default_locale"` // en | zh-CN
+}
+
+// AttributeConfig 用户自定义属性定义
+type AttributeConfig struct {
+	Name     string   `mapstructure:"name"`
+	Type     string   `mapstructure:"type"` // string | integer | number | boolean
+	Required bool     `mapstructure:"required"`
+	Pattern  string   `mapstructure:"pattern"`
+	Enum     []string `mapstructure:"enum"`
+}
+
 // Config 应用配置
 type Config struct {
-	Environment Environment `mapstructure:"environment"`
-	Service     string      `mapstructure:"service"`
-	Version     string      `mapstructure:"version"`
-	HTTP        HTTPConfig  `mapstructure:"http"`
-	GRPC        GRPCConfig  `mapstructure:"grpc"`
-	Log         LogConfig   `mapstructure:"log"`
-	DB          DBConfig    `mapstructure:"db"`
-	Redis       RedisConfig `mapstructure:"redis"`
-	Asynq       AsynqConfig `mapstructure:"asynq"`
-	Kafka       KafkaConfig `mapstructure:"kafka"`
+	Environment Environment       `mapstructure:"environment"`
+	Service     string            `mapstructure:"service"`
+	Version     string            `mapstructure:"version"`
+	HTTP        HTTPConfig        `mapstructure:"http"`
+	GRPC        GRPCConfig        `mapstructure:"grpc"`
+	TLS         TLSConfig         `mapstructure:"tls"`
+	Admin       AdminConfig       `mapstructure:"admin"`
+	Log         LogConfig         `mapstructure:"log"`
+	DB          DBConfig          `mapstructure:"db"`
+	Redis       RedisConfig       `mapstructure:"redis"`
+	Asynq       AsynqConfig       `mapstructure:"asynq"`
+	Kafka       KafkaConfig       `mapstructure:"kafka"`
+	Batch       BatchConfig       `mapstructure:"batch"`
+	Tenancy     TenancyConfig     `mapstructure:"tenancy"`
+	PII         PIIConfig         `mapstructure:"pii"`
+	Metrics     MetricsConfig     `mapstructure:"metrics"`
+	Health      HealthConfig      `mapstructure:"health"`
+	RateLimit   RateLimitConfig   `mapstructure:"ratelimit"`
+	Idempotency IdempotencyConfig `mapstructure:"idempotency"`
+	EventStream EventStreamConfig `mapstructure:"event_stream"`
+	// RequestLimits 按路由的请求超时与请求体上限
+	RequestLimits RequestLimitsConfig `mapstructure:"request_limits"`
+	I18n          I18nConfig          `mapstructure:"i18n"`
+	// Attributes 用户自定义属性 schema
+	Attributes []AttributeConfig `mapstructure:"attributes"`
 }
 
 // Load 加载配置
@@ -172,12 +414,39 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("http.enable_cors", true)
 	v.SetDefault("http.enable_metrics", true)
 	v.SetDefault("http.enable_health", true)
+	v.SetDefault("http.trusted_proxies", []string{})
+	v.SetDefault("http.problem_details", false)
+	v.SetDefault("http.problem_type_base", "")
+	v.SetDefault("http.user_api", string(UserAPIGin))
+	v.SetDefault("http.cors.allow_origins", []string{"*"})
+	v.SetDefault("http.cors.allow_credentials", false)
+	v.SetDefault("http.cors.max_age", "10m")
+	v.SetDefault("http.security_headers.enabled", true)
+	v.SetDefault("http.security_headers.hsts_max_age", "4320h")
+	v.SetDefault("http.security_headers.hsts_include_subdomains", true)
+	v.SetDefault("http.security_headers.content_type_nosniff", true)
+	v.SetDefault("http.security_headers.frame_options", "DENY")
+	v.SetDefault("http.security_headers.content_security_policy", "default-src 'none'; frame-ancestors 'none'")
+	v.SetDefault("http.security_headers.referrer_policy", "no-referrer")
 
 	// gRPC 配置
 	v.SetDefault("grpc.port", 9090)
 	v.SetDefault("grpc.enable_reflection", true)
 	v.SetDefault("grpc.max_recv_msg_size", 10485760)
 	v.SetDefault("grpc.max_send_msg_size", 10485760)
+	v.SetDefault("grpc.shared_port", false)
+
+	// TLS 默认配置
+	v.SetDefault("tls.enabled", false)
+	v.SetDefault("tls.cert_file", "")
+	v.SetDefault("tls.key_file", "")
+	v.SetDefault("tls.min_version", "1.2")
+	v.SetDefault("tls.client_ca_file", "")
+
+	// 运维接口默认配置
+	v.SetDefault("admin.enabled", false)
+	v.SetDefault("admin.address", "")
+	v.SetDefault("admin.token", "")
 
 	// 日志配置
 	v.SetDefault("log.level", "info")
@@ -229,6 +498,60 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("kafka.replicas", 1)
 	v.SetDefault("kafka.retention", "168h")
 	v.SetDefault("kafka.max_message_bytes", 1048576)
+
+	// 批量操作配置
+	v.SetDefault("batch.async_threshold", 100)
+	v.SetDefault("batch.max_size", 10000)
+	v.SetDefault("batch.job_retention", "24h")
+
+	// 多租户配置
+	v.SetDefault("tenancy.default_tenant", "default")
+	v.SetDefault("tenancy.header", "X-Tenant-ID")
+	v.SetDefault("tenancy.jwt_claim", "tenant_id")
+	v.SetDefault("tenancy.require_jwt", true)
+	v.SetDefault("tenancy.cache_ttl", "30s")
+
+	// 个人数据加密配置
+	v.SetDefault("pii.keyring_file", "")
+
+	// 指标配置
+	v.SetDefault("metrics.enabled", true)
+	v.SetDefault("metrics.path", "/metrics")
+	v.SetDefault("metrics.worker_address", ":9091")
+	v.SetDefault("metrics.namespace", "")
+	v.SetDefault("metrics.go_runtime", true)
+	v.SetDefault("metrics.db_stats", true)
+
+	// 健康检查配置
+	v.SetDefault("health.timeout", "2s")
+	v.SetDefault("health.cache_ttl", "2s")
+	v.SetDefault("health.shutdown_delay", "5s")
+
+	// 限流配置
+	v.SetDefault("ratelimit.enabled", true)
+	v.SetDefault("ratelimit.prefix", "ratelimit")
+	v.SetDefault("ratelimit.api_key_header", "X-API-Key")
+
+	// 幂等键配置
+	v.SetDefault("idempotency.enabled", true)
+	v.SetDefault("idempotency.header", "Idempotency-Key")
+	v.SetDefault("idempotency.prefix", "idempotency")
+	v.SetDefault("idempotency.ttl", "24h")
+	v.SetDefault("idempotency.lock_ttl", "1m")
+	v.SetDefault("idempotency.max_body_bytes", 1<<20)
+
+	// 事件推送配置
+	v.SetDefault("event_stream.enabled", false)
+	v.SetDefault("event_stream.channel", "classic:user-events")
+	v.SetDefault("event_stream.replay_buffer", 1000)
+	v.SetDefault("event_stream.heartbeat", "15s")
+
+	// 请求超时与请求体上限 (默认时限小于 http.write_timeout，超时后仍能写出 504)
+	v.SetDefault("request_limits.timeout", "15s")
+	v.SetDefault("request_limits.max_body_bytes", 1<<20)
+
+	// 本地化配置
+	v.SetDefault("i18n.default_locale", i18n.English)
 }
 
 // Validate 验证配置
@@ -245,6 +568,73 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("http address is required")
 	}
 
+	for _, proxy := range c.HTTP.TrustedProxies {
+		if net.ParseIP(proxy) == nil {
+			if _, _, err := net.ParseCIDR(proxy); err != nil {
+				return fmt.Errorf("invalid http trusted_proxies entry: %q (want an IP or CIDR)", proxy)
+			}
+		}
+	}
+
+	switch c.HTTP.UserAPI {
+	case UserAPIGin, UserAPIGateway:
+	default:
+		return fmt.Errorf("invalid http user_api: %q (want gin or gateway)", c.HTTP.UserAPI)
+	}
+
+	for _, route := range c.HTTP.CORS.Routes {
+		if !strings.HasPrefix(route.Path, "/") {
+			return fmt.Errorf("http cors route path must start with /: %q", route.Path)
+		}
+	}
+	for _, origin := range c.HTTP.CORS.allOrigins() {
+		if origin != "*" && strings.Count(origin, "*") > 1 {
+			return fmt.Errorf("http cors origin may contain a single wildcard: %q", origin)
+		}
+	}
+	for _, policy := range c.HTTP.CORS.policies() {
+		// 浏览器拒绝带凭据的 "*"，回显任意来源又等于允许任何站点携带用户凭据
+		if policy.AllowCredentials && slices.Contains(policy.AllowOrigins, "*") {
+			return fmt.Errorf("http cors allow_origins \"*\" cannot be combined with allow_credentials")
+		}
+	}
+
+	// 验证多租户配置
+	// 开启 require_jwt 但没有 jwt_secret 时所有请求都使用默认租户（单租户部署）
+	if c.Tenancy.RequireJWT && c.Tenancy.JWTSecret == "" && c.Tenancy.DefaultTenant == "" {
+		return fmt.Errorf("tenancy jwt_secret or default_tenant is required when require_jwt is enabled")
+	}
+
+	// 验证 TLS 配置
+	if c.TLS.Enabled {
+		if c.TLS.CertFile == "" || c.TLS.KeyFile == "" {
+			return fmt.Errorf("tls cert_file and key_file are required")
+		}
+		switch c.TLS.MinVersion {
+		case "", "1.2", "1.3":
+		default:
+			return fmt.Errorf("invalid tls min_version: %q (want 1.2 or 1.3)", c.TLS.MinVersion)
+		}
+	}
+
+	// 验证运维接口配置
+	if c.Admin.Enabled && c.Admin.Address == "" && c.Admin.Token == "" {
+		return fmt.Errorf("admin token is required when the admin endpoints share the http address")
+	}
+
+	// 验证事件推送配置
+	if c.EventStream.Enabled {
+		if c.EventStream.Channel == "" {
+			return fmt.Errorf("event_stream channel is required")
+		}
+		if c.EventStream.ReplayBuffer <= 0 {
+			return fmt.Errorf("event_stream replay_buffer must be positive")
+		}
+		if c.EventStream.Heartbeat <= 0 {
+			return fmt.Errorf("event_stream heartbeat must be positive")
+		}
+	}
+
 	// 验证日志配置
 	if c.Log.Level == "" {
 		return fmt.Errorf("log level is required")
@@ -255,6 +645,12 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("db driver is required")
 	}
 
+	// 验证本地化配置
+	if _, ok := i18n.Default().Supported(c.I18n.DefaultLocale); !ok {
+		return fmt.Errorf("unsupported i18n default locale: %s (supported: %s)",
+			c.I18n.DefaultLocale, strings.Join(i18n.Default().Locales(), ", "))
+	}
+
 	return nil
 }
 
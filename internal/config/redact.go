@@ -0,0 +1,64 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// RedactedValue 替换敏感配置项的值
+const RedactedValue = "[REDACTED]"
+
+// Redacted 返回生效配置，键名与配置文件一致 (mapstructure tag)；
+// 标记 redact:"true" 的字段 (密码、令牌、DSN 等) 非空时替换为 RedactedValue
+func (c *Config) Redacted() map[string]any {
+	return redactValue(reflect.ValueOf(*c)).(map[string]any)
+}
+
+func redactValue(v reflect.Value) any {
+	if v.Type() == reflect.TypeOf(time.Duration(0)) {
+		return time.Duration(v.Int()).String()
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		out := make(map[string]any, v.NumField())
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			key, _, _ := strings.Cut(field.Tag.Get("mapstructure"), ",")
+			if key == "" {
+				key = strings.ToLower(field.Name)
+			}
+			if field.Tag.Get("redact") == "true" && !v.Field(i).IsZero() {
+				out[key] = RedactedValue
+				continue
+			}
+			out[key] = redactValue(v.Field(i))
+		}
+		return out
+	case reflect.Slice, reflect.Array:
+		out := make([]any, v.Len())
+		for i := range out {
+			out[i] = redactValue(v.Index(i))
+		}
+		return out
+	case reflect.Map:
+		out := make(map[string]any, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			out[fmt.Sprint(iter.Key().Interface())] = redactValue(iter.Value())
+		}
+		return out
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return redactValue(v.Elem())
+	default:
+		return v.Interface()
+	}
+}
@@ -25,15 +25,37 @@ func (s Status) IsValid() bool {
 }
 
 type User struct {
-	ID        int32
+	ID         int32
+	TenantID   string
+	Name       string
+	Email      string
+	EmailIndex string
+	Password   string
+	Status     Status
+	Attributes []byte
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+type Tenant struct {
+	ID        string
 	Name      string
-	Email     string
-	Password  string
-	Status    Status
+	Status    string
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
 
+type Event struct {
+	ID          int64
+	TenantID    string
+	AggregateID string
+	SequenceNo  int32
+	Type        string
+	Payload     []byte
+	Metadata    []byte
+	OccurredAt  time.Time
+}
+
 // Null* types for nullable fields
 type NullStatus struct {
 	Status Status
@@ -10,13 +10,27 @@ import (
 
 type Querier interface {
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
-	GetUserByID(ctx context.Context, id int32) (User, error)
-	GetUserByEmail(ctx context.Context, email string) (User, error)
+	GetUserByID(ctx context.Context, arg GetUserByIDParams) (User, error)
+	GetUserByEmail(ctx context.Context, arg GetUserByEmailParams) (User, error)
 	UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error)
-	DeleteUser(ctx context.Context, id int32) error
+	DeleteUser(ctx context.Context, arg DeleteUserParams) error
 	ListUsers(ctx context.Context, arg ListUsersParams) ([]User, error)
 	CountUsers(ctx context.Context, arg CountUsersParams) (int64, error)
-	ExistsByEmail(ctx context.Context, email string) (bool, error)
+	ExistsByEmail(ctx context.Context, arg ExistsByEmailParams) (bool, error)
+	ScanUsers(ctx context.Context, arg ScanUsersParams) ([]User, error)
+	UpdateUserPII(ctx context.Context, arg UpdateUserPIIParams) (int64, error)
+	CreateTenant(ctx context.Context, arg CreateTenantParams) error
+	GetTenantByID(ctx context.Context, id string) (Tenant, error)
+	UpdateTenant(ctx context.Context, arg UpdateTenantParams) error
+	DeleteTenant(ctx context.Context, id string) error
+	ListTenants(ctx context.Context, arg ListTenantsParams) ([]Tenant, error)
+	CountTenants(ctx context.Context) (int64, error)
+	CreateEvent(ctx context.Context, arg CreateEventParams) error
+	GetLastEventSequence(ctx context.Context, arg GetLastEventSequenceParams) (int32, error)
+	ListAggregateEvents(ctx context.Context, arg ListAggregateEventsParams) ([]Event, error)
+	ListEvents(ctx context.Context, arg ListEventsParams) ([]Event, error)
+	UpdateEventPayload(ctx context.Context, arg UpdateEventPayloadParams) error
+	UpdateEventPII(ctx context.Context, arg UpdateEventPIIParams) (int64, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -8,6 +8,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -32,25 +33,31 @@ func (q *Queries) WithTx(tx *sql.Tx) *Queries {
 
 // CreateUserParams represents parameters for CreateUser
 type CreateUserParams struct {
-	Name      string
-	Email     string
-	Password  string
-	Status    Status
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	TenantID   string
+	Name       string
+	Email      string
+	EmailIndex string
+	Password   string
+	Status     Status
+	Attributes []byte
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
 }
 
 // CreateUser inserts a new user and returns the created record
 func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
 	const query = `
-		INSERT INTO users (name, email, password, status, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?)
+		INSERT INTO users (tenant_id, name, email, email_index, password, status, attributes, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 	result, err := q.db.ExecContext(ctx, query,
+		arg.TenantID,
 		arg.Name,
 		arg.Email,
+		arg.EmailIndex,
 		arg.Password,
 		string(arg.Status),
+		nullJSON(arg.Attributes),
 		arg.CreatedAt,
 		arg.UpdatedAt,
 	)
@@ -63,21 +70,30 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		return User{}, fmt.Errorf("get last insert id: %w", err)
 	}
 
-	return q.GetUserByID(ctx, int32(id))
+	return q.GetUserByID(ctx, GetUserByIDParams{TenantID: arg.TenantID, ID: int32(id)})
 }
 
-// GetUserByID retrieves a user by ID
-func (q *Queries) GetUserByID(ctx context.Context, id int32) (User, error) {
-	const query = `SELECT id, name, email, password, status, created_at, updated_at FROM users WHERE id = ? LIMIT 1`
+// GetUserByIDParams represents parameters for GetUserByID
+type GetUserByIDParams struct {
+	TenantID string
+	ID       int32
+}
+
+// GetUserByID retrieves a user by ID within a tenant
+func (q *Queries) GetUserByID(ctx context.Context, arg GetUserByIDParams) (User, error) {
+	const query = `SELECT id, tenant_id, name, email, email_index, password, status, attributes, created_at, updated_at FROM users WHERE tenant_id = ? AND id = ? LIMIT 1`
 
 	var user User
 	var statusStr string
-	err := q.db.QueryRowContext(ctx, query, id).Scan(
+	err := q.db.QueryRowContext(ctx, query, arg.TenantID, arg.ID).Scan(
 		&user.ID,
+		&user.TenantID,
 		&user.Name,
 		&user.Email,
+		&user.EmailIndex,
 		&user.Password,
 		&statusStr,
+		&user.Attributes,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
@@ -88,18 +104,28 @@ func (q *Queries) GetUserByID(ctx context.Context, id int32) (User, error) {
 	return user, nil
 }
 
-// GetUserByEmail retrieves a user by email
-func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error) {
-	const query = `SELECT id, name, email, password, status, created_at, updated_at FROM users WHERE email = ? LIMIT 1`
+// GetUserByEmailParams represents parameters for GetUserByEmail
+type GetUserByEmailParams struct {
+	TenantID     string
+	EmailIndexes []string
+}
+
+// GetUserByEmail retrieves a user by the blind indexes of the email within a tenant
+func (q *Queries) GetUserByEmail(ctx context.Context, arg GetUserByEmailParams) (User, error) {
+	indexCond, indexArgs := inCondition("email_index", arg.EmailIndexes)
+	query := `SELECT id, tenant_id, name, email, email_index, password, status, attributes, created_at, updated_at FROM users WHERE tenant_id = ? AND ` + indexCond + ` LIMIT 1`
 
 	var user User
 	var statusStr string
-	err := q.db.QueryRowContext(ctx, query, email).Scan(
+	err := q.db.QueryRowContext(ctx, query, append([]interface{}{arg.TenantID}, indexArgs...)...).Scan(
 		&user.ID,
+		&user.TenantID,
 		&user.Name,
 		&user.Email,
+		&user.EmailIndex,
 		&user.Password,
 		&statusStr,
+		&user.Attributes,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
@@ -112,38 +138,52 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error
 
 // UpdateUserParams represents parameters for UpdateUser
 type UpdateUserParams struct {
-	Name      string
-	Email     string
-	Status    Status
-	UpdatedAt time.Time
-	ID        int32
+	Name       string
+	Email      string
+	EmailIndex string
+	Password   string
+	Status     Status
+	Attributes []byte
+	UpdatedAt  time.Time
+	TenantID   string
+	ID         int32
 }
 
 // UpdateUser updates a user and returns the updated record
 func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error) {
 	const query = `
 		UPDATE users
-		SET name = ?, email = ?, status = ?, updated_at = ?
-		WHERE id = ?
+		SET name = ?, email = ?, email_index = ?, password = COALESCE(NULLIF(?, ''), password), status = ?, attributes = ?, updated_at = ?
+		WHERE tenant_id = ? AND id = ?
 	`
 	_, err := q.db.ExecContext(ctx, query,
 		arg.Name,
 		arg.Email,
+		arg.EmailIndex,
+		arg.Password,
 		string(arg.Status),
+		nullJSON(arg.Attributes),
 		arg.UpdatedAt,
+		arg.TenantID,
 		arg.ID,
 	)
 	if err != nil {
 		return User{}, fmt.Errorf("update user: %w", err)
 	}
 
-	return q.GetUserByID(ctx, arg.ID)
+	return q.GetUserByID(ctx, GetUserByIDParams{TenantID: arg.TenantID, ID: arg.ID})
 }
 
-// DeleteUser deletes a user by ID
-func (q *Queries) DeleteUser(ctx context.Context, id int32) error {
-	const query = `DELETE FROM users WHERE id = ?`
-	_, err := q.db.ExecContext(ctx, query, id)
+// DeleteUserParams represents parameters for DeleteUser
+type DeleteUserParams struct {
+	TenantID string
+	ID       int32
+}
+
+// DeleteUser deletes a user by ID within a tenant
+func (q *Queries) DeleteUser(ctx context.Context, arg DeleteUserParams) error {
+	const query = `DELETE FROM users WHERE tenant_id = ? AND id = ?`
+	_, err := q.db.ExecContext(ctx, query, arg.TenantID, arg.ID)
 	if err != nil {
 		return fmt.Errorf("delete user: %w", err)
 	}
@@ -152,22 +192,30 @@ func (q *Queries) DeleteUser(ctx context.Context, id int32) error {
 
 // ListUsersParams represents parameters for ListUsers
 type ListUsersParams struct {
-	ID       NullInt32
-	Name     NullString
-	Email    NullString
-	Status   NullStatus
-	Limit    int32
-	Offset   int32
+	TenantID     string
+	ID           NullInt32
+	Name         NullString
+	Email        NullString
+	EmailIndexes []string
+	Status       NullStatus
+	Attributes   []AttributeFilter
+	Limit        int32
+	Offset       int32
 }
 
 // ListUsers retrieves a paginated list of users
 func (q *Queries) ListUsers(ctx context.Context, arg ListUsersParams) ([]User, error) {
-	const query = `
-		SELECT id, name, email, password, status, created_at, updated_at FROM users
-		WHERE (? IS NULL OR id = ?)
+	const baseQuery = `
+		SELECT id, tenant_id, name, email, email_index, password, status, attributes, created_at, updated_at FROM users
+		WHERE tenant_id = ?
+		  AND (? IS NULL OR id = ?)
 		  AND (? IS NULL OR name LIKE CONCAT('%', ?, '%'))
 		  AND (? IS NULL OR email LIKE CONCAT('%', ?, '%'))
 		  AND (? IS NULL OR status = ?)
+	`
+	indexCond, indexArgs := emailIndexCondition(arg.EmailIndexes)
+	attrCond, attrArgs := attributeConditions(arg.Attributes)
+	query := baseQuery + indexCond + attrCond + `
 		ORDER BY id DESC
 		LIMIT ? OFFSET ?
 	`
@@ -206,13 +254,18 @@ func (q *Queries) ListUsers(ctx context.Context, arg ListUsersParams) ([]User, e
 		emailCond = nil
 	}
 
-	rows, err := q.db.QueryContext(ctx, query,
+	args := []interface{}{
+		arg.TenantID,
 		idVal, idCond,
 		nameVal, nameCond,
 		emailVal, emailCond,
 		statusVal, statusVal,
-		arg.Limit, arg.Offset,
-	)
+	}
+	args = append(args, indexArgs...)
+	args = append(args, attrArgs...)
+	args = append(args, arg.Limit, arg.Offset)
+
+	rows, err := q.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("list users: %w", err)
 	}
@@ -224,10 +277,13 @@ func (q *Queries) ListUsers(ctx context.Context, arg ListUsersParams) ([]User, e
 		var statusStr string
 		if err := rows.Scan(
 			&user.ID,
+			&user.TenantID,
 			&user.Name,
 			&user.Email,
+			&user.EmailIndex,
 			&user.Password,
 			&statusStr,
+			&user.Attributes,
 			&user.CreatedAt,
 			&user.UpdatedAt,
 		); err != nil {
@@ -246,21 +302,28 @@ func (q *Queries) ListUsers(ctx context.Context, arg ListUsersParams) ([]User, e
 
 // CountUsersParams represents parameters for CountUsers
 type CountUsersParams struct {
-	ID     NullInt32
-	Name   NullString
-	Email  NullString
-	Status NullStatus
+	TenantID     string
+	ID           NullInt32
+	Name         NullString
+	Email        NullString
+	EmailIndexes []string
+	Status       NullStatus
+	Attributes   []AttributeFilter
 }
 
 // CountUsers counts users matching the criteria
 func (q *Queries) CountUsers(ctx context.Context, arg CountUsersParams) (int64, error) {
-	const query = `
+	const baseQuery = `
 		SELECT COUNT(*) as count FROM users
-		WHERE (? IS NULL OR id = ?)
+		WHERE tenant_id = ?
+		  AND (? IS NULL OR id = ?)
 		  AND (? IS NULL OR name LIKE CONCAT('%', ?, '%'))
 		  AND (? IS NULL OR email LIKE CONCAT('%', ?, '%'))
 		  AND (? IS NULL OR status = ?)
 	`
+	indexCond, indexArgs := emailIndexCondition(arg.EmailIndexes)
+	attrCond, attrArgs := attributeConditions(arg.Attributes)
+	query := baseQuery + indexCond + attrCond
 
 	var statusVal interface{}
 	if arg.Status.Valid {
@@ -296,13 +359,18 @@ func (q *Queries) CountUsers(ctx context.Context, arg CountUsersParams) (int64,
 		emailCond = nil
 	}
 
-	var count int64
-	err := q.db.QueryRowContext(ctx, query,
+	args := []interface{}{
+		arg.TenantID,
 		idVal, idCond,
 		nameVal, nameCond,
 		emailVal, emailCond,
 		statusVal, statusVal,
-	).Scan(&count)
+	}
+	args = append(args, indexArgs...)
+	args = append(args, attrArgs...)
+
+	var count int64
+	err := q.db.QueryRowContext(ctx, query, args...).Scan(&count)
 	if err != nil {
 		return 0, fmt.Errorf("count users: %w", err)
 	}
@@ -310,14 +378,146 @@ func (q *Queries) CountUsers(ctx context.Context, arg CountUsersParams) (int64,
 	return count, nil
 }
 
-// ExistsByEmail checks if a user with the given email exists
-func (q *Queries) ExistsByEmail(ctx context.Context, email string) (bool, error) {
-	const query = `SELECT EXISTS(SELECT 1 FROM users WHERE email = ?)`
+// ExistsByEmailParams represents parameters for ExistsByEmail
+type ExistsByEmailParams struct {
+	TenantID     string
+	EmailIndexes []string
+}
+
+// ExistsByEmail checks if a user with one of the given email blind indexes exists within a tenant
+func (q *Queries) ExistsByEmail(ctx context.Context, arg ExistsByEmailParams) (bool, error) {
+	indexCond, indexArgs := inCondition("email_index", arg.EmailIndexes)
+	query := `SELECT EXISTS(SELECT 1 FROM users WHERE tenant_id = ? AND ` + indexCond + `)`
 
 	var exists bool
-	err := q.db.QueryRowContext(ctx, query, email).Scan(&exists)
+	err := q.db.QueryRowContext(ctx, query, append([]interface{}{arg.TenantID}, indexArgs...)...).Scan(&exists)
 	if err != nil {
 		return false, fmt.Errorf("exists by email: %w", err)
 	}
 	return exists, nil
 }
+
+// ScanUsersParams represents parameters for ScanUsers
+type ScanUsersParams struct {
+	AfterID int32
+	Limit   int32
+}
+
+// ScanUsers retrieves users of all tenants in id order (maintenance tasks such as PII rekeying)
+func (q *Queries) ScanUsers(ctx context.Context, arg ScanUsersParams) ([]User, error) {
+	const query = `SELECT id, tenant_id, name, email, email_index, password, status, attributes, created_at, updated_at FROM users WHERE id > ? ORDER BY id LIMIT ?`
+
+	rows, err := q.db.QueryContext(ctx, query, arg.AfterID, arg.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("scan users: %w", err)
+	}
+	defer rows.Close()
+
+	var users []User
+	for rows.Next() {
+		var user User
+		var statusStr string
+		if err := rows.Scan(
+			&user.ID,
+			&user.TenantID,
+			&user.Name,
+			&user.Email,
+			&user.EmailIndex,
+			&user.Password,
+			&statusStr,
+			&user.Attributes,
+			&user.CreatedAt,
+			&user.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan user: %w", err)
+		}
+		user.Status = Status(statusStr)
+		users = append(users, user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+
+	return users, nil
+}
+
+// UpdateUserPIIParams represents parameters for UpdateUserPII
+type UpdateUserPIIParams struct {
+	Name       string
+	Email      string
+	EmailIndex string
+	ID         int32
+	// CurrentName and CurrentEmail are the values read before re-encryption
+	CurrentName  string
+	CurrentEmail string
+}
+
+// UpdateUserPII rewrites the PII columns of a user unless they changed since they were read.
+// It returns the number of updated rows; updated_at is kept.
+func (q *Queries) UpdateUserPII(ctx context.Context, arg UpdateUserPIIParams) (int64, error) {
+	const query = `
+		UPDATE users
+		SET name = ?, email = ?, email_index = ?, updated_at = updated_at
+		WHERE id = ? AND name = ? AND email = ?
+	`
+	result, err := q.db.ExecContext(ctx, query,
+		arg.Name,
+		arg.Email,
+		arg.EmailIndex,
+		arg.ID,
+		arg.CurrentName,
+		arg.CurrentEmail,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("update user pii: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// AttributeFilter matches a JSON attribute by equality
+type AttributeFilter struct {
+	// Path is the JSON path of the attribute, e.g. $."plan"
+	Path string
+	// Value is the JSON-encoded value to compare with
+	Value []byte
+}
+
+// attributeConditions builds the WHERE fragment for attribute filters.
+// Both sides go through JSON_EXTRACT so strings, numbers and booleans compare
+// the same way on MySQL and SQLite.
+func attributeConditions(filters []AttributeFilter) (string, []interface{}) {
+	var cond strings.Builder
+	args := make([]interface{}, 0, len(filters)*2)
+	for _, f := range filters {
+		cond.WriteString("\n\t\t  AND JSON_EXTRACT(attributes, ?) = JSON_EXTRACT(?, '$')")
+		args = append(args, f.Path, string(f.Value))
+	}
+	return cond.String(), args
+}
+
+// emailIndexCondition builds the WHERE fragment matching any of the email blind indexes; none means no filter
+func emailIndexCondition(indexes []string) (string, []interface{}) {
+	if len(indexes) == 0 {
+		return "", nil
+	}
+	cond, args := inCondition("email_index", indexes)
+	return "\n\t\t  AND " + cond, args
+}
+
+// inCondition builds the "column IN (...)" fragment for values; at least one value is required
+func inCondition(column string, values []string) (string, []interface{}) {
+	args := make([]interface{}, len(values))
+	for i, v := range values {
+		args[i] = v
+	}
+	return column + " IN (" + strings.TrimSuffix(strings.Repeat("?, ", len(values)), ", ") + ")", args
+}
+
+// nullJSON stores empty attributes as NULL
+func nullJSON(data []byte) interface{} {
+	if len(data) == 0 {
+		return nil
+	}
+	return string(data)
+}
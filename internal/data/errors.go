@@ -0,0 +1,136 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"net"
+	"strings"
+
+	"example.com/classic/pkg/errors"
+	"github.com/go-sql-driver/mysql"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// ErrorKind classification of a database error
+type ErrorKind int
+
+const (
+	// KindUnknown any other error
+	KindUnknown ErrorKind = iota
+	// KindUniqueViolation duplicate value in a unique key
+	KindUniqueViolation
+	// KindForeignKeyViolation missing referenced row, or a referenced row being deleted
+	KindForeignKeyViolation
+	// KindRetryable deadlocks, serialization failures, lock / statement timeouts and lost connections
+	KindRetryable
+	// KindTimeout the request context's deadline passed; retrying within the same request cannot succeed
+	KindTimeout
+)
+
+// MySQL error numbers
+const (
+	mysqlDuplicateEntry    = 1062
+	mysqlRowIsReferenced   = 1451
+	mysqlNoReferencedRow   = 1452
+	mysqlRowIsReferenced2  = 1217
+	mysqlNoReferencedRow2  = 1216
+	mysqlLockWaitTimeout   = 1205
+	mysqlDeadlock          = 1213
+	mysqlQueryInterrupted  = 1317
+	mysqlMaxExecutionTime  = 3024
+	mysqlServerGone        = 2006
+	mysqlServerLost        = 2013
+	mysqlTooManyConnection = 1040
+)
+
+// ClassifyError inspects MySQL, SQLite and Postgres driver errors (wrapped or not).
+// Postgres drivers (lib/pq, pgx) are recognized through their SQLState method.
+func ClassifyError(err error) ErrorKind {
+	if err == nil {
+		return KindUnknown
+	}
+
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		switch mysqlErr.Number {
+		case mysqlDuplicateEntry:
+			return KindUniqueViolation
+		case mysqlRowIsReferenced, mysqlNoReferencedRow, mysqlRowIsReferenced2, mysqlNoReferencedRow2:
+			return KindForeignKeyViolation
+		case mysqlLockWaitTimeout, mysqlDeadlock, mysqlQueryInterrupted, mysqlMaxExecutionTime,
+			mysqlServerGone, mysqlServerLost, mysqlTooManyConnection:
+			return KindRetryable
+		}
+		return KindUnknown
+	}
+
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		switch sqliteErr.Code() {
+		case sqlite3.SQLITE_CONSTRAINT_UNIQUE, sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY:
+			return KindUniqueViolation
+		case sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY:
+			return KindForeignKeyViolation
+		}
+		// Primary result code in the low byte of extended codes
+		switch sqliteErr.Code() & 0xff {
+		case sqlite3.SQLITE_BUSY, sqlite3.SQLITE_LOCKED:
+			return KindRetryable
+		}
+		return KindUnknown
+	}
+
+	var pgErr interface{ SQLState() string }
+	if errors.As(err, &pgErr) {
+		state := pgErr.SQLState()
+		switch {
+		case state == "23505":
+			return KindUniqueViolation
+		case state == "23503":
+			return KindForeignKeyViolation
+		// serialization_failure, deadlock_detected, lock_not_available, query_canceled (statement timeout),
+		// too_many_connections, connection exceptions (class 08) and operator intervention (class 57P)
+		case state == "40001", state == "40P01", state == "55P03", state == "57014", state == "53300",
+			strings.HasPrefix(state, "08"), strings.HasPrefix(state, "57P"):
+			return KindRetryable
+		}
+		return KindUnknown
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return KindTimeout
+	}
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) || errors.Is(err, mysql.ErrInvalidConn) {
+		return KindRetryable
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return KindRetryable
+	}
+	return KindUnknown
+}
+
+// TranslateError converts a database error into an application error.
+// Constraint violations become conflict / unprocessable errors, transient failures
+// become retryable errors and a passed request deadline becomes a timeout error;
+// anything else is an internal error with message.
+// Repositories check ClassifyError first when a violation has an entity-specific meaning.
+func TranslateError(err error, message string) error {
+	if err == nil {
+		return nil
+	}
+	switch ClassifyError(err) {
+	case KindUniqueViolation:
+		return errors.Wrap(err, errors.ErrCodeConflict, "resource already exists")
+	case KindForeignKeyViolation:
+		return errors.Wrap(err, errors.ErrCodeUnprocessableEntity, "referenced resource does not exist or is still in use")
+	case KindRetryable:
+		return errors.Wrap(err, errors.ErrCodeServiceUnavailable, "database temporarily unavailable, please retry")
+	case KindTimeout:
+		return errors.Wrap(err, errors.ErrCodeRequestTimeout, errors.ErrRequestTimeout.Message)
+	default:
+		return errors.WrapInternalError(err, message)
+	}
+}
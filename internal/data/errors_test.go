@@ -0,0 +1,106 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"example.com/classic/pkg/errors"
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+)
+
+// pgError mimics the errors of lib/pq and pgx
+type pgError struct{ state string }
+
+func (e *pgError) Error() string    { return "pg error " + e.state }
+func (e *pgError) SQLState() string { return e.state }
+
+func TestClassifyError_Drivers(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want ErrorKind
+	}{
+		{"nil", nil, KindUnknown},
+		{"mysql duplicate", &mysql.MySQLError{Number: 1062}, KindUniqueViolation},
+		{"mysql parent missing", &mysql.MySQLError{Number: 1452}, KindForeignKeyViolation},
+		{"mysql row referenced", &mysql.MySQLError{Number: 1451}, KindForeignKeyViolation},
+		{"mysql deadlock", &mysql.MySQLError{Number: 1213}, KindRetryable},
+		{"mysql lock wait timeout", &mysql.MySQLError{Number: 1205}, KindRetryable},
+		{"mysql syntax", &mysql.MySQLError{Number: 1064}, KindUnknown},
+		{"mysql invalid connection", mysql.ErrInvalidConn, KindRetryable},
+		{"postgres unique", &pgError{"23505"}, KindUniqueViolation},
+		{"postgres foreign key", &pgError{"23503"}, KindForeignKeyViolation},
+		{"postgres serialization", &pgError{"40001"}, KindRetryable},
+		{"postgres connection failure", &pgError{"08006"}, KindRetryable},
+		{"postgres syntax", &pgError{"42601"}, KindUnknown},
+		{"deadline", context.DeadlineExceeded, KindTimeout},
+		{"wrapped deadline", fmt.Errorf("query users: %w", context.DeadlineExceeded), KindTimeout},
+		{"bad connection", driver.ErrBadConn, KindRetryable},
+		{"wrapped", fmt.Errorf("create user: %w", &mysql.MySQLError{Number: 1062}), KindUniqueViolation},
+		{"other", fmt.Errorf("boom"), KindUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ClassifyError(tt.err))
+		})
+	}
+}
+
+func TestClassifyError_SQLite(t *testing.T) {
+	sqldb, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "errors.db")+"?_pragma=foreign_keys(1)")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = sqldb.Close() })
+
+	_, err = sqldb.Exec(`
+CREATE TABLE parents (id INTEGER PRIMARY KEY);
+CREATE TABLE children (id INTEGER PRIMARY KEY, parent_id INTEGER NOT NULL REFERENCES parents (id), name TEXT UNIQUE);
+INSERT INTO parents (id) VALUES (1);
+INSERT INTO children (id, parent_id, name) VALUES (1, 1, 'a');
+`)
+	require.NoError(t, err)
+
+	_, err = sqldb.Exec(`INSERT INTO children (id, parent_id, name) VALUES (2, 1, 'a')`)
+	assert.Equal(t, KindUniqueViolation, ClassifyError(err))
+	_, err = sqldb.Exec(`INSERT INTO children (id, parent_id, name) VALUES (1, 1, 'b')`)
+	assert.Equal(t, KindUniqueViolation, ClassifyError(err))
+	_, err = sqldb.Exec(`INSERT INTO children (id, parent_id, name) VALUES (3, 42, 'c')`)
+	assert.Equal(t, KindForeignKeyViolation, ClassifyError(err))
+	_, err = sqldb.Exec(`DELETE FROM parents WHERE id = 1`)
+	assert.Equal(t, KindForeignKeyViolation, ClassifyError(err))
+	_, err = sqldb.Exec(`SELECT * FROM missing`)
+	assert.Equal(t, KindUnknown, ClassifyError(err))
+}
+
+func TestTranslateError(t *testing.T) {
+	code := func(err error) errors.ErrorCode {
+		var bizErr *errors.Error
+		require.True(t, errors.As(err, &bizErr))
+		return bizErr.Code
+	}
+
+	assert.NoError(t, TranslateError(nil, "query failed"))
+	assert.Equal(t, errors.ErrCodeConflict, code(TranslateError(&mysql.MySQLError{Number: 1062}, "query failed")))
+	assert.Equal(t, errors.ErrCodeUnprocessableEntity, code(TranslateError(&pgError{"23503"}, "query failed")))
+	assert.Equal(t, errors.ErrCodeInternalError, code(TranslateError(fmt.Errorf("boom"), "query failed")))
+
+	retryable := TranslateError(&mysql.MySQLError{Number: 1213}, "query failed")
+	assert.Equal(t, errors.ErrCodeServiceUnavailable, code(retryable))
+	assert.True(t, errors.IsRetryable(retryable))
+	assert.False(t, errors.IsRetryable(TranslateError(fmt.Errorf("boom"), "query failed")))
+
+	// A passed request deadline is a timeout, not an outage worth retrying
+	timeout := TranslateError(fmt.Errorf("query users: %w", context.DeadlineExceeded), "query failed")
+	assert.Equal(t, errors.ErrCodeRequestTimeout, code(timeout))
+	assert.False(t, errors.IsRetryable(timeout))
+
+	// The driver error stays in the chain
+	var mysqlErr *mysql.MySQLError
+	assert.True(t, errors.As(retryable, &mysqlErr))
+}
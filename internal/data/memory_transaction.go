@@ -0,0 +1,107 @@
+package data
+
+import (
+	"context"
+	"sync"
+
+	"example.com/classic/internal/domain"
+)
+
+// MemoryTxParticipant is the per-transaction state of an in-memory repository
+type MemoryTxParticipant interface {
+	// Commit publishes the transaction state to the repository
+	Commit()
+	// Rollback discards the transaction state
+	Rollback()
+}
+
+// MemoryTx is the transaction handle of in-memory repositories.
+// Repositories enlist lazily on first use and are committed or rolled back together.
+type MemoryTx struct {
+	mu           sync.Mutex
+	participants map[interface{}]MemoryTxParticipant
+	order        []MemoryTxParticipant
+}
+
+// Enlist returns the participant registered under key, calling begin to create it on first use
+func (tx *MemoryTx) Enlist(key interface{}, begin func() MemoryTxParticipant) MemoryTxParticipant {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+
+	if participant, ok := tx.participants[key]; ok {
+		return participant
+	}
+	participant := begin()
+	tx.participants[key] = participant
+	tx.order = append(tx.order, participant)
+	return participant
+}
+
+func (tx *MemoryTx) finish(commit bool) {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+
+	for _, participant := range tx.order {
+		if commit {
+			participant.Commit()
+		} else {
+			participant.Rollback()
+		}
+	}
+	tx.participants = nil
+	tx.order = nil
+}
+
+// MemoryTransactionManager implements domain.TransactionManager for in-memory repositories
+type MemoryTransactionManager struct{}
+
+// NewMemoryTransactionManager creates a new in-memory transaction manager
+func NewMemoryTransactionManager() *MemoryTransactionManager {
+	return &MemoryTransactionManager{}
+}
+
+// WithTransaction executes a function within a transaction; a nested call joins the outer transaction
+func (tm *MemoryTransactionManager) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := domain.TxFromContext(ctx).(*MemoryTx); ok {
+		return fn(ctx)
+	}
+
+	tx := &MemoryTx{participants: make(map[interface{}]MemoryTxParticipant)}
+	committed := false
+	defer func() {
+		// Release the repositories even if fn panics
+		if !committed {
+			tx.finish(false)
+		}
+	}()
+
+	if err := fn(domain.ContextWithTx(ctx, tx)); err != nil {
+		return err
+	}
+	committed = true
+	tx.finish(true)
+	return nil
+}
+
+// WithTransactionResult executes a function within a transaction and returns a result
+func (tm *MemoryTransactionManager) WithTransactionResult(ctx context.Context, fn func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	var result interface{}
+
+	err := tm.WithTransaction(ctx, func(ctx context.Context) error {
+		r, err := fn(ctx)
+		if err != nil {
+			return err
+		}
+		result = r
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Ensure MemoryTransactionManager implements domain.TransactionManager
+var _ domain.TransactionManager = (*MemoryTransactionManager)(nil)
@@ -0,0 +1,231 @@
+package data
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"example.com/classic/pkg/logger"
+)
+
+// schemaFiles holds schema.sql (the current schema, loaded into empty databases)
+// and migrations/NNNN_name.sql (the steps that bring an existing database up to it).
+// Both are written for MySQL.
+//
+//go:embed schema.sql migrations/*.sql
+var schemaFiles embed.FS
+
+// SchemaFS returns the embedded schema.sql and migrations
+func SchemaFS() fs.FS {
+	return schemaFiles
+}
+
+// Migration a versioned schema change
+type Migration struct {
+	Version int
+	Name    string
+	SQL     string
+}
+
+// LoadMigrations reads migrations/NNNN_name.sql from fsys, ordered by version
+func LoadMigrations(fsys fs.FS) ([]Migration, error) {
+	paths, err := fs.Glob(fsys, "migrations/*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	migrations := make([]Migration, 0, len(paths))
+	for _, p := range paths {
+		name := strings.TrimSuffix(path.Base(p), ".sql")
+		prefix, _, _ := strings.Cut(name, "_")
+		version, err := strconv.Atoi(prefix)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("migration %s: name must start with a positive version", p)
+		}
+		content, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return nil, fmt.Errorf("read migration %s: %w", p, err)
+		}
+		migrations = append(migrations, Migration{Version: version, Name: name, SQL: string(content)})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	for i, m := range migrations {
+		if m.Version != i+1 {
+			return nil, fmt.Errorf("migration %s: expected version %d", m.Name, i+1)
+		}
+	}
+	return migrations, nil
+}
+
+// Migrator brings a database to the latest schema version.
+// Applied versions are recorded in schema_migrations. A database without a users
+// table is created from schema.sql; a users table without recorded versions is
+// the baseline schema (version 0).
+type Migrator struct {
+	db   *sql.DB
+	fsys fs.FS
+	log  logger.Logger
+}
+
+// NewMigrator creates a migrator for the schema and migrations in fsys
+func NewMigrator(db *sql.DB, fsys fs.FS, log logger.Logger) *Migrator {
+	return &Migrator{
+		db:   db,
+		fsys: fsys,
+		log:  log,
+	}
+}
+
+// Version returns the latest applied version and the latest available version
+func (m *Migrator) Version(ctx context.Context) (current, latest int, err error) {
+	migrations, err := LoadMigrations(m.fsys)
+	if err != nil {
+		return 0, 0, err
+	}
+	if err := m.ensureVersionTable(ctx); err != nil {
+		return 0, 0, err
+	}
+	current, err = m.currentVersion(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	return current, len(migrations), nil
+}
+
+// Migrate applies every migration above the recorded version, in order, and returns how many ran.
+// MySQL commits DDL implicitly, so a migration that fails halfway is not rolled back
+// and has to be completed by hand before Migrate is run again.
+func (m *Migrator) Migrate(ctx context.Context) (int, error) {
+	migrations, err := LoadMigrations(m.fsys)
+	if err != nil {
+		return 0, err
+	}
+	if err := m.ensureVersionTable(ctx); err != nil {
+		return 0, err
+	}
+	current, err := m.currentVersion(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	if current == 0 && !m.tableExists(ctx, "users") {
+		if err := m.createSchema(ctx, migrations); err != nil {
+			return 0, err
+		}
+		m.log.Info(ctx, "database schema created", logger.F("version", len(migrations)))
+		return 0, nil
+	}
+
+	applied := 0
+	for _, migration := range migrations {
+		if migration.Version <= current {
+			continue
+		}
+		m.log.Info(ctx, "applying migration", logger.F("migration", migration.Name))
+		for _, stmt := range splitStatements(migration.SQL) {
+			if _, err := m.db.ExecContext(ctx, stmt); err != nil {
+				return applied, fmt.Errorf("migration %s: %w", migration.Name, err)
+			}
+		}
+		if err := m.recordVersion(ctx, migration); err != nil {
+			return applied, err
+		}
+		applied++
+	}
+	return applied, nil
+}
+
+// createSchema loads schema.sql into an empty database and marks every migration as applied
+func (m *Migrator) createSchema(ctx context.Context, migrations []Migration) error {
+	content, err := fs.ReadFile(m.fsys, "schema.sql")
+	if err != nil {
+		return fmt.Errorf("read schema: %w", err)
+	}
+	for _, stmt := range splitStatements(string(content)) {
+		if _, err := m.db.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("create schema: %w", err)
+		}
+	}
+	for _, migration := range migrations {
+		if err := m.recordVersion(ctx, migration); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *Migrator) ensureVersionTable(ctx context.Context) error {
+	_, err := m.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+    version INT PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    applied_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+)`)
+	if err != nil {
+		return fmt.Errorf("create schema_migrations: %w", err)
+	}
+	return nil
+}
+
+func (m *Migrator) currentVersion(ctx context.Context) (int, error) {
+	var version sql.NullInt64
+	if err := m.db.QueryRowContext(ctx, "SELECT MAX(version) FROM schema_migrations").Scan(&version); err != nil {
+		return 0, fmt.Errorf("read schema version: %w", err)
+	}
+	return int(version.Int64), nil
+}
+
+func (m *Migrator) recordVersion(ctx context.Context, migration Migration) error {
+	_, err := m.db.ExecContext(ctx,
+		"INSERT INTO schema_migrations (version, name) VALUES (?, ?)", migration.Version, migration.Name)
+	if err != nil {
+		return fmt.Errorf("record migration %s: %w", migration.Name, err)
+	}
+	return nil
+}
+
+func (m *Migrator) tableExists(ctx context.Context, table string) bool {
+	rows, err := m.db.QueryContext(ctx, "SELECT 1 FROM "+table+" WHERE 1 = 0")
+	if err != nil {
+		return false
+	}
+	_ = rows.Close()
+	return true
+}
+
+// splitStatements splits a SQL file into statements ending with ";" at the end of a line.
+// Comment-only chunks are dropped.
+func splitStatements(content string) []string {
+	var stmts []string
+	var current strings.Builder
+	hasCode := false
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		trimmed := strings.TrimSpace(line)
+		if trimmed != "" && !strings.HasPrefix(trimmed, "--") {
+			hasCode = true
+		}
+		current.WriteString(line)
+		current.WriteString("\n")
+		if strings.HasSuffix(trimmed, ";") && !strings.HasPrefix(trimmed, "--") {
+			if hasCode {
+				stmts = append(stmts, strings.TrimSpace(current.String()))
+			}
+			current.Reset()
+			hasCode = false
+		}
+	}
+	if hasCode {
+		stmts = append(stmts, strings.TrimSpace(current.String()))
+	}
+	return stmts
+}
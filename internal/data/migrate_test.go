@@ -0,0 +1,148 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"example.com/classic/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+)
+
+// sqliteSchemaFS the same layout as the embedded files, in SQLite dialect
+var sqliteSchemaFS = fstest.MapFS{
+	"schema.sql": {Data: []byte(`
+-- current schema
+CREATE TABLE tenants (id TEXT PRIMARY KEY);
+INSERT INTO tenants (id) VALUES ('default');
+CREATE TABLE users (
+    id INTEGER PRIMARY KEY,
+    tenant_id TEXT NOT NULL DEFAULT 'default' REFERENCES tenants (id),
+    email TEXT NOT NULL
+);
+`)},
+	"migrations/0001_tenants.sql": {Data: []byte(`
+-- the default tenant exists before users reference it
+CREATE TABLE tenants (id TEXT PRIMARY KEY);
+INSERT INTO tenants (id) VALUES ('default');
+ALTER TABLE users ADD COLUMN tenant_id TEXT NULL;
+UPDATE users SET tenant_id = 'default' WHERE tenant_id IS NULL;
+`)},
+	"migrations/0002_tenant_email.sql": {Data: []byte(`
+CREATE UNIQUE INDEX uk_tenant_email ON users (tenant_id, email);
+`)},
+}
+
+func openMigrationDB(t *testing.T) *sql.DB {
+	t.Helper()
+	sqldb, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "migrate.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = sqldb.Close() })
+	return sqldb
+}
+
+func TestLoadMigrations_Embedded(t *testing.T) {
+	migrations, err := LoadMigrations(SchemaFS())
+	require.NoError(t, err)
+	require.NotEmpty(t, migrations)
+	for i, m := range migrations {
+		assert.Equal(t, i+1, m.Version, m.Name)
+		assert.NotEmpty(t, splitStatements(m.SQL), m.Name)
+	}
+}
+
+func TestLoadMigrations_RejectsGaps(t *testing.T) {
+	fsys := fstest.MapFS{
+		"migrations/0001_a.sql": {Data: []byte("SELECT 1;")},
+		"migrations/0003_c.sql": {Data: []byte("SELECT 1;")},
+	}
+	_, err := LoadMigrations(fsys)
+	assert.Error(t, err)
+
+	fsys = fstest.MapFS{"migrations/first.sql": {Data: []byte("SELECT 1;")}}
+	_, err = LoadMigrations(fsys)
+	assert.Error(t, err)
+}
+
+func TestMigrator_UpgradesBaseline(t *testing.T) {
+	ctx := context.Background()
+	sqldb := openMigrationDB(t)
+	_, err := sqldb.Exec(`CREATE TABLE users (id INTEGER PRIMARY KEY, email TEXT NOT NULL);
+INSERT INTO users (email) VALUES ('a@example.com'), ('b@example.com');`)
+	require.NoError(t, err)
+
+	m := NewMigrator(sqldb, sqliteSchemaFS, logger.New("test", "error", false))
+	applied, err := m.Migrate(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, applied)
+
+	current, latest, err := m.Version(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, current)
+	assert.Equal(t, 2, latest)
+
+	var missing int
+	require.NoError(t, sqldb.QueryRow("SELECT COUNT(*) FROM users WHERE tenant_id IS NULL").Scan(&missing))
+	assert.Zero(t, missing)
+
+	applied, err = m.Migrate(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, applied)
+}
+
+func TestMigrator_CreatesEmptyDatabase(t *testing.T) {
+	ctx := context.Background()
+	sqldb := openMigrationDB(t)
+
+	m := NewMigrator(sqldb, sqliteSchemaFS, logger.New("test", "error", false))
+	applied, err := m.Migrate(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, applied)
+
+	current, _, err := m.Version(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, current)
+
+	_, err = sqldb.Exec("INSERT INTO users (email) VALUES ('a@example.com')")
+	require.NoError(t, err)
+}
+
+func TestMigrator_StopsAtFailedMigration(t *testing.T) {
+	ctx := context.Background()
+	sqldb := openMigrationDB(t)
+	_, err := sqldb.Exec(`CREATE TABLE users (id INTEGER PRIMARY KEY, email TEXT NOT NULL)`)
+	require.NoError(t, err)
+
+	fsys := fstest.MapFS{
+		"schema.sql":                 sqliteSchemaFS["schema.sql"],
+		"migrations/0001_ok.sql":     {Data: []byte("ALTER TABLE users ADD COLUMN name TEXT NULL;")},
+		"migrations/0002_broken.sql": {Data: []byte("ALTER TABLE missing ADD COLUMN name TEXT NULL;")},
+	}
+	m := NewMigrator(sqldb, fsys, logger.New("test", "error", false))
+	applied, err := m.Migrate(ctx)
+	require.Error(t, err)
+	assert.Equal(t, 1, applied)
+
+	current, _, err := m.Version(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, current)
+}
+
+func TestSplitStatements(t *testing.T) {
+	stmts := splitStatements(`-- header only;
+CREATE TABLE a (
+    id INT -- trailing comment
+);
+
+-- comment
+INSERT INTO a VALUES (1);
+UPDATE a SET id = 2`)
+	require.Len(t, stmts, 3)
+	assert.Contains(t, stmts[0], "CREATE TABLE a")
+	assert.Equal(t, "-- comment\nINSERT INTO a VALUES (1);", stmts[1])
+	assert.Equal(t, "UPDATE a SET id = 2", stmts[2])
+}
@@ -0,0 +1,3 @@
+-- Custom user attributes (stored as a JSON object)
+ALTER TABLE users
+    ADD COLUMN attributes JSON NULL AFTER status;
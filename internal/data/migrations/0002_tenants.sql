@@ -0,0 +1,26 @@
+-- Multi-tenancy: existing users move to the default tenant.
+-- The default tenant is created and tenant_id is backfilled before the
+-- NOT NULL, unique and foreign-key constraints are added.
+CREATE TABLE IF NOT EXISTS tenants (
+    id VARCHAR(64) PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+);
+
+INSERT IGNORE INTO tenants (id, name, status) VALUES ('default', 'Default', 'active');
+
+ALTER TABLE users
+    ADD COLUMN tenant_id VARCHAR(64) NULL AFTER id;
+
+UPDATE users SET tenant_id = 'default' WHERE tenant_id IS NULL;
+
+ALTER TABLE users
+    MODIFY tenant_id VARCHAR(64) NOT NULL DEFAULT 'default',
+    DROP INDEX email,
+    DROP INDEX idx_email,
+    DROP INDEX idx_status,
+    ADD UNIQUE KEY uk_tenant_email (tenant_id, email),
+    ADD INDEX idx_tenant_status (tenant_id, status),
+    ADD CONSTRAINT fk_users_tenant FOREIGN KEY (tenant_id) REFERENCES tenants (id);
@@ -0,0 +1,12 @@
+-- Domain event store; users created before this migration have an empty history
+CREATE TABLE IF NOT EXISTS events (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    tenant_id VARCHAR(64) NOT NULL,
+    aggregate_id VARCHAR(64) NOT NULL,
+    sequence_no INT NOT NULL,
+    type VARCHAR(100) NOT NULL,
+    payload JSON NOT NULL,
+    metadata JSON NULL,
+    occurred_at DATETIME(6) NOT NULL,
+    UNIQUE KEY uk_aggregate_sequence (tenant_id, aggregate_id, sequence_no)
+);
@@ -0,0 +1,19 @@
+-- PII encryption at rest: name/email widen to hold ciphertext, and lookups and
+-- uniqueness move to the email_index blind index.
+--
+-- Order: existing rows are still plaintext, so email_index is backfilled with the
+-- unkeyed index (SHA-256 of the trimmed, lower-cased email, as written without a
+-- keyring) before it becomes NOT NULL and unique. With a keyring, run
+-- go run ./cmd/rekey after this migration: it encrypts these rows and replaces
+-- their index with the keyed one. Until then lookups also try the unkeyed index.
+ALTER TABLE users
+    DROP INDEX uk_tenant_email,
+    MODIFY name VARCHAR(512) NOT NULL,
+    MODIFY email VARCHAR(512) NOT NULL,
+    ADD COLUMN email_index CHAR(64) NULL AFTER email;
+
+UPDATE users SET email_index = SHA2(LOWER(TRIM(email)), 256) WHERE email_index IS NULL;
+
+ALTER TABLE users
+    MODIFY email_index CHAR(64) NOT NULL,
+    ADD UNIQUE KEY uk_tenant_email_index (tenant_id, email_index);
@@ -0,0 +1,27 @@
+-- name: CreateEvent :exec
+INSERT INTO events (tenant_id, aggregate_id, sequence_no, type, payload, metadata, occurred_at)
+VALUES (?, ?, ?, ?, ?, ?, ?);
+
+-- name: GetLastEventSequence :one
+SELECT COALESCE(MAX(sequence_no), 0) FROM events
+WHERE tenant_id = ? AND aggregate_id = ?;
+
+-- name: ListAggregateEvents :many
+SELECT * FROM events
+WHERE tenant_id = ? AND aggregate_id = ? AND sequence_no > ?
+ORDER BY sequence_no
+LIMIT ?;
+
+-- name: ListEvents :many
+-- Events of all tenants in global order, for projection replay
+SELECT * FROM events
+WHERE id > ?
+ORDER BY id
+LIMIT ?;
+
+-- name: UpdateEventPayload :exec
+UPDATE events SET payload = ? WHERE id = ?;
+
+-- name: UpdateEventPII :execrows
+-- Rekey: only rewrites the payload when it did not change since it was read
+UPDATE events SET payload = ? WHERE id = ? AND CAST(payload AS CHAR) = ?;
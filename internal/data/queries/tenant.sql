@@ -0,0 +1,22 @@
+-- name: CreateTenant :exec
+INSERT INTO tenants (id, name, status, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?);
+
+-- name: GetTenantByID :one
+SELECT * FROM tenants WHERE id = ? LIMIT 1;
+
+-- name: UpdateTenant :exec
+UPDATE tenants
+SET name = ?, status = ?, updated_at = ?
+WHERE id = ?;
+
+-- name: DeleteTenant :exec
+DELETE FROM tenants WHERE id = ?;
+
+-- name: ListTenants :many
+SELECT * FROM tenants
+ORDER BY created_at, id
+LIMIT ? OFFSET ?;
+
+-- name: CountTenants :one
+SELECT COUNT(*) as count FROM tenants;
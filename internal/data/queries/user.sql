@@ -1,26 +1,38 @@
+-- Every user query is scoped by tenant_id; the repository takes it from the request context.
+-- name and email may hold PII ciphertext; email lookups and uniqueness go through email_index (blind index).
+
 -- name: CreateUser :one
-INSERT INTO users (name, email, password, status, created_at, updated_at)
-VALUES (?, ?, ?, ?, ?, ?)
+INSERT INTO users (tenant_id, name, email, email_index, password, status, attributes, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
 RETURNING *;
 
 -- name: GetUserByID :one
-SELECT * FROM users WHERE id = ? LIMIT 1;
+SELECT * FROM users WHERE tenant_id = ? AND id = ? LIMIT 1;
 
 -- name: GetUserByEmail :one
-SELECT * FROM users WHERE email = ? LIMIT 1;
+SELECT * FROM users WHERE tenant_id = ? AND email_index IN (sqlc.slice('email_indexes')) LIMIT 1;
 
+-- An empty password keeps the stored hash (entities loaded without credentials)
 -- name: UpdateUser :one
 UPDATE users
-SET name = ?, email = ?, status = ?, updated_at = ?
-WHERE id = ?
+SET name = ?, email = ?, email_index = ?, password = COALESCE(NULLIF(?, ''), password), status = ?, attributes = ?, updated_at = ?
+WHERE tenant_id = ? AND id = ?
 RETURNING *;
 
 -- name: DeleteUser :exec
-DELETE FROM users WHERE id = ?;
+DELETE FROM users WHERE tenant_id = ? AND id = ?;
+
+-- name / email LIKE filters only work on plaintext columns; with PII encryption the repository matches a full
+-- email address through email_index and bounded name / email substrings after decryption.
+-- An email blind index filter (one or more index forms) and attribute filters are appended at runtime as
+--   AND email_index IN (?, ...)
+--   AND JSON_EXTRACT(attributes, ?) = JSON_EXTRACT(?, '$')
+-- (one per filter: JSON path, JSON-encoded value) before ORDER BY.
 
 -- name: ListUsers :many
 SELECT * FROM users
-WHERE (? IS NULL OR id = ?)
+WHERE tenant_id = ?
+  AND (? IS NULL OR id = ?)
   AND (? IS NULL OR name LIKE CONCAT('%', ?, '%'))
   AND (? IS NULL OR email LIKE CONCAT('%', ?, '%'))
   AND (? IS NULL OR status = ?)
@@ -29,10 +41,22 @@ LIMIT ? OFFSET ?;
 
 -- name: CountUsers :one
 SELECT COUNT(*) as count FROM users
-WHERE (? IS NULL OR id = ?)
+WHERE tenant_id = ?
+  AND (? IS NULL OR id = ?)
   AND (? IS NULL OR name LIKE CONCAT('%', ?, '%'))
   AND (? IS NULL OR email LIKE CONCAT('%', ?, '%'))
   AND (? IS NULL OR status = ?);
 
 -- name: ExistsByEmail :one
-SELECT EXISTS(SELECT 1 FROM users WHERE email = ?) as exists;
+SELECT EXISTS(SELECT 1 FROM users WHERE tenant_id = ? AND email_index IN (sqlc.slice('email_indexes'))) as exists;
+
+-- Maintenance queries span all tenants.
+
+-- name: ScanUsers :many
+SELECT * FROM users WHERE id > ? ORDER BY id LIMIT ?;
+
+-- Only rewrites rows whose PII is unchanged since it was read; keeps updated_at.
+-- name: UpdateUserPII :execrows
+UPDATE users
+SET name = ?, email = ?, email_index = ?, updated_at = updated_at
+WHERE id = ? AND name = ? AND email = ?;
@@ -1,12 +1,42 @@
+-- Schema for tenant management
+CREATE TABLE tenants (
+    id VARCHAR(64) PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+);
+
+INSERT INTO tenants (id, name, status) VALUES ('default', 'Default', 'active');
+
 -- Schema for user management
+-- name and email hold AES-GCM ciphertext when PII encryption is enabled;
+-- email_index is the blind index (HMAC-SHA256) used for lookups and uniqueness.
 CREATE TABLE users (
     id INT AUTO_INCREMENT PRIMARY KEY,
-    name VARCHAR(50) NOT NULL,
-    email VARCHAR(100) NOT NULL UNIQUE,
+    tenant_id VARCHAR(64) NOT NULL DEFAULT 'default',
+    name VARCHAR(512) NOT NULL,
+    email VARCHAR(512) NOT NULL,
+    email_index CHAR(64) NOT NULL,
     password VARCHAR(255) NOT NULL,
     status VARCHAR(20) NOT NULL DEFAULT 'inactive',
+    attributes JSON NULL,
     created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
     updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
-    INDEX idx_email (email),
-    INDEX idx_status (status)
+    UNIQUE KEY uk_tenant_email_index (tenant_id, email_index),
+    INDEX idx_tenant_status (tenant_id, status),
+    CONSTRAINT fk_users_tenant FOREIGN KEY (tenant_id) REFERENCES tenants (id)
+);
+
+-- Schema for the domain event store (append-only; payloads are only rewritten by GDPR erasure)
+CREATE TABLE events (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    tenant_id VARCHAR(64) NOT NULL,
+    aggregate_id VARCHAR(64) NOT NULL,
+    sequence_no INT NOT NULL,
+    type VARCHAR(100) NOT NULL,
+    payload JSON NOT NULL,
+    metadata JSON NULL,
+    occurred_at DATETIME(6) NOT NULL,
+    UNIQUE KEY uk_aggregate_sequence (tenant_id, aggregate_id, sequence_no)
 );
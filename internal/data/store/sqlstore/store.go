@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"example.com/classic/internal/config"
+	"example.com/classic/internal/data"
 	"example.com/classic/pkg/logger"
 	_ "github.com/go-sql-driver/mysql"
 	_ "modernc.org/sqlite"
@@ -82,7 +83,7 @@ func New(ctx context.Context, cfg *config.Config, log logger.Logger) (*Store, er
 		log:    log,
 	}
 
-	// Auto migrate (using schema.sql)
+	// Auto migrate (schema.sql and internal/data/migrations)
 	if cfg.DB.AutoMigrate {
 		if err := store.AutoMigrate(ctx); err != nil {
 			log.Error(ctx, "failed to auto migrate database", logger.F("error", err))
@@ -94,12 +95,21 @@ func New(ctx context.Context, cfg *config.Config, log logger.Logger) (*Store, er
 	return store, nil
 }
 
-// AutoMigrate runs schema migration
+// AutoMigrate creates an empty database from schema.sql or applies the pending
+// versioned migrations (internal/data/migrations). Both are written for MySQL;
+// other drivers keep managing their schema by hand.
 func (s *Store) AutoMigrate(ctx context.Context) error {
+	if s.config.DB.Driver != "mysql" {
+		s.log.Warn(ctx, "auto migrate only supports mysql; skipping", logger.F("driver", s.config.DB.Driver))
+		return nil
+	}
+
 	s.log.Info(ctx, "starting database migration")
-	// For sqlc, migrations should be handled separately (e.g., golang-migrate)
-	// This is a placeholder for manual migration execution
-	s.log.Info(ctx, "database migration completed successfully")
+	applied, err := data.NewMigrator(s.DB, data.SchemaFS(), s.log).Migrate(ctx)
+	if err != nil {
+		return err
+	}
+	s.log.Info(ctx, "database migration completed successfully", logger.F("applied", applied))
 	return nil
 }
 
@@ -3,7 +3,6 @@ package data
 import (
 	"context"
 	"database/sql"
-	"fmt"
 
 	"example.com/classic/internal/domain"
 	"example.com/classic/pkg/logger"
@@ -27,7 +26,7 @@ func NewTransactionManager(db *sql.DB, log logger.Logger) *TransactionManager {
 func (tm *TransactionManager) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
 	tx, err := tm.db.BeginTx(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("begin transaction: %w", err)
+		return TranslateError(err, "begin transaction failed")
 	}
 
 	// Create transaction context
@@ -44,8 +43,9 @@ func (tm *TransactionManager) WithTransaction(ctx context.Context, fn func(ctx c
 	}
 
 	// Commit transaction
+	// Deferred constraints and lost connections surface at commit
 	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("commit transaction: %w", err)
+		return TranslateError(err, "commit transaction failed")
 	}
 
 	return nil
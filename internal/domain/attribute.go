@@ -0,0 +1,264 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// AttributeType 自定义属性类型
+type AttributeType string
+
+const (
+	AttributeTypeString  AttributeType = "string"
+	AttributeTypeInteger AttributeType = "integer"
+	AttributeTypeNumber  AttributeType = "number"
+	AttributeTypeBoolean AttributeType = "boolean"
+)
+
+// IsValid 验证属性类型是否有效
+func (t AttributeType) IsValid() bool {
+	switch t {
+	case AttributeTypeString, AttributeTypeInteger, AttributeTypeNumber, AttributeTypeBoolean:
+		return true
+	default:
+		return false
+	}
+}
+
+// attributeNameRegex 属性名规则（同时保证可安全拼入 JSON 路径）
+var attributeNameRegex = regexp.MustCompile(`^[a-z][a-z0-9_]{0,62}$`)
+
+// AttributeDefinition 自定义属性定义
+type AttributeDefinition struct {
+	Name     string
+	Type     AttributeType
+	Required bool
+	// Pattern 字符串值需匹配的正则（仅 string 类型）
+	Pattern string
+	// Enum 允许的取值（按字符串形式比较）
+	Enum []string
+
+	pattern *regexp.Regexp
+}
+
+// Attributes 用户自定义属性值（已按 schema 规范化：string / int64 / float64 / bool）
+type Attributes map[string]interface{}
+
+// Clone 复制属性
+func (a Attributes) Clone() Attributes {
+	if a == nil {
+		return nil
+	}
+	clone := make(Attributes, len(a))
+	for k, v := range a {
+		clone[k] = v
+	}
+	return clone
+}
+
+// AttributeFilter 按自定义属性等值过滤
+type AttributeFilter struct {
+	Name  string
+	Value interface{}
+}
+
+// AttributeSchema 自定义属性 schema（领域规则）
+type AttributeSchema struct {
+	definitions map[string]*AttributeDefinition
+}
+
+// NewAttributeSchema 创建属性 schema，校验定义并编译正则
+func NewAttributeSchema(definitions []AttributeDefinition) (*AttributeSchema, error) {
+	schema := &AttributeSchema{definitions: make(map[string]*AttributeDefinition, len(definitions))}
+	for i := range definitions {
+		def := definitions[i]
+		if !attributeNameRegex.MatchString(def.Name) {
+			return nil, fmt.Errorf("invalid attribute name: %q", def.Name)
+		}
+		if _, exists := schema.definitions[def.Name]; exists {
+			return nil, fmt.Errorf("duplicate attribute: %s", def.Name)
+		}
+		if !def.Type.IsValid() {
+			return nil, fmt.Errorf("attribute %s: invalid type %q", def.Name, def.Type)
+		}
+		if def.Pattern != "" {
+			if def.Type != AttributeTypeString {
+				return nil, fmt.Errorf("attribute %s: pattern is only allowed for string attributes", def.Name)
+			}
+			pattern, err := regexp.Compile(def.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("attribute %s: invalid pattern: %w", def.Name, err)
+			}
+			def.pattern = pattern
+		}
+		schema.definitions[def.Name] = &def
+	}
+	return schema, nil
+}
+
+// Definitions 返回按名称排序的属性定义
+func (s *AttributeSchema) Definitions() []AttributeDefinition {
+	if s == nil {
+		return nil
+	}
+	defs := make([]AttributeDefinition, 0, len(s.definitions))
+	for _, def := range s.definitions {
+		defs = append(defs, *def)
+	}
+	sort.Slice(defs, func(i, j int) bool { return defs[i].Name < defs[j].Name })
+	return defs
+}
+
+// Validate 校验并规范化属性值；未定义的属性、缺失的必填属性都会报错
+// nil schema 不允许任何属性
+func (s *AttributeSchema) Validate(attrs Attributes) (Attributes, error) {
+	normalized := make(Attributes, len(attrs))
+	for name, raw := range attrs {
+		// nil 值表示删除该属性（也允许删除已从 schema 中移除的属性）
+		if raw == nil {
+			continue
+		}
+		def, err := s.definition(name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := def.normalize(raw)
+		if err != nil {
+			return nil, err
+		}
+		normalized[name] = value
+	}
+
+	if s != nil {
+		for name, def := range s.definitions {
+			if _, ok := normalized[name]; def.Required && !ok {
+				return nil, fmt.Errorf("attribute %s is required", name)
+			}
+		}
+	}
+	return normalized, nil
+}
+
+// Normalize 将存储中读出的属性值转换为规范类型 (如 number 属性的 3 读出为 float64)
+// 未定义或不再合法的值 (schema 变更前写入的) 保持原样，不影响读取；nil schema 原样返回
+func (s *AttributeSchema) Normalize(attrs Attributes) Attributes {
+	if s == nil {
+		return attrs
+	}
+	for name, raw := range attrs {
+		def, ok := s.definitions[name]
+		if !ok {
+			continue
+		}
+		if value, err := def.normalize(raw); err == nil {
+			attrs[name] = value
+		}
+	}
+	return attrs
+}
+
+// ParseFilter 将查询字符串中的属性过滤值转换为带类型的过滤条件
+func (s *AttributeSchema) ParseFilter(name, raw string) (*AttributeFilter, error) {
+	def, err := s.definition(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var value interface{}
+	switch def.Type {
+	case AttributeTypeString:
+		value = raw
+	case AttributeTypeInteger:
+		if value, err = strconv.ParseInt(raw, 10, 64); err != nil {
+			return nil, fmt.Errorf("attribute %s must be an integer", name)
+		}
+	case AttributeTypeNumber:
+		if value, err = strconv.ParseFloat(raw, 64); err != nil {
+			return nil, fmt.Errorf("attribute %s must be a number", name)
+		}
+	case AttributeTypeBoolean:
+		if value, err = strconv.ParseBool(raw); err != nil {
+			return nil, fmt.Errorf("attribute %s must be a boolean", name)
+		}
+	}
+	return &AttributeFilter{Name: name, Value: value}, nil
+}
+
+func (s *AttributeSchema) definition(name string) (*AttributeDefinition, error) {
+	if s != nil {
+		if def, ok := s.definitions[name]; ok {
+			return def, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown attribute: %s", name)
+}
+
+// normalize 校验单个属性值并转换为规范类型
+func (d *AttributeDefinition) normalize(raw interface{}) (interface{}, error) {
+	var value interface{}
+	switch d.Type {
+	case AttributeTypeString:
+		str, ok := raw.(string)
+		if !ok {
+			return nil, fmt.Errorf("attribute %s must be a string", d.Name)
+		}
+		if d.pattern != nil && !d.pattern.MatchString(str) {
+			return nil, fmt.Errorf("attribute %s does not match pattern %s", d.Name, d.Pattern)
+		}
+		value = str
+	case AttributeTypeInteger:
+		n, ok := toFloat(raw)
+		if !ok || n != math.Trunc(n) || math.Abs(n) > 1<<53 {
+			return nil, fmt.Errorf("attribute %s must be an integer", d.Name)
+		}
+		value = int64(n)
+	case AttributeTypeNumber:
+		n, ok := toFloat(raw)
+		if !ok {
+			return nil, fmt.Errorf("attribute %s must be a number", d.Name)
+		}
+		value = n
+	case AttributeTypeBoolean:
+		b, ok := raw.(bool)
+		if !ok {
+			return nil, fmt.Errorf("attribute %s must be a boolean", d.Name)
+		}
+		value = b
+	}
+
+	if len(d.Enum) > 0 {
+		str := fmt.Sprint(value)
+		for _, allowed := range d.Enum {
+			if str == allowed {
+				return value, nil
+			}
+		}
+		return nil, fmt.Errorf("attribute %s must be one of %v", d.Name, d.Enum)
+	}
+	return value, nil
+}
+
+// toFloat 接受 JSON 解码、protobuf Struct 以及 Go 原生的数值类型
+func toFloat(raw interface{}) (float64, bool) {
+	switch n := raw.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
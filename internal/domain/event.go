@@ -10,6 +10,15 @@ func AggregateID(aggregateType string, id int) string {
 	return aggregateType + "-" + strconv.Itoa(id)
 }
 
+// 用户领域事件类型
+const (
+	EventTypeUserCreated       = "user.created"
+	EventTypeUserUpdated       = "user.updated"
+	EventTypeUserStatusChanged = "user.status_changed"
+	EventTypeUserDeleted       = "user.deleted"
+	EventTypeUserErased        = "user.erased"
+)
+
 // DomainEvent 领域事件接口
 type DomainEvent interface {
 	EventType() string
@@ -17,6 +26,26 @@ type DomainEvent interface {
 	AggregateID() string
 }
 
+// TenantEvent 可按租户分发的领域事件
+type TenantEvent interface {
+	DomainEvent
+	Tenant() string
+	SetTenant(tenantID string)
+}
+
+// eventTenant 发布事件的租户，由应用服务在发布前按请求上下文设置；不属于事件载荷，不写入事件存储
+type eventTenant struct {
+	tenantID string
+}
+
+func (t *eventTenant) Tenant() string {
+	return t.tenantID
+}
+
+func (t *eventTenant) SetTenant(tenantID string) {
+	t.tenantID = tenantID
+}
+
 // EventRecorder 事件记录器，用于在聚合根中收集事件
 type EventRecorder struct {
 	events []DomainEvent
@@ -51,10 +80,12 @@ func (r *EventRecorder) HasEvents() bool {
 
 // UserCreatedEvent 用户创建事件
 type UserCreatedEvent struct {
-	UserID    int
-	Email     string
-	Name      string
+	UserID     int    `json:"user_id"`
+	Email      string `json:"email"`
+	Name       string `json:"name"`
+	Locale     string `json:"locale,omitempty"` // 通知邮件使用的语言，由应用服务按请求语言设置
 	occurredAt time.Time
+	eventTenant
 }
 
 func NewUserCreatedEvent(userID int, email, name string) *UserCreatedEvent {
@@ -67,7 +98,7 @@ func NewUserCreatedEvent(userID int, email, name string) *UserCreatedEvent {
 }
 
 func (e *UserCreatedEvent) EventType() string {
-	return "user.created"
+	return EventTypeUserCreated
 }
 
 func (e *UserCreatedEvent) OccurredAt() time.Time {
@@ -80,10 +111,11 @@ func (e *UserCreatedEvent) AggregateID() string {
 
 // UserUpdatedEvent 用户更新事件
 type UserUpdatedEvent struct {
-	UserID    int
-	Email     string
-	Name      string
+	UserID     int    `json:"user_id"`
+	Email      string `json:"email"`
+	Name       string `json:"name"`
 	occurredAt time.Time
+	eventTenant
 }
 
 func NewUserUpdatedEvent(userID int, email, name string) *UserUpdatedEvent {
@@ -96,7 +128,7 @@ func NewUserUpdatedEvent(userID int, email, name string) *UserUpdatedEvent {
 }
 
 func (e *UserUpdatedEvent) EventType() string {
-	return "user.updated"
+	return EventTypeUserUpdated
 }
 
 func (e *UserUpdatedEvent) OccurredAt() time.Time {
@@ -109,12 +141,14 @@ func (e *UserUpdatedEvent) AggregateID() string {
 
 // UserStatusChangedEvent 用户状态变更事件
 type UserStatusChangedEvent struct {
-	UserID     int
-	Email      string
-	Name       string
-	OldStatus  Status
-	NewStatus  Status
+	UserID     int    `json:"user_id"`
+	Email      string `json:"email"`
+	Name       string `json:"name"`
+	OldStatus  Status `json:"old_status"`
+	NewStatus  Status `json:"new_status"`
+	Locale     string `json:"locale,omitempty"` // 通知邮件使用的语言，由应用服务按请求语言设置
 	occurredAt time.Time
+	eventTenant
 }
 
 func NewUserStatusChangedEvent(userID int, email, name string, oldStatus, newStatus Status) *UserStatusChangedEvent {
@@ -129,7 +163,7 @@ func NewUserStatusChangedEvent(userID int, email, name string, oldStatus, newSta
 }
 
 func (e *UserStatusChangedEvent) EventType() string {
-	return "user.status_changed"
+	return EventTypeUserStatusChanged
 }
 
 func (e *UserStatusChangedEvent) OccurredAt() time.Time {
@@ -142,10 +176,11 @@ func (e *UserStatusChangedEvent) AggregateID() string {
 
 // UserDeletedEvent 用户删除事件
 type UserDeletedEvent struct {
-	UserID     int
-	Email      string
-	Name       string
+	UserID     int    `json:"user_id"`
+	Email      string `json:"email"`
+	Name       string `json:"name"`
 	occurredAt time.Time
+	eventTenant
 }
 
 func NewUserDeletedEvent(userID int, email, name string) *UserDeletedEvent {
@@ -158,7 +193,7 @@ func NewUserDeletedEvent(userID int, email, name string) *UserDeletedEvent {
 }
 
 func (e *UserDeletedEvent) EventType() string {
-	return "user.deleted"
+	return EventTypeUserDeleted
 }
 
 func (e *UserDeletedEvent) OccurredAt() time.Time {
@@ -167,4 +202,32 @@ func (e *UserDeletedEvent) OccurredAt() time.Time {
 
 func (e *UserDeletedEvent) AggregateID() string {
 	return AggregateID("user", e.UserID)
-}
\ No newline at end of file
+}
+
+// UserErasedEvent 用户个人数据擦除事件（不含个人数据）
+type UserErasedEvent struct {
+	UserID     int    `json:"user_id"`
+	TenantID   string `json:"tenant_id"`
+	occurredAt time.Time
+	eventTenant
+}
+
+func NewUserErasedEvent(userID int, tenantID string) *UserErasedEvent {
+	return &UserErasedEvent{
+		UserID:     userID,
+		TenantID:   tenantID,
+		occurredAt: time.Now(),
+	}
+}
+
+func (e *UserErasedEvent) EventType() string {
+	return EventTypeUserErased
+}
+
+func (e *UserErasedEvent) OccurredAt() time.Time {
+	return e.occurredAt
+}
+
+func (e *UserErasedEvent) AggregateID() string {
+	return AggregateID("user", e.UserID)
+}
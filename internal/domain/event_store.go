@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// EventMetadata 事件元数据（来自请求上下文）
+type EventMetadata struct {
+	TraceID   string `json:"trace_id,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
+	Actor     string `json:"actor,omitempty"`
+}
+
+// StoredEvent 已持久化的领域事件
+type StoredEvent struct {
+	// Position 全局顺序，重放的游标
+	Position    int64
+	TenantID    string
+	AggregateID string
+	// Sequence 聚合内的顺序，从 1 开始连续递增
+	Sequence   int
+	Type       string
+	Payload    json.RawMessage
+	Metadata   EventMetadata
+	OccurredAt time.Time
+}
+
+// eventDecoders 按事件类型还原领域事件
+var eventDecoders = map[string]func(occurredAt time.Time) DomainEvent{
+	EventTypeUserCreated:       func(t time.Time) DomainEvent { return &UserCreatedEvent{occurredAt: t} },
+	EventTypeUserUpdated:       func(t time.Time) DomainEvent { return &UserUpdatedEvent{occurredAt: t} },
+	EventTypeUserStatusChanged: func(t time.Time) DomainEvent { return &UserStatusChangedEvent{occurredAt: t} },
+	EventTypeUserDeleted:       func(t time.Time) DomainEvent { return &UserDeletedEvent{occurredAt: t} },
+	EventTypeUserErased:        func(t time.Time) DomainEvent { return &UserErasedEvent{occurredAt: t} },
+}
+
+// Decode 将载荷还原为领域事件
+func (e *StoredEvent) Decode() (DomainEvent, error) {
+	newEvent, ok := eventDecoders[e.Type]
+	if !ok {
+		return nil, fmt.Errorf("unknown event type: %s", e.Type)
+	}
+	event := newEvent(e.OccurredAt)
+	if err := json.Unmarshal(e.Payload, event); err != nil {
+		return nil, fmt.Errorf("decode %s event: %w", e.Type, err)
+	}
+	return event, nil
+}
+
+// EventStore 领域事件存储（仅追加），按租户隔离
+type EventStore interface {
+	// Append 追加事件并按聚合分配序号；与聚合在同一事务中调用
+	Append(ctx context.Context, events []DomainEvent) error
+	// Load 按序号读取聚合在 afterSequence 之后的事件，最多 limit 条
+	Load(ctx context.Context, aggregateID string, afterSequence, limit int) ([]*StoredEvent, error)
+	// ReadAll 按全局顺序读取所有租户在 afterPosition 之后的事件，用于重放
+	ReadAll(ctx context.Context, afterPosition int64, limit int) ([]*StoredEvent, error)
+	// Redact 覆盖聚合所有事件载荷中已有的字段（个人数据擦除）
+	Redact(ctx context.Context, aggregateID string, fields map[string]interface{}) error
+}
+
+// Projection 读模型投影，可通过重放事件存储重建
+type Projection interface {
+	Name() string
+	// Reset 清空投影，重放从第一个事件开始
+	Reset(ctx context.Context) error
+	// Apply 按全局顺序应用一个事件
+	Apply(ctx context.Context, event *StoredEvent) error
+}
@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+)
+
+// erasedPasswordHash 擦除后的密码哈希占位值，不是合法的 bcrypt 哈希，任何密码都无法通过校验
+const erasedPasswordHash = "!"
+
+// ErasedEmailDomain 擦除后假名邮箱使用的保留域名（RFC 2606）
+const ErasedEmailDomain = "erased.invalid"
+
+// NewErasurePseudonym 生成随机假名和邮箱
+// 假名不由原始数据派生，因此擦除不可逆；随机部分保证租户内邮箱仍然唯一
+func NewErasurePseudonym() (Name, Email, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return Name{}, Email{}, fmt.Errorf("generate pseudonym: %w", err)
+	}
+	token := "erased-" + hex.EncodeToString(buf)
+
+	name, err := NewName(token)
+	if err != nil {
+		return Name{}, Email{}, err
+	}
+	email, err := NewEmail(token + "@" + ErasedEmailDomain)
+	if err != nil {
+		return Name{}, Email{}, err
+	}
+	return *name, *email, nil
+}
+
+// ErasedEventFields 擦除时写入已存储事件载荷的字段，覆盖用户事件中的姓名和邮箱
+func ErasedEventFields(pseudonym Name, email Email) map[string]interface{} {
+	return map[string]interface{}{
+		"name":  pseudonym.String(),
+		"email": email.String(),
+	}
+}
+
+// PersonalDataSource 个人数据来源（GDPR 数据导出）
+// 持有用户数据的存储（审计、会话、事件等）实现该接口，导出时各自贡献一个分区
+type PersonalDataSource interface {
+	// Section 分区名，作为导出归档中的文件名
+	Section() string
+	// Collect 返回该用户在当前租户下的全部数据，需可被 JSON 序列化
+	Collect(ctx context.Context, userID int) (interface{}, error)
+}
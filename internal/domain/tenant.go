@@ -0,0 +1,184 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// DefaultTenantID 默认租户（单租户部署及历史数据）
+const DefaultTenantID = "default"
+
+// TenantStatus 租户状态
+type TenantStatus string
+
+const (
+	TenantStatusActive    TenantStatus = "active"
+	TenantStatusSuspended TenantStatus = "suspended"
+)
+
+// IsValid 验证租户状态是否有效
+func (s TenantStatus) IsValid() bool {
+	switch s {
+	case TenantStatusActive, TenantStatusSuspended:
+		return true
+	default:
+		return false
+	}
+}
+
+// tenantIDRegex 租户ID规则（可用作子域名）
+var tenantIDRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,62}$`)
+
+// ValidateTenantID 校验租户ID
+func ValidateTenantID(id string) error {
+	if !tenantIDRegex.MatchString(id) {
+		return fmt.Errorf("invalid tenant id: %q (lowercase letters, digits and '-', max 63)", id)
+	}
+	return nil
+}
+
+// Tenant 租户实体
+type Tenant struct {
+	id        string
+	name      string
+	status    TenantStatus
+	createdAt time.Time
+	updatedAt time.Time
+}
+
+// NewTenant 创建新租户（业务规则在此校验）
+func NewTenant(id, name string) (*Tenant, error) {
+	if err := ValidateTenantID(id); err != nil {
+		return nil, err
+	}
+	name = strings.TrimSpace(name)
+	if err := validateTenantName(name); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	return &Tenant{
+		id:        id,
+		name:      name,
+		status:    TenantStatusActive,
+		createdAt: now,
+		updatedAt: now,
+	}, nil
+}
+
+// RebuildTenant 从持久化数据重建租户（由仓储调用）
+func RebuildTenant(id, name string, status TenantStatus, createdAt, updatedAt time.Time) *Tenant {
+	return &Tenant{
+		id:        id,
+		name:      name,
+		status:    status,
+		createdAt: createdAt,
+		updatedAt: updatedAt,
+	}
+}
+
+// ID 获取租户ID
+func (t *Tenant) ID() string {
+	return t.id
+}
+
+// Name 获取租户名称
+func (t *Tenant) Name() string {
+	return t.name
+}
+
+// Status 获取租户状态
+func (t *Tenant) Status() TenantStatus {
+	return t.status
+}
+
+// CreatedAt 获取创建时间
+func (t *Tenant) CreatedAt() time.Time {
+	return t.createdAt
+}
+
+// UpdatedAt 获取更新时间
+func (t *Tenant) UpdatedAt() time.Time {
+	return t.updatedAt
+}
+
+// IsActive 租户是否可用
+func (t *Tenant) IsActive() bool {
+	return t.status == TenantStatusActive
+}
+
+// Rename 修改租户名称
+func (t *Tenant) Rename(name string) error {
+	name = strings.TrimSpace(name)
+	if err := validateTenantName(name); err != nil {
+		return err
+	}
+	t.name = name
+	t.updatedAt = time.Now()
+	return nil
+}
+
+// ChangeStatus 修改租户状态
+func (t *Tenant) ChangeStatus(status TenantStatus) error {
+	if !status.IsValid() {
+		return fmt.Errorf("invalid tenant status: %s", status)
+	}
+	if t.id == DefaultTenantID && status != TenantStatusActive {
+		return fmt.Errorf("default tenant cannot be suspended")
+	}
+	t.status = status
+	t.updatedAt = time.Now()
+	return nil
+}
+
+// CanBeDeleted 检查租户是否可以删除（需先清空用户）
+func (t *Tenant) CanBeDeleted(userCount int64) error {
+	if t.id == DefaultTenantID {
+		return fmt.Errorf("default tenant cannot be deleted")
+	}
+	if userCount > 0 {
+		return fmt.Errorf("tenant still has %d users", userCount)
+	}
+	return nil
+}
+
+// SetUpdatedAt 设置更新时间（由仓储调用）
+func (t *Tenant) SetUpdatedAt(updatedAt time.Time) {
+	t.updatedAt = updatedAt
+}
+
+func validateTenantName(name string) error {
+	if name == "" {
+		return fmt.Errorf("tenant name cannot be empty")
+	}
+	if len(name) > 100 {
+		return fmt.Errorf("tenant name length cannot exceed 100 characters")
+	}
+	return nil
+}
+
+// TenantRepository 租户仓储接口（租户本身不受租户隔离约束）
+type TenantRepository interface {
+	// Create 创建租户
+	Create(ctx context.Context, tenant *Tenant) error
+
+	// GetByID 根据ID获取租户
+	GetByID(ctx context.Context, id string) (*Tenant, error)
+
+	// Update 更新租户
+	Update(ctx context.Context, tenant *Tenant) error
+
+	// Delete 删除租户
+	Delete(ctx context.Context, id string) error
+
+	// List 分页查询租户
+	List(ctx context.Context, page, pageSize int) ([]*Tenant, int64, error)
+}
+
+// TenantCache 请求解析时缓存的租户
+type TenantCache interface {
+	// Invalidate 删除缓存的租户，使状态变更立即生效
+	Invalidate(id string)
+}
@@ -11,12 +11,14 @@ const (
 	StatusActive   Status = "active"
 	StatusInactive Status = "inactive"
 	StatusBanned   Status = "banned"
+	// StatusErased 个人数据已被擦除（GDPR），不可再变更
+	StatusErased Status = "erased"
 )
 
 // IsValid 验证状态是否有效
 func (s Status) IsValid() bool {
 	switch s {
-	case StatusActive, StatusInactive, StatusBanned:
+	case StatusActive, StatusInactive, StatusBanned, StatusErased:
 		return true
 	default:
 		return false
@@ -63,22 +65,45 @@ type UserRepository interface {
 
 // UserListParams 用户列表查询参数
 type UserListParams struct {
-	ID       *int
-	Name     *string
-	Email    *string
-	Status   *Status
-	Page     int
-	PageSize int
+	ID     *int
+	Name   *string
+	Email  *string
+	Status *Status
+	// Attributes 自定义属性等值过滤（AND 关系）
+	Attributes []AttributeFilter
+	Page       int
+	PageSize   int
 }
 
 // PasswordHasher 密码哈希器接口（领域服务）
 type PasswordHasher interface {
 	Hash(password string) (string, error)
 	Verify(hashedPassword, password string) error
+	// CheckHash 校验外部导入的哈希值：格式与强度须与 Hash 的输出一致
+	CheckHash(hashedPassword string) error
+}
+
+// PIICipher 个人数据字段加密器接口（静态加密 + 盲索引）
+type PIICipher interface {
+	// Enabled 是否加密；未配置密钥时按明文存储
+	Enabled() bool
+	// Encrypt 使用主密钥加密；aad 标识值所在的行与列（AES-GCM 附加认证数据），解密时须一致
+	Encrypt(plaintext, aad string) (string, error)
+	// Decrypt 解密；启用加密前写入的明文原样返回
+	Decrypt(stored, aad string) (string, error)
+	// BlindIndex 返回用于等值查询和唯一约束的确定性索引
+	BlindIndex(value string) string
+	// LookupIndexes 返回等值查询匹配的索引：BlindIndex 及尚未 rekey 的旧行使用的索引
+	LookupIndexes(value string) []string
+	// NeedsRekey 存储值是否为明文或由非主密钥加密
+	NeedsRekey(stored string) bool
 }
 
 // UserFactory 用户工厂接口（领域服务）
 type UserFactory interface {
 	// CreateNewUser 创建新用户聚合根
 	CreateNewUser(name, email, password string) (*UserAggregate, error)
-}
\ No newline at end of file
+
+	// CreateNewUserWithHashedPassword 使用已哈希的密码创建新用户聚合根（用于批量导入）
+	CreateNewUserWithHashedPassword(name, email, hashedPassword string) (*UserAggregate, error)
+}
@@ -57,6 +57,39 @@ func (a *UserAggregate) ID() int {
 	return a.user.ID()
 }
 
+// MarkCreated 记录用户创建事件（仓储分配ID后由应用服务调用）
+func (a *UserAggregate) MarkCreated() {
+	a.events.AddEvent(NewUserCreatedEvent(
+		a.user.ID(),
+		a.user.Email().String(),
+		a.user.Name().String(),
+	))
+}
+
+// MarkDeleted 校验删除规则并记录用户删除事件（由应用服务在删除前调用）
+func (a *UserAggregate) MarkDeleted() error {
+	if err := a.user.CanBeDeleted(); err != nil {
+		return err
+	}
+
+	a.events.AddEvent(NewUserDeletedEvent(
+		a.user.ID(),
+		a.user.Email().String(),
+		a.user.Name().String(),
+	))
+
+	return nil
+}
+
+// Erase 擦除个人数据并记录擦除事件（事件不携带个人数据）
+func (a *UserAggregate) Erase(pseudonym Name, email Email) error {
+	if err := a.user.Erase(pseudonym, email); err != nil {
+		return err
+	}
+	a.events.AddEvent(NewUserErasedEvent(a.user.ID(), a.user.TenantID()))
+	return nil
+}
+
 // ChangeStatus 改变用户状态（聚合根协调）
 func (a *UserAggregate) ChangeStatus(newStatus Status) error {
 	oldStatus := a.user.Status()
@@ -94,6 +127,22 @@ func (a *UserAggregate) UpdateProfile(name Name, email Email) error {
 	return nil
 }
 
+// UpdateAttributes 更新自定义属性
+func (a *UserAggregate) UpdateAttributes(schema *AttributeSchema, attrs Attributes) error {
+	if err := a.user.ChangeAttributes(schema, attrs); err != nil {
+		return err
+	}
+
+	// 记录领域事件
+	a.events.AddEvent(NewUserUpdatedEvent(
+		a.user.ID(),
+		a.user.Email().String(),
+		a.user.Name().String(),
+	))
+
+	return nil
+}
+
 // ChangePassword 更改密码
 func (a *UserAggregate) ChangePassword(hashedPassword HashedPassword) error {
 	// 密码变更不记录详细事件（出于安全考虑），但可以记录审计日志
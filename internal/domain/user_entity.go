@@ -8,10 +8,12 @@ import (
 // User 用户实体（包含业务行为）
 type User struct {
 	id             int
+	tenantID       string
 	name           Name
 	email          Email
 	hashedPassword HashedPassword
 	status         Status
+	attributes     Attributes
 	createdAt      time.Time
 	updatedAt      time.Time
 }
@@ -60,6 +62,16 @@ func (u *User) Status() Status {
 	return u.status
 }
 
+// TenantID 获取所属租户ID
+func (u *User) TenantID() string {
+	return u.tenantID
+}
+
+// Attributes 获取自定义属性（返回副本）
+func (u *User) Attributes() Attributes {
+	return u.attributes.Clone()
+}
+
 // CreatedAt 获取创建时间
 func (u *User) CreatedAt() time.Time {
 	return u.createdAt
@@ -76,6 +88,11 @@ func (u *User) ChangeStatus(newStatus Status) error {
 		return fmt.Errorf("invalid status: %s", newStatus)
 	}
 
+	// 业务规则：已擦除的用户不能再变更状态，擦除只能通过 Erase
+	if u.status == StatusErased || newStatus == StatusErased {
+		return fmt.Errorf("status of an erased user cannot be changed")
+	}
+
 	// 业务规则：被禁止的用户不能直接激活
 	if u.status == StatusBanned && newStatus == StatusActive {
 		return fmt.Errorf("cannot directly activate a banned user")
@@ -93,6 +110,9 @@ func (u *User) ChangeStatus(newStatus Status) error {
 
 // UpdateProfile 更新用户资料（业务行为）
 func (u *User) UpdateProfile(name Name, email Email) error {
+	if u.status == StatusErased {
+		return fmt.Errorf("profile of an erased user cannot be changed")
+	}
 	u.name = name
 	u.email = email
 	u.updatedAt = time.Now()
@@ -101,6 +121,9 @@ func (u *User) UpdateProfile(name Name, email Email) error {
 
 // ChangePassword 更改密码（业务行为）
 func (u *User) ChangePassword(hashedPassword HashedPassword) error {
+	if u.status == StatusErased {
+		return fmt.Errorf("password of an erased user cannot be changed")
+	}
 	u.hashedPassword = hashedPassword
 	u.updatedAt = time.Now()
 	return nil
@@ -120,6 +143,26 @@ func (u *User) IsActive() bool {
 	return u.status == StatusActive
 }
 
+// IsErased 检查用户个人数据是否已被擦除
+func (u *User) IsErased() bool {
+	return u.status == StatusErased
+}
+
+// Erase 不可逆地擦除个人数据（业务行为）
+// 姓名和邮箱替换为假名，凭证与自定义属性被清除，ID 保留以维持审计记录的引用
+func (u *User) Erase(pseudonym Name, email Email) error {
+	if u.status == StatusErased {
+		return fmt.Errorf("user is already erased")
+	}
+	u.name = pseudonym
+	u.email = email
+	u.hashedPassword = HashedPassword{value: erasedPasswordHash}
+	u.attributes = nil
+	u.status = StatusErased
+	u.updatedAt = time.Now()
+	return nil
+}
+
 // IsBanned 检查用户是否被禁止
 func (u *User) IsBanned() bool {
 	return u.status == StatusBanned
@@ -130,6 +173,30 @@ func (u *User) SetID(id int) {
 	u.id = id
 }
 
+// ChangeAttributes 按 schema 校验并替换自定义属性（业务行为）
+func (u *User) ChangeAttributes(schema *AttributeSchema, attrs Attributes) error {
+	if u.status == StatusErased {
+		return fmt.Errorf("attributes of an erased user cannot be changed")
+	}
+	normalized, err := schema.Validate(attrs)
+	if err != nil {
+		return err
+	}
+	u.attributes = normalized
+	u.updatedAt = time.Now()
+	return nil
+}
+
+// SetTenantID 设置所属租户（由仓储调用，租户取自请求上下文）
+func (u *User) SetTenantID(tenantID string) {
+	u.tenantID = tenantID
+}
+
+// SetAttributes 设置自定义属性（从数据库重建时由仓储调用，不做校验）
+func (u *User) SetAttributes(attrs Attributes) {
+	u.attributes = attrs
+}
+
 // SetCreatedAt 设置创建时间（由仓储调用）
 func (u *User) SetCreatedAt(t time.Time) {
 	u.createdAt = t
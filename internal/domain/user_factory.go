@@ -52,4 +52,32 @@ func (f *userFactory) CreateNewUser(name, email, password string) (*UserAggregat
 	}
 
 	return aggregate, nil
-}
\ No newline at end of file
+}
+
+// CreateNewUserWithHashedPassword 使用已哈希的密码创建新用户聚合根
+func (f *userFactory) CreateNewUserWithHashedPassword(name, email, hashedPassword string) (*UserAggregate, error) {
+	nameVO, err := NewName(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid name: %w", err)
+	}
+
+	emailVO, err := NewEmail(email)
+	if err != nil {
+		return nil, fmt.Errorf("invalid email: %w", err)
+	}
+
+	hashedPasswordVO, err := NewHashedPassword(hashedPassword)
+	if err != nil {
+		return nil, fmt.Errorf("invalid password hash: %w", err)
+	}
+	if err := f.passwordHasher.CheckHash(hashedPassword); err != nil {
+		return nil, fmt.Errorf("invalid password hash: %w", err)
+	}
+
+	aggregate, err := NewUserAggregate(*nameVO, *emailVO, *hashedPasswordVO)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create user aggregate: %w", err)
+	}
+
+	return aggregate, nil
+}
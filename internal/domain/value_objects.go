@@ -6,6 +6,23 @@ import (
 	"unicode"
 )
 
+// ValidationError 值对象校验错误，携带字段名与校验码便于接口层返回字段级明细
+type ValidationError struct {
+	Field   string
+	Code    string
+	Param   string // 校验参数，如长度限制，供接口层本地化消息
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+// invalid 创建值对象校验错误
+func invalid(field, code, param, format string, args ...any) error {
+	return &ValidationError{Field: field, Code: code, Param: param, Message: fmt.Sprintf(format, args...)}
+}
+
 // Email 邮箱值对象
 type Email struct {
 	value string
@@ -17,13 +34,13 @@ var emailRegex = regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]
 // NewEmail 创建邮箱值对象
 func NewEmail(email string) (*Email, error) {
 	if email == "" {
-		return nil, fmt.Errorf("email cannot be empty")
+		return nil, invalid("email", "required", "", "email cannot be empty")
 	}
 	if len(email) > 100 {
-		return nil, fmt.Errorf("email length cannot exceed 100 characters")
+		return nil, invalid("email", "max", "100", "email length cannot exceed 100 characters")
 	}
 	if !emailRegex.MatchString(email) {
-		return nil, fmt.Errorf("invalid email format: %s", email)
+		return nil, invalid("email", "email", "", "invalid email format: %s", email)
 	}
 	return &Email{value: email}, nil
 }
@@ -46,13 +63,13 @@ type Password struct {
 // NewPassword 创建密码值对象
 func NewPassword(password string) (*Password, error) {
 	if password == "" {
-		return nil, fmt.Errorf("password cannot be empty")
+		return nil, invalid("password", "required", "", "password cannot be empty")
 	}
 	if len(password) < 6 {
-		return nil, fmt.Errorf("password must be at least 6 characters")
+		return nil, invalid("password", "min", "6", "password must be at least 6 characters")
 	}
 	if len(password) > 100 {
-		return nil, fmt.Errorf("password length cannot exceed 100 characters")
+		return nil, invalid("password", "max", "100", "password length cannot exceed 100 characters")
 	}
 	// 可选：添加密码强度检查
 	if err := validatePasswordStrength(password); err != nil {
@@ -81,10 +98,10 @@ func validatePasswordStrength(password string) error {
 	}
 	// 放宽要求：至少包含字母和数字
 	if !hasLower && !hasUpper {
-		return fmt.Errorf("password must contain at least one letter")
+		return invalid("password", "strength", "letter", "password must contain at least one letter")
 	}
 	if !hasDigit {
-		return fmt.Errorf("password must contain at least one digit")
+		return invalid("password", "strength", "digit", "password must contain at least one digit")
 	}
 	return nil
 }
@@ -97,13 +114,13 @@ type Name struct {
 // NewName 创建姓名值对象
 func NewName(name string) (*Name, error) {
 	if name == "" {
-		return nil, fmt.Errorf("name cannot be empty")
+		return nil, invalid("name", "required", "", "name cannot be empty")
 	}
 	if len(name) < 2 {
-		return nil, fmt.Errorf("name must be at least 2 characters")
+		return nil, invalid("name", "min", "2", "name must be at least 2 characters")
 	}
 	if len(name) > 50 {
-		return nil, fmt.Errorf("name length cannot exceed 50 characters")
+		return nil, invalid("name", "max", "50", "name length cannot exceed 50 characters")
 	}
 	return &Name{value: name}, nil
 }
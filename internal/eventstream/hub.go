@@ -0,0 +1,197 @@
+package eventstream
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"example.com/classic/internal/config"
+	"example.com/classic/pkg/logger"
+	"github.com/redis/go-redis/v9"
+)
+
+// subscriptionBuffer events queued for a subscriber; a subscriber falling further behind is dropped
+const subscriptionBuffer = 64
+
+// Filter selects the events delivered to a subscriber
+type Filter struct {
+	TenantID    string   // required match; events of other tenants are never delivered
+	Types       []string // empty matches every type
+	AggregateID string   // empty matches every aggregate
+}
+
+// Match reports whether the event passes the filter
+func (f Filter) Match(e Event) bool {
+	if e.TenantID != f.TenantID {
+		return false
+	}
+	if len(f.Types) > 0 && !slices.Contains(f.Types, e.Type) {
+		return false
+	}
+	return f.AggregateID == "" || e.AggregateID == f.AggregateID
+}
+
+// Subscription live events of a subscriber
+type Subscription struct {
+	hub    *Hub
+	filter Filter
+	events chan Event
+}
+
+// Events delivers the matching events. It is closed when the subscription is closed, when the
+// subscriber falls behind or when the hub stops; the client then resumes with Last-Event-ID
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Close stops the delivery
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	s.hub.remove(s)
+}
+
+// Hub receives the events of all replicas from the Redis channel and fans them out to the
+// subscribers of this replica. It keeps the latest events for resuming subscribers
+type Hub struct {
+	client  redis.UniversalClient
+	channel string
+	log     logger.Logger
+
+	mu          sync.Mutex
+	replay      []Event // ring buffer of the latest events, oldest at next once full
+	next        int
+	subscribers map[*Subscription]struct{}
+	stopped     bool
+}
+
+// NewHub creates a hub from the event stream config; it returns nil when the stream is disabled
+func NewHub(cfg config.EventStreamConfig, client redis.UniversalClient, log logger.Logger) *Hub {
+	if !cfg.Enabled {
+		return nil
+	}
+	return &Hub{
+		client:      client,
+		channel:     cfg.Channel,
+		log:         log,
+		replay:      make([]Event, 0, cfg.ReplayBuffer),
+		subscribers: make(map[*Subscription]struct{}),
+	}
+}
+
+// Run receives events until ctx is done, then closes all subscriptions.
+// The Redis subscription reconnects on its own; events published while it is down are lost
+func (h *Hub) Run(ctx context.Context) {
+	pubsub := h.client.Subscribe(ctx, h.channel)
+	defer func() { _ = pubsub.Close() }()
+	defer h.closeAll()
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-messages:
+			if !ok {
+				return
+			}
+			event, err := decode(msg.Payload)
+			if err != nil {
+				h.log.Warn(ctx, "invalid event on stream channel", logger.Err(err))
+				continue
+			}
+			h.dispatch(event)
+		}
+	}
+}
+
+// Subscribe registers a subscriber. With lastEventID > 0 it also returns the buffered events
+// after lastEventID that match the filter, to be sent before the live events; events older than
+// the replay buffer are not available
+func (h *Hub) Subscribe(filter Filter, lastEventID int64) (*Subscription, []Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var missed []Event
+	if lastEventID > 0 {
+		for _, event := range h.buffered() {
+			if event.ID > lastEventID && filter.Match(event) {
+				missed = append(missed, event)
+			}
+		}
+	}
+
+	sub := &Subscription{hub: h, filter: filter, events: make(chan Event, subscriptionBuffer)}
+	if h.stopped {
+		close(sub.events)
+		return sub, missed
+	}
+	h.subscribers[sub] = struct{}{}
+	return sub, missed
+}
+
+// dispatch buffers the event and delivers it to the matching subscribers
+func (h *Hub) dispatch(event Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.replay) < cap(h.replay) {
+		h.replay = append(h.replay, event)
+	} else if len(h.replay) > 0 {
+		h.replay[h.next] = event
+		h.next = (h.next + 1) % len(h.replay)
+	}
+
+	for sub := range h.subscribers {
+		if !sub.filter.Match(event) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			h.log.Warn(context.Background(), "event stream subscriber too slow, disconnecting",
+				logger.String("tenant_id", sub.filter.TenantID))
+			h.remove(sub)
+		}
+	}
+}
+
+// buffered returns the replay buffer from oldest to newest
+func (h *Hub) buffered() []Event {
+	return append(slices.Clone(h.replay[h.next:]), h.replay[:h.next]...)
+}
+
+// remove unregisters and closes the subscription; the caller holds mu
+func (h *Hub) remove(sub *Subscription) {
+	if _, ok := h.subscribers[sub]; ok {
+		delete(h.subscribers, sub)
+		close(sub.events)
+	}
+}
+
+func (h *Hub) closeAll() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.stopped = true
+	for sub := range h.subscribers {
+		h.remove(sub)
+	}
+}
+
+// decode parses a channel message: "<id> <event JSON>"
+func decode(payload string) (Event, error) {
+	idText, data, _ := strings.Cut(payload, " ")
+	id, err := strconv.ParseInt(idText, 10, 64)
+	if err != nil {
+		return Event{}, err
+	}
+	var event Event
+	if err := json.Unmarshal([]byte(data), &event); err != nil {
+		return Event{}, err
+	}
+	event.ID = id
+	return event, nil
+}
@@ -0,0 +1,148 @@
+package eventstream
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"example.com/classic/internal/config"
+	"example.com/classic/internal/domain"
+	"example.com/classic/pkg/logger"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestStream(t *testing.T, replayBuffer int) (*Publisher, *Hub) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	cfg := config.EventStreamConfig{Enabled: true, Channel: "test:events", ReplayBuffer: replayBuffer}
+	hub := NewHub(cfg, client, logger.New("test", "error", false))
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		hub.Run(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	require.Eventually(t, func() bool {
+		return mr.PubSubNumSub(cfg.Channel)[cfg.Channel] == 1
+	}, time.Second, 10*time.Millisecond)
+
+	return NewPublisher(cfg, client), hub
+}
+
+func publish(t *testing.T, publisher *Publisher, tenantID string, event domain.TenantEvent) int64 {
+	t.Helper()
+	event.SetTenant(tenantID)
+	id, err := publisher.Publish(context.Background(), event)
+	require.NoError(t, err)
+	return id
+}
+
+func receive(t *testing.T, sub *Subscription) Event {
+	t.Helper()
+	select {
+	case event, ok := <-sub.Events():
+		require.True(t, ok, "subscription closed")
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("no event received")
+		return Event{}
+	}
+}
+
+func TestHub_FiltersByTenantTypeAndAggregate(t *testing.T) {
+	publisher, hub := newTestStream(t, 10)
+
+	all, _ := hub.Subscribe(Filter{TenantID: "acme"}, 0)
+	defer all.Close()
+	statuses, _ := hub.Subscribe(Filter{TenantID: "acme", Types: []string{domain.EventTypeUserStatusChanged}}, 0)
+	defer statuses.Close()
+	user2, _ := hub.Subscribe(Filter{TenantID: "acme", AggregateID: "user-2"}, 0)
+	defer user2.Close()
+
+	publish(t, publisher, "globex", domain.NewUserCreatedEvent(9, "x@globex.test", "x"))
+	created := publish(t, publisher, "acme", domain.NewUserCreatedEvent(1, "a@acme.test", "a"))
+	changed := publish(t, publisher, "acme", domain.NewUserStatusChangedEvent(2, "b@acme.test", "b", domain.StatusActive, domain.StatusInactive))
+
+	event := receive(t, all)
+	assert.Equal(t, created, event.ID)
+	assert.Equal(t, domain.EventTypeUserCreated, event.Type)
+	assert.Equal(t, "user-1", event.AggregateID)
+	assert.Equal(t, "acme", event.TenantID)
+	var data map[string]any
+	require.NoError(t, json.Unmarshal(event.Data, &data))
+	assert.Equal(t, "a@acme.test", data["email"])
+	assert.Equal(t, changed, receive(t, all).ID)
+
+	assert.Equal(t, changed, receive(t, statuses).ID)
+	assert.Equal(t, changed, receive(t, user2).ID)
+	assert.Empty(t, statuses.Events())
+	assert.Empty(t, user2.Events())
+}
+
+func TestHub_ReplaysAfterLastEventID(t *testing.T) {
+	publisher, hub := newTestStream(t, 3)
+
+	// Receiving on live subscribers ensures the events reached the replay buffer
+	acme, _ := hub.Subscribe(Filter{TenantID: "acme"}, 0)
+	defer acme.Close()
+	globex, _ := hub.Subscribe(Filter{TenantID: "globex"}, 0)
+	defer globex.Close()
+
+	var ids []int64
+	for i := 1; i <= 5; i++ {
+		ids = append(ids, publish(t, publisher, "acme", domain.NewUserUpdatedEvent(i, "u@acme.test", "u")))
+		receive(t, acme)
+	}
+	other := publish(t, publisher, "globex", domain.NewUserUpdatedEvent(9, "x@globex.test", "x"))
+	receive(t, globex)
+
+	// The buffer holds the last three events; older ones are gone
+	resumed, missed := hub.Subscribe(Filter{TenantID: "acme"}, ids[1])
+	defer resumed.Close()
+	require.Len(t, missed, 2)
+	assert.Equal(t, ids[3], missed[0].ID)
+	assert.Equal(t, ids[4], missed[1].ID)
+
+	_, missed = hub.Subscribe(Filter{TenantID: "acme"}, ids[4])
+	assert.Empty(t, missed)
+	_, missed = hub.Subscribe(Filter{TenantID: "acme"}, 0)
+	assert.Empty(t, missed)
+	_, missed = hub.Subscribe(Filter{TenantID: "globex"}, ids[0])
+	require.Len(t, missed, 1)
+	assert.Equal(t, other, missed[0].ID)
+
+	// Live events follow the replayed ones
+	next := publish(t, publisher, "acme", domain.NewUserDeletedEvent(1, "u@acme.test", "u"))
+	assert.Equal(t, next, receive(t, resumed).ID)
+}
+
+func TestHub_DropsSlowSubscriber(t *testing.T) {
+	publisher, hub := newTestStream(t, 10)
+
+	slow, _ := hub.Subscribe(Filter{TenantID: "acme"}, 0)
+	fast, _ := hub.Subscribe(Filter{TenantID: "acme"}, 0)
+	defer fast.Close()
+
+	for i := 0; i <= subscriptionBuffer; i++ {
+		publish(t, publisher, "acme", domain.NewUserUpdatedEvent(1, "u@acme.test", "u"))
+		receive(t, fast)
+	}
+
+	received := 0
+	for range slow.Events() {
+		received++
+	}
+	assert.Equal(t, subscriptionBuffer, received)
+	slow.Close() // closing a dropped subscription is a no-op
+}
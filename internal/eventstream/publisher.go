@@ -0,0 +1,117 @@
+// Package eventstream pushes user domain events to live subscribers (the SSE endpoint
+// GET /api/v1/events/stream). Every replica publishes the events it raises to a Redis
+// pub/sub channel and every replica's Hub receives them, so a client gets the events of
+// its tenant no matter which replica it is connected to.
+package eventstream
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"example.com/classic/internal/config"
+	"example.com/classic/internal/domain"
+	"github.com/redis/go-redis/v9"
+)
+
+// publishTimeout bounds the Redis round trip of a published event
+const publishTimeout = 2 * time.Second
+
+// Event a domain event as delivered to subscribers
+type Event struct {
+	// ID increases with every published event across all replicas; clients resume with Last-Event-ID
+	ID          int64           `json:"id"`
+	Type        string          `json:"type"`
+	AggregateID string          `json:"aggregate_id"`
+	TenantID    string          `json:"tenant_id,omitempty"`
+	OccurredAt  time.Time       `json:"occurred_at"`
+	Data        json.RawMessage `json:"data"`
+}
+
+// publishScript numbers the event and publishes it in one step, so IDs arrive in increasing order
+//
+// KEYS[1] sequence key; ARGV: channel, event
+var publishScript = redis.NewScript(`
+local id = redis.call("INCR", KEYS[1])
+redis.call("PUBLISH", ARGV[1], id .. " " .. ARGV[2])
+return id
+`)
+
+// Publisher publishes domain events to the Redis channel
+type Publisher struct {
+	client  redis.Scripter
+	channel string
+}
+
+// NewPublisher creates a publisher from the event stream config; it returns nil when the stream is disabled
+func NewPublisher(cfg config.EventStreamConfig, client redis.Scripter) *Publisher {
+	if !cfg.Enabled {
+		return nil
+	}
+	return &Publisher{client: client, channel: cfg.Channel}
+}
+
+// Publish publishes the event and returns its ID
+func (p *Publisher) Publish(ctx context.Context, event domain.DomainEvent) (int64, error) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return 0, fmt.Errorf("marshal event %s: %w", event.EventType(), err)
+	}
+	e := Event{
+		Type:        event.EventType(),
+		AggregateID: event.AggregateID(),
+		OccurredAt:  event.OccurredAt(),
+		Data:        data,
+	}
+	if te, ok := event.(domain.TenantEvent); ok {
+		e.TenantID = te.Tenant()
+	}
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return 0, fmt.Errorf("marshal event %s: %w", event.EventType(), err)
+	}
+
+	id, err := publishScript.Run(ctx, p.client, []string{p.channel + ":seq"}, p.channel, payload).Int64()
+	if err != nil {
+		return 0, fmt.Errorf("publish event %s: %w", event.EventType(), err)
+	}
+	return id, nil
+}
+
+// Processors returns an event processor per user event type, to be registered with the domain
+// event publisher; nil when the stream is disabled
+func (p *Publisher) Processors() []domain.EventProcessor {
+	if p == nil {
+		return nil
+	}
+	eventTypes := []string{
+		domain.EventTypeUserCreated,
+		domain.EventTypeUserUpdated,
+		domain.EventTypeUserStatusChanged,
+		domain.EventTypeUserDeleted,
+		domain.EventTypeUserErased,
+	}
+	processors := make([]domain.EventProcessor, len(eventTypes))
+	for i, eventType := range eventTypes {
+		processors[i] = &processor{publisher: p, eventType: eventType}
+	}
+	return processors
+}
+
+// processor forwards the events of one type to the stream
+type processor struct {
+	publisher *Publisher
+	eventType string
+}
+
+func (p *processor) EventType() string {
+	return p.eventType
+}
+
+func (p *processor) Process(event domain.DomainEvent) error {
+	ctx, cancel := context.WithTimeout(context.Background(), publishTimeout)
+	defer cancel()
+	_, err := p.publisher.Publish(ctx, event)
+	return err
+}
@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"example.com/classic/internal/eventstream"
+	"example.com/classic/pkg/contextx"
+	"example.com/classic/pkg/logger"
+	"example.com/classic/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// EventStreamHandler HTTP handler pushing the domain events of the tenant as server-sent events
+type EventStreamHandler struct {
+	hub       *eventstream.Hub
+	heartbeat time.Duration
+	log       logger.Logger
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewEventStreamHandler creates event stream handler instance; heartbeat is the interval of the keep-alive comments
+func NewEventStreamHandler(hub *eventstream.Hub, heartbeat time.Duration, log logger.Logger) *EventStreamHandler {
+	return &EventStreamHandler{
+		hub:       hub,
+		heartbeat: heartbeat,
+		log:       log,
+		done:      make(chan struct{}),
+	}
+}
+
+// Close ends the open streams, so that a graceful shutdown does not wait for them
+func (h *EventStreamHandler) Close() {
+	h.closeOnce.Do(func() { close(h.done) })
+}
+
+// Stream pushes domain events as they happen
+// @Summary Stream domain events
+// @Description Server-sent events of the tenant's user domain events (user.created, user.status_changed ...). Each event carries its id, the event type as event name and the event as JSON data; comments are sent as heartbeats. Reconnecting clients send Last-Event-ID to receive the events they missed, as long as they are still in the replay buffer
+// @Tags Events
+// @Produce text/event-stream
+// @Param type query string false "Comma-separated event types to receive, e.g. user.created,user.deleted"
+// @Param aggregate query string false "Only events of this aggregate, e.g. user-42"
+// @Param Last-Event-ID header int false "Resume after this event id"
+// @Success 200 {object} eventstream.Event
+// @Failure 400 {object} response.Response
+// @Router /api/v1/events/stream [get]
+func (h *EventStreamHandler) Stream(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var lastEventID int64
+	if value := c.GetHeader("Last-Event-ID"); value != "" {
+		id, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || id < 0 {
+			response.InvalidParam(c, "invalid Last-Event-ID")
+			return
+		}
+		lastEventID = id
+	}
+
+	var types []string
+	for _, value := range c.QueryArray("type") {
+		for _, eventType := range strings.Split(value, ",") {
+			if eventType = strings.TrimSpace(eventType); eventType != "" {
+				types = append(types, eventType)
+			}
+		}
+	}
+
+	sub, missed := h.hub.Subscribe(eventstream.Filter{
+		TenantID:    contextx.GetTenantID(ctx),
+		Types:       types,
+		AggregateID: c.Query("aggregate"),
+	}, lastEventID)
+	defer sub.Close()
+
+	// The stream outlives http.read_timeout and http.write_timeout
+	rc := http.NewResponseController(c.Writer)
+	_ = rc.SetReadDeadline(time.Time{})
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	header := c.Writer.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("X-Accel-Buffering", "no") // no response buffering in nginx
+	c.Status(http.StatusOK)
+
+	for _, event := range missed {
+		if err := writeEvent(c.Writer, event); err != nil {
+			return
+		}
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-h.done:
+			return
+		case <-heartbeat.C:
+			if _, err := io.WriteString(c.Writer, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		case event, ok := <-sub.Events():
+			if !ok {
+				// Dropped for falling behind or the hub stopped; the client reconnects with Last-Event-ID
+				return
+			}
+			if err := writeEvent(c.Writer, event); err != nil {
+				h.log.Debug(ctx, "event stream closed", logger.Err(err))
+				return
+			}
+		}
+		c.Writer.Flush()
+	}
+}
+
+// writeEvent writes one event in the text/event-stream format
+func writeEvent(w io.Writer, event eventstream.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+	return err
+}
@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"example.com/classic/internal/config"
+	"example.com/classic/internal/domain"
+	"example.com/classic/internal/eventstream"
+	"example.com/classic/pkg/contextx"
+	"example.com/classic/pkg/logger"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventStreamHandler_Stream(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	cfg := config.EventStreamConfig{Enabled: true, Channel: "test:events", ReplayBuffer: 10}
+	log := logger.New("test", "error", false)
+	hub := eventstream.NewHub(cfg, client, log)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go hub.Run(ctx)
+	require.Eventually(t, func() bool {
+		return mr.PubSubNumSub(cfg.Channel)[cfg.Channel] == 1
+	}, time.Second, 10*time.Millisecond)
+
+	publisher := eventstream.NewPublisher(cfg, client)
+	publish := func(event domain.TenantEvent) int64 {
+		event.SetTenant("acme")
+		id, err := publisher.Publish(context.Background(), event)
+		require.NoError(t, err)
+		return id
+	}
+
+	h := NewEventStreamHandler(hub, 50*time.Millisecond, log)
+	engine := gin.New()
+	engine.GET("/api/v1/events/stream", func(c *gin.Context) {
+		c.Request = c.Request.WithContext(contextx.WithTenantID(c.Request.Context(), "acme"))
+		c.Next()
+	}, h.Stream)
+	server := httptest.NewServer(engine)
+	t.Cleanup(server.Close)
+
+	// Fill the replay buffer; a live subscription shows when the events arrived
+	live, _ := hub.Subscribe(eventstream.Filter{TenantID: "acme"}, 0)
+	first := publish(domain.NewUserCreatedEvent(1, "a@acme.test", "a"))
+	publish(domain.NewUserCreatedEvent(2, "b@acme.test", "b"))
+	missed := publish(domain.NewUserStatusChangedEvent(2, "b@acme.test", "b", domain.StatusActive, domain.StatusInactive))
+	for range 3 {
+		<-live.Events()
+	}
+	live.Close()
+
+	t.Run("invalid Last-Event-ID", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, server.URL+"/api/v1/events/stream", nil)
+		require.NoError(t, err)
+		req.Header.Set("Last-Event-ID", "abc")
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("resume, heartbeat and live events", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, server.URL+"/api/v1/events/stream?type=user.status_changed,user.deleted", nil)
+		require.NoError(t, err)
+		req.Header.Set("Last-Event-ID", strconv.FormatInt(first, 10))
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+		lines := bufio.NewScanner(resp.Body)
+		next := func() string {
+			require.True(t, lines.Scan())
+			return lines.Text()
+		}
+
+		// Only the missed status change is replayed; the filtered creation is skipped
+		assert.Equal(t, "id: "+strconv.FormatInt(missed, 10), next())
+		assert.Equal(t, "event: user.status_changed", next())
+		assert.Contains(t, next(), `"aggregate_id":"user-2"`)
+		assert.Equal(t, "", next())
+
+		assert.Equal(t, ": heartbeat", next())
+		assert.Equal(t, "", next())
+
+		deleted := publish(domain.NewUserDeletedEvent(1, "a@acme.test", "a"))
+		for line := next(); line != "id: "+strconv.FormatInt(deleted, 10); line = next() {
+			assert.True(t, line == ": heartbeat" || line == "", "unexpected line %q", line)
+		}
+		assert.Equal(t, "event: user.deleted", next())
+		assert.True(t, strings.HasPrefix(next(), "data: {"))
+	})
+}
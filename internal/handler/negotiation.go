@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"io"
+
+	"example.com/classic/api/grpc/pb"
+	"example.com/classic/internal/domain"
+	"example.com/classic/internal/handler/presenter"
+	"example.com/classic/internal/handler/request"
+	"example.com/classic/internal/service/dto"
+	"example.com/classic/pkg/errors"
+	"example.com/classic/pkg/response"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"google.golang.org/protobuf/proto"
+)
+
+// bindBody 按 Content-Type 解码并校验请求体 (JSON / MessagePack / protobuf)；
+// protobuf 请求体先解码到 msg，再由 convert 转换为 obj。msg 为 nil 表示接口不接受 protobuf
+func bindBody(c *gin.Context, obj any, msg proto.Message, convert func()) error {
+	codec, ok := response.RequestCodec(c)
+	if !ok {
+		return errors.ErrUnsupportedMediaType
+	}
+	if _, isJSON := codec.(response.JSONCodec); isJSON {
+		return c.ShouldBindJSON(obj)
+	}
+
+	data, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return err
+	}
+	if _, isProto := codec.(response.ProtobufCodec); isProto {
+		if msg == nil {
+			return errors.ErrUnsupportedMediaType
+		}
+		if err := codec.Unmarshal(data, msg); err != nil {
+			return err
+		}
+		convert()
+	} else if err := codec.Unmarshal(data, obj); err != nil {
+		return err
+	}
+	return binding.Validator.ValidateStruct(obj)
+}
+
+// userBody 单个用户的响应数据：协商为 protobuf 时为 pb.User，其余格式按稀疏字段集裁剪
+func userBody(c *gin.Context, fields presenter.Fields, user *domain.User) any {
+	if response.Negotiated(c, response.ProtobufContentType) {
+		return toPBUser(user)
+	}
+	return fields.Select(presenter.NewUserV1(dto.UserDTOFromUser(user)))
+}
+
+// writeUserPage 写出用户分页：协商为 protobuf 时为 pb.ListResponse
+func writeUserPage(c *gin.Context, fields presenter.Fields, users []*domain.User, total int64, page, pageSize int) {
+	if response.Negotiated(c, response.ProtobufContentType) {
+		pbUsers := make([]*pb.User, len(users))
+		for i, user := range users {
+			pbUsers[i] = toPBUser(user)
+		}
+		response.Success(c, &pb.ListResponse{Users: pbUsers, Total: total})
+		return
+	}
+	response.SuccessWithPage(c, fields.Select(presenter.NewUsersV1(dto.UserDTOFromUsers(users))), total, page, pageSize)
+}
+
+// createUserRequestFromPB converts a protobuf registration to the HTTP request
+func createUserRequestFromPB(req *pb.RegisterRequest) request.CreateUserRequest {
+	return request.CreateUserRequest{
+		Name:       req.Name,
+		Email:      req.Email,
+		Password:   req.Password,
+		Attributes: fromPBAttributes(req.Attributes),
+	}
+}
+
+// updateUserRequestFromPB converts a protobuf update to the HTTP request; the ID comes from the path
+func updateUserRequestFromPB(req *pb.UpdateRequest) request.UpdateUserRequest {
+	out := request.UpdateUserRequest{
+		Name:       req.Name,
+		Email:      req.Email,
+		Attributes: fromPBAttributes(req.Attributes),
+	}
+	if req.Status != nil {
+		status := fromPBStatus(*req.Status)
+		out.Status = &status
+	}
+	return out
+}
+
+// changeStatusRequestFromPB converts a protobuf status change to the HTTP request; the ID comes from the path
+func changeStatusRequestFromPB(req *pb.ChangeStatusRequest) request.ChangeStatusRequest {
+	return request.ChangeStatusRequest{Status: fromPBStatus(req.Status)}
+}
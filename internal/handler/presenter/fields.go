@@ -0,0 +1,138 @@
+package presenter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Fields a sparse fieldset (?fields=id,email); empty selects every field
+type Fields []string
+
+// FieldNames returns the JSON field names of a model in declaration order
+func FieldNames(model any) []string {
+	t := reflect.TypeOf(model)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	names := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		if name := jsonName(t.Field(i)); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// ParseFields parses a comma-separated list of fields of model, in the order the model declares them.
+// Fields the model does not have are an error.
+func ParseFields(raw string, model any) (Fields, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, nil
+	}
+	known := FieldNames(model)
+	requested := make(map[string]bool)
+	for _, name := range strings.Split(raw, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if !slices.Contains(known, name) {
+			return nil, fmt.Errorf("unknown field %q", name)
+		}
+		requested[name] = true
+	}
+
+	var fields Fields
+	for _, name := range known {
+		if requested[name] {
+			fields = append(fields, name)
+		}
+	}
+	return fields, nil
+}
+
+// Select keeps the fields of the set in a model (a struct pointer or a slice of them);
+// with an empty set the model is returned unchanged
+func (f Fields) Select(v any) any {
+	if len(f) == 0 || v == nil {
+		return v
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Slice {
+		out := make([]any, rv.Len())
+		for i := range out {
+			out[i] = f.project(rv.Index(i))
+		}
+		return out
+	}
+	return f.project(rv)
+}
+
+// project picks the fields of the set from a struct
+func (f Fields) project(v reflect.Value) any {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	p := make(partial, 0, 2*len(f))
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name := jsonName(t.Field(i))
+		for _, field := range f {
+			if field == name {
+				p = append(p, name, v.Field(i).Interface())
+				break
+			}
+		}
+	}
+	return p
+}
+
+// partial a projected object as alternating names and values; fields keep the order of the model
+type partial []any
+
+// MapBySlice marks partial as a map for the MessagePack codec
+func (partial) MapBySlice() {}
+
+// MarshalJSON implements json.Marshaler
+func (p partial) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i := 0; i < len(p); i += 2 {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(p[i])
+		value, err := json.Marshal(p[i+1])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// jsonName returns the JSON name of a field, or empty when it is not marshalled
+func jsonName(field reflect.StructField) string {
+	if !field.IsExported() {
+		return ""
+	}
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return field.Name
+	}
+	return name
+}
@@ -0,0 +1,38 @@
+package presenter
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFields(t *testing.T) {
+	fields, err := ParseFields(" email, id,,email ", UserV1{})
+	require.NoError(t, err)
+	// Model order, duplicates removed
+	assert.Equal(t, Fields{"id", "email"}, fields)
+
+	fields, err = ParseFields("", UserV1{})
+	require.NoError(t, err)
+	assert.Nil(t, fields)
+
+	_, err = ParseFields("id,password", UserV1{})
+	assert.EqualError(t, err, `unknown field "password"`)
+}
+
+func TestFields_Select(t *testing.T) {
+	users := []*UserV1{{ID: 1, Name: "Alice", Email: "alice@example.com"}, nil}
+
+	data, err := json.Marshal(Fields{"id", "email"}.Select(users))
+	require.NoError(t, err)
+	assert.Equal(t, `[{"id":1,"email":"alice@example.com"},null]`, string(data))
+
+	data, err = json.Marshal(Fields{"name"}.Select(users[0]))
+	require.NoError(t, err)
+	assert.Equal(t, `{"name":"Alice"}`, string(data))
+
+	// Without fields the model is returned as is
+	assert.Same(t, users[0], Fields(nil).Select(users[0]))
+}
@@ -0,0 +1,56 @@
+// Package presenter defines the public JSON representation of HTTP resources.
+// Models are named after the API version that serves them (UserV1 for /api/v1). Published fields
+// are never renamed or removed; an incompatible change gets a new model version instead. The
+// golden files under internal/handler/testdata lock down the wire format.
+package presenter
+
+import (
+	"time"
+
+	"example.com/classic/internal/domain"
+	"example.com/classic/internal/service/dto"
+)
+
+// UserV1 a user as returned by /api/v1
+type UserV1 struct {
+	ID       int           `json:"id"`
+	TenantID string        `json:"tenant_id"`
+	Name     string        `json:"name"`
+	Email    string        `json:"email"`
+	Status   domain.Status `json:"status"`
+	// Attributes custom attributes; an empty object when the user has none
+	Attributes map[string]interface{} `json:"attributes"`
+	// CreatedAt RFC 3339 timestamp in UTC
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// NewUserV1 builds the representation of a user
+func NewUserV1(user *dto.UserDTO) *UserV1 {
+	if user == nil {
+		return nil
+	}
+	attributes := user.Attributes
+	if attributes == nil {
+		attributes = map[string]interface{}{}
+	}
+	return &UserV1{
+		ID:         user.ID,
+		TenantID:   user.TenantID,
+		Name:       user.Name,
+		Email:      user.Email,
+		Status:     user.Status,
+		Attributes: attributes,
+		CreatedAt:  user.CreatedAt.UTC(),
+		UpdatedAt:  user.UpdatedAt.UTC(),
+	}
+}
+
+// NewUsersV1 builds the representation of a list of users; an empty array when there are none
+func NewUsersV1(users []*dto.UserDTO) []*UserV1 {
+	out := make([]*UserV1, len(users))
+	for i, user := range users {
+		out[i] = NewUserV1(user)
+	}
+	return out
+}
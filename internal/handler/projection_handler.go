@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"example.com/classic/internal/service"
+	"example.com/classic/pkg/logger"
+	"example.com/classic/pkg/response"
+	"example.com/classic/pkg/tracer"
+	"github.com/gin-gonic/gin"
+)
+
+// ProjectionHandler HTTP handler for the projection admin API
+type ProjectionHandler struct {
+	projectionService service.ProjectionService
+	log               logger.Logger
+}
+
+// NewProjectionHandler creates projection handler instance
+func NewProjectionHandler(projectionService service.ProjectionService, log logger.Logger) *ProjectionHandler {
+	return &ProjectionHandler{
+		projectionService: projectionService,
+		log:               log,
+	}
+}
+
+// List lists the registered projections
+// @Summary List projections
+// @Description List the registered read-model projections and their last replay (admin)
+// @Tags Projection Admin
+// @Produce json
+// @Param X-Admin-Token header string true "admin token"
+// @Success 200 {object} response.Response{data=[]dto.ProjectionStatus}
+// @Failure 403 {object} response.Response
+// @Router /api/v1/admin/projections [get]
+func (h *ProjectionHandler) List(c *gin.Context) {
+	response.Success(c, h.projectionService.List(c.Request.Context()))
+}
+
+// Replay rebuilds a projection from the event store
+// @Summary Replay projection
+// @Description Reset a projection and replay every stored event of all tenants into it (admin)
+// @Tags Projection Admin
+// @Produce json
+// @Param X-Admin-Token header string true "admin token"
+// @Param name path string true "Projection name"
+// @Success 200 {object} response.Response{data=dto.ProjectionStatus}
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/admin/projections/{name}/replay [post]
+func (h *ProjectionHandler) Replay(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Handler span
+	span, ctx := tracer.StartSpan(ctx, h.log, "handler:ReplayProjection")
+	defer span.End()
+
+	status, err := h.projectionService.Replay(ctx, c.Param("name"))
+	if err != nil {
+		span.EndWithError(err)
+		writeError(c, h.log, err)
+		return
+	}
+
+	response.SuccessWithMsg(c, "success.projection_replayed", status)
+}
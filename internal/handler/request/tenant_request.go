@@ -0,0 +1,23 @@
+package request
+
+import (
+	"example.com/classic/internal/domain"
+)
+
+// CreateTenantRequest create tenant request
+type CreateTenantRequest struct {
+	ID   string `json:"id" binding:"required,min=1,max=63"`
+	Name string `json:"name" binding:"required,min=2,max=100"`
+}
+
+// UpdateTenantRequest update tenant request
+type UpdateTenantRequest struct {
+	Name   *string              `json:"name,omitempty" binding:"omitempty,min=2,max=100"`
+	Status *domain.TenantStatus `json:"status,omitempty" binding:"omitempty,oneof=active suspended"`
+}
+
+// TenantQuery tenant list query parameters
+type TenantQuery struct {
+	Page     int `form:"page,default=1" binding:"min=1"`
+	PageSize int `form:"page_size,default=10" binding:"min=1,max=100"`
+}
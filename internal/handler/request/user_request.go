@@ -6,9 +6,10 @@ import (
 
 // CreateUserRequest create user request
 type CreateUserRequest struct {
-	Name     string `json:"name" binding:"required,min=2,max=50"`
-	Email    string `json:"email" binding:"required,email"`
-	Password string `json:"password" binding:"required,min=6,max=100"`
+	Name       string                 `json:"name" binding:"required,min=2,max=50"`
+	Email      string                 `json:"email" binding:"required,email"`
+	Password   string                 `json:"password" binding:"required,min=6,max=100"`
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
 }
 
 // UpdateUserRequest update user request
@@ -16,6 +17,8 @@ type UpdateUserRequest struct {
 	Name   *string        `json:"name,omitempty" binding:"omitempty,min=2,max=50"`
 	Email  *string        `json:"email,omitempty" binding:"omitempty,email"`
 	Status *domain.Status `json:"status,omitempty" binding:"omitempty,oneof=active inactive banned"`
+	// Attributes merge-patch of custom attributes; null removes an attribute
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
 }
 
 // ChangeStatusRequest change status request
@@ -25,10 +28,41 @@ type ChangeStatusRequest struct {
 
 // UserQuery user query parameters
 type UserQuery struct {
-	ID       *int           `form:"id,omitempty"`
-	Name     *string        `form:"name,omitempty"`
-	Email    *string        `form:"email,omitempty"`
-	Status   *domain.Status `form:"status,omitempty"`
-	Page     int            `form:"page,default=1" binding:"min=1"`
-	PageSize int            `form:"page_size,default=10" binding:"min=1,max=100"`
+	ID     *int           `form:"id,omitempty"`
+	Name   *string        `form:"name,omitempty"`
+	Email  *string        `form:"email,omitempty"`
+	Status *domain.Status `form:"status,omitempty"`
+	// Attributes custom attribute filters from attr.<name>=value
+	Attributes map[string]string `form:"-"`
+	Page       int               `form:"page,default=1" binding:"min=1"`
+	PageSize   int               `form:"page_size,default=10" binding:"min=1,max=100"`
+}
+
+// BatchFilter List filters selecting the users of a batch action
+type BatchFilter struct {
+	ID     *int           `json:"id,omitempty"`
+	Name   *string        `json:"name,omitempty"`
+	Email  *string        `json:"email,omitempty"`
+	Status *domain.Status `json:"status,omitempty" binding:"omitempty,oneof=active inactive banned"`
+	// Attributes custom attribute filters (name -> value as in attr.<name>=value)
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// BatchChangeStatusRequest batch change status request; exactly one of ids or filter
+type BatchChangeStatusRequest struct {
+	IDs    []int         `json:"ids,omitempty" binding:"omitempty,dive,min=1"`
+	Filter *BatchFilter  `json:"filter,omitempty"`
+	Status domain.Status `json:"status" binding:"required,oneof=active inactive banned"`
+}
+
+// BatchDeleteRequest batch delete request; exactly one of ids or filter
+type BatchDeleteRequest struct {
+	IDs    []int        `json:"ids,omitempty" binding:"omitempty,dive,min=1"`
+	Filter *BatchFilter `json:"filter,omitempty"`
+}
+
+// UserHistoryQuery user event history query parameters
+type UserHistoryQuery struct {
+	AfterSequence int `form:"after_sequence" binding:"min=0"`
+	Limit         int `form:"limit,default=50" binding:"min=1,max=200"`
 }
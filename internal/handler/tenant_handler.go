@@ -0,0 +1,266 @@
+package handler
+
+import (
+	"example.com/classic/internal/handler/request"
+	"example.com/classic/internal/service"
+	"example.com/classic/internal/service/dto"
+	"example.com/classic/pkg/contextx"
+	"example.com/classic/pkg/logger"
+	"example.com/classic/pkg/response"
+	"example.com/classic/pkg/tracer"
+	"github.com/gin-gonic/gin"
+)
+
+// TenantHandler HTTP handler for the tenant admin API
+type TenantHandler struct {
+	tenantService service.TenantService
+	userService   service.UserService
+	log           logger.Logger
+}
+
+// NewTenantHandler creates tenant handler instance
+func NewTenantHandler(
+	tenantService service.TenantService,
+	userService service.UserService,
+	log logger.Logger,
+) *TenantHandler {
+	return &TenantHandler{
+		tenantService: tenantService,
+		userService:   userService,
+		log:           log,
+	}
+}
+
+// Create creates a tenant
+// @Summary Create tenant
+// @Description Create a new tenant (admin)
+// @Tags Tenant Admin
+// @Accept json
+// @Produce json
+// @Param X-Admin-Token header string true "admin token"
+// @Param tenant body request.CreateTenantRequest true "tenant info"
+// @Success 200 {object} response.Response{data=dto.TenantDTO}
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/admin/tenants [post]
+func (h *TenantHandler) Create(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Handler span
+	span, ctx := tracer.StartSpan(ctx, h.log, "handler:CreateTenant")
+	defer span.End()
+
+	var req request.CreateTenantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Warn(ctx, "invalid request body", logger.Err(err))
+		writeBodyError(c, &req, err)
+		return
+	}
+
+	tenant, err := h.tenantService.Create(ctx, &dto.CreateTenantParams{
+		ID:   req.ID,
+		Name: req.Name,
+	})
+	if err != nil {
+		span.EndWithError(err)
+		writeError(c, h.log, err)
+		return
+	}
+
+	response.SuccessWithMsg(c, "success.tenant_created", dto.TenantDTOFromTenant(tenant))
+}
+
+// List lists tenants
+// @Summary List tenants
+// @Description Paginated tenant list (admin)
+// @Tags Tenant Admin
+// @Produce json
+// @Param X-Admin-Token header string true "admin token"
+// @Param page query int false "page" default(1)
+// @Param page_size query int false "page size" default(10)
+// @Success 200 {object} response.Response{data=response.PageResponse{data=[]dto.TenantDTO}}
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/admin/tenants [get]
+func (h *TenantHandler) List(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Handler span
+	span, ctx := tracer.StartSpan(ctx, h.log, "handler:ListTenants")
+	defer span.End()
+
+	var query request.TenantQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.BadRequest(c, queryError(ctx, &query, err))
+		return
+	}
+
+	tenants, total, err := h.tenantService.List(ctx, query.Page, query.PageSize)
+	if err != nil {
+		span.EndWithError(err)
+		writeError(c, h.log, err)
+		return
+	}
+
+	response.SuccessWithPage(c, dto.TenantDTOFromTenants(tenants), total, query.Page, query.PageSize)
+}
+
+// GetByID gets a tenant
+// @Summary Get tenant
+// @Description Get tenant by ID (admin)
+// @Tags Tenant Admin
+// @Produce json
+// @Param X-Admin-Token header string true "admin token"
+// @Param id path string true "Tenant ID"
+// @Success 200 {object} response.Response{data=dto.TenantDTO}
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/admin/tenants/{id} [get]
+func (h *TenantHandler) GetByID(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Handler span
+	span, ctx := tracer.StartSpan(ctx, h.log, "handler:GetTenant")
+	defer span.End()
+
+	tenant, err := h.tenantService.GetByID(ctx, c.Param("id"))
+	if err != nil {
+		span.EndWithError(err)
+		writeError(c, h.log, err)
+		return
+	}
+
+	response.Success(c, dto.TenantDTOFromTenant(tenant))
+}
+
+// Update renames a tenant or changes its status
+// @Summary Update tenant
+// @Description Rename a tenant or suspend/activate it (admin)
+// @Tags Tenant Admin
+// @Accept json
+// @Produce json
+// @Param X-Admin-Token header string true "admin token"
+// @Param id path string true "Tenant ID"
+// @Param tenant body request.UpdateTenantRequest true "tenant update info"
+// @Success 200 {object} response.Response{data=dto.TenantDTO}
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/admin/tenants/{id} [put]
+func (h *TenantHandler) Update(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Handler span
+	span, ctx := tracer.StartSpan(ctx, h.log, "handler:UpdateTenant")
+	defer span.End()
+
+	var req request.UpdateTenantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Warn(ctx, "invalid request body", logger.Err(err))
+		writeBodyError(c, &req, err)
+		return
+	}
+
+	id := c.Param("id")
+	tenant, err := h.tenantService.Update(ctx, id, &dto.UpdateTenantParams{
+		Name:   req.Name,
+		Status: req.Status,
+	})
+	if err != nil {
+		span.EndWithError(err)
+		writeError(c, h.log, err)
+		return
+	}
+
+	response.SuccessWithMsg(c, "success.tenant_updated", dto.TenantDTOFromTenant(tenant))
+}
+
+// Delete deletes a tenant without users
+// @Summary Delete tenant
+// @Description Delete a tenant that has no users (admin)
+// @Tags Tenant Admin
+// @Produce json
+// @Param X-Admin-Token header string true "admin token"
+// @Param id path string true "Tenant ID"
+// @Success 200 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/admin/tenants/{id} [delete]
+func (h *TenantHandler) Delete(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Handler span
+	span, ctx := tracer.StartSpan(ctx, h.log, "handler:DeleteTenant")
+	defer span.End()
+
+	id := c.Param("id")
+	if err := h.tenantService.Delete(ctx, id); err != nil {
+		span.EndWithError(err)
+		writeError(c, h.log, err)
+		return
+	}
+
+	response.SuccessWithMsg(c, "success.tenant_deleted", nil)
+}
+
+// ListUsers lists the users of one tenant
+// @Summary List tenant users
+// @Description List users of the given tenant with the same filters as the user list (admin)
+// @Tags Tenant Admin
+// @Produce json,application/msgpack,application/x-protobuf
+// @Param X-Admin-Token header string true "admin token"
+// @Param id path string true "Tenant ID"
+// @Param page query int false "page" default(1)
+// @Param page_size query int false "page size" default(10)
+// @Param fields query string false "Comma-separated user fields to return, e.g. id,email"
+// @Success 200 {object} response.Response{data=response.PageResponse{data=[]presenter.UserV1}}
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/admin/tenants/{id}/users [get]
+func (h *TenantHandler) ListUsers(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Handler span
+	span, ctx := tracer.StartSpan(ctx, h.log, "handler:ListTenantUsers")
+	defer span.End()
+
+	tenant, err := h.tenantService.GetByID(ctx, c.Param("id"))
+	if err != nil {
+		span.EndWithError(err)
+		writeError(c, h.log, err)
+		return
+	}
+
+	// 管理接口不经过租户中间件，这里显式指定目标租户
+	ctx = contextx.WithTenantID(ctx, tenant.ID())
+
+	query := parseUserQuery(c)
+	fields, ok := userFields(c)
+	if !ok {
+		return
+	}
+	users, total, err := h.userService.List(ctx, &dto.UserQueryParams{
+		ID:         query.ID,
+		Name:       query.Name,
+		Email:      query.Email,
+		Status:     query.Status,
+		Attributes: query.Attributes,
+		Page:       query.Page,
+		PageSize:   query.PageSize,
+	})
+	if err != nil {
+		span.EndWithError(err)
+		writeError(c, h.log, err)
+		return
+	}
+
+	writeUserPage(c, fields, users, total, query.Page, query.PageSize)
+}
@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"example.com/classic/internal/handler/request"
+	"example.com/classic/internal/service"
+	"example.com/classic/internal/service/dto"
+	"example.com/classic/pkg/logger"
+	"example.com/classic/pkg/response"
+	"example.com/classic/pkg/tracer"
+	"github.com/gin-gonic/gin"
+)
+
+// UserBatchHandler HTTP handler for bulk user actions
+type UserBatchHandler struct {
+	batchService service.UserBatchService
+	log          logger.Logger
+}
+
+// NewUserBatchHandler creates user batch handler instance
+func NewUserBatchHandler(batchService service.UserBatchService, log logger.Logger) *UserBatchHandler {
+	return &UserBatchHandler{
+		batchService: batchService,
+		log:          log,
+	}
+}
+
+// BatchChangeStatus changes the status of many users
+// @Summary Batch change user status
+// @Description Change the status of users selected by ids or by List filter. Small batches return per-user results; large batches return 202 with a job to poll
+// @Tags User Management
+// @Accept json
+// @Produce json
+// @Param request body request.BatchChangeStatusRequest true "target users and status"
+// @Success 200 {object} response.Response{data=dto.BatchOutcome}
+// @Success 202 {object} response.Response{data=dto.BatchOutcome}
+// @Failure 400 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/users:batchChangeStatus [post]
+func (h *UserBatchHandler) BatchChangeStatus(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Handler span
+	span, ctx := tracer.StartSpan(ctx, h.log, "handler:BatchChangeStatus")
+	defer span.End()
+
+	var req request.BatchChangeStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Warn(ctx, "invalid request body", logger.Err(err))
+		writeBodyError(c, &req, err)
+		return
+	}
+
+	h.log.Info(ctx, "batch change status request received",
+		logger.Int("ids", len(req.IDs)),
+		logger.Bool("has_filter", req.Filter != nil),
+		logger.String("new_status", string(req.Status)))
+
+	outcome, err := h.batchService.ChangeStatus(ctx, toBatchSelector(req.IDs, req.Filter), req.Status)
+	if err != nil {
+		span.EndWithError(err)
+		writeError(c, h.log, err)
+		return
+	}
+
+	h.writeOutcome(c, outcome)
+}
+
+// BatchDelete deletes many users
+// @Summary Batch delete users
+// @Description Delete users selected by ids or by List filter. Small batches return per-user results; large batches return 202 with a job to poll
+// @Tags User Management
+// @Accept json
+// @Produce json
+// @Param request body request.BatchDeleteRequest true "target users"
+// @Success 200 {object} response.Response{data=dto.BatchOutcome}
+// @Success 202 {object} response.Response{data=dto.BatchOutcome}
+// @Failure 400 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/users:batchDelete [post]
+func (h *UserBatchHandler) BatchDelete(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Handler span
+	span, ctx := tracer.StartSpan(ctx, h.log, "handler:BatchDelete")
+	defer span.End()
+
+	var req request.BatchDeleteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.log.Warn(ctx, "invalid request body", logger.Err(err))
+		writeBodyError(c, &req, err)
+		return
+	}
+
+	h.log.Info(ctx, "batch delete request received",
+		logger.Int("ids", len(req.IDs)),
+		logger.Bool("has_filter", req.Filter != nil))
+
+	outcome, err := h.batchService.Delete(ctx, toBatchSelector(req.IDs, req.Filter))
+	if err != nil {
+		span.EndWithError(err)
+		writeError(c, h.log, err)
+		return
+	}
+
+	h.writeOutcome(c, outcome)
+}
+
+// GetJob returns the progress of an asynchronous batch job
+// @Summary Get batch job
+// @Description Poll the state and progress of an asynchronous batch job
+// @Tags User Management
+// @Produce json
+// @Param id path string true "Job ID"
+// @Success 200 {object} response.Response{data=dto.BatchJob}
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/batchJobs/{id} [get]
+func (h *UserBatchHandler) GetJob(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Handler span
+	span, ctx := tracer.StartSpan(ctx, h.log, "handler:GetBatchJob")
+	defer span.End()
+
+	job, err := h.batchService.GetJob(ctx, c.Param("id"))
+	if err != nil {
+		span.EndWithError(err)
+		writeError(c, h.log, err)
+		return
+	}
+
+	response.Success(c, job)
+}
+
+// writeOutcome responds 200 with the results, or 202 when the batch was queued
+func (h *UserBatchHandler) writeOutcome(c *gin.Context, outcome *dto.BatchOutcome) {
+	if outcome.Job != nil {
+		c.Header("Location", "/api/v1/batchJobs/"+outcome.Job.ID)
+		response.Accepted(c, "success.batch_accepted", outcome)
+		return
+	}
+	response.Success(c, outcome)
+}
+
+// toBatchSelector converts request targets to a service selector
+func toBatchSelector(ids []int, filter *request.BatchFilter) *dto.BatchSelector {
+	selector := &dto.BatchSelector{IDs: ids}
+	if filter != nil {
+		selector.Filter = &dto.UserQueryParams{
+			ID:         filter.ID,
+			Name:       filter.Name,
+			Email:      filter.Email,
+			Status:     filter.Status,
+			Attributes: filter.Attributes,
+		}
+	}
+	return selector
+}
@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"example.com/classic/api/grpc/pb"
+	"example.com/classic/pkg/errors"
+	"example.com/classic/pkg/logger"
+	"example.com/classic/pkg/response"
+	"github.com/gin-gonic/gin"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// UserGateway serves the UserService methods annotated in api/proto/user.proto as REST (grpc-gateway),
+// calling the gRPC implementation in-process. Routes are mounted on gin so that the HTTP middleware
+// (tenancy, limits, idempotency, metrics) still applies.
+type UserGateway struct {
+	mux *runtime.ServeMux
+	log logger.Logger
+}
+
+// ginContextKey carries the gin context through the gateway to its error handler
+type ginContextKey struct{}
+
+// NewUserGateway creates the REST gateway of a UserService implementation
+func NewUserGateway(server pb.UserServiceServer, log logger.Logger) (*UserGateway, error) {
+	g := &UserGateway{log: log}
+	g.mux = runtime.NewServeMux(
+		// proto 字段名 (snake_case) 并输出零值，与 gin 处理器一样包装为统一响应格式
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, &envelopeMarshaler{JSONPb: runtime.JSONPb{
+			MarshalOptions:   protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true},
+			UnmarshalOptions: protojson.UnmarshalOptions{DiscardUnknown: true},
+		}}),
+		runtime.WithMarshalerOption(response.ProtobufContentType, &runtime.ProtoMarshaller{}),
+		runtime.WithErrorHandler(g.handleError),
+	)
+	if err := pb.RegisterUserServiceHandlerServer(context.Background(), g.mux, server); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// Handle forwards a gin request to the gateway
+func (g *UserGateway) Handle(c *gin.Context) {
+	ctx := context.WithValue(c.Request.Context(), ginContextKey{}, c)
+	g.mux.ServeHTTP(c.Writer, c.Request.WithContext(ctx))
+}
+
+// handleError writes gateway errors like the gin handlers do (status codes, envelope or problem+json, i18n)
+func (g *UserGateway) handleError(ctx context.Context, mux *runtime.ServeMux, marshaler runtime.Marshaler, w http.ResponseWriter, r *http.Request, err error) {
+	c, ok := r.Context().Value(ginContextKey{}).(*gin.Context)
+	if !ok {
+		runtime.DefaultHTTPErrorHandler(ctx, mux, marshaler, w, r, err)
+		return
+	}
+
+	// 服务实现返回业务错误；gRPC status 来自网关自身 (请求体解码、路径与查询参数)
+	if st, isStatus := status.FromError(err); isStatus {
+		switch st.Code() {
+		case codes.InvalidArgument:
+			err = errors.WrapInvalidParam(err, "invalid request: "+st.Message())
+		default:
+			err = errors.WrapInternalError(err, st.Message())
+		}
+	}
+	writeError(c, g.log, err)
+}
+
+// envelopeMarshaler wraps protobuf responses in response.Response
+type envelopeMarshaler struct {
+	runtime.JSONPb
+}
+
+// Marshal implements runtime.Marshaler
+func (m *envelopeMarshaler) Marshal(v any) ([]byte, error) {
+	data, err := m.JSONPb.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := v.(proto.Message); !ok {
+		return data, nil
+	}
+	return json.Marshal(response.Response{
+		Code: int(errors.ErrCodeSuccess),
+		Msg:  "success",
+		Data: json.RawMessage(data),
+	})
+}
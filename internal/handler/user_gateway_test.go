@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"example.com/classic/api/grpc/pb"
+	"example.com/classic/internal/domain"
+	"example.com/classic/internal/service/dto"
+	"example.com/classic/pkg/errors"
+	"example.com/classic/pkg/logger"
+	"example.com/classic/pkg/response"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestUserGateway(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	log := logger.New("test", "error", false)
+
+	mockService := new(MockUserService)
+	gateway, err := NewUserGateway(NewUserGRPCHandler(mockService, nil, nil, log), log)
+	require.NoError(t, err)
+
+	engine := gin.New()
+	engine.POST("/api/v1/users", gateway.Handle)
+	engine.GET("/api/v1/users/:id", gateway.Handle)
+	engine.PATCH("/api/v1/users/:id/status", gateway.Handle)
+
+	serve := func(method, target, body string, header http.Header) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		for name, values := range header {
+			req.Header[name] = values
+		}
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("register", func(t *testing.T) {
+		mockService.On("Register", mock.Anything, &dto.RegisterParams{
+			Name: "Test User", Email: "test@example.com", Password: "password123",
+			Attributes: map[string]interface{}{"plan": "pro"},
+		}).Return(goldenUser(1, "Test User", "test@example.com"), nil).Once()
+
+		w := serve("POST", "/api/v1/users", `{"name":"Test User","email":"test@example.com","password":"password123","attributes":{"plan":"pro"},"unknown":1}`, nil)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp struct {
+			Code int                    `json:"code"`
+			Msg  string                 `json:"msg"`
+			Data map[string]interface{} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, 0, resp.Code)
+		// The proto JSON mapping is the contract: proto field names and enum value names
+		assert.Equal(t, "STATUS_ACTIVE", resp.Data["status"])
+		assert.Equal(t, "2024-03-01T09:30:00Z", resp.Data["created_at"])
+		assert.Equal(t, "test@example.com", resp.Data["email"])
+	})
+
+	t.Run("change status from path and body", func(t *testing.T) {
+		mockService.On("ChangeStatus", mock.Anything, 7, domain.StatusBanned).Return(nil).Once()
+		mockService.On("GetByID", mock.Anything, 7).Return(goldenUser(7, "Test User", "test@example.com"), nil).Once()
+
+		w := serve("PATCH", "/api/v1/users/7/status", `{"status":"STATUS_BANNED"}`, nil)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("erased user", func(t *testing.T) {
+		erased := goldenUser(8, "Test User", "test@example.com")
+		pseudonym, err := domain.NewName("erased-8")
+		require.NoError(t, err)
+		email, err := domain.NewEmail("erased-8@example.invalid")
+		require.NoError(t, err)
+		require.NoError(t, erased.Erase(*pseudonym, *email))
+		mockService.On("GetByID", mock.Anything, 8).Return(erased, nil).Once()
+
+		w := serve("GET", "/api/v1/users/8", "", nil)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"status":"STATUS_ERASED"`)
+	})
+
+	t.Run("protobuf", func(t *testing.T) {
+		mockService.On("GetByID", mock.Anything, 1).Return(goldenUser(1, "Test User", "test@example.com"), nil).Once()
+
+		w := serve("GET", "/api/v1/users/1", "", http.Header{"Accept": {response.ProtobufContentType}})
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var user pb.UserResponse
+		require.NoError(t, proto.Unmarshal(w.Body.Bytes(), &user))
+		assert.Equal(t, int32(1), user.Id)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		mockService.On("GetByID", mock.Anything, 2).Return(nil, errors.ErrUserNotFound).Once()
+
+		w := serve("GET", "/api/v1/users/2", "", nil)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		var resp map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, float64(errors.ErrCodeUserNotFound), resp["code"])
+	})
+
+	t.Run("invalid path parameter", func(t *testing.T) {
+		w := serve("GET", "/api/v1/users/abc", "", http.Header{"Accept": {response.ProblemContentType}})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		var problem response.Problem
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+		assert.Equal(t, int(errors.ErrCodeInvalidParam), problem.Code)
+	})
+
+	mockService.AssertExpectations(t)
+}
+
+func TestPBStatus_RoundTrip(t *testing.T) {
+	for _, status := range []domain.Status{domain.StatusActive, domain.StatusInactive, domain.StatusBanned, domain.StatusErased} {
+		assert.NotEqual(t, pb.Status_STATUS_UNSPECIFIED, toPBStatus(status), status)
+		assert.Equal(t, status, fromPBStatus(toPBStatus(status)))
+	}
+}
@@ -2,27 +2,34 @@ package handler
 
 import (
 	"context"
+	"encoding/json"
+	"io"
 
 	"example.com/classic/api/grpc/pb"
 	"example.com/classic/internal/domain"
 	"example.com/classic/internal/service"
 	"example.com/classic/internal/service/dto"
 	"example.com/classic/pkg/logger"
+	"google.golang.org/protobuf/types/known/structpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 // UserGRPCHandler implements pb.UserServiceServer
 type UserGRPCHandler struct {
 	pb.UnimplementedUserServiceServer
-	userSvc service.UserService
-	log     logger.Logger
+	userSvc    service.UserService
+	batchSvc   service.UserBatchService
+	historySvc service.UserHistoryService
+	log        logger.Logger
 }
 
 // NewUserGRPCHandler creates a new gRPC user handler
-func NewUserGRPCHandler(userSvc service.UserService, log logger.Logger) pb.UserServiceServer {
+func NewUserGRPCHandler(userSvc service.UserService, batchSvc service.UserBatchService, historySvc service.UserHistoryService, log logger.Logger) pb.UserServiceServer {
 	return &UserGRPCHandler{
-		userSvc: userSvc,
-		log:     log,
+		userSvc:    userSvc,
+		batchSvc:   batchSvc,
+		historySvc: historySvc,
+		log:        log,
 	}
 }
 
@@ -31,9 +38,10 @@ func (h *UserGRPCHandler) Register(ctx context.Context, req *pb.RegisterRequest)
 	h.log.Debug(ctx, "gRPC register request", logger.F("email", req.Email))
 
 	user, err := h.userSvc.Register(ctx, &dto.RegisterParams{
-		Name:     req.Name,
-		Email:    req.Email,
-		Password: req.Password,
+		Name:       req.Name,
+		Email:      req.Email,
+		Password:   req.Password,
+		Attributes: fromPBAttributes(req.Attributes),
 	})
 	if err != nil {
 		return nil, err
@@ -66,9 +74,12 @@ func (h *UserGRPCHandler) Update(ctx context.Context, req *pb.UpdateRequest) (*p
 		updateParams.Email = req.Email
 	}
 	if req.Status != nil {
-		status := domain.Status(req.Status.String())
+		status := fromPBStatus(*req.Status)
 		updateParams.Status = &status
 	}
+	if req.Attributes != nil {
+		updateParams.Attributes = fromPBAttributes(req.Attributes)
+	}
 
 	user, err := h.userSvc.Update(ctx, int(req.Id), updateParams)
 	if err != nil {
@@ -93,33 +104,14 @@ func (h *UserGRPCHandler) Delete(ctx context.Context, req *pb.DeleteRequest) (*p
 func (h *UserGRPCHandler) List(ctx context.Context, req *pb.ListRequest) (*pb.ListResponse, error) {
 	h.log.Debug(ctx, "gRPC list request", logger.F("page", req.Page))
 
-	queryParams := &dto.UserQueryParams{
-		Page:     int(req.Page),
-		PageSize: int(req.PageSize),
-	}
-	if req.Id != nil {
-		id := int(*req.Id)
-		queryParams.ID = &id
-	}
-	if req.Name != nil {
-		queryParams.Name = req.Name
-	}
-	if req.Email != nil {
-		queryParams.Email = req.Email
-	}
-	if req.Status != nil {
-		status := domain.Status(req.Status.String())
-		queryParams.Status = &status
-	}
-
-	users, total, err := h.userSvc.List(ctx, queryParams)
+	users, total, err := h.userSvc.List(ctx, toQueryParams(req))
 	if err != nil {
 		return nil, err
 	}
 
 	pbUsers := make([]*pb.User, len(users))
 	for i, user := range users {
-		pbUsers[i] = h.toUser(user)
+		pbUsers[i] = toPBUser(user)
 	}
 
 	return &pb.ListResponse{
@@ -134,7 +126,7 @@ func (h *UserGRPCHandler) ChangeStatus(ctx context.Context, req *pb.ChangeStatus
 		logger.F("id", req.Id),
 		logger.F("status", req.Status))
 
-	status := domain.Status(req.Status.String())
+	status := fromPBStatus(req.Status)
 	if err := h.userSvc.ChangeStatus(ctx, int(req.Id), status); err != nil {
 		return nil, err
 	}
@@ -147,38 +139,333 @@ func (h *UserGRPCHandler) ChangeStatus(ctx context.Context, req *pb.ChangeStatus
 	return h.toUserResponse(user), nil
 }
 
+// ImportUsers imports users from a client stream; options are read from the first message
+func (h *UserGRPCHandler) ImportUsers(stream pb.UserService_ImportUsersServer) error {
+	ctx := stream.Context()
+	h.log.Debug(ctx, "gRPC import users request")
+
+	first, err := stream.Recv()
+	if err == io.EOF {
+		return stream.SendAndClose(&pb.ImportUsersResponse{})
+	}
+	if err != nil {
+		return err
+	}
+
+	opts := dto.ImportOptions{
+		DryRun: first.GetOptions().GetDryRun(),
+		Upsert: first.GetOptions().GetUpsert(),
+	}
+	source := &grpcImportSource{stream: stream, pending: first}
+
+	var results []*pb.ImportRowResult
+	summary, err := h.userSvc.Import(ctx, source, opts, func(result *dto.ImportRowResult) error {
+		results = append(results, &pb.ImportRowResult{
+			Line:   int32(result.Line),
+			Email:  result.Email,
+			Action: string(result.Action),
+			UserId: int32(result.UserID),
+			Code:   int32(result.Code),
+			Error:  result.Error,
+		})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return stream.SendAndClose(&pb.ImportUsersResponse{
+		Results:   results,
+		Total:     int32(summary.Total),
+		Created:   int32(summary.Created),
+		Updated:   int32(summary.Updated),
+		Unchanged: int32(summary.Unchanged),
+		Failed:    int32(summary.Failed),
+		DryRun:    summary.DryRun,
+	})
+}
+
+// ExportUsers streams users matching the List filters
+func (h *UserGRPCHandler) ExportUsers(req *pb.ListRequest, stream pb.UserService_ExportUsersServer) error {
+	ctx := stream.Context()
+	h.log.Debug(ctx, "gRPC export users request")
+
+	return h.userSvc.Export(ctx, toQueryParams(req), func(user *domain.User) error {
+		return stream.Send(toPBUser(user))
+	})
+}
+
+// BatchChangeStatus changes the status of users selected by ids or filter
+func (h *UserGRPCHandler) BatchChangeStatus(ctx context.Context, req *pb.BatchChangeStatusRequest) (*pb.BatchResponse, error) {
+	h.log.Debug(ctx, "gRPC batch change status request", logger.F("status", req.Status))
+
+	outcome, err := h.batchSvc.ChangeStatus(ctx, toBatchSelectorPB(req.Selector), fromPBStatus(req.Status))
+	if err != nil {
+		return nil, err
+	}
+
+	return toBatchResponse(outcome), nil
+}
+
+// BatchDelete deletes users selected by ids or filter
+func (h *UserGRPCHandler) BatchDelete(ctx context.Context, req *pb.BatchDeleteRequest) (*pb.BatchResponse, error) {
+	h.log.Debug(ctx, "gRPC batch delete request")
+
+	outcome, err := h.batchSvc.Delete(ctx, toBatchSelectorPB(req.Selector))
+	if err != nil {
+		return nil, err
+	}
+
+	return toBatchResponse(outcome), nil
+}
+
+// GetBatchJob returns the progress of an asynchronous batch job
+func (h *UserGRPCHandler) GetBatchJob(ctx context.Context, req *pb.GetBatchJobRequest) (*pb.BatchJob, error) {
+	h.log.Debug(ctx, "gRPC get batch job request", logger.F("id", req.Id))
+
+	job, err := h.batchSvc.GetJob(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return toPBBatchJob(job), nil
+}
+
+// GetUserHistory returns a page of the stored domain events of a user
+func (h *UserGRPCHandler) GetUserHistory(ctx context.Context, req *pb.GetUserHistoryRequest) (*pb.GetUserHistoryResponse, error) {
+	h.log.Debug(ctx, "gRPC get user history request", logger.F("id", req.Id))
+
+	history, err := h.historySvc.GetHistory(ctx, int(req.Id), int(req.AfterSequence), int(req.Limit))
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]*pb.UserEvent, len(history.Events))
+	for i, event := range history.Events {
+		events[i] = toPBUserEvent(event)
+	}
+	return &pb.GetUserHistoryResponse{
+		UserId:       int32(history.UserID),
+		Events:       events,
+		NextSequence: int32(history.NextSequence),
+	}, nil
+}
+
+// grpcImportSource adapts the ImportUsers client stream to dto.UserImportSource
+type grpcImportSource struct {
+	stream  pb.UserService_ImportUsersServer
+	pending *pb.ImportUsersRequest
+	line    int
+}
+
+// Next returns the next row from the stream; messages without a row are skipped
+func (s *grpcImportSource) Next(ctx context.Context) (*dto.ImportRow, error) {
+	for {
+		msg := s.pending
+		s.pending = nil
+		if msg == nil {
+			var err error
+			if msg, err = s.stream.Recv(); err != nil {
+				return nil, err
+			}
+		}
+		if msg.Row == nil {
+			continue
+		}
+
+		s.line++
+		return &dto.ImportRow{
+			Line:           s.line,
+			Name:           msg.Row.Name,
+			Email:          msg.Row.Email,
+			Password:       msg.Row.Password,
+			HashedPassword: msg.Row.PasswordHash,
+			Attributes:     fromPBAttributes(msg.Row.Attributes),
+		}, nil
+	}
+}
+
+// toQueryParams converts a list request to service query params
+func toQueryParams(req *pb.ListRequest) *dto.UserQueryParams {
+	queryParams := &dto.UserQueryParams{
+		Page:     int(req.Page),
+		PageSize: int(req.PageSize),
+	}
+	if req.Id != nil {
+		id := int(*req.Id)
+		queryParams.ID = &id
+	}
+	if req.Name != nil {
+		queryParams.Name = req.Name
+	}
+	if req.Email != nil {
+		queryParams.Email = req.Email
+	}
+	if req.Status != nil {
+		status := fromPBStatus(*req.Status)
+		queryParams.Status = &status
+	}
+	if len(req.Attributes) > 0 {
+		queryParams.Attributes = req.Attributes
+	}
+	return queryParams
+}
+
+// toBatchSelectorPB converts a protobuf batch selector to a service selector
+func toBatchSelectorPB(sel *pb.BatchSelector) *dto.BatchSelector {
+	selector := &dto.BatchSelector{}
+	if sel == nil {
+		return selector
+	}
+	for _, id := range sel.Ids {
+		selector.IDs = append(selector.IDs, int(id))
+	}
+	if sel.Filter != nil {
+		selector.Filter = toQueryParams(sel.Filter)
+	}
+	return selector
+}
+
+// toBatchResponse converts a batch outcome to protobuf
+func toBatchResponse(outcome *dto.BatchOutcome) *pb.BatchResponse {
+	return &pb.BatchResponse{
+		Result: toPBBatchResult(outcome.Result),
+		Job:    toPBBatchJob(outcome.Job),
+	}
+}
+
+// toPBBatchJob converts a batch job to protobuf
+func toPBBatchJob(job *dto.BatchJob) *pb.BatchJob {
+	if job == nil {
+		return nil
+	}
+	return &pb.BatchJob{
+		Id:     job.ID,
+		State:  string(job.State),
+		Result: toPBBatchResult(job.Result),
+		Error:  job.Error,
+	}
+}
+
+// toPBBatchResult converts a batch result to protobuf
+func toPBBatchResult(result *dto.BatchResult) *pb.BatchResult {
+	if result == nil {
+		return nil
+	}
+	items := make([]*pb.BatchItemResult, len(result.Items))
+	for i, item := range result.Items {
+		items[i] = &pb.BatchItemResult{
+			UserId:  int32(item.UserID),
+			Success: item.Success,
+			Code:    int32(item.Code),
+			Error:   item.Error,
+		}
+	}
+	return &pb.BatchResult{
+		Operation: string(result.Operation),
+		Total:     int32(result.Total),
+		Processed: int32(result.Processed),
+		Succeeded: int32(result.Succeeded),
+		Failed:    int32(result.Failed),
+		Items:     items,
+	}
+}
+
 // toPBStatus converts domain.Status to pb.Status
 func toPBStatus(s domain.Status) pb.Status {
 	switch s {
 	case domain.StatusActive:
-		return pb.Status_ACTIVE
+		return pb.Status_STATUS_ACTIVE
 	case domain.StatusInactive:
-		return pb.Status_INACTIVE
+		return pb.Status_STATUS_INACTIVE
+	case domain.StatusBanned:
+		return pb.Status_STATUS_BANNED
+	case domain.StatusErased:
+		return pb.Status_STATUS_ERASED
 	default:
-		return pb.Status_UNSPECIFIED
+		return pb.Status_STATUS_UNSPECIFIED
+	}
+}
+
+// fromPBStatus converts pb.Status to domain.Status
+func fromPBStatus(s pb.Status) domain.Status {
+	switch s {
+	case pb.Status_STATUS_ACTIVE:
+		return domain.StatusActive
+	case pb.Status_STATUS_INACTIVE:
+		return domain.StatusInactive
+	case pb.Status_STATUS_BANNED:
+		return domain.StatusBanned
+	case pb.Status_STATUS_ERASED:
+		return domain.StatusErased
+	default:
+		return domain.Status("")
+	}
+}
+
+// fromPBAttributes converts a protobuf Struct to attribute values; null values are kept as nil
+func fromPBAttributes(attrs *structpb.Struct) map[string]interface{} {
+	if attrs == nil {
+		return nil
+	}
+	return attrs.AsMap()
+}
+
+// toPBAttributes converts user attributes to a protobuf Struct
+func toPBAttributes(attrs domain.Attributes) *structpb.Struct {
+	if len(attrs) == 0 {
+		return nil
+	}
+	// Attribute values are normalized by the domain to string/int64/float64/bool
+	st, err := structpb.NewStruct(attrs)
+	if err != nil {
+		return nil
+	}
+	return st
+}
+
+// toPBUserEvent converts a history event to protobuf
+func toPBUserEvent(event *dto.UserEvent) *pb.UserEvent {
+	var payload *structpb.Struct
+	var values map[string]interface{}
+	if err := json.Unmarshal(event.Payload, &values); err == nil {
+		payload, _ = structpb.NewStruct(values)
+	}
+	return &pb.UserEvent{
+		Sequence: int32(event.Sequence),
+		Type:     event.Type,
+		Payload:  payload,
+		Metadata: &pb.EventMetadata{
+			TraceId:   event.Metadata.TraceID,
+			RequestId: event.Metadata.RequestID,
+			Actor:     event.Metadata.Actor,
+		},
+		OccurredAt: timestamppb.New(event.OccurredAt),
 	}
 }
 
 // toUserResponse converts domain user to protobuf response
 func (h *UserGRPCHandler) toUserResponse(user *domain.User) *pb.UserResponse {
 	return &pb.UserResponse{
-		Id:        int32(user.ID()),
-		Name:      user.Name().String(),
-		Email:     user.Email().String(),
-		Status:    toPBStatus(user.Status()),
-		CreatedAt: timestamppb.New(user.CreatedAt()),
-		UpdatedAt: timestamppb.New(user.UpdatedAt()),
+		Id:         int32(user.ID()),
+		Name:       user.Name().String(),
+		Email:      user.Email().String(),
+		Status:     toPBStatus(user.Status()),
+		Attributes: toPBAttributes(user.Attributes()),
+		CreatedAt:  timestamppb.New(user.CreatedAt()),
+		UpdatedAt:  timestamppb.New(user.UpdatedAt()),
 	}
 }
 
-// toUser converts domain user to protobuf user
-func (h *UserGRPCHandler) toUser(user *domain.User) *pb.User {
+// toPBUser converts domain user to protobuf user
+func toPBUser(user *domain.User) *pb.User {
 	return &pb.User{
-		Id:        int32(user.ID()),
-		Name:      user.Name().String(),
-		Email:     user.Email().String(),
-		Status:    toPBStatus(user.Status()),
-		CreatedAt: timestamppb.New(user.CreatedAt()),
-		UpdatedAt: timestamppb.New(user.UpdatedAt()),
+		Id:         int32(user.ID()),
+		Name:       user.Name().String(),
+		Email:      user.Email().String(),
+		Status:     toPBStatus(user.Status()),
+		Attributes: toPBAttributes(user.Attributes()),
+		CreatedAt:  timestamppb.New(user.CreatedAt()),
+		UpdatedAt:  timestamppb.New(user.UpdatedAt()),
 	}
 }
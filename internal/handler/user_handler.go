@@ -1,8 +1,11 @@
 package handler
 
 import (
+	"context"
 	"strconv"
+	"strings"
 
+	"example.com/classic/api/grpc/pb"
 	"example.com/classic/internal/domain"
 	"example.com/classic/internal/handler/request"
 	"example.com/classic/internal/service"
@@ -32,11 +35,13 @@ func NewUserHandler(userService service.UserService, log logger.Logger) *UserHan
 // @Summary User registration
 // @Description Create new user account
 // @Tags User Management
-// @Accept json
-// @Produce json
+// @Accept json,application/msgpack,application/x-protobuf
+// @Produce json,application/msgpack,application/x-protobuf
 // @Param user body request.CreateUserRequest true "user registration info"
-// @Success 200 {object} response.Response{data=domain.User}
+// @Param fields query string false "Comma-separated user fields to return, e.g. id,email"
+// @Success 200 {object} response.Response{data=presenter.UserV1}
 // @Failure 400 {object} response.Response
+// @Failure 415 {object} response.Response
 // @Failure 409 {object} response.Response
 // @Failure 500 {object} response.Response
 // @Router /api/v1/users [post]
@@ -50,9 +55,15 @@ func (h *UserHandler) Register(c *gin.Context) {
 	h.log.Info(ctx, "  user registration request received")
 
 	var req request.CreateUserRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+	var pbReq pb.RegisterRequest
+	if err := bindBody(c, &req, &pbReq, func() { req = createUserRequestFromPB(&pbReq) }); err != nil {
 		h.log.Warn(ctx, "invalid request body", logger.Err(err))
-		response.InvalidParam(c, "invalid request body: "+err.Error())
+		writeBodyError(c, &req, err)
+		return
+	}
+
+	fields, ok := userFields(c)
+	if !ok {
 		return
 	}
 
@@ -61,9 +72,10 @@ func (h *UserHandler) Register(c *gin.Context) {
 		logger.String("name", req.Name))
 
 	user, err := h.userService.Register(ctx, &dto.RegisterParams{
-		Name:     req.Name,
-		Email:    req.Email,
-		Password: req.Password,
+		Name:       req.Name,
+		Email:      req.Email,
+		Password:   req.Password,
+		Attributes: req.Attributes,
 	})
 	if err != nil {
 		span.EndWithError(err)
@@ -72,7 +84,7 @@ func (h *UserHandler) Register(c *gin.Context) {
 	}
 
 	h.log.Info(ctx, "user registration successful", logger.Int("user_id", user.ID()))
-	response.SuccessWithMsg(c, "user registered successfully", user)
+	response.SuccessWithMsg(c, "success.user_registered", userBody(c, fields, user))
 }
 
 // GetByID 根据ID获取用户
@@ -80,9 +92,10 @@ func (h *UserHandler) Register(c *gin.Context) {
 // @Description 根据用户ID获取用户详细信息
 // @Tags 用户管理
 // @Accept json
-// @Produce json
+// @Produce json,application/msgpack,application/x-protobuf
 // @Param id path int true "用户ID"
-// @Success 200 {object} response.Response{data=domain.User}
+// @Param fields query string false "Comma-separated user fields to return, e.g. id,email"
+// @Success 200 {object} response.Response{data=presenter.UserV1}
 // @Failure 400 {object} response.Response
 // @Failure 404 {object} response.Response
 // @Failure 500 {object} response.Response
@@ -102,6 +115,11 @@ func (h *UserHandler) GetByID(c *gin.Context) {
 		return
 	}
 
+	fields, ok := userFields(c)
+	if !ok {
+		return
+	}
+
 	h.log.Debug(ctx, "getting user by id", logger.Int("user_id", id))
 
 	user, err := h.userService.GetByID(ctx, id)
@@ -112,19 +130,21 @@ func (h *UserHandler) GetByID(c *gin.Context) {
 	}
 
 	h.log.Debug(ctx, "user retrieved successfully", logger.Int("user_id", id))
-	response.Success(c, user)
+	response.Success(c, userBody(c, fields, user))
 }
 
 // Update updates user info
 // @Summary Update user info
 // @Description Update specified user's info
 // @Tags User Management
-// @Accept json
-// @Produce json
+// @Accept json,application/msgpack,application/x-protobuf
+// @Produce json,application/msgpack,application/x-protobuf
 // @Param id path int true "User ID"
 // @Param user body request.UpdateUserRequest true "user update info"
-// @Success 200 {object} response.Response{data=domain.User}
+// @Param fields query string false "Comma-separated user fields to return, e.g. id,email"
+// @Success 200 {object} response.Response{data=presenter.UserV1}
 // @Failure 400 {object} response.Response
+// @Failure 415 {object} response.Response
 // @Failure 404 {object} response.Response
 // @Failure 409 {object} response.Response
 // @Failure 500 {object} response.Response
@@ -145,9 +165,15 @@ func (h *UserHandler) Update(c *gin.Context) {
 	}
 
 	var req request.UpdateUserRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+	var pbReq pb.UpdateRequest
+	if err := bindBody(c, &req, &pbReq, func() { req = updateUserRequestFromPB(&pbReq) }); err != nil {
 		h.log.Warn(ctx, "invalid request body", logger.Err(err))
-		response.InvalidParam(c, "invalid request body: "+err.Error())
+		writeBodyError(c, &req, err)
+		return
+	}
+
+	fields, ok := userFields(c)
+	if !ok {
 		return
 	}
 
@@ -158,9 +184,10 @@ func (h *UserHandler) Update(c *gin.Context) {
 		logger.Bool("has_status", req.Status != nil))
 
 	user, err := h.userService.Update(ctx, id, &dto.UpdateParams{
-		Name:   req.Name,
-		Email:  req.Email,
-		Status: req.Status,
+		Name:       req.Name,
+		Email:      req.Email,
+		Status:     req.Status,
+		Attributes: req.Attributes,
 	})
 	if err != nil {
 		span.EndWithError(err)
@@ -169,7 +196,7 @@ func (h *UserHandler) Update(c *gin.Context) {
 	}
 
 	h.log.Info(ctx, "user updated successfully", logger.Int("user_id", id))
-	response.SuccessWithMsg(c, "user updated successfully", user)
+	response.SuccessWithMsg(c, "success.user_updated", userBody(c, fields, user))
 }
 
 // Delete 删除用户
@@ -208,7 +235,7 @@ func (h *UserHandler) Delete(c *gin.Context) {
 	}
 
 	h.log.Info(ctx, "user deleted successfully", logger.Int("user_id", id))
-	response.SuccessWithMsg(c, "user deleted successfully", nil)
+	response.SuccessWithMsg(c, "success.user_deleted", nil)
 }
 
 // List queries user list
@@ -216,13 +243,15 @@ func (h *UserHandler) Delete(c *gin.Context) {
 // @Description Paginated query of user list with filtering
 // @Tags User Management
 // @Accept json
-// @Produce json
+// @Produce json,application/msgpack,application/x-protobuf
 // @Param page query int false "Page number" default(1)
 // @Param page_size query int false "Page size" default(20)
 // @Param name query string false "User name"
 // @Param email query string false "User email"
 // @Param status query string false "User status"
-// @Success 200 {object} response.Response{data=response.PageResponse{data=[]domain.User}}
+// @Param attr.{name} query string false "Custom attribute filter, e.g. attr.plan=pro"
+// @Param fields query string false "Comma-separated user fields to return, e.g. id,email"
+// @Success 200 {object} response.Response{data=response.PageResponse{data=[]presenter.UserV1}}
 // @Failure 400 {object} response.Response
 // @Failure 500 {object} response.Response
 // @Router /api/v1/users [get]
@@ -234,36 +263,10 @@ func (h *UserHandler) List(c *gin.Context) {
 	defer span.End()
 
 	// Parse query parameters
-	query := &request.UserQuery{
-		Page:     1,
-		PageSize: 20,
-	}
-
-	if pageStr := c.Query("page"); pageStr != "" {
-		if page, err := strconv.Atoi(pageStr); err == nil && page > 0 {
-			query.Page = page
-		}
-	}
-
-	if pageSizeStr := c.Query("page_size"); pageSizeStr != "" {
-		if pageSize, err := strconv.Atoi(pageSizeStr); err == nil && pageSize > 0 && pageSize <= 100 {
-			query.PageSize = pageSize
-		}
-	}
-
-	if name := c.Query("name"); name != "" {
-		query.Name = &name
-	}
-
-	if email := c.Query("email"); email != "" {
-		query.Email = &email
-	}
-
-	if status := c.Query("status"); status != "" {
-		statusEnum := domain.Status(status)
-		if statusEnum.IsValid() {
-			query.Status = &statusEnum
-		}
+	query := parseUserQuery(c)
+	fields, ok := userFields(c)
+	if !ok {
+		return
 	}
 
 	h.log.Debug(ctx, "listing users",
@@ -273,12 +276,13 @@ func (h *UserHandler) List(c *gin.Context) {
 		logger.Bool("has_email_filter", query.Email != nil))
 
 	users, total, err := h.userService.List(ctx, &dto.UserQueryParams{
-		ID:       query.ID,
-		Name:     query.Name,
-		Email:    query.Email,
-		Status:   query.Status,
-		Page:     query.Page,
-		PageSize: query.PageSize,
+		ID:         query.ID,
+		Name:       query.Name,
+		Email:      query.Email,
+		Status:     query.Status,
+		Attributes: query.Attributes,
+		Page:       query.Page,
+		PageSize:   query.PageSize,
 	})
 	if err != nil {
 		span.EndWithError(err)
@@ -289,19 +293,20 @@ func (h *UserHandler) List(c *gin.Context) {
 	h.log.Debug(ctx, "users listed successfully",
 		logger.Int64("total", total),
 		logger.Int("count", len(users)))
-	response.SuccessWithPage(c, users, total, query.Page, query.PageSize)
+	writeUserPage(c, fields, users, total, query.Page, query.PageSize)
 }
 
 // ChangeStatus changes user status
 // @Summary Change user status
 // @Description Change specified user's status
 // @Tags User Management
-// @Accept json
+// @Accept json,application/msgpack,application/x-protobuf
 // @Produce json
 // @Param id path int true "User ID"
 // @Param status body request.ChangeStatusRequest true "status info"
 // @Success 200 {object} response.Response
 // @Failure 400 {object} response.Response
+// @Failure 415 {object} response.Response
 // @Failure 404 {object} response.Response
 // @Failure 500 {object} response.Response
 // @Router /api/v1/users/{id}/status [patch]
@@ -321,9 +326,10 @@ func (h *UserHandler) ChangeStatus(c *gin.Context) {
 	}
 
 	var req request.ChangeStatusRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+	var pbReq pb.ChangeStatusRequest
+	if err := bindBody(c, &req, &pbReq, func() { req = changeStatusRequestFromPB(&pbReq) }); err != nil {
 		h.log.Warn(ctx, "invalid request body", logger.Err(err))
-		response.InvalidParam(c, "invalid request body: "+err.Error())
+		writeBodyError(c, &req, err)
 		return
 	}
 
@@ -348,31 +354,105 @@ func (h *UserHandler) ChangeStatus(c *gin.Context) {
 	h.log.Info(ctx, "user status changed successfully",
 		logger.Int("user_id", id),
 		logger.String("status", string(status)))
-	response.SuccessWithMsg(c, "user status changed successfully", nil)
+	response.SuccessWithMsg(c, "success.user_status_changed", nil)
+}
+
+// attributeQueryPrefix prefixes custom attribute filters in the query string
+const attributeQueryPrefix = "attr."
+
+// parseUserQuery parses the List filters and pagination from the query string
+func parseUserQuery(c *gin.Context) *request.UserQuery {
+	query := &request.UserQuery{
+		Page:     1,
+		PageSize: 20,
+	}
+
+	if pageStr := c.Query("page"); pageStr != "" {
+		if page, err := strconv.Atoi(pageStr); err == nil && page > 0 {
+			query.Page = page
+		}
+	}
+
+	if pageSizeStr := c.Query("page_size"); pageSizeStr != "" {
+		if pageSize, err := strconv.Atoi(pageSizeStr); err == nil && pageSize > 0 && pageSize <= 100 {
+			query.PageSize = pageSize
+		}
+	}
+
+	if name := c.Query("name"); name != "" {
+		query.Name = &name
+	}
+
+	if email := c.Query("email"); email != "" {
+		query.Email = &email
+	}
+
+	if status := c.Query("status"); status != "" {
+		statusEnum := domain.Status(status)
+		if statusEnum.IsValid() {
+			query.Status = &statusEnum
+		}
+	}
+
+	// Custom attribute filters: attr.<name>=value
+	for key, values := range c.Request.URL.Query() {
+		name, ok := strings.CutPrefix(key, attributeQueryPrefix)
+		if !ok || name == "" || len(values) == 0 {
+			continue
+		}
+		if query.Attributes == nil {
+			query.Attributes = make(map[string]string)
+		}
+		query.Attributes[name] = values[0]
+	}
+
+	return query
 }
 
 // handleError handles errors uniformly
 func (h *UserHandler) handleError(c *gin.Context, err error) {
+	writeError(c, h.log, err)
+}
+
+// writeError logs the error and writes the matching HTTP response
+func writeError(c *gin.Context, log logger.Logger, err error) {
 	ctx := c.Request.Context()
 
 	// Log error with trace context
-	h.log.Error(ctx, "handler error", logger.Err(err))
+	log.Error(ctx, "handler error", logger.Err(err))
+
+	// 超过路由时限的请求 (包括因此失败的数据库调用) 统一返回 504
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		response.GatewayTimeout(c, errors.Wrap(err, errors.ErrCodeRequestTimeout, errors.ErrRequestTimeout.Message))
+		return
+	}
 
 	// Return appropriate response based on error type
 	if domainErr, ok := err.(*errors.Error); ok {
+		domainErr = domainFieldErrors(ctx, domainErr, err)
 		switch domainErr.Code {
-		case errors.ErrCodeInvalidParam:
+		case errors.ErrCodeInvalidParam, errors.ErrCodeTenantRequired:
 			response.BadRequest(c, domainErr)
-		case errors.ErrCodeNotFound:
+		case errors.ErrCodeNotFound, errors.ErrCodeUserNotFound, errors.ErrCodeTenantNotFound:
 			response.NotFound(c, domainErr)
-		case errors.ErrCodeConflict:
+		case errors.ErrCodeConflict, errors.ErrCodeUserAlreadyExists, errors.ErrCodeTenantAlreadyExists:
 			response.Conflict(c, domainErr)
+		case errors.ErrCodeUnprocessableEntity:
+			response.UnprocessableEntity(c, domainErr)
 		case errors.ErrCodeUnauthorized:
 			response.Unauthorized(c, domainErr)
-		case errors.ErrCodeForbidden:
+		case errors.ErrCodeForbidden, errors.ErrCodeTenantInactive:
 			response.Forbidden(c, domainErr)
 		case errors.ErrCodeTooManyRequest:
 			response.TooManyRequests(c, domainErr)
+		case errors.ErrCodeServiceUnavailable:
+			response.ServiceUnavailable(c, domainErr)
+		case errors.ErrCodeRequestTooLarge:
+			response.RequestEntityTooLarge(c, domainErr)
+		case errors.ErrCodeRequestTimeout:
+			response.GatewayTimeout(c, domainErr)
+		case errors.ErrCodeUnsupportedMediaType:
+			response.UnsupportedMediaType(c, domainErr)
 		default:
 			response.InternalServerError(c, domainErr)
 		}
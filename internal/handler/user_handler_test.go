@@ -4,19 +4,32 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
 	"time"
 
+	"example.com/classic/api/grpc/pb"
 	"example.com/classic/internal/domain"
 	"example.com/classic/internal/handler/request"
 	"example.com/classic/internal/service"
 	"example.com/classic/internal/service/dto"
+	"example.com/classic/pkg/contextx"
+	"example.com/classic/pkg/errors"
+	"example.com/classic/pkg/i18n"
 	"example.com/classic/pkg/logger"
+	"example.com/classic/pkg/response"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
 )
 
 // MockUserService mock user service
@@ -66,6 +79,19 @@ func (m *MockUserService) ChangeStatus(ctx context.Context, id int, status domai
 	return args.Error(0)
 }
 
+func (m *MockUserService) Import(ctx context.Context, source dto.UserImportSource, opts dto.ImportOptions, sink dto.ImportResultSink) (*dto.ImportSummary, error) {
+	args := m.Called(ctx, source, opts, sink)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.ImportSummary), args.Error(1)
+}
+
+func (m *MockUserService) Export(ctx context.Context, query *dto.UserQueryParams, fn func(user *domain.User) error) error {
+	args := m.Called(ctx, query, fn)
+	return args.Error(0)
+}
+
 // Verify MockUserService implements service.UserService
 var _ service.UserService = (*MockUserService)(nil)
 
@@ -244,7 +270,448 @@ func TestUserHandler_List(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestUserHandler_Register_DatabaseErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	log := logger.New("test", "error", false)
+
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		retryAfter string
+	}{
+		{"email taken", errors.ErrUserAlreadyExists, http.StatusConflict, ""},
+		{"unique violation", errors.New(errors.ErrCodeConflict, "resource already exists"), http.StatusConflict, ""},
+		{"foreign key violation", errors.New(errors.ErrCodeUnprocessableEntity, "referenced resource does not exist"), http.StatusUnprocessableEntity, ""},
+		{"deadlock", errors.New(errors.ErrCodeServiceUnavailable, "database temporarily unavailable, please retry"), http.StatusServiceUnavailable, "1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockUserService)
+			handler := NewUserHandler(mockService, log)
+			mockService.On("Register", mock.Anything, mock.AnythingOfType("*dto.RegisterParams")).Return(nil, tt.err)
+
+			reqBytes, _ := json.Marshal(request.CreateUserRequest{Name: "Test User", Email: "test@example.com", Password: "password123"})
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest("POST", "/api/v1/users", bytes.NewBuffer(reqBytes))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			handler.Register(c)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Equal(t, tt.retryAfter, w.Header().Get("Retry-After"))
+		})
+	}
+}
+
+func TestUserHandler_Register_RequestLimits(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	log := logger.New("test", "error", false)
+
+	// A body over the route limit is rejected with 413
+	handler := NewUserHandler(new(MockUserService), log)
+	reqBytes, _ := json.Marshal(request.CreateUserRequest{Name: "Test User", Email: "test@example.com", Password: "password123"})
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("POST", "/api/v1/users", nil)
+	c.Request.Body = http.MaxBytesReader(w, io.NopCloser(bytes.NewReader(reqBytes)), 16)
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	handler.Register(c)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	var resp response.Response
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, int(errors.ErrCodeRequestTooLarge), resp.Code)
+
+	// A database call cut off by the request deadline is reported as a timeout, not as retryable
+	mockService := new(MockUserService)
+	handler = NewUserHandler(mockService, log)
+	mockService.On("Register", mock.Anything, mock.AnythingOfType("*dto.RegisterParams")).
+		Return(nil, errors.Wrap(context.DeadlineExceeded, errors.ErrCodeServiceUnavailable, "database temporarily unavailable, please retry"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-ctx.Done()
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("POST", "/api/v1/users", bytes.NewBuffer(reqBytes)).WithContext(ctx)
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	handler.Register(c)
+
+	assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, int(errors.ErrCodeRequestTimeout), resp.Code)
+}
+
+func TestUserHandler_Register_ProblemDetails(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := NewUserHandler(new(MockUserService), logger.New("test", "error", false))
+
+	// Problem details are selected per request through the Accept header
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("POST", "/api/v1/users", bytes.NewBufferString(`{"name":"A","email":"not-an-email"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Request.Header.Set("Accept", "application/problem+json, application/json;q=0.5")
+	c.Request = c.Request.WithContext(contextx.WithTraceID(c.Request.Context(), "trace-1"))
+
+	handler.Register(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, response.ProblemContentType, w.Header().Get("Content-Type"))
+
+	var problem response.Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, "about:blank", problem.Type)
+	assert.Equal(t, "Bad Request", problem.Title)
+	assert.Equal(t, http.StatusBadRequest, problem.Status)
+	assert.Equal(t, "/api/v1/users", problem.Instance)
+	assert.Equal(t, int(errors.ErrCodeInvalidParam), problem.Code)
+	assert.Equal(t, "trace-1", problem.TraceID)
+	assert.Contains(t, problem.Detail, "invalid request body")
+	assert.Equal(t, []errors.FieldError{
+		{Field: "name", Code: "min", Message: "name must be at least 2 characters"},
+		{Field: "email", Code: "email", Message: "email must be a valid email address"},
+		{Field: "password", Code: "required", Message: "password is required"},
+	}, problem.Errors)
+
+	// Type mismatches name the offending field
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("POST", "/api/v1/users", bytes.NewBufferString(`{"name":"Test User","email":42}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Request.Header.Set("Accept", response.ProblemContentType)
+
+	handler.Register(c)
+
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, []errors.FieldError{{Field: "email", Code: "type", Message: "email must be a string"}}, problem.Errors)
+}
+
+func TestUserHandler_Register_DomainValidationProblem(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockUserService)
+	handler := NewUserHandler(mockService, logger.New("test", "error", false))
+
+	// The service reports value-object failures the way the user factory produces them
+	_, voErr := domain.NewPassword("abcdefg")
+	mockService.On("Register", mock.Anything, mock.AnythingOfType("*dto.RegisterParams")).
+		Return(nil, errors.WrapInvalidParam(fmt.Errorf("invalid password: %w", voErr), "invalid password: "+voErr.Error()))
+
+	engine := gin.New()
+	engine.Use(response.ProblemDetails(response.ProblemOptions{Enabled: true, TypeBase: "https://errors.example.com/"}))
+	engine.POST("/api/v1/users", handler.Register)
+
+	reqBytes, _ := json.Marshal(request.CreateUserRequest{Name: "Test User", Email: "test@example.com", Password: "abcdefg"})
+	req := httptest.NewRequest("POST", "/api/v1/users", bytes.NewBuffer(reqBytes))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, response.ProblemContentType, w.Header().Get("Content-Type"))
+
+	var problem response.Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, "https://errors.example.com/400", problem.Type)
+	assert.Equal(t, "invalid password: password must contain at least one digit", problem.Detail)
+	assert.Equal(t, []errors.FieldError{
+		{Field: "password", Code: "strength", Message: "password must contain at least one digit"},
+	}, problem.Errors)
+}
+
+func TestUserHandler_Register_Localized(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockUserService)
+	handler := NewUserHandler(mockService, logger.New("test", "error", false))
+	zh := contextx.WithLocale(context.Background(), i18n.SimplifiedChinese)
+
+	// Validation failures name the first failed field in the negotiated language
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("POST", "/api/v1/users", bytes.NewBufferString(`{"name":"A","email":"test@example.com","password":"password123"}`)).WithContext(zh)
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	handler.Register(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	var body response.Response
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "请求参数错误：name 至少需要 2 个字符", body.Msg)
+
+	// Business errors use the translation of their code
+	mockService.On("Register", mock.Anything, mock.AnythingOfType("*dto.RegisterParams")).Return(nil, errors.ErrUserAlreadyExists)
+	reqBytes, _ := json.Marshal(request.CreateUserRequest{Name: "Test User", Email: "test@example.com", Password: "password123"})
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("POST", "/api/v1/users", bytes.NewBuffer(reqBytes)).WithContext(zh)
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Request.Header.Set("Accept", response.ProblemContentType)
+
+	handler.Register(c)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	var problem response.Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, "用户已存在", problem.Detail)
+}
+
+func TestFieldPath(t *testing.T) {
+	assert.Equal(t, "ids[1]", fieldPath(reflect.TypeOf(&request.BatchDeleteRequest{}), "BatchDeleteRequest.IDs[1]", "json"))
+	assert.Equal(t, "page_size", fieldPath(reflect.TypeOf(&request.TenantQuery{}), "TenantQuery.PageSize", "form"))
+}
+
 // createTestUser creates a test user entity
+// updateGolden rewrites the golden files: go test ./internal/handler -run Golden -update
+var updateGolden = flag.Bool("update", false, "update golden files")
+
+// assertGolden compares an indented JSON body with testdata/<name>.golden.json
+func assertGolden(t *testing.T, name string, body []byte) {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, json.Indent(&buf, body, "", "  "))
+	buf.WriteByte('\n')
+
+	path := filepath.Join("testdata", name+".golden.json")
+	if *updateGolden {
+		require.NoError(t, os.MkdirAll("testdata", 0o755))
+		require.NoError(t, os.WriteFile(path, buf.Bytes(), 0o644))
+	}
+	want, err := os.ReadFile(path)
+	require.NoError(t, err, "run go test ./internal/handler -run Golden -update to create %s", path)
+	assert.Equal(t, string(want), buf.String(), "response differs from %s", path)
+}
+
+// goldenUser a user with every field set; timestamps are not in UTC to check normalization
+func goldenUser(id int, name, email string) *domain.User {
+	user := createTestUser(id, name, email)
+	shanghai := time.FixedZone("CST", 8*3600)
+	user.SetTenantID("acme")
+	user.SetAttributes(domain.Attributes{"plan": "pro", "seats": 5})
+	user.SetCreatedAt(time.Date(2024, 3, 1, 17, 30, 0, 0, shanghai))
+	user.SetUpdatedAt(time.Date(2024, 3, 2, 9, 15, 30, 0, shanghai))
+	return user
+}
+
+func TestUserHandler_Golden(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	log := logger.New("test", "error", false)
+
+	bare := createTestUser(3, "User3", "user3@example.com")
+	bare.SetCreatedAt(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	bare.SetUpdatedAt(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	tests := []struct {
+		name   string
+		target string
+		setup  func(*MockUserService)
+		call   func(*UserHandler, *gin.Context)
+	}{
+		{
+			name:   "get_user",
+			target: "/api/v1/users/1",
+			setup: func(m *MockUserService) {
+				m.On("GetByID", mock.Anything, 1).Return(goldenUser(1, "Test User", "test@example.com"), nil)
+			},
+			call: (*UserHandler).GetByID,
+		},
+		{
+			name:   "get_user_sparse",
+			target: "/api/v1/users/1?fields=email,id",
+			setup: func(m *MockUserService) {
+				m.On("GetByID", mock.Anything, 1).Return(goldenUser(1, "Test User", "test@example.com"), nil)
+			},
+			call: (*UserHandler).GetByID,
+		},
+		{
+			name:   "list_users",
+			target: "/api/v1/users?page=1&page_size=2",
+			setup: func(m *MockUserService) {
+				users := []*domain.User{goldenUser(1, "Test User", "test@example.com"), bare}
+				m.On("List", mock.Anything, mock.AnythingOfType("*dto.UserQueryParams")).Return(users, int64(3), nil)
+			},
+			call: (*UserHandler).List,
+		},
+		{
+			name:   "list_users_sparse",
+			target: "/api/v1/users?fields=id,name,status",
+			setup: func(m *MockUserService) {
+				users := []*domain.User{goldenUser(1, "Test User", "test@example.com"), bare}
+				m.On("List", mock.Anything, mock.AnythingOfType("*dto.UserQueryParams")).Return(users, int64(2), nil)
+			},
+			call: (*UserHandler).List,
+		},
+		{
+			name:   "list_users_empty",
+			target: "/api/v1/users",
+			setup: func(m *MockUserService) {
+				m.On("List", mock.Anything, mock.AnythingOfType("*dto.UserQueryParams")).Return([]*domain.User{}, int64(0), nil)
+			},
+			call: (*UserHandler).List,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockUserService)
+			tt.setup(mockService)
+			handler := NewUserHandler(mockService, log)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest("GET", tt.target, nil)
+			c.Params = gin.Params{{Key: "id", Value: "1"}}
+
+			tt.call(handler, c)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assertGolden(t, tt.name, w.Body.Bytes())
+		})
+	}
+}
+
+func TestUserHandler_GetByID_UnknownField(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := NewUserHandler(new(MockUserService), logger.New("test", "error", false))
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/api/v1/users/1?fields=id,password", nil)
+	c.Request.Header.Set("Accept", response.ProblemContentType)
+	c.Params = gin.Params{{Key: "id", Value: "1"}}
+
+	handler.GetByID(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	var problem response.Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, `invalid query: unknown field "password"`, problem.Detail)
+	require.Len(t, problem.Errors, 1)
+	assert.Equal(t, "fields", problem.Errors[0].Field)
+	assert.Equal(t, "oneof", problem.Errors[0].Code)
+}
+
+func TestUserHandler_ContentNegotiation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	log := logger.New("test", "error", false)
+
+	newContext := func(method, target, contentType, accept string, body []byte) (*gin.Context, *httptest.ResponseRecorder) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(method, target, bytes.NewReader(body))
+		if contentType != "" {
+			c.Request.Header.Set("Content-Type", contentType)
+		}
+		c.Request.Header.Set("Accept", accept)
+		c.Params = gin.Params{{Key: "id", Value: "1"}}
+		return c, w
+	}
+
+	t.Run("msgpack", func(t *testing.T) {
+		mockService := new(MockUserService)
+		mockService.On("Register", mock.Anything, &dto.RegisterParams{
+			Name: "Test User", Email: "test@example.com", Password: "password123",
+			Attributes: map[string]interface{}{"level": int64(3)},
+		}).Return(goldenUser(1, "Test User", "test@example.com"), nil)
+		handler := NewUserHandler(mockService, log)
+
+		body, err := response.MsgpackCodec{}.Marshal(map[string]interface{}{
+			"name": "Test User", "email": "test@example.com", "password": "password123",
+			"attributes": map[string]interface{}{"level": 3},
+		})
+		require.NoError(t, err)
+		c, w := newContext("POST", "/api/v1/users?fields=id,email,created_at", response.MsgpackContentType, response.MsgpackContentType, body)
+
+		handler.Register(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, response.MsgpackContentType, w.Header().Get("Content-Type"))
+		assert.Contains(t, w.Header().Values("Vary"), "Accept")
+		var resp map[string]interface{}
+		require.NoError(t, response.MsgpackCodec{}.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, "user registered successfully", resp["msg"])
+		assert.Equal(t, map[string]interface{}{
+			"id":         int64(1),
+			"email":      "test@example.com",
+			"created_at": time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC),
+		}, resp["data"])
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("protobuf", func(t *testing.T) {
+		mockService := new(MockUserService)
+		banned := domain.StatusBanned
+		mockService.On("Update", mock.Anything, 1, &dto.UpdateParams{Status: &banned}).Return(goldenUser(1, "Test User", "test@example.com"), nil)
+		handler := NewUserHandler(mockService, log)
+
+		status := pb.Status_STATUS_BANNED
+		body, err := proto.Marshal(&pb.UpdateRequest{Id: 99, Status: &status})
+		require.NoError(t, err)
+		c, w := newContext("PUT", "/api/v1/users/1?fields=id", response.ProtobufContentType, "application/x-protobuf, application/json;q=0.5", body)
+
+		handler.Update(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, response.ProtobufContentType, w.Header().Get("Content-Type"))
+		// protobuf always carries the full message
+		var user pb.User
+		require.NoError(t, proto.Unmarshal(w.Body.Bytes(), &user))
+		assert.Equal(t, int32(1), user.Id)
+		assert.Equal(t, "test@example.com", user.Email)
+		assert.Equal(t, "pro", user.Attributes.AsMap()["plan"])
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("protobuf list", func(t *testing.T) {
+		mockService := new(MockUserService)
+		users := []*domain.User{goldenUser(1, "Test User", "test@example.com"), createTestUser(2, "User2", "user2@example.com")}
+		mockService.On("List", mock.Anything, mock.AnythingOfType("*dto.UserQueryParams")).Return(users, int64(7), nil)
+		handler := NewUserHandler(mockService, log)
+
+		c, w := newContext("GET", "/api/v1/users?page=1&page_size=2", "", response.ProtobufContentType, nil)
+
+		handler.List(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var list pb.ListResponse
+		require.NoError(t, proto.Unmarshal(w.Body.Bytes(), &list))
+		assert.Equal(t, int64(7), list.Total)
+		require.Len(t, list.Users, 2)
+		assert.Equal(t, "user2@example.com", list.Users[1].Email)
+	})
+
+	t.Run("protobuf validation error falls back to JSON", func(t *testing.T) {
+		handler := NewUserHandler(new(MockUserService), log)
+
+		body, err := proto.Marshal(&pb.ChangeStatusRequest{Id: 1})
+		require.NoError(t, err)
+		c, w := newContext("PATCH", "/api/v1/users/1/status", response.ProtobufContentType, response.ProtobufContentType, body)
+
+		handler.ChangeStatus(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+		var resp map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, float64(errors.ErrCodeInvalidParam), resp["code"])
+	})
+
+	t.Run("unsupported media type", func(t *testing.T) {
+		handler := NewUserHandler(new(MockUserService), log)
+
+		c, w := newContext("POST", "/api/v1/users", "application/xml", "", []byte("<user/>"))
+
+		handler.Register(c)
+
+		assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+		var resp map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, float64(errors.ErrCodeUnsupportedMediaType), resp["code"])
+	})
+}
+
 func createTestUser(id int, name, email string) *domain.User {
 	nameVO, _ := domain.NewName(name)
 	emailVO, _ := domain.NewEmail(email)
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"strconv"
+
+	"example.com/classic/internal/handler/request"
+	"example.com/classic/internal/service"
+	"example.com/classic/pkg/logger"
+	"example.com/classic/pkg/response"
+	"example.com/classic/pkg/tracer"
+	"github.com/gin-gonic/gin"
+)
+
+// UserHistoryHandler HTTP handler for the user event history
+type UserHistoryHandler struct {
+	historyService service.UserHistoryService
+	log            logger.Logger
+}
+
+// NewUserHistoryHandler creates user history handler instance
+func NewUserHistoryHandler(historyService service.UserHistoryService, log logger.Logger) *UserHistoryHandler {
+	return &UserHistoryHandler{
+		historyService: historyService,
+		log:            log,
+	}
+}
+
+// GetHistory returns the domain events of a user, oldest first
+// @Summary Get user history
+// @Description Page through the stored domain events of a user; pass next_sequence as after_sequence to get the next page
+// @Tags User Management
+// @Produce json
+// @Param id path int true "User ID"
+// @Param after_sequence query int false "Return events after this sequence number" default(0)
+// @Param limit query int false "Page size" default(50) maximum(200)
+// @Success 200 {object} response.Response{data=dto.UserHistory}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/users/{id}/history [get]
+func (h *UserHistoryHandler) GetHistory(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Handler span
+	span, ctx := tracer.StartSpan(ctx, h.log, "handler:GetUserHistory")
+	defer span.End()
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.InvalidParam(c, "invalid user id")
+		return
+	}
+
+	var query request.UserHistoryQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.BadRequest(c, queryError(ctx, &query, err))
+		return
+	}
+
+	history, err := h.historyService.GetHistory(ctx, id, query.AfterSequence, query.Limit)
+	if err != nil {
+		span.EndWithError(err)
+		writeError(c, h.log, err)
+		return
+	}
+
+	response.Success(c, history)
+}
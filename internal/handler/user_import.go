@@ -0,0 +1,410 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"example.com/classic/internal/domain"
+	"example.com/classic/internal/handler/presenter"
+	"example.com/classic/internal/service/dto"
+	"example.com/classic/pkg/errors"
+	"example.com/classic/pkg/logger"
+	"example.com/classic/pkg/response"
+	"example.com/classic/pkg/tracer"
+	"github.com/gin-gonic/gin"
+)
+
+// Bulk import/export formats
+const (
+	formatCSV    = "csv"
+	formatNDJSON = "ndjson"
+
+	contentTypeCSV    = "text/csv"
+	contentTypeNDJSON = "application/x-ndjson"
+)
+
+// Import bulk imports users
+// @Summary Bulk import users
+// @Description Import users from a CSV or NDJSON upload. Per-row results are streamed back as NDJSON, followed by a summary line
+// @Tags User Management
+// @Accept text/csv,application/x-ndjson
+// @Produce application/x-ndjson
+// @Param format query string false "csv or ndjson, defaults to the request Content-Type"
+// @Param dry_run query bool false "validate rows without persisting"
+// @Param upsert query bool false "update users whose email already exists"
+// @Success 200 {object} dto.ImportRowResult
+// @Failure 400 {object} response.Response
+// @Router /api/v1/users:import [post]
+func (h *UserHandler) Import(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	span, ctx := tracer.StartSpan(ctx, h.log, "handler:Import")
+	defer span.End()
+
+	format := resolveFormat(c.Query("format"), c.ContentType())
+	if format == "" {
+		response.InvalidParam(c, "unsupported import format, use csv or ndjson")
+		return
+	}
+	liftBulkDeadlines(c)
+
+	source, err := newImportSource(format, c.Request.Body)
+	if err != nil {
+		h.log.Warn(ctx, "invalid import body", logger.Err(err))
+		response.InvalidParam(c, "invalid import body: "+err.Error())
+		return
+	}
+
+	opts := dto.ImportOptions{
+		DryRun: queryBool(c, "dry_run"),
+		Upsert: queryBool(c, "upsert"),
+	}
+
+	h.log.Info(ctx, "bulk import request received",
+		logger.String("format", format),
+		logger.Bool("dry_run", opts.DryRun),
+		logger.Bool("upsert", opts.Upsert))
+
+	// Results are written while the upload is still being read
+	_ = http.NewResponseController(c.Writer).EnableFullDuplex()
+	c.Header("Content-Type", contentTypeNDJSON)
+	c.Status(http.StatusOK)
+
+	enc := json.NewEncoder(c.Writer)
+	summary, err := h.userService.Import(ctx, source, opts, func(result *dto.ImportRowResult) error {
+		if err := enc.Encode(result); err != nil {
+			return err
+		}
+		c.Writer.Flush()
+		return nil
+	})
+	if err != nil {
+		span.EndWithError(err)
+		h.log.Error(ctx, "bulk import aborted", logger.Err(err))
+		var appErr *errors.Error
+		if !errors.As(err, &appErr) {
+			appErr = errors.WrapInternalError(err, "import aborted")
+		}
+		_ = enc.Encode(gin.H{"error": response.Response{Code: int(appErr.Code), Msg: appErr.Message}})
+		return
+	}
+
+	_ = enc.Encode(gin.H{"summary": summary})
+}
+
+// Export bulk exports users
+// @Summary Bulk export users
+// @Description Stream every user matching the List filters as CSV or NDJSON
+// @Tags User Management
+// @Produce text/csv,application/x-ndjson
+// @Param format query string false "csv or ndjson, defaults to the Accept header"
+// @Param name query string false "User name"
+// @Param email query string false "User email"
+// @Param status query string false "User status"
+// @Success 200 {object} presenter.UserV1
+// @Failure 400 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/users:export [get]
+func (h *UserHandler) Export(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	span, ctx := tracer.StartSpan(ctx, h.log, "handler:Export")
+	defer span.End()
+
+	format := resolveFormat(c.Query("format"), c.GetHeader("Accept"))
+	if format == "" {
+		format = formatNDJSON
+	}
+	liftBulkDeadlines(c)
+
+	query := parseUserQuery(c)
+	// The writer sets the download headers with the first user, so errors before it are written as usual
+	writer := newExportWriter(format, c.Writer)
+
+	err := h.userService.Export(ctx, &dto.UserQueryParams{
+		ID:         query.ID,
+		Name:       query.Name,
+		Email:      query.Email,
+		Status:     query.Status,
+		Attributes: query.Attributes,
+	}, writer.write)
+	if err != nil {
+		span.EndWithError(err)
+		if !c.Writer.Written() {
+			h.handleError(c, err)
+			return
+		}
+		h.log.Error(ctx, "bulk export aborted", logger.Err(err))
+		return
+	}
+
+	if err := writer.flush(); err != nil {
+		h.log.Error(ctx, "flush export failed", logger.Err(err))
+	}
+}
+
+// liftBulkDeadlines lets an upload or download outlive http.read_timeout and http.write_timeout;
+// it stays bounded by the request deadline of request_limits (the bulk rule)
+func liftBulkDeadlines(c *gin.Context) {
+	deadline, _ := c.Request.Context().Deadline()
+	rc := http.NewResponseController(c.Writer)
+	_ = rc.SetReadDeadline(deadline)
+	_ = rc.SetWriteDeadline(deadline)
+}
+
+// resolveFormat picks the bulk format from an explicit parameter or a media type
+func resolveFormat(explicit, mediaType string) string {
+	switch strings.ToLower(explicit) {
+	case formatCSV, formatNDJSON:
+		return strings.ToLower(explicit)
+	case "":
+	default:
+		return ""
+	}
+
+	for _, part := range strings.Split(mediaType, ",") {
+		mt, _, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		switch mt {
+		case contentTypeCSV:
+			return formatCSV
+		case contentTypeNDJSON, "application/jsonl", "application/json":
+			return formatNDJSON
+		}
+	}
+	return ""
+}
+
+// queryBool reads a boolean query parameter, defaulting to false
+func queryBool(c *gin.Context, key string) bool {
+	v, err := strconv.ParseBool(c.Query(key))
+	return err == nil && v
+}
+
+// newImportSource creates an import source for the given format
+func newImportSource(format string, body io.Reader) (dto.UserImportSource, error) {
+	if format == formatCSV {
+		return newCSVImportSource(body)
+	}
+	return newNDJSONImportSource(body), nil
+}
+
+// csvImportSource reads rows from a CSV upload with a header line
+type csvImportSource struct {
+	reader  *csv.Reader
+	columns map[string]int
+	line    int
+}
+
+// newCSVImportSource reads the header; name and email columns are required
+func newCSVImportSource(body io.Reader) (*csvImportSource, error) {
+	reader := csv.NewReader(bufio.NewReader(body))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read csv header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"name", "email"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("csv header must contain a %q column", required)
+		}
+	}
+
+	return &csvImportSource{reader: reader, columns: columns, line: 1}, nil
+}
+
+// Next implements dto.UserImportSource
+func (s *csvImportSource) Next(ctx context.Context) (*dto.ImportRow, error) {
+	record, err := s.reader.Read()
+	if err == io.EOF {
+		return nil, io.EOF
+	}
+	s.line++
+	if err != nil {
+		if _, ok := err.(*csv.ParseError); ok {
+			return nil, &dto.RowError{Line: s.line, Err: err}
+		}
+		return nil, err
+	}
+
+	var attributes map[string]interface{}
+	if value := s.field(record, "attributes"); value != "" {
+		if err := json.Unmarshal([]byte(value), &attributes); err != nil {
+			return nil, &dto.RowError{Line: s.line, Err: fmt.Errorf("attributes must be a JSON object: %w", err)}
+		}
+	}
+
+	return &dto.ImportRow{
+		Line:           s.line,
+		Name:           s.field(record, "name"),
+		Email:          s.field(record, "email"),
+		Password:       s.field(record, "password"),
+		HashedPassword: s.field(record, "password_hash"),
+		Attributes:     attributes,
+	}, nil
+}
+
+// field returns the named column of a record, or "" if absent
+func (s *csvImportSource) field(record []string, name string) string {
+	i, ok := s.columns[name]
+	if !ok || i >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[i])
+}
+
+// ndjsonImportSource reads one JSON object per line
+type ndjsonImportSource struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+// ndjsonImportRow is the wire format of an NDJSON import line
+type ndjsonImportRow struct {
+	Name         string                 `json:"name"`
+	Email        string                 `json:"email"`
+	Password     string                 `json:"password"`
+	PasswordHash string                 `json:"password_hash"`
+	Attributes   map[string]interface{} `json:"attributes"`
+}
+
+func newNDJSONImportSource(body io.Reader) *ndjsonImportSource {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	return &ndjsonImportSource{scanner: scanner}
+}
+
+// Next implements dto.UserImportSource; blank lines are skipped
+func (s *ndjsonImportSource) Next(ctx context.Context) (*dto.ImportRow, error) {
+	for s.scanner.Scan() {
+		s.line++
+		data := strings.TrimSpace(s.scanner.Text())
+		if data == "" {
+			continue
+		}
+
+		var row ndjsonImportRow
+		if err := json.Unmarshal([]byte(data), &row); err != nil {
+			return nil, &dto.RowError{Line: s.line, Err: err}
+		}
+		return &dto.ImportRow{
+			Line:           s.line,
+			Name:           row.Name,
+			Email:          row.Email,
+			Password:       row.Password,
+			HashedPassword: row.PasswordHash,
+			Attributes:     row.Attributes,
+		}, nil
+	}
+	if err := s.scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
+
+// exportWriter encodes exported users in the requested format
+type exportWriter struct {
+	format    string
+	w         http.ResponseWriter
+	csv       *csv.Writer
+	json      *json.Encoder
+	started   bool
+	wroteHead bool
+}
+
+func newExportWriter(format string, w http.ResponseWriter) *exportWriter {
+	ew := &exportWriter{format: format, w: w}
+	if format == formatCSV {
+		ew.csv = csv.NewWriter(w)
+	} else {
+		ew.json = json.NewEncoder(w)
+	}
+	return ew
+}
+
+func (ew *exportWriter) contentType() string {
+	if ew.format == formatCSV {
+		return contentTypeCSV
+	}
+	return contentTypeNDJSON
+}
+
+// start sets the download headers before the first output
+func (ew *exportWriter) start() {
+	if ew.started {
+		return
+	}
+	ew.started = true
+	ew.w.Header().Set("Content-Type", ew.contentType())
+	ew.w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="users.%s"`, ew.format))
+}
+
+// write encodes one user
+func (ew *exportWriter) write(user *domain.User) error {
+	ew.start()
+	if ew.json != nil {
+		return ew.json.Encode(presenter.NewUserV1(dto.UserDTOFromUser(user)))
+	}
+
+	if err := ew.writeHead(); err != nil {
+		return err
+	}
+	// Attributes use the JSON object form of the import attributes column, so the file can be re-imported
+	var attributes string
+	if attrs := user.Attributes(); len(attrs) > 0 {
+		data, err := json.Marshal(attrs)
+		if err != nil {
+			return fmt.Errorf("encode attributes of user %d: %w", user.ID(), err)
+		}
+		attributes = string(data)
+	}
+	return ew.csv.Write([]string{
+		strconv.Itoa(user.ID()),
+		user.Name().String(),
+		user.Email().String(),
+		user.Status().String(),
+		user.CreatedAt().Format(time.RFC3339),
+		user.UpdatedAt().Format(time.RFC3339),
+		attributes,
+	})
+}
+
+// writeHead writes the CSV header once
+func (ew *exportWriter) writeHead() error {
+	if ew.wroteHead {
+		return nil
+	}
+	ew.wroteHead = true
+	return ew.csv.Write([]string{"id", "name", "email", "status", "created_at", "updated_at", "attributes"})
+}
+
+// flush flushes buffered CSV output; an empty export still gets a header
+func (ew *exportWriter) flush() error {
+	ew.start()
+	if ew.csv == nil {
+		return nil
+	}
+	if err := ew.writeHead(); err != nil {
+		return err
+	}
+	ew.csv.Flush()
+	return ew.csv.Error()
+}
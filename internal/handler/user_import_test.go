@@ -0,0 +1,365 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"example.com/classic/internal/domain"
+	"example.com/classic/internal/service/dto"
+	"example.com/classic/pkg/errors"
+	"example.com/classic/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// serveBulk runs the import or export handler on a test request
+func serveBulk(h gin.HandlerFunc, method, target, contentType, body string, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	h(c)
+	return w
+}
+
+// readSource drains an import source, collecting the parsed rows and the per-line errors
+func readSource(t *testing.T, source dto.UserImportSource) ([]*dto.ImportRow, []*dto.RowError) {
+	t.Helper()
+	var rows []*dto.ImportRow
+	var rowErrors []*dto.RowError
+	for {
+		row, err := source.Next(context.Background())
+		if err == io.EOF {
+			return rows, rowErrors
+		}
+		if rowErr, ok := err.(*dto.RowError); ok {
+			rowErrors = append(rowErrors, rowErr)
+			continue
+		}
+		require.NoError(t, err)
+		rows = append(rows, row)
+	}
+}
+
+// ndjsonLines decodes an NDJSON response body
+func ndjsonLines(t *testing.T, body string) []map[string]any {
+	t.Helper()
+	var lines []map[string]any
+	scanner := bufio.NewScanner(strings.NewReader(body))
+	for scanner.Scan() {
+		var line map[string]any
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &line), scanner.Text())
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func TestUserHandler_Import_MalformedCSVHeader(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockUserService)
+	handler := NewUserHandler(mockService, logger.New("test", "error", false))
+
+	for name, body := range map[string]string{
+		"missing email column": "name,mail,password\nAlice,alice@example.com,password123\n",
+		"empty body":           "",
+		"unterminated quote":   "name,\"email\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			w := serveBulk(handler.Import, "POST", "/api/v1/users:import", "text/csv", body, nil)
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Contains(t, w.Body.String(), "invalid import body")
+		})
+	}
+	mockService.AssertNotCalled(t, "Import", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUserHandler_Import_CSVRows(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockUserService)
+	handler := NewUserHandler(mockService, logger.New("test", "error", false))
+
+	var rows []*dto.ImportRow
+	var rowErrors []*dto.RowError
+	mockService.On("Import", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			rows, rowErrors = readSource(t, args.Get(1).(dto.UserImportSource))
+		}).
+		Return(&dto.ImportSummary{}, nil)
+
+	// Header names are case-insensitive and columns may come in any order
+	body := " Email ,NAME,password_hash,attributes\n" +
+		"alice@example.com,Alice,$2a$10$hash,\"{\"\"plan\"\": \"\"pro\"\"}\"\n" +
+		"bob@example.com,\"Bob \"x\"\n" +
+		"carol@example.com,Carol\n" +
+		"dave@example.com,Dave,,[1]\n"
+	w := serveBulk(handler.Import, "POST", "/api/v1/users:import", "text/csv", body, nil)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	require.Len(t, rows, 2)
+	assert.Equal(t, dto.ImportRow{
+		Line: 2, Name: "Alice", Email: "alice@example.com", HashedPassword: "$2a$10$hash",
+		Attributes: map[string]interface{}{"plan": "pro"},
+	}, *rows[0])
+	assert.Equal(t, dto.ImportRow{Line: 4, Name: "Carol", Email: "carol@example.com"}, *rows[1])
+	// A broken quote and an attributes cell that is not a JSON object fail their line only
+	require.Len(t, rowErrors, 2)
+	assert.Equal(t, 3, rowErrors[0].Line)
+	assert.Equal(t, 5, rowErrors[1].Line)
+	assert.Contains(t, rowErrors[1].Err.Error(), "attributes must be a JSON object")
+}
+
+func TestUserHandler_Import_BadNDJSONLine(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockUserService)
+	handler := NewUserHandler(mockService, logger.New("test", "error", false))
+
+	var rows []*dto.ImportRow
+	var rowErrors []*dto.RowError
+	mockService.On("Import", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			rows, rowErrors = readSource(t, args.Get(1).(dto.UserImportSource))
+		}).
+		Return(&dto.ImportSummary{}, nil)
+
+	body := `{"name":"Alice","email":"alice@example.com","password":"password123"}` + "\n" +
+		`{"name":"Bob","email":` + "\n" +
+		"\n" +
+		`{"name":"Carol","email":"carol@example.com","password_hash":"$2a$10$hash","attributes":{"seats":3,"plan":null}}` + "\n"
+	w := serveBulk(handler.Import, "POST", "/api/v1/users:import", "application/x-ndjson", body, nil)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	require.Len(t, rows, 2)
+	assert.Equal(t, dto.ImportRow{Line: 1, Name: "Alice", Email: "alice@example.com", Password: "password123"}, *rows[0])
+	// Blank lines are skipped but still counted
+	assert.Equal(t, dto.ImportRow{
+		Line: 4, Name: "Carol", Email: "carol@example.com", HashedPassword: "$2a$10$hash",
+		Attributes: map[string]interface{}{"seats": float64(3), "plan": nil},
+	}, *rows[1])
+	require.Len(t, rowErrors, 1)
+	assert.Equal(t, 2, rowErrors[0].Line)
+}
+
+func TestUserHandler_Import_DryRun(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockUserService)
+	handler := NewUserHandler(mockService, logger.New("test", "error", false))
+
+	mockService.On("Import", mock.Anything, mock.Anything, dto.ImportOptions{DryRun: true, Upsert: true}, mock.Anything).
+		Run(func(args mock.Arguments) {
+			sink := args.Get(3).(dto.ImportResultSink)
+			require.NoError(t, sink(&dto.ImportRowResult{Line: 2, Email: "alice@example.com", Action: dto.ImportActionCreated}))
+			require.NoError(t, sink(&dto.ImportRowResult{Line: 3, Email: "bob", Action: dto.ImportActionFailed, Code: 10001, Error: "invalid email"}))
+		}).
+		Return(&dto.ImportSummary{Total: 2, Created: 1, Failed: 1, DryRun: true}, nil)
+
+	body := "name,email,password\nAlice,alice@example.com,password123\nBob,bob,password123\n"
+	w := serveBulk(handler.Import, "POST", "/api/v1/users:import?format=csv&dry_run=true&upsert=1", "application/octet-stream", body, nil)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, contentTypeNDJSON, w.Header().Get("Content-Type"))
+	lines := ndjsonLines(t, w.Body.String())
+	require.Len(t, lines, 3)
+	assert.Equal(t, map[string]any{"line": float64(2), "email": "alice@example.com", "action": "created"}, lines[0])
+	assert.Equal(t, "failed", lines[1]["action"])
+	assert.Equal(t, "invalid email", lines[1]["error"])
+	assert.Equal(t, map[string]any{"summary": map[string]any{
+		"total": float64(2), "created": float64(1), "updated": float64(0), "unchanged": float64(0), "failed": float64(1), "dry_run": true,
+	}}, lines[2])
+	mockService.AssertExpectations(t)
+}
+
+func TestUserHandler_Import_Aborted(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockUserService)
+	handler := NewUserHandler(mockService, logger.New("test", "error", false))
+
+	// A wrapped business error keeps its code in the final line
+	mockService.On("Import", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(&dto.ImportSummary{}, fmt.Errorf("import: %w", errors.ErrRequestTooLarge))
+
+	body := "name,email,password\nAlice,alice@example.com,password123\n"
+	w := serveBulk(handler.Import, "POST", "/api/v1/users:import", "text/csv", body, nil)
+
+	lines := ndjsonLines(t, w.Body.String())
+	require.Len(t, lines, 1)
+	assert.Equal(t, map[string]any{"error": map[string]any{
+		"code": float64(errors.ErrCodeRequestTooLarge), "msg": "request body too large",
+	}}, lines[0])
+}
+
+func TestUserHandler_Import_UnsupportedFormat(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockUserService)
+	handler := NewUserHandler(mockService, logger.New("test", "error", false))
+
+	for _, target := range []string{"/api/v1/users:import", "/api/v1/users:import?format=xlsx"} {
+		w := serveBulk(handler.Import, "POST", target, "application/xml", "<users/>", nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code, target)
+		assert.Contains(t, w.Body.String(), "unsupported import format")
+	}
+	mockService.AssertNotCalled(t, "Import", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestResolveFormat(t *testing.T) {
+	tests := []struct {
+		explicit, mediaType, want string
+	}{
+		{"csv", "application/x-ndjson", formatCSV}, // the parameter wins over the media type
+		{"NDJSON", "", formatNDJSON},
+		{"xlsx", "text/csv", ""}, // an unknown parameter is not overridden
+		{"", "text/csv; charset=utf-8", formatCSV},
+		{"", "application/x-ndjson", formatNDJSON},
+		{"", "application/jsonl", formatNDJSON},
+		{"", "application/json", formatNDJSON},
+		{"", "text/html, text/csv;q=0.9", formatCSV}, // first supported entry of an Accept list
+		{"", "application/xml", ""},
+		{"", "", ""},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, resolveFormat(tt.explicit, tt.mediaType), "%q %q", tt.explicit, tt.mediaType)
+	}
+}
+
+func TestUserHandler_Export(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	users := []*domain.User{
+		createTestUser(1, "Alice", "alice@example.com"),
+		createTestUser(2, "Bob", "bob@example.com"),
+	}
+	users[0].SetAttributes(domain.Attributes{"plan": "pro", "seats": int64(3)})
+	exportUsers := func(args mock.Arguments) {
+		fn := args.Get(2).(func(user *domain.User) error)
+		for _, user := range users {
+			require.NoError(t, fn(user))
+		}
+	}
+
+	t.Run("csv with filters", func(t *testing.T) {
+		mockService := new(MockUserService)
+		handler := NewUserHandler(mockService, logger.New("test", "error", false))
+		mockService.On("Export", mock.Anything, mock.MatchedBy(func(query *dto.UserQueryParams) bool {
+			return query.Name != nil && *query.Name == "a" &&
+				query.Email != nil && *query.Email == "example.com" &&
+				query.Status != nil && *query.Status == domain.StatusActive &&
+				assert.ObjectsAreEqual(map[string]string{"plan": "pro"}, query.Attributes)
+		}), mock.Anything).Run(exportUsers).Return(nil)
+
+		w := serveBulk(handler.Export, "GET", "/api/v1/users:export?format=csv&name=a&email=example.com&status=active&attr.plan=pro", "", "", nil)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, contentTypeCSV, w.Header().Get("Content-Type"))
+		assert.Equal(t, `attachment; filename="users.csv"`, w.Header().Get("Content-Disposition"))
+		lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+		require.Len(t, lines, 3)
+		assert.Equal(t, "id,name,email,status,created_at,updated_at,attributes", lines[0])
+		assert.True(t, strings.HasPrefix(lines[1], "1,Alice,alice@example.com,active,"), lines[1])
+		assert.True(t, strings.HasSuffix(lines[1], `,"{""plan"":""pro"",""seats"":3}"`), lines[1])
+		assert.True(t, strings.HasSuffix(lines[2], ","), lines[2])
+		mockService.AssertExpectations(t)
+
+		// The export can be read back by the import
+		source, err := newCSVImportSource(strings.NewReader(w.Body.String()))
+		require.NoError(t, err)
+		rows, rowErrors := readSource(t, source)
+		assert.Empty(t, rowErrors)
+		require.Len(t, rows, 2)
+		assert.Equal(t, map[string]interface{}{"plan": "pro", "seats": float64(3)}, rows[0].Attributes)
+		assert.Nil(t, rows[1].Attributes)
+	})
+
+	t.Run("ndjson from Accept, invalid status ignored", func(t *testing.T) {
+		mockService := new(MockUserService)
+		handler := NewUserHandler(mockService, logger.New("test", "error", false))
+		mockService.On("Export", mock.Anything, mock.MatchedBy(func(query *dto.UserQueryParams) bool {
+			return query.Status == nil && query.Name == nil
+		}), mock.Anything).Run(exportUsers).Return(nil)
+
+		w := serveBulk(handler.Export, "GET", "/api/v1/users:export?status=bogus", "", "",
+			http.Header{"Accept": {"application/x-ndjson"}})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, contentTypeNDJSON, w.Header().Get("Content-Type"))
+		lines := ndjsonLines(t, w.Body.String())
+		require.Len(t, lines, 2)
+		assert.Equal(t, "bob@example.com", lines[1]["email"])
+		assert.NotContains(t, w.Body.String(), "hashed_password")
+	})
+
+	t.Run("empty csv export has a header", func(t *testing.T) {
+		mockService := new(MockUserService)
+		handler := NewUserHandler(mockService, logger.New("test", "error", false))
+		mockService.On("Export", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+		w := serveBulk(handler.Export, "GET", "/api/v1/users:export", "", "", http.Header{"Accept": {"text/csv"}})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, contentTypeCSV, w.Header().Get("Content-Type"))
+		assert.Equal(t, "id,name,email,status,created_at,updated_at,attributes\n", w.Body.String())
+	})
+
+	t.Run("failure before the first user is a regular error", func(t *testing.T) {
+		mockService := new(MockUserService)
+		handler := NewUserHandler(mockService, logger.New("test", "error", false))
+		mockService.On("Export", mock.Anything, mock.Anything, mock.Anything).
+			Return(errors.New(errors.ErrCodeServiceUnavailable, "database unavailable"))
+
+		w := serveBulk(handler.Export, "GET", "/api/v1/users:export?format=csv", "", "", nil)
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+		assert.Empty(t, w.Header().Get("Content-Disposition"))
+	})
+}
+
+func TestUserHandler_Export_OutlivesWriteTimeout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(MockUserService)
+	handler := NewUserHandler(mockService, logger.New("test", "error", false))
+	mockService.On("Export", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		fn := args.Get(2).(func(user *domain.User) error)
+		for i := 1; i <= 4; i++ {
+			time.Sleep(100 * time.Millisecond)
+			require.NoError(t, fn(createTestUser(i, "User", fmt.Sprintf("user%d@example.com", i))))
+		}
+	}).Return(nil)
+
+	engine := gin.New()
+	// The bulk request limit, as set by the request limit middleware
+	engine.Use(func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	})
+	engine.GET("/api/v1/users:export", handler.Export)
+	server := httptest.NewUnstartedServer(engine)
+	server.Config.ReadTimeout = 150 * time.Millisecond
+	server.Config.WriteTimeout = 150 * time.Millisecond
+	server.Start()
+	t.Cleanup(server.Close)
+
+	resp, err := http.Get(server.URL + "/api/v1/users:export")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	lines := ndjsonLines(t, string(body))
+	require.Len(t, lines, 4)
+	assert.Equal(t, "user4@example.com", lines[3]["email"])
+}
@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"example.com/classic/internal/service"
+	"example.com/classic/internal/service/dto"
+	"example.com/classic/internal/taskqueue"
+	"example.com/classic/pkg/contextx"
+	"example.com/classic/pkg/logger"
+)
+
+// UserJobHandler processes user background jobs on the worker
+type UserJobHandler struct {
+	batchSvc   service.UserBatchService
+	privacySvc service.UserPrivacyService
+	log        logger.Logger
+}
+
+// NewUserJobHandler creates user job handler instance
+func NewUserJobHandler(batchSvc service.UserBatchService, privacySvc service.UserPrivacyService, log logger.Logger) *UserJobHandler {
+	return &UserJobHandler{
+		batchSvc:   batchSvc,
+		privacySvc: privacySvc,
+		log:        log,
+	}
+}
+
+// Register registers the job handlers on the task queue
+func (h *UserJobHandler) Register(queue taskqueue.TaskQueue) error {
+	if err := queue.RegisterHandler(service.TaskTypeUserBatch, taskqueue.HandlerFunc(h.ProcessBatch)); err != nil {
+		return err
+	}
+	return queue.RegisterHandler(service.TaskTypeUserErase, taskqueue.HandlerFunc(h.ProcessErasure))
+}
+
+// ProcessBatch runs an asynchronous batch job, writing progress as the task result
+func (h *UserJobHandler) ProcessBatch(ctx context.Context, task *taskqueue.Task) error {
+	var payload dto.BatchJobPayload
+	if err := json.Unmarshal(task.Payload, &payload); err != nil {
+		return fmt.Errorf("invalid batch payload: %w", err)
+	}
+
+	h.log.Info(ctx, "processing batch job",
+		logger.String("task_id", task.ID),
+		logger.String("operation", string(payload.Operation)),
+		logger.Int("count", len(payload.IDs)))
+
+	// The worker has no request pipeline: restore the tenant and locale the job was submitted under
+	ctx = contextx.WithTenantID(ctx, payload.TenantID)
+	if payload.Locale != "" {
+		ctx = contextx.WithLocale(ctx, payload.Locale)
+	}
+
+	_, err := h.batchSvc.RunJob(ctx, &payload, func(progress *dto.BatchResult) error {
+		if task.ResultWriter == nil {
+			return nil
+		}
+		data, err := json.Marshal(progress)
+		if err != nil {
+			return err
+		}
+		_, err = task.ResultWriter.Write(data)
+		return err
+	})
+	return err
+}
+
+// ProcessErasure erases the personal data of one user; retries are safe
+func (h *UserJobHandler) ProcessErasure(ctx context.Context, task *taskqueue.Task) error {
+	var payload dto.ErasureJobPayload
+	if err := json.Unmarshal(task.Payload, &payload); err != nil {
+		return fmt.Errorf("invalid erasure payload: %w", err)
+	}
+
+	h.log.Info(ctx, "processing erasure job",
+		logger.String("task_id", task.ID),
+		logger.Int("user_id", payload.UserID))
+
+	ctx = contextx.WithTenantID(ctx, payload.TenantID)
+	return h.privacySvc.Erase(ctx, payload.UserID)
+}
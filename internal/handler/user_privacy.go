@@ -0,0 +1,172 @@
+package handler
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"example.com/classic/internal/service"
+	"example.com/classic/internal/service/dto"
+	"example.com/classic/pkg/logger"
+	"example.com/classic/pkg/response"
+	"example.com/classic/pkg/tracer"
+	"github.com/gin-gonic/gin"
+)
+
+// contentTypeZip personal data archive media type
+const contentTypeZip = "application/zip"
+
+// UserPrivacyHandler HTTP handler for data-subject requests (GDPR)
+type UserPrivacyHandler struct {
+	privacyService service.UserPrivacyService
+	log            logger.Logger
+}
+
+// NewUserPrivacyHandler creates user privacy handler instance
+func NewUserPrivacyHandler(privacyService service.UserPrivacyService, log logger.Logger) *UserPrivacyHandler {
+	return &UserPrivacyHandler{
+		privacyService: privacyService,
+		log:            log,
+	}
+}
+
+// personalDataManifest describes the files of the archive
+type personalDataManifest struct {
+	FormatVersion int       `json:"format_version"`
+	UserID        int       `json:"user_id"`
+	TenantID      string    `json:"tenant_id"`
+	GeneratedAt   time.Time `json:"generated_at"`
+	Files         []string  `json:"files"`
+}
+
+// ExportPersonalData exports everything held on a user
+// @Summary Export personal data
+// @Description Download a ZIP archive with manifest.json, profile.json and one JSON file per personal data section
+// @Tags User Management
+// @Produce application/zip
+// @Param id path int true "User ID"
+// @Success 200 {file} file
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/users/{id}/export [get]
+func (h *UserPrivacyHandler) ExportPersonalData(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Handler span
+	span, ctx := tracer.StartSpan(ctx, h.log, "handler:ExportPersonalData")
+	defer span.End()
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.InvalidParam(c, "invalid user id")
+		return
+	}
+
+	export, err := h.privacyService.ExportPersonalData(ctx, id)
+	if err != nil {
+		span.EndWithError(err)
+		writeError(c, h.log, err)
+		return
+	}
+
+	archive, err := buildPersonalDataArchive(export)
+	if err != nil {
+		span.EndWithError(err)
+		writeError(c, h.log, err)
+		return
+	}
+
+	h.log.Info(ctx, "personal data exported", logger.Int("user_id", id))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="user-%d-personal-data.zip"`, id))
+	c.Data(http.StatusOK, contentTypeZip, archive)
+}
+
+// buildPersonalDataArchive writes the export as a ZIP of JSON files
+func buildPersonalDataArchive(export *dto.PersonalDataExport) ([]byte, error) {
+	sections := make([]string, 0, len(export.Sections))
+	for name := range export.Sections {
+		sections = append(sections, name)
+	}
+	sort.Strings(sections)
+
+	files := []string{"profile.json"}
+	for _, name := range sections {
+		files = append(files, name+".json")
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	write := func(name string, v interface{}) error {
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: export.GeneratedAt})
+		if err != nil {
+			return err
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+
+	if err := write("manifest.json", &personalDataManifest{
+		FormatVersion: export.FormatVersion,
+		UserID:        export.UserID,
+		TenantID:      export.TenantID,
+		GeneratedAt:   export.GeneratedAt,
+		Files:         files,
+	}); err != nil {
+		return nil, err
+	}
+	if err := write("profile.json", export.Profile); err != nil {
+		return nil, err
+	}
+	for _, name := range sections {
+		if err := write(name+".json", export.Sections[name]); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// RequestErasure starts the irreversible erasure of a user's personal data
+// @Summary Erase personal data
+// @Description Pseudonymize name and email and drop credentials. Runs as a background job; the user ID stays valid for the audit trail
+// @Tags User Management
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 202 {object} response.Response{data=dto.ErasureJob}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/users/{id}/erasure [post]
+func (h *UserPrivacyHandler) RequestErasure(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Handler span
+	span, ctx := tracer.StartSpan(ctx, h.log, "handler:RequestErasure")
+	defer span.End()
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response.InvalidParam(c, "invalid user id")
+		return
+	}
+
+	job, err := h.privacyService.RequestErasure(ctx, id)
+	if err != nil {
+		span.EndWithError(err)
+		writeError(c, h.log, err)
+		return
+	}
+
+	response.Accepted(c, "success.erasure_scheduled", job)
+}
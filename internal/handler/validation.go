@@ -0,0 +1,190 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"example.com/classic/internal/domain"
+	"example.com/classic/internal/handler/presenter"
+	"example.com/classic/pkg/errors"
+	"example.com/classic/pkg/i18n"
+	"example.com/classic/pkg/response"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// writeBodyError 写出请求体绑定失败的响应：超过请求体上限返回 413，格式不受支持返回 415，其余返回 400
+func writeBodyError(c *gin.Context, obj any, err error) {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		response.RequestEntityTooLarge(c, errors.Wrap(err, errors.ErrCodeRequestTooLarge, errors.ErrRequestTooLarge.Message))
+		return
+	}
+	if errors.Is(err, errors.ErrUnsupportedMediaType) {
+		response.UnsupportedMediaType(c, errors.ErrUnsupportedMediaType)
+		return
+	}
+	response.BadRequest(c, bodyError(c.Request.Context(), obj, err))
+}
+
+// bodyError 将请求体绑定失败转换为参数错误，校验失败逐字段给出明细
+func bodyError(ctx context.Context, obj any, err error) *errors.Error {
+	return bindingError(ctx, obj, err, "json", "invalid request body")
+}
+
+// queryError 将查询参数绑定失败转换为参数错误
+func queryError(ctx context.Context, obj any, err error) *errors.Error {
+	return bindingError(ctx, obj, err, "form", "invalid query")
+}
+
+// bindingError 保留原有的错误信息，字段名按 tag 还原为客户端看到的名称，字段说明按请求语言渲染
+func bindingError(ctx context.Context, obj any, err error, tag, message string) *errors.Error {
+	bizErr := errors.WrapInvalidParam(err, message+": "+err.Error())
+
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		fields := make([]errors.FieldError, 0, len(validationErrs))
+		for _, fe := range validationErrs {
+			field := fieldPath(reflect.TypeOf(obj), fe.StructNamespace(), tag)
+			param := fe.Param()
+			if fe.Tag() == "oneof" {
+				param = strings.Join(strings.Fields(param), ", ")
+			}
+			fields = append(fields, errors.FieldError{Field: field, Code: fe.Tag(), Message: fieldMessage(ctx, field, fe.Tag(), param, fe.Kind())})
+		}
+		return bizErr.WithFields(fields...)
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return bizErr.WithFields(errors.FieldError{
+			Field:   typeErr.Field,
+			Code:    "type",
+			Message: fieldMessage(ctx, typeErr.Field, "type", jsonTypeName(typeErr.Type), reflect.Invalid),
+		})
+	}
+	return bizErr
+}
+
+// userFields 解析用户接口的稀疏字段集 (?fields=id,email)，字段无效时写出 400
+func userFields(c *gin.Context) (presenter.Fields, bool) {
+	fields, err := presenter.ParseFields(c.Query("fields"), presenter.UserV1{})
+	if err != nil {
+		allowed := strings.Join(presenter.FieldNames(presenter.UserV1{}), ", ")
+		response.BadRequest(c, errors.WrapInvalidParam(err, "invalid query: "+err.Error()).WithFields(errors.FieldError{
+			Field:   "fields",
+			Code:    "oneof",
+			Message: fieldMessage(c.Request.Context(), "fields", "oneof", allowed, reflect.String),
+		}))
+		return nil, false
+	}
+	return fields, true
+}
+
+// domainFieldErrors 为携带值对象校验错误的业务错误补充字段明细
+func domainFieldErrors(ctx context.Context, bizErr *errors.Error, err error) *errors.Error {
+	var validationErr *domain.ValidationError
+	if len(bizErr.Fields) > 0 || !errors.As(err, &validationErr) {
+		return bizErr
+	}
+	return bizErr.WithFields(errors.FieldError{
+		Field:   validationErr.Field,
+		Code:    validationErr.Code,
+		Message: fieldMessage(ctx, validationErr.Field, validationErr.Code, validationErr.Param, reflect.String),
+	})
+}
+
+// fieldPath 把 validator 的结构体命名空间 (CreateUserRequest.IDs[0]) 转换为请求中的字段路径 (ids[0])
+func fieldPath(t reflect.Type, namespace, tag string) string {
+	segments := strings.Split(namespace, ".")[1:]
+	path := make([]string, 0, len(segments))
+	for _, segment := range segments {
+		name, index := segment, ""
+		if i := strings.IndexByte(segment, '['); i >= 0 {
+			name, index = segment[:i], segment[i:]
+		}
+
+		for t != nil && t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		if t == nil || t.Kind() != reflect.Struct {
+			path = append(path, segment)
+			t = nil
+			continue
+		}
+		field, ok := t.FieldByName(name)
+		if !ok {
+			path = append(path, segment)
+			t = nil
+			continue
+		}
+
+		t = field.Type
+		if index != "" {
+			for t.Kind() == reflect.Pointer {
+				t = t.Elem()
+			}
+			if k := t.Kind(); k == reflect.Slice || k == reflect.Array || k == reflect.Map {
+				t = t.Elem()
+			}
+		}
+		// 匿名嵌入字段在 JSON 中被展开
+		if field.Anonymous && index == "" {
+			continue
+		}
+		path = append(path, tagName(field, tag)+index)
+	}
+	return strings.Join(path, ".")
+}
+
+// tagName 读取字段在 json/form tag 中的名称，未声明时沿用字段名
+func tagName(field reflect.StructField, tag string) string {
+	name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+	if name == "" || name == "-" {
+		return field.Name
+	}
+	return name
+}
+
+// fieldMessage 按请求语言渲染字段校验说明；长度规则按字段类型使用字符数或元素个数的表述
+func fieldMessage(ctx context.Context, field, code, param string, kind reflect.Kind) string {
+	data := map[string]string{"Field": field, "Param": param, "Rule": code}
+
+	keys := []string{"validation." + code}
+	switch {
+	case code == "strength":
+		keys = []string{"validation.strength." + param}
+	case kind == reflect.String:
+		keys = append([]string{"validation." + code + ".string"}, keys...)
+	case kind == reflect.Slice || kind == reflect.Array || kind == reflect.Map:
+		keys = append([]string{"validation." + code + ".items"}, keys...)
+	}
+	for _, key := range keys {
+		if msg, ok := i18n.Translate(ctx, key, data); ok {
+			return msg
+		}
+	}
+	return i18n.T(ctx, "validation.rule", data)
+}
+
+// jsonTypeName 返回 Go 类型对应的 JSON 类型名
+func jsonTypeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	default:
+		return "object"
+	}
+}
@@ -0,0 +1,192 @@
+// Package idempotency makes retried mutating requests safe. A client sends the same
+// idempotency key with every attempt of one operation; the first attempt runs and its response
+// is stored in Redis together with a fingerprint of the request, later attempts get the stored
+// response back instead of running the operation again.
+package idempotency
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"example.com/classic/internal/config"
+	"example.com/classic/pkg/errors"
+	"github.com/redis/go-redis/v9"
+)
+
+// MaxKeyLength longest idempotency key accepted
+const MaxKeyLength = 255
+
+var (
+	// ErrInProgress an attempt with the same key is still running
+	ErrInProgress = errors.New(errors.ErrCodeConflict, "a request with this idempotency key is in progress")
+	// ErrMismatch the key was used before for a different request
+	ErrMismatch = errors.New(errors.ErrCodeUnprocessableEntity, "idempotency key was already used for a different request")
+	// ErrInvalidKey the key is empty or too long
+	ErrInvalidKey = errors.New(errors.ErrCodeInvalidParam, fmt.Sprintf("idempotency key must be 1 to %d characters", MaxKeyLength))
+)
+
+// Record state of an idempotency key
+type Record struct {
+	// Token identifies the attempt holding the key; only it may complete or release the key
+	Token       string `json:"token"`
+	Fingerprint string `json:"fingerprint"`
+	Completed   bool   `json:"completed"`
+	// Status HTTP status, or gRPC code
+	Status      int    `json:"status,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+// completeScript stores the response if the key is still held by the attempt
+//
+// KEYS[1] key; ARGV: token, record, ttl (ms)
+var completeScript = redis.NewScript(`
+local current = redis.call("GET", KEYS[1])
+if not current or cjson.decode(current).token ~= ARGV[1] then
+  return 0
+end
+redis.call("SET", KEYS[1], ARGV[2], "PX", ARGV[3])
+return 1
+`)
+
+// releaseScript deletes the key if it is still held by the attempt
+//
+// KEYS[1] key; ARGV: token
+var releaseScript = redis.NewScript(`
+local current = redis.call("GET", KEYS[1])
+if not current or cjson.decode(current).token ~= ARGV[1] then
+  return 0
+end
+return redis.call("DEL", KEYS[1])
+`)
+
+// Store keeps idempotency records in Redis
+type Store struct {
+	client       redis.Cmdable
+	prefix       string
+	ttl          time.Duration
+	lockTTL      time.Duration
+	maxBodyBytes int
+}
+
+// NewStore creates a store from the idempotency config; it returns nil when idempotency is disabled
+func NewStore(cfg config.IdempotencyConfig, client redis.Cmdable) *Store {
+	if !cfg.Enabled {
+		return nil
+	}
+	return &Store{
+		client:       client,
+		prefix:       cfg.Prefix,
+		ttl:          cfg.TTL,
+		lockTTL:      cfg.LockTTL,
+		maxBodyBytes: cfg.MaxBodyBytes,
+	}
+}
+
+// MaxBodyBytes largest request body fingerprinted in memory and largest response stored
+func (s *Store) MaxBodyBytes() int {
+	return s.maxBodyBytes
+}
+
+// Key builds the storage key of a client key. Keys are scoped by tenant and authenticated
+// subject, so that clients can neither replay nor block each other's requests.
+func (s *Store) Key(tenantID, subject, key string) (string, error) {
+	if key == "" || len(key) > MaxKeyLength {
+		return "", ErrInvalidKey
+	}
+	sum := sha256.Sum256([]byte(key))
+	return s.prefix + ":" + tenantID + ":" + subject + ":" + hex.EncodeToString(sum[:16]), nil
+}
+
+// Begin claims a key for an attempt of the request with the given fingerprint.
+// It returns the token of the claim when the request should run, or the completed record
+// when the response should be replayed. ErrMismatch and ErrInProgress reject the attempt.
+func (s *Store) Begin(ctx context.Context, key, fingerprint string) (token string, replay *Record, err error) {
+	token, err = newToken()
+	if err != nil {
+		return "", nil, err
+	}
+	claim, err := json.Marshal(Record{Token: token, Fingerprint: fingerprint})
+	if err != nil {
+		return "", nil, fmt.Errorf("marshal idempotency record: %w", err)
+	}
+
+	// The key can expire between SET and GET; one retry is enough since the next SET succeeds
+	for attempt := 0; attempt < 2; attempt++ {
+		ok, err := s.client.SetNX(ctx, key, claim, s.lockTTL).Result()
+		if err != nil {
+			return "", nil, fmt.Errorf("claim idempotency key: %w", err)
+		}
+		if ok {
+			return token, nil, nil
+		}
+
+		value, err := s.client.Get(ctx, key).Bytes()
+		if err == redis.Nil {
+			continue
+		}
+		if err != nil {
+			return "", nil, fmt.Errorf("get idempotency key: %w", err)
+		}
+		var record Record
+		if err := json.Unmarshal(value, &record); err != nil {
+			return "", nil, fmt.Errorf("unmarshal idempotency record: %w", err)
+		}
+		switch {
+		case record.Fingerprint != fingerprint:
+			return "", nil, ErrMismatch
+		case !record.Completed:
+			return "", nil, ErrInProgress
+		default:
+			return "", &record, nil
+		}
+	}
+	return "", nil, ErrInProgress
+}
+
+// Complete stores the response of the attempt holding the key for replays
+func (s *Store) Complete(ctx context.Context, key, token, fingerprint string, record Record) error {
+	record.Token = token
+	record.Fingerprint = fingerprint
+	record.Completed = true
+	value, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("marshal idempotency record: %w", err)
+	}
+	if err := completeScript.Run(ctx, s.client, []string{key}, token, value, s.ttl.Milliseconds()).Err(); err != nil {
+		return fmt.Errorf("complete idempotency key: %w", err)
+	}
+	return nil
+}
+
+// Release frees the key so that the request can be retried, e.g. after a server error
+func (s *Store) Release(ctx context.Context, key, token string) error {
+	if err := releaseScript.Run(ctx, s.client, []string{key}, token).Err(); err != nil {
+		return fmt.Errorf("release idempotency key: %w", err)
+	}
+	return nil
+}
+
+// Fingerprint hashes the parts that identify a request
+func Fingerprint(parts ...[]byte) string {
+	h := sha256.New()
+	for _, part := range parts {
+		// Length prefixes keep ("ab", "c") and ("a", "bc") apart
+		fmt.Fprintf(h, "%d:", len(part))
+		h.Write(part)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func newToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate idempotency token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
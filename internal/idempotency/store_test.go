@@ -0,0 +1,121 @@
+package idempotency
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"example.com/classic/internal/config"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestStore(t *testing.T) (*Store, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return NewStore(config.IdempotencyConfig{
+		Enabled:      true,
+		Prefix:       "idempotency",
+		TTL:          time.Hour,
+		LockTTL:      time.Minute,
+		MaxBodyBytes: 1 << 10,
+	}, client), mr
+}
+
+func TestStore_Replay(t *testing.T) {
+	store, mr := newTestStore(t)
+	ctx := context.Background()
+	key, err := store.Key("acme", "alice", "retry-1")
+	require.NoError(t, err)
+	fingerprint := Fingerprint([]byte("POST"), []byte("/api/v1/users"), []byte(`{"name":"alice"}`))
+
+	token, replay, err := store.Begin(ctx, key, fingerprint)
+	require.NoError(t, err)
+	assert.NotEmpty(t, token)
+	assert.Nil(t, replay)
+	assert.InDelta(t, time.Minute, mr.TTL(key), float64(time.Second))
+
+	// A retry while the first attempt runs is rejected
+	_, _, err = store.Begin(ctx, key, fingerprint)
+	assert.ErrorIs(t, err, ErrInProgress)
+
+	require.NoError(t, store.Complete(ctx, key, token, fingerprint, Record{
+		Status:      201,
+		ContentType: "application/json",
+		Body:        []byte(`{"id":1}`),
+	}))
+	assert.InDelta(t, time.Hour, mr.TTL(key), float64(time.Second))
+
+	_, replay, err = store.Begin(ctx, key, fingerprint)
+	require.NoError(t, err)
+	require.NotNil(t, replay)
+	assert.Equal(t, 201, replay.Status)
+	assert.Equal(t, "application/json", replay.ContentType)
+	assert.Equal(t, `{"id":1}`, string(replay.Body))
+
+	// The same key with a different payload
+	other := Fingerprint([]byte("POST"), []byte("/api/v1/users"), []byte(`{"name":"bob"}`))
+	_, _, err = store.Begin(ctx, key, other)
+	assert.ErrorIs(t, err, ErrMismatch)
+}
+
+func TestStore_Release(t *testing.T) {
+	store, mr := newTestStore(t)
+	ctx := context.Background()
+	key, _ := store.Key("acme", "", "retry-2")
+
+	token, _, err := store.Begin(ctx, key, "f")
+	require.NoError(t, err)
+
+	// Only the attempt holding the key can release or complete it
+	require.NoError(t, store.Release(ctx, key, "someone-else"))
+	require.NoError(t, store.Complete(ctx, key, "someone-else", "f", Record{Status: 200}))
+	_, _, err = store.Begin(ctx, key, "f")
+	assert.ErrorIs(t, err, ErrInProgress)
+
+	require.NoError(t, store.Release(ctx, key, token))
+	assert.False(t, mr.Exists(key))
+
+	// After a released or expired lock the request runs again
+	token, replay, err := store.Begin(ctx, key, "f")
+	require.NoError(t, err)
+	assert.Nil(t, replay)
+	mr.FastForward(2 * time.Minute)
+	newToken, _, err := store.Begin(ctx, key, "f")
+	require.NoError(t, err)
+	assert.NotEqual(t, token, newToken)
+
+	// The expired attempt cannot overwrite the new one
+	require.NoError(t, store.Complete(ctx, key, token, "f", Record{Status: 200}))
+	_, _, err = store.Begin(ctx, key, "f")
+	assert.ErrorIs(t, err, ErrInProgress)
+}
+
+func TestStore_Key(t *testing.T) {
+	store, _ := newTestStore(t)
+
+	a, err := store.Key("acme", "alice", "k")
+	require.NoError(t, err)
+	b, _ := store.Key("acme", "bob", "k")
+	c, _ := store.Key("globex", "alice", "k")
+	assert.NotEqual(t, a, b)
+	assert.NotEqual(t, a, c)
+	assert.True(t, strings.HasPrefix(a, "idempotency:acme:alice:"))
+
+	_, err = store.Key("acme", "alice", "")
+	assert.ErrorIs(t, err, ErrInvalidKey)
+	_, err = store.Key("acme", "alice", strings.Repeat("k", MaxKeyLength+1))
+	assert.ErrorIs(t, err, ErrInvalidKey)
+
+	assert.Nil(t, NewStore(config.IdempotencyConfig{}, nil))
+}
+
+func TestFingerprint(t *testing.T) {
+	assert.Equal(t, Fingerprint([]byte("a"), []byte("b")), Fingerprint([]byte("a"), []byte("b")))
+	assert.NotEqual(t, Fingerprint([]byte("ab"), []byte("c")), Fingerprint([]byte("a"), []byte("bc")))
+}
@@ -0,0 +1,212 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"example.com/classic/internal/domain"
+)
+
+// ciphertextPrefix marks encrypted values:
+//
+//	enc:v2:<key id>:<wrapped data key>:<nonce + ciphertext>
+//
+// The data key is random per value and wrapped (AES-GCM) with the key encryption key <key id>.
+// The value is sealed with the caller's additional data (its row and column), so a ciphertext
+// copied to another row or column no longer decrypts.
+const ciphertextPrefix = "enc:v2:"
+
+// encryptedPrefix is common to all ciphertext versions; only v2 is decrypted
+const encryptedPrefix = "enc:"
+
+// dataKeySize AES-256 data keys
+const dataKeySize = 32
+
+var encoding = base64.RawStdEncoding
+
+// envelopeCipher AES-GCM envelope encryption with an HMAC-SHA256 blind index
+type envelopeCipher struct {
+	keyring *Keyring
+}
+
+// NewEnvelopeCipher creates a PII cipher using the keyring
+func NewEnvelopeCipher(keyring *Keyring) domain.PIICipher {
+	return &envelopeCipher{keyring: keyring}
+}
+
+// LoadCipher creates the PII cipher from a keyring file; without a file PII is stored in plaintext
+func LoadCipher(keyringFile string) (domain.PIICipher, error) {
+	if keyringFile == "" {
+		return NewPlaintextCipher(), nil
+	}
+	keyring, err := LoadKeyring(keyringFile)
+	if err != nil {
+		return nil, err
+	}
+	return NewEnvelopeCipher(keyring), nil
+}
+
+// Enabled values are encrypted
+func (c *envelopeCipher) Enabled() bool {
+	return true
+}
+
+// Encrypt encrypts with a fresh data key wrapped by the primary key
+func (c *envelopeCipher) Encrypt(plaintext, aad string) (string, error) {
+	dataKey := make([]byte, dataKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", fmt.Errorf("generate data key: %w", err)
+	}
+	wrapped, err := seal(c.keyring.keys[c.keyring.primary], dataKey, nil)
+	if err != nil {
+		return "", fmt.Errorf("wrap data key: %w", err)
+	}
+	sealed, err := seal(dataKey, []byte(plaintext), []byte(aad))
+	if err != nil {
+		return "", fmt.Errorf("encrypt value: %w", err)
+	}
+	return ciphertextPrefix + c.keyring.primary + ":" + encoding.EncodeToString(wrapped) + ":" + encoding.EncodeToString(sealed), nil
+}
+
+// Decrypt decrypts values encrypted with any key of the keyring; plaintext is returned as is
+func (c *envelopeCipher) Decrypt(stored, aad string) (string, error) {
+	if !strings.HasPrefix(stored, encryptedPrefix) {
+		return stored, nil
+	}
+	stored, ok := strings.CutPrefix(stored, ciphertextPrefix)
+	if !ok {
+		return "", fmt.Errorf("unsupported ciphertext version")
+	}
+	parts := strings.Split(stored, ":")
+	if len(parts) != 3 {
+		return "", fmt.Errorf("malformed ciphertext")
+	}
+	kek, ok := c.keyring.keys[parts[0]]
+	if !ok {
+		return "", fmt.Errorf("unknown key id %q", parts[0])
+	}
+	wrapped, err := encoding.DecodeString(parts[1])
+	if err != nil {
+		return "", fmt.Errorf("decode data key: %w", err)
+	}
+	sealed, err := encoding.DecodeString(parts[2])
+	if err != nil {
+		return "", fmt.Errorf("decode ciphertext: %w", err)
+	}
+
+	dataKey, err := open(kek, wrapped, nil)
+	if err != nil {
+		return "", fmt.Errorf("unwrap data key: %w", err)
+	}
+	plaintext, err := open(dataKey, sealed, []byte(aad))
+	if err != nil {
+		return "", fmt.Errorf("decrypt value: %w", err)
+	}
+	return string(plaintext), nil
+}
+
+// BlindIndex returns the hex HMAC-SHA256 of the normalized value
+func (c *envelopeCipher) BlindIndex(value string) string {
+	mac := hmac.New(sha256.New, c.keyring.indexKey)
+	mac.Write([]byte(normalize(value)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// LookupIndexes returns the keyed index and the unkeyed one of rows written before encryption
+// was enabled, so those rows stay findable and their emails taken until rekey recomputes the index
+func (c *envelopeCipher) LookupIndexes(value string) []string {
+	return []string{c.BlindIndex(value), plaintextCipher{}.BlindIndex(value)}
+}
+
+// NeedsRekey reports plaintext values and values encrypted with a non-primary key
+func (c *envelopeCipher) NeedsRekey(stored string) bool {
+	return !strings.HasPrefix(stored, ciphertextPrefix+c.keyring.primary+":")
+}
+
+// plaintextCipher stores PII unencrypted (no keyring configured)
+type plaintextCipher struct{}
+
+// NewPlaintextCipher creates a PII cipher that does not encrypt
+func NewPlaintextCipher() domain.PIICipher {
+	return plaintextCipher{}
+}
+
+// Enabled values are not encrypted
+func (plaintextCipher) Enabled() bool {
+	return false
+}
+
+// Encrypt returns the plaintext
+func (plaintextCipher) Encrypt(plaintext, _ string) (string, error) {
+	return plaintext, nil
+}
+
+// Decrypt returns the stored value; encrypted values cannot be read without a keyring
+func (plaintextCipher) Decrypt(stored, _ string) (string, error) {
+	if strings.HasPrefix(stored, encryptedPrefix) {
+		return "", fmt.Errorf("value is encrypted but no keyring is configured")
+	}
+	return stored, nil
+}
+
+// BlindIndex returns the hex SHA-256 of the normalized value
+func (plaintextCipher) BlindIndex(value string) string {
+	sum := sha256.Sum256([]byte(normalize(value)))
+	return hex.EncodeToString(sum[:])
+}
+
+// LookupIndexes returns the blind index
+func (c plaintextCipher) LookupIndexes(value string) []string {
+	return []string{c.BlindIndex(value)}
+}
+
+// NeedsRekey nothing to rekey without a keyring
+func (plaintextCipher) NeedsRekey(string) bool {
+	return false
+}
+
+// normalize makes lookups case-insensitive, like the former unique key on the email column
+func normalize(value string) string {
+	return strings.ToLower(strings.TrimSpace(value))
+}
+
+// seal encrypts with AES-GCM and prepends the nonce
+func seal(key, plaintext, additionalData []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+// open decrypts the output of seal; additionalData must be the one passed to seal
+func open(key, sealed, additionalData []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, fmt.Errorf("ciphertext too short")
+	}
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, ciphertext, additionalData)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,154 @@
+package encryption
+
+import (
+	"bytes"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testKeyring(t *testing.T, primary string) *Keyring {
+	t.Helper()
+	keyring, err := NewKeyring(primary, map[string][]byte{
+		"k1": bytes.Repeat([]byte{1}, keySize),
+		"k2": bytes.Repeat([]byte{2}, keySize),
+	}, bytes.Repeat([]byte{9}, minIndexKeySize))
+	require.NoError(t, err)
+	return keyring
+}
+
+const testAAD = "users.email:default/1"
+
+func TestEnvelopeCipher_RoundTrip(t *testing.T) {
+	c := NewEnvelopeCipher(testKeyring(t, "k1"))
+
+	encrypted, err := c.Encrypt("alice@example.com", testAAD)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(encrypted, "enc:v2:k1:"))
+	assert.NotContains(t, encrypted, "alice")
+	assert.LessOrEqual(t, len(encrypted), 512)
+
+	// Every encryption uses a fresh data key
+	again, err := c.Encrypt("alice@example.com", testAAD)
+	require.NoError(t, err)
+	assert.NotEqual(t, encrypted, again)
+
+	decrypted, err := c.Decrypt(encrypted, testAAD)
+	require.NoError(t, err)
+	assert.Equal(t, "alice@example.com", decrypted)
+
+	// Legacy plaintext is returned as is
+	decrypted, err = c.Decrypt("bob@example.com", testAAD)
+	require.NoError(t, err)
+	assert.Equal(t, "bob@example.com", decrypted)
+
+	// Tampering is detected
+	tampered := []byte(encrypted)
+	i := len(tampered) - 10
+	if tampered[i] == 'A' {
+		tampered[i] = 'B'
+	} else {
+		tampered[i] = 'A'
+	}
+	_, err = c.Decrypt(string(tampered), testAAD)
+	assert.Error(t, err)
+}
+
+func TestEnvelopeCipher_AdditionalData(t *testing.T) {
+	keyring := testKeyring(t, "k1")
+	c := NewEnvelopeCipher(keyring)
+
+	// A value copied to another row or column does not decrypt
+	encrypted, err := c.Encrypt("alice@example.com", testAAD)
+	require.NoError(t, err)
+	_, err = c.Decrypt(encrypted, "users.email:default/2")
+	assert.Error(t, err)
+	_, err = c.Decrypt(encrypted, "users.name:default/1")
+	assert.Error(t, err)
+
+	// Values sealed without additional data are not accepted under another version prefix
+	wrapped, err := seal(keyring.keys["k1"], bytes.Repeat([]byte{7}, dataKeySize), nil)
+	require.NoError(t, err)
+	sealed, err := seal(bytes.Repeat([]byte{7}, dataKeySize), []byte("Alice"), nil)
+	require.NoError(t, err)
+	_, err = c.Decrypt("enc:v1:k1:"+encoding.EncodeToString(wrapped)+":"+encoding.EncodeToString(sealed), testAAD)
+	assert.Error(t, err)
+	assert.False(t, c.NeedsRekey(encrypted))
+}
+
+func TestEnvelopeCipher_Rotation(t *testing.T) {
+	old := NewEnvelopeCipher(testKeyring(t, "k1"))
+	rotated := NewEnvelopeCipher(testKeyring(t, "k2"))
+
+	encrypted, err := old.Encrypt("Alice", testAAD)
+	require.NoError(t, err)
+	assert.False(t, old.NeedsRekey(encrypted))
+	assert.True(t, rotated.NeedsRekey(encrypted))
+	assert.True(t, rotated.NeedsRekey("Alice"))
+
+	// Values encrypted with a retired key stay readable while it is in the keyring
+	decrypted, err := rotated.Decrypt(encrypted, testAAD)
+	require.NoError(t, err)
+	assert.Equal(t, "Alice", decrypted)
+
+	withoutOld, err := NewKeyring("k2", map[string][]byte{"k2": bytes.Repeat([]byte{2}, keySize)}, bytes.Repeat([]byte{9}, minIndexKeySize))
+	require.NoError(t, err)
+	_, err = NewEnvelopeCipher(withoutOld).Decrypt(encrypted, testAAD)
+	assert.Error(t, err)
+}
+
+func TestBlindIndex(t *testing.T) {
+	c := NewEnvelopeCipher(testKeyring(t, "k1"))
+
+	index := c.BlindIndex("alice@example.com")
+	assert.Len(t, index, 64)
+	assert.Equal(t, index, c.BlindIndex(" Alice@Example.com"))
+	assert.NotEqual(t, index, c.BlindIndex("bob@example.com"))
+	// The index is keyed
+	assert.NotEqual(t, index, NewPlaintextCipher().BlindIndex("alice@example.com"))
+
+	// Lookups also match rows indexed before encryption was enabled
+	assert.Equal(t, []string{index, NewPlaintextCipher().BlindIndex("alice@example.com")}, c.LookupIndexes("Alice@example.com"))
+	assert.Equal(t, []string{NewPlaintextCipher().BlindIndex("alice@example.com")}, NewPlaintextCipher().LookupIndexes("alice@example.com"))
+}
+
+func TestPlaintextCipher(t *testing.T) {
+	c := NewPlaintextCipher()
+	assert.False(t, c.Enabled())
+
+	stored, err := c.Encrypt("Alice", testAAD)
+	require.NoError(t, err)
+	assert.Equal(t, "Alice", stored)
+
+	encrypted, err := NewEnvelopeCipher(testKeyring(t, "k1")).Encrypt("Alice", testAAD)
+	require.NoError(t, err)
+	_, err = c.Decrypt(encrypted, testAAD)
+	assert.Error(t, err)
+}
+
+func TestLoadKeyring(t *testing.T) {
+	key := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, keySize))
+	write := func(content string) string {
+		path := filepath.Join(t.TempDir(), "keyring.json")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		return path
+	}
+
+	keyring, err := LoadKeyring(write(`{"primary": "k1", "keys": {"k1": "` + key + `"}, "blind_index_key": "` + key + `"}`))
+	require.NoError(t, err)
+	assert.Equal(t, "k1", keyring.Primary())
+
+	_, err = LoadKeyring(write(`{"primary": "k2", "keys": {"k1": "` + key + `"}, "blind_index_key": "` + key + `"}`))
+	assert.Error(t, err)
+	_, err = LoadKeyring(write(`{"primary": "k1", "keys": {"k1": "c2hvcnQ="}, "blind_index_key": "` + key + `"}`))
+	assert.Error(t, err)
+	_, err = LoadKeyring(write(`{"primary": "a:b", "keys": {"a:b": "` + key + `"}, "blind_index_key": "` + key + `"}`))
+	assert.Error(t, err)
+	_, err = LoadKeyring(write(`{"primary": "k1", "keys": {"k1": "` + key + `"}}`))
+	assert.Error(t, err)
+}
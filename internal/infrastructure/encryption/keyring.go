@@ -0,0 +1,86 @@
+package encryption
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// keySize AES-256 key encryption keys
+const keySize = 32
+
+// minIndexKeySize minimum length of the blind index HMAC key
+const minIndexKeySize = 32
+
+// keyringFile on-disk keyring format; keys are base64 encoded
+//
+//	{
+//	  "primary": "2026-10",
+//	  "keys": {"2026-01": "...", "2026-10": "..."},
+//	  "blind_index_key": "..."
+//	}
+type keyringFile struct {
+	Primary       string            `json:"primary"`
+	Keys          map[string]string `json:"keys"`
+	BlindIndexKey string            `json:"blind_index_key"`
+}
+
+// Keyring key encryption keys by ID and the blind index key
+type Keyring struct {
+	primary  string
+	keys     map[string][]byte
+	indexKey []byte
+}
+
+// LoadKeyring reads a keyring file
+func LoadKeyring(path string) (*Keyring, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read keyring: %w", err)
+	}
+	var file keyringFile
+	if err := json.Unmarshal(raw, &file); err != nil {
+		return nil, fmt.Errorf("parse keyring: %w", err)
+	}
+
+	keys := make(map[string][]byte, len(file.Keys))
+	for id, encoded := range file.Keys {
+		if id == "" || strings.Contains(id, ":") {
+			return nil, fmt.Errorf("invalid key id %q", id)
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("decode key %s: %w", id, err)
+		}
+		keys[id] = key
+	}
+
+	indexKey, err := base64.StdEncoding.DecodeString(file.BlindIndexKey)
+	if err != nil {
+		return nil, fmt.Errorf("decode blind index key: %w", err)
+	}
+	return NewKeyring(file.Primary, keys, indexKey)
+}
+
+// NewKeyring creates a keyring; primary must be one of keys
+func NewKeyring(primary string, keys map[string][]byte, indexKey []byte) (*Keyring, error) {
+	if _, ok := keys[primary]; !ok {
+		return nil, fmt.Errorf("primary key %q not in keyring", primary)
+	}
+	for id, key := range keys {
+		if len(key) != keySize {
+			return nil, fmt.Errorf("key %s must be %d bytes, got %d", id, keySize, len(key))
+		}
+	}
+	if len(indexKey) < minIndexKeySize {
+		return nil, fmt.Errorf("blind index key must be at least %d bytes", minIndexKeySize)
+	}
+	return &Keyring{primary: primary, keys: keys, indexKey: indexKey}, nil
+}
+
+// Primary returns the ID of the key used for new ciphertexts
+func (k *Keyring) Primary() string {
+	return k.primary
+}
@@ -27,6 +27,24 @@ func (h *BcryptPasswordHasher) Hash(password string) (string, error) {
 	return string(hashedBytes), nil
 }
 
+// bcryptHashLength bcrypt 哈希的固定长度 ($2a$10$ + 22 位盐 + 31 位哈希)
+const bcryptHashLength = 60
+
+// CheckHash 校验已哈希的密码：须为 bcrypt 格式，cost 不低于当前配置
+func (h *BcryptPasswordHasher) CheckHash(hashedPassword string) error {
+	if len(hashedPassword) != bcryptHashLength {
+		return fmt.Errorf("not a bcrypt hash")
+	}
+	cost, err := bcrypt.Cost([]byte(hashedPassword))
+	if err != nil {
+		return fmt.Errorf("not a bcrypt hash: %w", err)
+	}
+	if cost < h.cost {
+		return fmt.Errorf("bcrypt cost %d is below the required %d", cost, h.cost)
+	}
+	return nil
+}
+
 // Verify 验证密码
 func (h *BcryptPasswordHasher) Verify(hashedPassword, password string) error {
 	err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
@@ -17,8 +17,8 @@ type AsynqEventPublisher struct {
 	log      logger.Logger
 }
 
-// NewAsynqEventPublisher 创建 Asynq 事件发布器
-func NewAsynqEventPublisher(taskQueue taskqueue.TaskQueue, log logger.Logger) domain.EventPublisher {
+// NewAsynqEventPublisher 创建 Asynq 事件发布器，processors 在默认处理器之后注册
+func NewAsynqEventPublisher(taskQueue taskqueue.TaskQueue, log logger.Logger, processors ...domain.EventProcessor) domain.EventPublisher {
 	publisher := &AsynqEventPublisher{
 		handlers: make(map[string][]domain.EventProcessor),
 		log:      log,
@@ -29,6 +29,9 @@ func NewAsynqEventPublisher(taskQueue taskqueue.TaskQueue, log logger.Logger) do
 	publisher.RegisterHandler(NewUserStatusChangedHandler(taskQueue))
 	publisher.RegisterHandler(NewUserUpdatedHandler(taskQueue))
 	publisher.RegisterHandler(NewUserDeletedHandler(taskQueue))
+	for _, processor := range processors {
+		publisher.RegisterHandler(processor)
+	}
 
 	return publisher
 }
@@ -59,7 +62,7 @@ func NewUserCreatedHandler(taskQueue taskqueue.TaskQueue) domain.EventProcessor
 
 func (h *UserCreatedHandler) Process(event domain.DomainEvent) error {
 	if userEvent, ok := event.(*domain.UserCreatedEvent); ok {
-		task := asynq.NewWelcomeEmailTaskV2(userEvent.UserID, userEvent.Email, userEvent.Name)
+		task := asynq.NewWelcomeEmailTaskV2(userEvent.UserID, userEvent.Email, userEvent.Name, userEvent.Locale)
 		const delaySeconds = 10
 		if _, err := h.taskQueue.EnqueueIn(context.Background(), task, time.Duration(delaySeconds)*time.Second); err != nil {
 			return fmt.Errorf("failed to enqueue welcome email task: %w", err)
@@ -92,6 +95,7 @@ func (h *UserStatusChangedHandler) Process(event domain.DomainEvent) error {
 			string(userEvent.OldStatus),
 			string(userEvent.NewStatus),
 			"system",
+			userEvent.Locale,
 		)
 		if _, err := h.taskQueue.Enqueue(context.Background(), task); err != nil {
 			return fmt.Errorf("failed to enqueue status change notification task: %w", err)
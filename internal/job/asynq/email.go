@@ -0,0 +1,30 @@
+package asynq
+
+import (
+	"example.com/classic/pkg/i18n"
+)
+
+// Email 渲染后的通知邮件
+type Email struct {
+	To      string
+	Locale  string
+	Subject string
+	Body    string
+}
+
+// renderEmail 用消息目录中的 email.<name>.subject 与 email.<name>.body 模板渲染邮件，
+// 缺失的翻译按目录的回退链处理
+func renderEmail(locale, name, to string, data map[string]string) Email {
+	catalog := i18n.Default()
+	subject, _ := catalog.Render(locale, "email."+name+".subject", data)
+	body, _ := catalog.Render(locale, "email."+name+".body", data)
+	return Email{To: to, Locale: locale, Subject: subject, Body: body}
+}
+
+// statusName 用户状态的本地化名称，没有翻译时保持原值
+func statusName(locale, status string) string {
+	if name, ok := i18n.Default().Render(locale, "status."+status, nil); ok {
+		return name
+	}
+	return status
+}
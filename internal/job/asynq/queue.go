@@ -2,12 +2,15 @@ package asynq
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
 	"example.com/classic/internal/config"
 	"example.com/classic/internal/taskqueue"
 	"example.com/classic/pkg/logger"
+	"example.com/classic/pkg/metrics"
 	"github.com/hibiken/asynq"
 )
 
@@ -15,15 +18,17 @@ import (
 var _ taskqueue.TaskQueue = (*Queue)(nil)
 
 type Queue struct {
-	client   *asynq.Client
-	server   *asynq.Server
+	client    *asynq.Client
+	server    *asynq.Server
+	inspector *asynq.Inspector
 	config   *config.Config
 	log      logger.Logger
+	metrics  *metrics.Metrics
 	handlers map[string]asynq.HandlerFunc
 }
 
 // New 创建任务队列
-func New(cfg *config.Config, log logger.Logger) (*Queue, error) {
+func New(cfg *config.Config, log logger.Logger, m *metrics.Metrics) (*Queue, error) {
 	// 创建 Redis 连接选项
 	redisOpt := asynq.RedisClientOpt{
 		Addr:     cfg.Asynq.RedisAddr,
@@ -47,10 +52,12 @@ func New(cfg *config.Config, log logger.Logger) (*Queue, error) {
 	)
 
 	queue := &Queue{
-		client:   client,
-		server:   server,
+		client:    client,
+		server:    server,
+		inspector: asynq.NewInspector(redisOpt),
 		config:   cfg,
 		log:      log,
+		metrics:  m,
 		handlers: make(map[string]asynq.HandlerFunc),
 	}
 
@@ -68,6 +75,7 @@ func (q *Queue) Start(ctx context.Context) error {
 
 	// 创建多路复用器
 	mux := asynq.NewServeMux()
+	mux.Use(q.metricsMiddleware)
 
 	// 注册任务处理器
 	for taskType, handler := range q.handlers {
@@ -85,6 +93,7 @@ func (q *Queue) Stop(ctx context.Context) error {
 
 	// 优雅关闭客户端
 	q.client.Close()
+	q.inspector.Close()
 
 	// 优雅关闭服务器
 	q.server.Shutdown()
@@ -92,6 +101,11 @@ func (q *Queue) Stop(ctx context.Context) error {
 	return nil
 }
 
+// Ping 检查 broker (Redis) 连接；asynq 客户端不接受 context，超时由调用方控制
+func (q *Queue) Ping(ctx context.Context) error {
+	return q.client.Ping()
+}
+
 // Enqueue 入队任务 (实现 taskqueue.TaskQueue 接口)
 func (q *Queue) Enqueue(ctx context.Context, task *taskqueue.Task, opts ...taskqueue.Option) (*taskqueue.TaskResult, error) {
 	options := taskqueue.DefaultEnqueueOptions()
@@ -103,6 +117,7 @@ func (q *Queue) Enqueue(ctx context.Context, task *taskqueue.Task, opts ...taskq
 	asynqOpts := q.convertOptions(options)
 
 	info, err := q.client.Enqueue(asynqTask, asynqOpts...)
+	q.metrics.ObserveEnqueue(task.Type, options.Queue, err)
 	if err != nil {
 		q.log.Error(ctx, "failed to enqueue task",
 			logger.Err(err),
@@ -134,6 +149,7 @@ func (q *Queue) EnqueueIn(ctx context.Context, task *taskqueue.Task, delay time.
 	asynqOpts = append(asynqOpts, asynq.ProcessIn(delay))
 
 	info, err := q.client.Enqueue(asynqTask, asynqOpts...)
+	q.metrics.ObserveEnqueue(task.Type, options.Queue, err)
 	if err != nil {
 		q.log.Error(ctx, "failed to enqueue delayed task",
 			logger.Err(err),
@@ -161,6 +177,7 @@ func (q *Queue) EnqueueAt(ctx context.Context, task *taskqueue.Task, processAt t
 	asynqOpts = append(asynqOpts, asynq.ProcessAt(processAt))
 
 	info, err := q.client.Enqueue(asynqTask, asynqOpts...)
+	q.metrics.ObserveEnqueue(task.Type, options.Queue, err)
 	if err != nil {
 		q.log.Error(ctx, "failed to enqueue scheduled task",
 			logger.Err(err),
@@ -180,14 +197,65 @@ func (q *Queue) EnqueueAt(ctx context.Context, task *taskqueue.Task, processAt t
 func (q *Queue) RegisterHandler(taskType string, handler taskqueue.Handler) error {
 	q.handlers[taskType] = func(ctx context.Context, t *asynq.Task) error {
 		return handler.Process(ctx, &taskqueue.Task{
-			Type:    t.Type(),
-			Payload: t.Payload(),
+			Type:         t.Type(),
+			Payload:      t.Payload(),
+			ID:           t.ResultWriter().TaskID(),
+			ResultWriter: t.ResultWriter(),
 		})
 	}
 	q.log.Debug(context.Background(), "registered task handler", logger.String("task_type", taskType))
 	return nil
 }
 
+// GetTaskInfo 查询任务状态 (实现 taskqueue.TaskQueue 接口)
+func (q *Queue) GetTaskInfo(ctx context.Context, queue, id string) (*taskqueue.TaskInfo, error) {
+	info, err := q.inspector.GetTaskInfo(queue, id)
+	if err != nil {
+		if errors.Is(err, asynq.ErrTaskNotFound) || errors.Is(err, asynq.ErrQueueNotFound) {
+			return nil, taskqueue.ErrTaskNotFound
+		}
+		return nil, fmt.Errorf("failed to get task info: %w", err)
+	}
+
+	return &taskqueue.TaskInfo{
+		ID:          info.ID,
+		Queue:       info.Queue,
+		Type:        info.Type,
+		State:       convertState(info.State),
+		Payload:     info.Payload,
+		Result:      info.Result,
+		LastErr:     info.LastErr,
+		CompletedAt: info.CompletedAt,
+	}, nil
+}
+
+// metricsMiddleware 记录每个任务的处理结果与耗时
+func (q *Queue) metricsMiddleware(next asynq.Handler) asynq.Handler {
+	return asynq.HandlerFunc(func(ctx context.Context, t *asynq.Task) error {
+		start := time.Now()
+		err := next.ProcessTask(ctx, t)
+		q.metrics.ObserveTask(t.Type(), time.Since(start), err)
+		return err
+	})
+}
+
+// convertState 转换 asynq 任务状态
+func convertState(state asynq.TaskState) taskqueue.TaskState {
+	switch state {
+	case asynq.TaskStateActive:
+		return taskqueue.TaskStateActive
+	case asynq.TaskStateRetry:
+		return taskqueue.TaskStateRetry
+	case asynq.TaskStateCompleted:
+		return taskqueue.TaskStateCompleted
+	case asynq.TaskStateArchived:
+		return taskqueue.TaskStateFailed
+	default:
+		// pending, scheduled, aggregating
+		return taskqueue.TaskStatePending
+	}
+}
+
 // convertOptions 转换选项为 asynq 选项
 func (q *Queue) convertOptions(opts *taskqueue.EnqueueOptions) []asynq.Option {
 	var asynqOpts []asynq.Option
@@ -207,6 +275,9 @@ func (q *Queue) convertOptions(opts *taskqueue.EnqueueOptions) []asynq.Option {
 	if opts.TaskID != "" {
 		asynqOpts = append(asynqOpts, asynq.TaskID(opts.TaskID))
 	}
+	if opts.Retention > 0 {
+		asynqOpts = append(asynqOpts, asynq.Retention(opts.Retention))
+	}
 
 	return asynqOpts
 }
@@ -239,10 +310,19 @@ func (q *Queue) handleWelcomeEmail(ctx context.Context, t *asynq.Task) error {
 		logger.F("task_id", t.ResultWriter().TaskID()),
 		logger.F("payload", string(t.Payload())))
 
-	// 这里实现发送欢迎邮件的逻辑
-	// 例如：解析任务数据，调用邮件服务等
-
-	q.log.Info(ctx, "welcome email task completed successfully")
+	var payload WelcomeEmailPayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		return fmt.Errorf("decode welcome email payload: %v: %w", err, asynq.SkipRetry)
+	}
+	email := renderEmail(q.emailLocale(payload.Locale), "welcome", payload.Email, map[string]string{
+		"Name":  payload.UserName,
+		"Email": payload.Email,
+	})
+
+	// 这里调用邮件服务发送渲染好的邮件
+	q.log.Info(ctx, "welcome email task completed successfully",
+		logger.String("locale", email.Locale),
+		logger.String("subject", email.Subject))
 	return nil
 }
 
@@ -252,13 +332,32 @@ func (q *Queue) handleStatusChangeNotification(ctx context.Context, t *asynq.Tas
 		logger.F("task_id", t.ResultWriter().TaskID()),
 		logger.F("payload", string(t.Payload())))
 
-	// 这里实现状态变更通知的逻辑
-	// 例如：发送邮件、短信、推送通知等
-
-	q.log.Info(ctx, "status change notification task completed successfully")
+	var payload StatusChangeNotificationPayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		return fmt.Errorf("decode status change payload: %v: %w", err, asynq.SkipRetry)
+	}
+	locale := q.emailLocale(payload.Locale)
+	email := renderEmail(locale, "status_changed", payload.Email, map[string]string{
+		"Name":      payload.UserName,
+		"OldStatus": statusName(locale, payload.OldStatus),
+		"NewStatus": statusName(locale, payload.NewStatus),
+	})
+
+	// 这里调用邮件服务发送渲染好的邮件 (也可发送短信、推送通知等)
+	q.log.Info(ctx, "status change notification task completed successfully",
+		logger.String("locale", email.Locale),
+		logger.String("subject", email.Subject))
 	return nil
 }
 
+// emailLocale 邮件语言：任务载荷中记录的请求语言，未记录时使用配置的默认语言
+func (q *Queue) emailLocale(locale string) string {
+	if locale != "" {
+		return locale
+	}
+	return q.config.I18n.DefaultLocale
+}
+
 // handleDataCleanup 处理数据清理任务
 func (q *Queue) handleDataCleanup(ctx context.Context, t *asynq.Task) error {
 	q.log.Info(ctx, "processing data cleanup task",
@@ -20,6 +20,7 @@ type WelcomeEmailPayload struct {
 	UserID    int    `json:"user_id"`
 	Email     string `json:"email"`
 	UserName  string `json:"user_name"`
+	Locale    string `json:"locale,omitempty"` // 邮件语言，为空时使用默认语言
 	Timestamp int64  `json:"timestamp"`
 }
 
@@ -31,6 +32,7 @@ type StatusChangeNotificationPayload struct {
 	OldStatus string `json:"old_status"`
 	NewStatus string `json:"new_status"`
 	ChangedBy string `json:"changed_by"`
+	Locale    string `json:"locale,omitempty"` // 邮件语言，为空时使用默认语言
 	Timestamp int64  `json:"timestamp"`
 }
 
@@ -109,11 +111,12 @@ func NewPriorityTask(taskType string, payload interface{}, priority int) *asynq.
 // ========== taskqueue.Task 构造函数 (推荐使用) ==========
 
 // NewWelcomeEmailTaskV2 创建欢迎邮件任务 (返回 taskqueue.Task)
-func NewWelcomeEmailTaskV2(userID int, email, userName string) *taskqueue.Task {
+func NewWelcomeEmailTaskV2(userID int, email, userName, locale string) *taskqueue.Task {
 	payload := WelcomeEmailPayload{
 		UserID:    userID,
 		Email:     email,
 		UserName:  userName,
+		Locale:    locale,
 		Timestamp: time.Now().Unix(),
 	}
 
@@ -125,7 +128,7 @@ func NewWelcomeEmailTaskV2(userID int, email, userName string) *taskqueue.Task {
 }
 
 // NewStatusChangeNotificationTaskV2 创建状态变更通知任务 (返回 taskqueue.Task)
-func NewStatusChangeNotificationTaskV2(userID int, email, userName, oldStatus, newStatus, changedBy string) *taskqueue.Task {
+func NewStatusChangeNotificationTaskV2(userID int, email, userName, oldStatus, newStatus, changedBy, locale string) *taskqueue.Task {
 	payload := StatusChangeNotificationPayload{
 		UserID:    userID,
 		Email:     email,
@@ -133,6 +136,7 @@ func NewStatusChangeNotificationTaskV2(userID int, email, userName, oldStatus, n
 		OldStatus: oldStatus,
 		NewStatus: newStatus,
 		ChangedBy: changedBy,
+		Locale:    locale,
 		Timestamp: time.Now().Unix(),
 	}
 
@@ -0,0 +1,266 @@
+// Package ratelimit limits requests per client IP, authenticated user, API key or route with GCRA.
+// Counters live in Redis so that all instances share them; while Redis is unavailable each
+// instance falls back to an in-process limiter.
+package ratelimit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"example.com/classic/internal/config"
+	"example.com/classic/pkg/logger"
+)
+
+// Key types of a rule
+const (
+	KeyIP     = "ip"
+	KeyUser   = "user"
+	KeyAPIKey = "api_key"
+	KeyRoute  = "route"
+)
+
+// storeRetryInterval how long the in-process limiter is used after a store error
+const storeRetryInterval = 5 * time.Second
+
+// Limit allows Burst requests at once and Rate requests per Period on average
+type Limit struct {
+	Rate   int
+	Period time.Duration
+	Burst  int
+}
+
+// Result outcome of a request against a limit
+type Result struct {
+	Allowed bool
+	Limit   Limit
+	// Remaining requests allowed right now
+	Remaining int
+	// RetryAfter when denied, time until the request would be allowed
+	RetryAfter time.Duration
+	// ResetAfter time until the full burst is available again
+	ResetAfter time.Duration
+}
+
+// Headers returns the RateLimit-* headers (IETF draft) and, when denied, Retry-After
+func (r Result) Headers() map[string]string {
+	headers := map[string]string{
+		"RateLimit-Limit":     strconv.Itoa(r.Limit.Burst),
+		"RateLimit-Remaining": strconv.Itoa(r.Remaining),
+		"RateLimit-Reset":     strconv.Itoa(ceilSeconds(r.ResetAfter)),
+		"RateLimit-Policy":    fmt.Sprintf("%d;w=%d", r.Limit.Rate, ceilSeconds(r.Limit.Period)),
+	}
+	if !r.Allowed {
+		headers["Retry-After"] = strconv.Itoa(ceilSeconds(r.RetryAfter))
+	}
+	return headers
+}
+
+// Store applies GCRA to a key
+type Store interface {
+	Allow(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// Request the attributes of a request that rules match on and keys are built from
+type Request struct {
+	// GRPC selects the Methods of the rules instead of their Routes
+	GRPC bool
+	// Method HTTP method; empty for gRPC
+	Method string
+	// Route HTTP route template, or the full gRPC method
+	Route    string
+	ClientIP string
+	// Subject authenticated user; requests without one are keyed by IP
+	Subject string
+	// APIKey requests without one are keyed by IP
+	APIKey string
+}
+
+// rule a configured rule with its parsed matchers
+type rule struct {
+	name    string
+	key     string
+	limit   Limit
+	routes  []routeMatcher
+	methods []string
+}
+
+type routeMatcher struct {
+	method string // empty matches every method
+	route  string
+}
+
+// Limiter applies the configured rules
+type Limiter struct {
+	rules        []rule
+	prefix       string
+	apiKeyHeader string
+	store        Store
+	fallback     Store
+	log          logger.Logger
+	now          func() time.Time
+	// storeRetryAt unix nanoseconds until which the store is skipped after an error
+	storeRetryAt atomic.Int64
+}
+
+// NewLimiter creates a limiter from the rate limit config; store may be nil to limit in-process only.
+// It returns nil when rate limiting is disabled or no rule is configured.
+func NewLimiter(cfg config.RateLimitConfig, store Store, log logger.Logger) (*Limiter, error) {
+	if !cfg.Enabled || len(cfg.Rules) == 0 {
+		return nil, nil
+	}
+
+	rules := make([]rule, 0, len(cfg.Rules))
+	for i, rc := range cfg.Rules {
+		r, err := parseRule(i, rc)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, r)
+	}
+	return &Limiter{
+		rules:        rules,
+		prefix:       cfg.Prefix,
+		apiKeyHeader: cfg.APIKeyHeader,
+		store:        store,
+		fallback:     NewMemoryStore(),
+		log:          log,
+		now:          time.Now,
+	}, nil
+}
+
+func parseRule(index int, rc config.RateLimitRule) (rule, error) {
+	name := rc.Name
+	if name == "" {
+		name = "rule" + strconv.Itoa(index)
+	}
+	if rc.Limit <= 0 || rc.Period <= 0 {
+		return rule{}, fmt.Errorf("rate limit rule %q: limit and period must be positive", name)
+	}
+	if rc.Burst < 0 {
+		return rule{}, fmt.Errorf("rate limit rule %q: burst must not be negative", name)
+	}
+	switch rc.Key {
+	case KeyIP, KeyUser, KeyAPIKey, KeyRoute:
+	default:
+		return rule{}, fmt.Errorf("rate limit rule %q: unknown key %q", name, rc.Key)
+	}
+	if len(rc.Routes) == 0 && len(rc.Methods) == 0 {
+		return rule{}, fmt.Errorf("rate limit rule %q: no routes or methods", name)
+	}
+
+	burst := rc.Burst
+	if burst == 0 {
+		burst = rc.Limit
+	}
+	r := rule{
+		name:    name,
+		key:     rc.Key,
+		limit:   Limit{Rate: rc.Limit, Period: rc.Period, Burst: burst},
+		methods: rc.Methods,
+	}
+	for _, route := range rc.Routes {
+		method, path, ok := strings.Cut(strings.TrimSpace(route), " ")
+		if !ok {
+			method, path = "", method
+		}
+		r.routes = append(r.routes, routeMatcher{method: strings.ToUpper(method), route: strings.TrimSpace(path)})
+	}
+	return r, nil
+}
+
+// APIKeyHeader returns the header carrying API keys
+func (l *Limiter) APIKeyHeader() string {
+	return l.apiKeyHeader
+}
+
+// Allow counts the request against every matching rule. The result is the most restrictive one;
+// matched is false when no rule applies.
+func (l *Limiter) Allow(ctx context.Context, req Request) (result Result, matched bool) {
+	for _, r := range l.rules {
+		if !r.matches(req) {
+			continue
+		}
+		current := l.allow(ctx, l.key(r, req), r.limit)
+		if !matched || moreRestrictive(current, result) {
+			result = current
+		}
+		matched = true
+	}
+	if !matched {
+		return Result{Allowed: true}, false
+	}
+	return result, true
+}
+
+// allow uses the store, or the in-process limiter while the store is failing
+func (l *Limiter) allow(ctx context.Context, key string, limit Limit) Result {
+	if l.store != nil && l.now().UnixNano() >= l.storeRetryAt.Load() {
+		result, err := l.store.Allow(ctx, key, limit)
+		if err == nil {
+			return result
+		}
+		l.storeRetryAt.Store(l.now().Add(storeRetryInterval).UnixNano())
+		l.log.Warn(ctx, "rate limit store unavailable, limiting in-process", logger.Err(err))
+	}
+	result, _ := l.fallback.Allow(ctx, key, limit)
+	return result
+}
+
+// key builds the counter key; user and API key rules fall back to the client IP
+func (l *Limiter) key(r rule, req Request) string {
+	var subject string
+	switch {
+	case r.key == KeyRoute:
+		subject = "route:" + req.Method + " " + req.Route
+	case r.key == KeyUser && req.Subject != "":
+		subject = "user:" + req.Subject
+	case r.key == KeyAPIKey && req.APIKey != "":
+		// API keys are secrets; only their hash is stored
+		sum := sha256.Sum256([]byte(req.APIKey))
+		subject = "api_key:" + hex.EncodeToString(sum[:16])
+	default:
+		subject = "ip:" + req.ClientIP
+	}
+	return l.prefix + ":" + r.name + ":" + subject
+}
+
+// matches reports whether the rule applies to the request
+func (r rule) matches(req Request) bool {
+	if req.GRPC {
+		for _, method := range r.methods {
+			if method == "*" || method == req.Route ||
+				(strings.HasSuffix(method, "/*") && strings.HasPrefix(req.Route, strings.TrimSuffix(method, "*"))) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, m := range r.routes {
+		if (m.route == "*" || m.route == req.Route) && (m.method == "" || m.method == req.Method) {
+			return true
+		}
+	}
+	return false
+}
+
+// moreRestrictive reports whether a should be reported instead of b
+func moreRestrictive(a, b Result) bool {
+	if a.Allowed != b.Allowed {
+		return !a.Allowed
+	}
+	if !a.Allowed {
+		return a.RetryAfter > b.RetryAfter
+	}
+	return a.Remaining < b.Remaining
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
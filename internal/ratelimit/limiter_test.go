@@ -0,0 +1,190 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"example.com/classic/internal/config"
+	"example.com/classic/pkg/logger"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStore_GCRA(t *testing.T) {
+	store := NewMemoryStore()
+	now := time.Now()
+	store.now = func() time.Time { return now }
+	limit := Limit{Rate: 3, Period: 3 * time.Second, Burst: 3}
+	ctx := context.Background()
+
+	for want := 2; want >= 0; want-- {
+		result, err := store.Allow(ctx, "k", limit)
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, want, result.Remaining)
+	}
+
+	result, _ := store.Allow(ctx, "k", limit)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, time.Second, result.RetryAfter)
+	assert.Equal(t, 3*time.Second, result.ResetAfter)
+
+	// One emission interval later one request is allowed again
+	now = now.Add(time.Second)
+	result, _ = store.Allow(ctx, "k", limit)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+
+	// Other keys are independent
+	result, _ = store.Allow(ctx, "other", limit)
+	assert.True(t, result.Allowed)
+}
+
+func TestRedisStore_GCRA(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	store := NewRedisStore(client)
+	limit := Limit{Rate: 2, Period: time.Minute, Burst: 2}
+	ctx := context.Background()
+
+	for want := 1; want >= 0; want-- {
+		result, err := store.Allow(ctx, "ratelimit:test:ip:10.0.0.1", limit)
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, want, result.Remaining)
+	}
+
+	result, err := store.Allow(ctx, "ratelimit:test:ip:10.0.0.1", limit)
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.InDelta(t, 30*time.Second, result.RetryAfter, float64(time.Second))
+	assert.InDelta(t, time.Minute, result.ResetAfter, float64(time.Second))
+
+	// The key expires once the burst is fully available again
+	ttl := mr.TTL("ratelimit:test:ip:10.0.0.1")
+	assert.InDelta(t, time.Minute, ttl, float64(time.Second))
+}
+
+// failingStore counts calls and always fails, like an unreachable Redis
+type failingStore struct {
+	calls int
+}
+
+func (s *failingStore) Allow(context.Context, string, Limit) (Result, error) {
+	s.calls++
+	return Result{}, errors.New("dial tcp: connection refused")
+}
+
+func newTestLimiter(t *testing.T, store Store, rules ...config.RateLimitRule) *Limiter {
+	t.Helper()
+	limiter, err := NewLimiter(config.RateLimitConfig{
+		Enabled:      true,
+		Prefix:       "ratelimit",
+		APIKeyHeader: "X-API-Key",
+		Rules:        rules,
+	}, store, logger.New("test", "error", false))
+	require.NoError(t, err)
+	return limiter
+}
+
+func TestLimiter_FallbackWhenStoreFails(t *testing.T) {
+	store := &failingStore{}
+	limiter := newTestLimiter(t, store, config.RateLimitRule{
+		Name: "register", Routes: []string{"POST /api/v1/users"}, Key: KeyIP, Limit: 1, Period: time.Minute,
+	})
+	req := Request{Method: "POST", Route: "/api/v1/users", ClientIP: "10.0.0.1"}
+	ctx := context.Background()
+
+	result, matched := limiter.Allow(ctx, req)
+	assert.True(t, matched)
+	assert.True(t, result.Allowed)
+	result, _ = limiter.Allow(ctx, req)
+	assert.False(t, result.Allowed)
+
+	// The failing store is not retried on every request
+	assert.Equal(t, 1, store.calls)
+}
+
+func TestLimiter_RulesAndKeys(t *testing.T) {
+	limiter := newTestLimiter(t, nil,
+		config.RateLimitRule{Name: "global", Routes: []string{"*"}, Methods: []string{"*"}, Key: KeyIP, Limit: 100, Period: time.Second},
+		config.RateLimitRule{Name: "register", Routes: []string{"POST /api/v1/users"}, Key: KeyIP, Limit: 1, Period: time.Minute},
+		config.RateLimitRule{Name: "per-user", Routes: []string{"/api/v1/users/:id"}, Key: KeyUser, Limit: 1, Period: time.Minute},
+		config.RateLimitRule{Name: "partner", Methods: []string{"/user.UserService/*"}, Key: KeyAPIKey, Limit: 1, Period: time.Minute},
+	)
+	ctx := context.Background()
+
+	// The most restrictive matching rule is reported
+	register := Request{Method: "POST", Route: "/api/v1/users", ClientIP: "10.0.0.1"}
+	result, _ := limiter.Allow(ctx, register)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 1, result.Limit.Rate)
+	result, _ = limiter.Allow(ctx, register)
+	assert.False(t, result.Allowed)
+
+	// Listing users only matches the global rule
+	result, _ = limiter.Allow(ctx, Request{Method: "GET", Route: "/api/v1/users", ClientIP: "10.0.0.1"})
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 100, result.Limit.Rate)
+
+	// Users are limited separately; anonymous requests are keyed by IP
+	get := func(subject, ip string) bool {
+		result, _ := limiter.Allow(ctx, Request{Method: "GET", Route: "/api/v1/users/:id", ClientIP: ip, Subject: subject})
+		return result.Allowed
+	}
+	assert.True(t, get("alice", "10.0.0.1"))
+	assert.False(t, get("alice", "10.0.0.2"))
+	assert.True(t, get("bob", "10.0.0.1"))
+	assert.True(t, get("", "10.0.0.3"))
+	assert.False(t, get("", "10.0.0.3"))
+
+	// gRPC requests match methods, not routes
+	call := Request{GRPC: true, Route: "/user.UserService/GetByID", ClientIP: "10.0.0.1", APIKey: "secret"}
+	result, _ = limiter.Allow(ctx, call)
+	assert.True(t, result.Allowed)
+	result, _ = limiter.Allow(ctx, call)
+	assert.False(t, result.Allowed)
+	assert.NotContains(t, limiter.key(limiter.rules[3], call), "secret")
+
+	_, matched := limiter.Allow(ctx, Request{GRPC: true, Route: "/grpc.health.v1.Health/Check", ClientIP: "10.0.0.9"})
+	assert.True(t, matched) // global rule
+}
+
+func TestNewLimiter(t *testing.T) {
+	log := logger.New("test", "error", false)
+
+	limiter, err := NewLimiter(config.RateLimitConfig{Enabled: true}, nil, log)
+	require.NoError(t, err)
+	assert.Nil(t, limiter)
+
+	_, err = NewLimiter(config.RateLimitConfig{Enabled: true, Rules: []config.RateLimitRule{
+		{Routes: []string{"*"}, Key: "session", Limit: 1, Period: time.Second},
+	}}, nil, log)
+	assert.Error(t, err)
+
+	_, err = NewLimiter(config.RateLimitConfig{Enabled: true, Rules: []config.RateLimitRule{
+		{Routes: []string{"*"}, Key: KeyIP, Limit: 0, Period: time.Second},
+	}}, nil, log)
+	assert.Error(t, err)
+}
+
+func TestResult_Headers(t *testing.T) {
+	result := Result{
+		Allowed:    false,
+		Limit:      Limit{Rate: 10, Period: time.Minute, Burst: 5},
+		RetryAfter: 1500 * time.Millisecond,
+		ResetAfter: 30 * time.Second,
+	}
+	assert.Equal(t, map[string]string{
+		"RateLimit-Limit":     "5",
+		"RateLimit-Remaining": "0",
+		"RateLimit-Reset":     "30",
+		"RateLimit-Policy":    "10;w=60",
+		"Retry-After":         "2",
+	}, result.Headers())
+}
@@ -0,0 +1,76 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// memorySweepInterval how often expired keys are dropped
+const memorySweepInterval = time.Minute
+
+// MemoryStore in-process GCRA store; limits apply per instance
+type MemoryStore struct {
+	mu        sync.Mutex
+	tats      map[string]time.Time
+	nextSweep time.Time
+	now       func() time.Time
+}
+
+// NewMemoryStore creates an empty in-process store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		tats: make(map[string]time.Time),
+		now:  time.Now,
+	}
+}
+
+// Allow implements Store
+func (s *MemoryStore) Allow(_ context.Context, key string, limit Limit) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if now.After(s.nextSweep) {
+		for k, tat := range s.tats {
+			if tat.Before(now) {
+				delete(s.tats, k)
+			}
+		}
+		s.nextSweep = now.Add(memorySweepInterval)
+	}
+
+	tat, result := gcra(now, s.tats[key], limit)
+	if result.Allowed {
+		s.tats[key] = tat
+	}
+	return result, nil
+}
+
+// gcra applies the generic cell rate algorithm. tat is the theoretical arrival time of the
+// next request; a request is allowed when tat is at most Burst emission intervals ahead of now.
+func gcra(now, tat time.Time, limit Limit) (time.Time, Result) {
+	emission := limit.Period / time.Duration(limit.Rate)
+	burstOffset := emission * time.Duration(limit.Burst)
+
+	if tat.Before(now) {
+		tat = now
+	}
+	newTAT := tat.Add(emission)
+	allowAt := newTAT.Add(-burstOffset)
+
+	if diff := now.Sub(allowAt); diff < 0 {
+		return tat, Result{
+			Allowed:    false,
+			Limit:      limit,
+			RetryAfter: -diff,
+			ResetAfter: tat.Sub(now),
+		}
+	}
+	return newTAT, Result{
+		Allowed:    true,
+		Limit:      limit,
+		Remaining:  int(now.Sub(allowAt) / emission),
+		ResetAfter: newTAT.Sub(now),
+	}
+}
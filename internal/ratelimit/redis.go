@@ -0,0 +1,97 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// gcraScript GCRA in a single round trip. The clock is the Redis server's, so instances with
+// skewed clocks share one timeline. Durations are in milliseconds; fractional values are
+// returned as strings because Redis truncates Lua numbers to integers.
+//
+// KEYS[1] key; ARGV: rate, period (ms), burst
+// Returns: allowed (0/1), remaining, retry_after (ms), reset_after (ms)
+var gcraScript = redis.NewScript(`
+redis.replicate_commands()
+
+local rate = tonumber(ARGV[1])
+local period = tonumber(ARGV[2])
+local burst = tonumber(ARGV[3])
+local emission = period / rate
+local burst_offset = emission * burst
+
+local time = redis.call("TIME")
+local now = tonumber(time[1]) * 1000 + tonumber(time[2]) / 1000
+
+local tat = tonumber(redis.call("GET", KEYS[1]))
+if not tat or tat < now then
+  tat = now
+end
+
+local new_tat = tat + emission
+local allow_at = new_tat - burst_offset
+local diff = now - allow_at
+if diff < 0 then
+  return {0, 0, tostring(-diff), tostring(tat - now)}
+end
+
+local reset_after = new_tat - now
+redis.call("SET", KEYS[1], tostring(new_tat), "PX", math.ceil(reset_after))
+return {1, math.floor(diff / emission), "0", tostring(reset_after)}
+`)
+
+// RedisStore GCRA store shared by all instances
+type RedisStore struct {
+	client redis.Scripter
+}
+
+// NewRedisStore creates a store on a Redis client
+func NewRedisStore(client redis.Scripter) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+// Allow implements Store
+func (s *RedisStore) Allow(ctx context.Context, key string, limit Limit) (Result, error) {
+	values, err := gcraScript.Run(ctx, s.client, []string{key},
+		limit.Rate, limit.Period.Milliseconds(), limit.Burst).Slice()
+	if err != nil {
+		return Result{}, fmt.Errorf("run rate limit script: %w", err)
+	}
+	if len(values) != 4 {
+		return Result{}, fmt.Errorf("unexpected rate limit script result: %v", values)
+	}
+
+	allowed, _ := values[0].(int64)
+	remaining, _ := values[1].(int64)
+	retryAfter, err := parseMillis(values[2])
+	if err != nil {
+		return Result{}, err
+	}
+	resetAfter, err := parseMillis(values[3])
+	if err != nil {
+		return Result{}, err
+	}
+	return Result{
+		Allowed:    allowed == 1,
+		Limit:      limit,
+		Remaining:  int(remaining),
+		RetryAfter: retryAfter,
+		ResetAfter: resetAfter,
+	}, nil
+}
+
+func parseMillis(value interface{}) (time.Duration, error) {
+	s, ok := value.(string)
+	if !ok {
+		return 0, fmt.Errorf("unexpected rate limit duration: %v", value)
+	}
+	ms, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parse rate limit duration: %w", err)
+	}
+	return time.Duration(ms * float64(time.Millisecond)), nil
+}
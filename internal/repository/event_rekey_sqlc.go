@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+
+	"example.com/classic/internal/data"
+	"example.com/classic/internal/data/db"
+	"example.com/classic/internal/domain"
+	"example.com/classic/pkg/errors"
+	"example.com/classic/pkg/logger"
+)
+
+// EventRekeyResult outcome of an event rekey run
+type EventRekeyResult struct {
+	// Scanned events of all tenants
+	Scanned int
+	// Rekeyed events whose PII fields were re-encrypted with the primary key
+	Rekeyed int
+	// Skipped events redacted by the application while being rekeyed
+	Skipped int
+}
+
+// EventRekeyer re-encrypts the PII fields of stored event payloads with the primary key.
+// Runs are idempotent: payloads already encrypted with the primary key are left alone.
+type EventRekeyer struct {
+	store *eventStoreSQLC
+}
+
+// NewEventRekeyer creates a rekeyer; cipher must have the new primary key
+func NewEventRekeyer(dbtx db.DBTX, cipher domain.PIICipher, log logger.Logger) *EventRekeyer {
+	return &EventRekeyer{store: &eventStoreSQLC{queries: db.New(dbtx), cipher: cipher, log: log}}
+}
+
+// Rekey walks the events of all tenants in append order, batchSize rows at a time.
+// With dryRun it only counts the events that need rekeying.
+func (r *EventRekeyer) Rekey(ctx context.Context, batchSize int, dryRun bool) (*EventRekeyResult, error) {
+	if !r.store.cipher.Enabled() {
+		return nil, errors.New(errors.ErrCodeInvalidParam, "PII encryption is not configured")
+	}
+	if batchSize < 1 {
+		batchSize = defaultRekeyBatchSize
+	}
+
+	result := &EventRekeyResult{}
+	var afterID int64
+	for {
+		events, err := r.store.queries.ListEvents(ctx, db.ListEventsParams{AfterID: afterID, Limit: int32(batchSize)})
+		if err != nil {
+			return result, data.TranslateError(err, "scan events failed")
+		}
+		for _, event := range events {
+			afterID = event.ID
+			result.Scanned++
+
+			rekeyed, err := r.rekeyEvent(ctx, event, dryRun)
+			if err != nil {
+				return result, err
+			}
+			switch {
+			case rekeyed:
+				result.Rekeyed++
+			case r.needsRekey(event):
+				result.Skipped++
+			}
+		}
+		if len(events) < batchSize {
+			break
+		}
+		r.store.log.Info(ctx, "event rekey progress", logger.F("scanned", result.Scanned), logger.F("rekeyed", result.Rekeyed))
+	}
+	return result, nil
+}
+
+// needsRekey reports whether a PII field of the payload is plaintext or uses a retired key
+func (r *EventRekeyer) needsRekey(event db.Event) bool {
+	needed := false
+	_, err := mapPIIFields(event.Payload, func(_, value string) (string, error) {
+		needed = needed || r.store.cipher.NeedsRekey(value)
+		return value, nil
+	})
+	return err == nil && needed
+}
+
+// rekeyEvent re-encrypts one event payload; it returns false when nothing was written
+func (r *EventRekeyer) rekeyEvent(ctx context.Context, event db.Event, dryRun bool) (bool, error) {
+	if !r.needsRekey(event) {
+		return false, nil
+	}
+	if dryRun {
+		return true, nil
+	}
+
+	payload, err := r.store.openPayload(event)
+	if err != nil {
+		return false, errors.WrapInternalError(err, "decrypt event payload failed")
+	}
+	payload, err = r.store.sealPayload(payload, event.TenantID, event.AggregateID, event.SequenceNo)
+	if err != nil {
+		return false, errors.WrapInternalError(err, "encrypt event payload failed")
+	}
+
+	updated, err := r.store.queries.UpdateEventPII(ctx, db.UpdateEventPIIParams{
+		Payload:        payload,
+		ID:             event.ID,
+		CurrentPayload: event.Payload,
+	})
+	if err != nil {
+		return false, data.TranslateError(err, "update event pii failed")
+	}
+	if updated == 0 {
+		// A GDPR erasure redacted the payload in the meantime, with the current keyring
+		r.store.log.Warn(ctx, "event changed during rekey, skipped", logger.F("event_id", event.ID))
+		return false, nil
+	}
+	return true, nil
+}
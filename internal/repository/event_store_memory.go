@@ -0,0 +1,218 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"example.com/classic/internal/data"
+	"example.com/classic/internal/data/db"
+	"example.com/classic/internal/domain"
+	"example.com/classic/pkg/errors"
+	"example.com/classic/pkg/logger"
+)
+
+// memoryEventState is the events table of the in-memory store, in global order
+type memoryEventState struct {
+	rows []db.Event
+}
+
+func (s *memoryEventState) clone() *memoryEventState {
+	return &memoryEventState{rows: append([]db.Event(nil), s.rows...)}
+}
+
+// nextID returns the position of the next appended event
+func (s *memoryEventState) nextID() int64 {
+	if len(s.rows) == 0 {
+		return 1
+	}
+	return s.rows[len(s.rows)-1].ID + 1
+}
+
+// eventStoreMemory implements EventStore in memory (tests and local development).
+// Transactions are handled like in userRepositoryMemory.
+type eventStoreMemory struct {
+	mu    sync.RWMutex
+	state *memoryEventState
+	log   logger.Logger
+}
+
+// NewEventStoreMemory creates a new in-memory event store; use it with data.MemoryTransactionManager
+func NewEventStoreMemory(log logger.Logger) domain.EventStore {
+	return &eventStoreMemory{
+		state: &memoryEventState{},
+		log:   log,
+	}
+}
+
+// memoryEventTx is the working copy of a transaction
+type memoryEventTx struct {
+	mu    sync.Mutex
+	store *eventStoreMemory
+	state *memoryEventState
+}
+
+func (tx *memoryEventTx) Commit() {
+	tx.store.state = tx.state
+	tx.store.mu.Unlock()
+}
+
+func (tx *memoryEventTx) Rollback() {
+	tx.store.mu.Unlock()
+}
+
+// view runs fn with read access to the state visible to ctx
+func (s *eventStoreMemory) view(ctx context.Context, fn func(state *memoryEventState) error) error {
+	if tx, ok := domain.TxFromContext(ctx).(*data.MemoryTx); ok && tx != nil {
+		return s.update(ctx, fn)
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return fn(s.state)
+}
+
+// update runs fn with write access; outside a transaction the change is applied only if fn succeeds
+func (s *eventStoreMemory) update(ctx context.Context, fn func(state *memoryEventState) error) error {
+	if tx, ok := domain.TxFromContext(ctx).(*data.MemoryTx); ok && tx != nil {
+		participant := tx.Enlist(s, func() data.MemoryTxParticipant {
+			s.mu.Lock()
+			return &memoryEventTx{store: s, state: s.state.clone()}
+		}).(*memoryEventTx)
+		participant.mu.Lock()
+		defer participant.mu.Unlock()
+		return fn(participant.state)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state := s.state.clone()
+	if err := fn(state); err != nil {
+		return err
+	}
+	s.state = state
+	return nil
+}
+
+// Append appends events; sequence numbers continue from the last event of each aggregate
+func (s *eventStoreMemory) Append(ctx context.Context, events []domain.DomainEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return err
+	}
+	metadata, err := json.Marshal(eventMetadata(ctx))
+	if err != nil {
+		return errors.WrapInternalError(err, "marshal event metadata failed")
+	}
+
+	rows := make([]db.Event, len(events))
+	for i, event := range events {
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return errors.WrapInternalError(err, "marshal event payload failed")
+		}
+		rows[i] = db.Event{
+			TenantID:    tenant,
+			AggregateID: event.AggregateID(),
+			Type:        event.EventType(),
+			Payload:     payload,
+			Metadata:    metadata,
+			OccurredAt:  event.OccurredAt(),
+		}
+	}
+
+	return s.update(ctx, func(state *memoryEventState) error {
+		for _, row := range rows {
+			row.SequenceNo = state.lastSequence(tenant, row.AggregateID) + 1
+			row.ID = state.nextID()
+			state.rows = append(state.rows, row)
+		}
+		return nil
+	})
+}
+
+// lastSequence returns the last sequence number of an aggregate (0 without events)
+func (s *memoryEventState) lastSequence(tenant, aggregateID string) int32 {
+	var last int32
+	for _, row := range s.rows {
+		if row.TenantID == tenant && row.AggregateID == aggregateID && row.SequenceNo > last {
+			last = row.SequenceNo
+		}
+	}
+	return last
+}
+
+// Load retrieves the events of an aggregate in sequence order
+func (s *eventStoreMemory) Load(ctx context.Context, aggregateID string, afterSequence, limit int) ([]*domain.StoredEvent, error) {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []db.Event
+	err = s.view(ctx, func(state *memoryEventState) error {
+		// Rows of an aggregate are appended in sequence order
+		for _, row := range state.rows {
+			if len(matched) == limit {
+				break
+			}
+			if row.TenantID == tenant && row.AggregateID == aggregateID && int(row.SequenceNo) > afterSequence {
+				matched = append(matched, row)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return dbEventsToDomain(matched)
+}
+
+// ReadAll retrieves the events of all tenants in global order
+func (s *eventStoreMemory) ReadAll(ctx context.Context, afterPosition int64, limit int) ([]*domain.StoredEvent, error) {
+	var matched []db.Event
+	err := s.view(ctx, func(state *memoryEventState) error {
+		for _, row := range state.rows {
+			if len(matched) == limit {
+				break
+			}
+			if row.ID > afterPosition {
+				matched = append(matched, row)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return dbEventsToDomain(matched)
+}
+
+// Redact overwrites fields in the payloads of all events of an aggregate
+func (s *eventStoreMemory) Redact(ctx context.Context, aggregateID string, fields map[string]interface{}) error {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	return s.update(ctx, func(state *memoryEventState) error {
+		for i, row := range state.rows {
+			if row.TenantID != tenant || row.AggregateID != aggregateID {
+				continue
+			}
+			payload, changed, err := redactPayload(row.Payload, fields)
+			if err != nil {
+				return errors.WrapInternalError(err, "redact event payload failed")
+			}
+			if changed {
+				state.rows[i].Payload = payload
+			}
+		}
+		return nil
+	})
+}
+
+// Ensure implementation
+var _ domain.EventStore = (*eventStoreMemory)(nil)
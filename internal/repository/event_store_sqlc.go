@@ -0,0 +1,323 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+
+	"example.com/classic/internal/data"
+	"example.com/classic/internal/data/db"
+	"example.com/classic/internal/domain"
+	"example.com/classic/pkg/contextx"
+	"example.com/classic/pkg/errors"
+	"example.com/classic/pkg/logger"
+)
+
+// systemActor is recorded when the context carries no user (jobs, CLI)
+const systemActor = "system"
+
+// piiEventFields payload fields holding personal data; they are encrypted like the users columns
+var piiEventFields = []string{"name", "email"}
+
+// eventStoreSQLC implements EventStore using sqlc
+type eventStoreSQLC struct {
+	queries *db.Queries
+	cipher  domain.PIICipher
+	log     logger.Logger
+}
+
+// NewEventStoreSQLC creates a new event store using sqlc; cipher encrypts the PII fields of the payloads
+func NewEventStoreSQLC(dbtx db.DBTX, cipher domain.PIICipher, log logger.Logger) domain.EventStore {
+	return &eventStoreSQLC{
+		queries: db.New(dbtx),
+		cipher:  cipher,
+		log:     log,
+	}
+}
+
+// getQueries returns the appropriate queries (transactional or regular)
+func (s *eventStoreSQLC) getQueries(ctx context.Context) *db.Queries {
+	if tx, ok := domain.TxFromContext(ctx).(*sql.Tx); ok && tx != nil {
+		return s.queries.WithTx(tx)
+	}
+	return s.queries
+}
+
+// eventMetadata collects the metadata recorded with every event of a request
+func eventMetadata(ctx context.Context) domain.EventMetadata {
+	actor := contextx.GetUserID(ctx)
+	if actor == "" {
+		actor = systemActor
+	}
+	return domain.EventMetadata{
+		TraceID:   contextx.GetTraceID(ctx),
+		RequestID: contextx.GetRequestID(ctx),
+		Actor:     actor,
+	}
+}
+
+// Append appends events; sequence numbers continue from the last event of each aggregate
+func (s *eventStoreSQLC) Append(ctx context.Context, events []domain.DomainEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return err
+	}
+	metadata, err := json.Marshal(eventMetadata(ctx))
+	if err != nil {
+		return errors.WrapInternalError(err, "marshal event metadata failed")
+	}
+
+	queries := s.getQueries(ctx)
+	sequences := make(map[string]int32)
+	for _, event := range events {
+		aggregateID := event.AggregateID()
+		sequence, ok := sequences[aggregateID]
+		if !ok {
+			sequence, err = queries.GetLastEventSequence(ctx, db.GetLastEventSequenceParams{
+				TenantID:    tenant,
+				AggregateID: aggregateID,
+			})
+			if err != nil {
+				return data.TranslateError(err, "get last event sequence failed")
+			}
+		}
+		sequence++
+		sequences[aggregateID] = sequence
+
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return errors.WrapInternalError(err, "marshal event payload failed")
+		}
+		payload, err = s.sealPayload(payload, tenant, aggregateID, sequence)
+		if err != nil {
+			return errors.WrapInternalError(err, "encrypt event payload failed")
+		}
+
+		err = queries.CreateEvent(ctx, db.CreateEventParams{
+			TenantID:    tenant,
+			AggregateID: aggregateID,
+			SequenceNo:  sequence,
+			Type:        event.EventType(),
+			Payload:     payload,
+			Metadata:    metadata,
+			OccurredAt:  event.OccurredAt(),
+		})
+		if err != nil {
+			s.log.Error(ctx, "append event failed",
+				logger.F("aggregate_id", aggregateID),
+				logger.F("event_type", event.EventType()),
+				logger.F("error", err))
+			// Another transaction appended to the aggregate with the same sequence number
+			if data.ClassifyError(err) == data.KindUniqueViolation {
+				return errors.Wrap(err, errors.ErrCodeConflict, "aggregate was modified concurrently, please retry")
+			}
+			return data.TranslateError(err, "append event failed")
+		}
+	}
+
+	s.log.Debug(ctx, "events appended", logger.F("count", len(events)))
+	return nil
+}
+
+// Load retrieves the events of an aggregate in sequence order
+func (s *eventStoreSQLC) Load(ctx context.Context, aggregateID string, afterSequence, limit int) ([]*domain.StoredEvent, error) {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.getQueries(ctx).ListAggregateEvents(ctx, db.ListAggregateEventsParams{
+		TenantID:      tenant,
+		AggregateID:   aggregateID,
+		AfterSequence: int32(afterSequence),
+		Limit:         int32(limit),
+	})
+	if err != nil {
+		s.log.Error(ctx, "load events failed", logger.F("error", err))
+		return nil, data.TranslateError(err, "load events failed")
+	}
+	if err := s.openPayloads(rows); err != nil {
+		return nil, err
+	}
+	return dbEventsToDomain(rows)
+}
+
+// ReadAll retrieves the events of all tenants in global order
+func (s *eventStoreSQLC) ReadAll(ctx context.Context, afterPosition int64, limit int) ([]*domain.StoredEvent, error) {
+	rows, err := s.getQueries(ctx).ListEvents(ctx, db.ListEventsParams{
+		AfterID: afterPosition,
+		Limit:   int32(limit),
+	})
+	if err != nil {
+		s.log.Error(ctx, "read events failed", logger.F("error", err))
+		return nil, data.TranslateError(err, "read events failed")
+	}
+	if err := s.openPayloads(rows); err != nil {
+		return nil, err
+	}
+	return dbEventsToDomain(rows)
+}
+
+// Redact overwrites fields in the payloads of all events of an aggregate
+func (s *eventStoreSQLC) Redact(ctx context.Context, aggregateID string, fields map[string]interface{}) error {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	queries := s.getQueries(ctx)
+	rows, err := queries.ListAggregateEvents(ctx, db.ListAggregateEventsParams{
+		TenantID:    tenant,
+		AggregateID: aggregateID,
+		Limit:       math.MaxInt32,
+	})
+	if err != nil {
+		return data.TranslateError(err, "load events failed")
+	}
+
+	redacted := 0
+	for _, row := range rows {
+		payload, err := s.openPayload(row)
+		if err != nil {
+			return errors.WrapInternalError(err, "decrypt event payload failed")
+		}
+		payload, changed, err := redactPayload(payload, fields)
+		if err != nil {
+			return errors.WrapInternalError(err, "redact event payload failed")
+		}
+		if !changed {
+			continue
+		}
+		if payload, err = s.sealPayload(payload, row.TenantID, row.AggregateID, row.SequenceNo); err != nil {
+			return errors.WrapInternalError(err, "encrypt event payload failed")
+		}
+		if err := queries.UpdateEventPayload(ctx, db.UpdateEventPayloadParams{Payload: payload, ID: row.ID}); err != nil {
+			return data.TranslateError(err, "update event payload failed")
+		}
+		redacted++
+	}
+
+	s.log.Info(ctx, "events redacted", logger.F("aggregate_id", aggregateID), logger.F("count", redacted))
+	return nil
+}
+
+// eventPIIAAD an event row is identified by its aggregate and sequence number
+func eventPIIAAD(field, tenantID, aggregateID string, sequence int32) string {
+	return piiAAD("events", "payload."+field, tenantID, aggregateID, strconv.Itoa(int(sequence)))
+}
+
+// sealPayload encrypts the PII fields of a payload before it is stored
+func (s *eventStoreSQLC) sealPayload(payload []byte, tenantID, aggregateID string, sequence int32) ([]byte, error) {
+	if !s.cipher.Enabled() {
+		return payload, nil
+	}
+	return mapPIIFields(payload, func(field, value string) (string, error) {
+		return s.cipher.Encrypt(value, eventPIIAAD(field, tenantID, aggregateID, sequence))
+	})
+}
+
+// openPayload decrypts the PII fields of a stored payload; plaintext values are returned as is
+func (s *eventStoreSQLC) openPayload(row db.Event) ([]byte, error) {
+	return mapPIIFields(row.Payload, func(field, value string) (string, error) {
+		return s.cipher.Decrypt(value, eventPIIAAD(field, row.TenantID, row.AggregateID, row.SequenceNo))
+	})
+}
+
+// openPayloads decrypts the payloads of rows in place
+func (s *eventStoreSQLC) openPayloads(rows []db.Event) error {
+	for i := range rows {
+		payload, err := s.openPayload(rows[i])
+		if err != nil {
+			return errors.WrapInternalError(err, "decrypt event payload failed")
+		}
+		rows[i].Payload = payload
+	}
+	return nil
+}
+
+// mapPIIFields replaces the string PII fields of payload with fn's result; the payload is
+// returned as is when nothing changed
+func mapPIIFields(payload []byte, fn func(field, value string) (string, error)) ([]byte, error) {
+	var values map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &values); err != nil {
+		return nil, err
+	}
+
+	changed := false
+	for _, field := range piiEventFields {
+		var value string
+		if raw, ok := values[field]; !ok || json.Unmarshal(raw, &value) != nil {
+			continue
+		}
+		mapped, err := fn(field, value)
+		if err != nil {
+			return nil, fmt.Errorf("event payload %s: %w", field, err)
+		}
+		if mapped == value {
+			continue
+		}
+		if values[field], err = json.Marshal(mapped); err != nil {
+			return nil, err
+		}
+		changed = true
+	}
+	if !changed {
+		return payload, nil
+	}
+	return json.Marshal(values)
+}
+
+// redactPayload replaces the fields present in payload
+func redactPayload(payload []byte, fields map[string]interface{}) ([]byte, bool, error) {
+	var values map[string]interface{}
+	if err := json.Unmarshal(payload, &values); err != nil {
+		return nil, false, err
+	}
+
+	changed := false
+	for name, value := range fields {
+		if _, ok := values[name]; ok {
+			values[name] = value
+			changed = true
+		}
+	}
+	if !changed {
+		return payload, false, nil
+	}
+
+	redacted, err := json.Marshal(values)
+	return redacted, true, err
+}
+
+// dbEventsToDomain converts db.Event rows to stored events
+func dbEventsToDomain(rows []db.Event) ([]*domain.StoredEvent, error) {
+	events := make([]*domain.StoredEvent, len(rows))
+	for i, row := range rows {
+		var metadata domain.EventMetadata
+		if len(row.Metadata) > 0 {
+			if err := json.Unmarshal(row.Metadata, &metadata); err != nil {
+				return nil, errors.WrapInternalError(err, "unmarshal event metadata failed")
+			}
+		}
+		events[i] = &domain.StoredEvent{
+			Position:    row.ID,
+			TenantID:    row.TenantID,
+			AggregateID: row.AggregateID,
+			Sequence:    int(row.SequenceNo),
+			Type:        row.Type,
+			Payload:     json.RawMessage(row.Payload),
+			Metadata:    metadata,
+			OccurredAt:  row.OccurredAt,
+		}
+	}
+	return events, nil
+}
+
+// Ensure implementation
+var _ domain.EventStore = (*eventStoreSQLC)(nil)
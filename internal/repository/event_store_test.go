@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"example.com/classic/internal/data"
+	"example.com/classic/internal/domain"
+	"example.com/classic/internal/infrastructure/encryption"
+	"example.com/classic/internal/repository/repositorytest"
+	"example.com/classic/pkg/contextx"
+	"example.com/classic/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventStoreMemory_Contract(t *testing.T) {
+	log := logger.New("test", "error", false)
+	repositorytest.RunEventStore(t, func(t *testing.T) (domain.EventStore, domain.TransactionManager) {
+		return NewEventStoreMemory(log), data.NewMemoryTransactionManager()
+	})
+}
+
+func TestEventStoreSQLC_Contract(t *testing.T) {
+	log := logger.New("test", "error", false)
+	repositorytest.RunEventStore(t, func(t *testing.T) (domain.EventStore, domain.TransactionManager) {
+		sqldb := openSQLite(t)
+		return NewEventStoreSQLC(sqldb, encryption.NewPlaintextCipher(), log), data.NewTransactionManager(sqldb, log)
+	})
+}
+
+func TestEventStoreSQLC_EncryptedContract(t *testing.T) {
+	log := logger.New("test", "error", false)
+	repositorytest.RunEventStore(t, func(t *testing.T) (domain.EventStore, domain.TransactionManager) {
+		sqldb := openSQLite(t)
+		return NewEventStoreSQLC(sqldb, testKeyring(t, "k1"), log), data.NewTransactionManager(sqldb, log)
+	})
+}
+
+func TestEventStoreSQLC_EncryptsPII(t *testing.T) {
+	log := logger.New("test", "error", false)
+	sqldb := openSQLite(t)
+	store := NewEventStoreSQLC(sqldb, testKeyring(t, "k1"), log)
+	ctx := contextx.WithTenantID(context.Background(), "default")
+
+	require.NoError(t, store.Append(ctx, []domain.DomainEvent{
+		domain.NewUserCreatedEvent(1, "alice@example.com", "Alice"),
+		domain.NewUserCreatedEvent(2, "bob@example.com", "Bob"),
+	}))
+
+	var payload string
+	require.NoError(t, sqldb.QueryRow(`SELECT payload FROM events WHERE aggregate_id = 'user-1'`).Scan(&payload))
+	assert.NotContains(t, payload, "alice")
+	assert.NotContains(t, payload, "Alice")
+	assert.Contains(t, payload, `"user_id":1`)
+	assert.Contains(t, payload, `"email":"enc:v2:k1:`)
+
+	events, err := store.Load(ctx, "user-1", 0, 10)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Contains(t, string(events[0].Payload), `"email":"alice@example.com"`)
+
+	// A payload copied to another event no longer decrypts
+	_, err = sqldb.Exec(`UPDATE events SET payload = ? WHERE aggregate_id = 'user-2'`, payload)
+	require.NoError(t, err)
+	_, err = store.Load(ctx, "user-2", 0, 10)
+	assert.Error(t, err)
+}
+
+func TestEventRekeyer_Rekey(t *testing.T) {
+	log := logger.New("test", "error", false)
+	sqldb := openSQLite(t)
+	ctx := contextx.WithTenantID(context.Background(), "default")
+
+	// One event written before encryption was enabled, one with the retired key k1
+	require.NoError(t, NewEventStoreSQLC(sqldb, encryption.NewPlaintextCipher(), log).Append(ctx, []domain.DomainEvent{
+		domain.NewUserCreatedEvent(1, "alice@example.com", "Alice"),
+	}))
+	require.NoError(t, NewEventStoreSQLC(sqldb, testKeyring(t, "k1"), log).Append(ctx, []domain.DomainEvent{
+		domain.NewUserCreatedEvent(2, "bob@example.com", "Bob"),
+	}))
+
+	cipher := testKeyring(t, "k2")
+	rekeyer := NewEventRekeyer(sqldb, cipher, log)
+
+	result, err := rekeyer.Rekey(ctx, 1, true)
+	require.NoError(t, err)
+	assert.Equal(t, &EventRekeyResult{Scanned: 2, Rekeyed: 2}, result)
+
+	result, err = rekeyer.Rekey(ctx, 1, false)
+	require.NoError(t, err)
+	assert.Equal(t, &EventRekeyResult{Scanned: 2, Rekeyed: 2}, result)
+
+	rows, err := sqldb.Query(`SELECT payload FROM events`)
+	require.NoError(t, err)
+	defer rows.Close()
+	for rows.Next() {
+		var payload string
+		require.NoError(t, rows.Scan(&payload))
+		assert.Equal(t, 2, strings.Count(payload, "enc:v2:k2:"), payload)
+	}
+	require.NoError(t, rows.Err())
+
+	// Both payloads are readable with the new keyring
+	all, err := NewEventStoreSQLC(sqldb, cipher, log).ReadAll(ctx, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	assert.Contains(t, string(all[0].Payload), "alice@example.com")
+	assert.Contains(t, string(all[1].Payload), "bob@example.com")
+
+	// A second run has nothing to do
+	result, err = rekeyer.Rekey(ctx, 10, false)
+	require.NoError(t, err)
+	assert.Equal(t, &EventRekeyResult{Scanned: 2}, result)
+}
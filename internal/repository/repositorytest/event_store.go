@@ -0,0 +1,211 @@
+package repositorytest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"example.com/classic/internal/domain"
+	"example.com/classic/pkg/contextx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// EventStoreFactory creates an empty event store and the transaction manager it participates in.
+// It is called once per scenario.
+type EventStoreFactory func(t *testing.T) (domain.EventStore, domain.TransactionManager)
+
+// RunEventStore runs the event store contract scenarios against the stores created by factory
+func RunEventStore(t *testing.T, factory EventStoreFactory) {
+	scenarios := []struct {
+		name string
+		run  func(t *testing.T, store domain.EventStore, tm domain.TransactionManager)
+	}{
+		{"AppendAndLoad", testEventAppendAndLoad},
+		{"Paging", testEventPaging},
+		{"TenantIsolation", testEventTenantIsolation},
+		{"ReadAll", testEventReadAll},
+		{"Redact", testEventRedact},
+		{"TransactionRollback", testEventTransactionRollback},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.name, func(t *testing.T) {
+			store, tm := factory(t)
+			scenario.run(t, store, tm)
+		})
+	}
+}
+
+func testEventAppendAndLoad(t *testing.T, store domain.EventStore, _ domain.TransactionManager) {
+	ctx := tenantCtx(tenantA)
+	ctx = contextx.WithTraceID(ctx, "trace-1")
+	ctx = contextx.WithUserID(ctx, "admin-7")
+
+	require.NoError(t, store.Append(ctx, []domain.DomainEvent{
+		domain.NewUserCreatedEvent(1, "alice@example.com", "Alice"),
+		domain.NewUserStatusChangedEvent(1, "alice@example.com", "Alice", domain.StatusActive, domain.StatusBanned),
+		domain.NewUserCreatedEvent(2, "bob@example.com", "Bob"),
+	}))
+	// Sequence numbers continue across appends
+	require.NoError(t, store.Append(ctx, []domain.DomainEvent{domain.NewUserUpdatedEvent(1, "alice@example.com", "Alice B")}))
+
+	events, err := store.Load(ctx, domain.AggregateID("user", 1), 0, 10)
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	for i, event := range events {
+		assert.Equal(t, i+1, event.Sequence)
+		assert.Equal(t, tenantA, event.TenantID)
+		assert.Equal(t, "user-1", event.AggregateID)
+		assert.Equal(t, "trace-1", event.Metadata.TraceID)
+		assert.Equal(t, "admin-7", event.Metadata.Actor)
+		assert.False(t, event.OccurredAt.IsZero())
+	}
+	assert.Equal(t, domain.EventTypeUserCreated, events[0].Type)
+	assert.Equal(t, domain.EventTypeUserStatusChanged, events[1].Type)
+	assert.Equal(t, domain.EventTypeUserUpdated, events[2].Type)
+
+	decoded, err := events[1].Decode()
+	require.NoError(t, err)
+	changed, ok := decoded.(*domain.UserStatusChangedEvent)
+	require.True(t, ok)
+	assert.Equal(t, 1, changed.UserID)
+	assert.Equal(t, domain.StatusActive, changed.OldStatus)
+	assert.Equal(t, domain.StatusBanned, changed.NewStatus)
+	assert.WithinDuration(t, events[1].OccurredAt, decoded.OccurredAt(), 0)
+
+	other, err := store.Load(ctx, domain.AggregateID("user", 2), 0, 10)
+	require.NoError(t, err)
+	require.Len(t, other, 1)
+	assert.Equal(t, 1, other[0].Sequence)
+
+	// Without a user in the context the system is the actor
+	require.NoError(t, store.Append(tenantCtx(tenantA), []domain.DomainEvent{domain.NewUserDeletedEvent(2, "bob@example.com", "Bob")}))
+	other, err = store.Load(ctx, domain.AggregateID("user", 2), 1, 10)
+	require.NoError(t, err)
+	require.Len(t, other, 1)
+	assert.Equal(t, "system", other[0].Metadata.Actor)
+}
+
+func testEventPaging(t *testing.T, store domain.EventStore, _ domain.TransactionManager) {
+	ctx := tenantCtx(tenantA)
+	for i := 0; i < 5; i++ {
+		require.NoError(t, store.Append(ctx, []domain.DomainEvent{domain.NewUserUpdatedEvent(1, "alice@example.com", "Alice")}))
+	}
+
+	page, err := store.Load(ctx, "user-1", 0, 2)
+	require.NoError(t, err)
+	require.Len(t, page, 2)
+	assert.Equal(t, 2, page[1].Sequence)
+
+	page, err = store.Load(ctx, "user-1", 2, 2)
+	require.NoError(t, err)
+	require.Len(t, page, 2)
+	assert.Equal(t, 3, page[0].Sequence)
+
+	page, err = store.Load(ctx, "user-1", 4, 2)
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, 5, page[0].Sequence)
+}
+
+func testEventTenantIsolation(t *testing.T, store domain.EventStore, _ domain.TransactionManager) {
+	require.NoError(t, store.Append(tenantCtx(tenantA), []domain.DomainEvent{domain.NewUserCreatedEvent(1, "alice@example.com", "Alice")}))
+
+	events, err := store.Load(tenantCtx(tenantB), "user-1", 0, 10)
+	require.NoError(t, err)
+	assert.Empty(t, events)
+
+	// Sequences are per tenant
+	require.NoError(t, store.Append(tenantCtx(tenantB), []domain.DomainEvent{domain.NewUserCreatedEvent(1, "carol@example.com", "Carol")}))
+	events, err = store.Load(tenantCtx(tenantB), "user-1", 0, 10)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, 1, events[0].Sequence)
+	assert.Contains(t, string(events[0].Payload), "carol@example.com")
+
+	// A tenant is required
+	err = store.Append(context.Background(), []domain.DomainEvent{domain.NewUserCreatedEvent(1, "alice@example.com", "Alice")})
+	assert.Error(t, err)
+	_, err = store.Load(context.Background(), "user-1", 0, 10)
+	assert.Error(t, err)
+}
+
+func testEventReadAll(t *testing.T, store domain.EventStore, _ domain.TransactionManager) {
+	require.NoError(t, store.Append(tenantCtx(tenantA), []domain.DomainEvent{domain.NewUserCreatedEvent(1, "alice@example.com", "Alice")}))
+	require.NoError(t, store.Append(tenantCtx(tenantB), []domain.DomainEvent{domain.NewUserCreatedEvent(2, "bob@example.com", "Bob")}))
+	require.NoError(t, store.Append(tenantCtx(tenantA), []domain.DomainEvent{domain.NewUserDeletedEvent(1, "alice@example.com", "Alice")}))
+
+	// All tenants in append order; no tenant is needed
+	all, err := store.ReadAll(context.Background(), 0, 10)
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+	assert.Equal(t, []string{tenantA, tenantB, tenantA}, []string{all[0].TenantID, all[1].TenantID, all[2].TenantID})
+	assert.Less(t, all[0].Position, all[1].Position)
+	assert.Less(t, all[1].Position, all[2].Position)
+
+	rest, err := store.ReadAll(context.Background(), all[0].Position, 1)
+	require.NoError(t, err)
+	require.Len(t, rest, 1)
+	assert.Equal(t, all[1].Position, rest[0].Position)
+
+	rest, err = store.ReadAll(context.Background(), all[2].Position, 10)
+	require.NoError(t, err)
+	assert.Empty(t, rest)
+}
+
+func testEventRedact(t *testing.T, store domain.EventStore, _ domain.TransactionManager) {
+	ctx := tenantCtx(tenantA)
+	require.NoError(t, store.Append(ctx, []domain.DomainEvent{
+		domain.NewUserCreatedEvent(1, "alice@example.com", "Alice"),
+		domain.NewUserErasedEvent(1, tenantA),
+		domain.NewUserCreatedEvent(2, "bob@example.com", "Bob"),
+	}))
+	require.NoError(t, store.Append(tenantCtx(tenantB), []domain.DomainEvent{domain.NewUserCreatedEvent(1, "alice@example.com", "Alice")}))
+
+	require.NoError(t, store.Redact(ctx, "user-1", map[string]interface{}{"name": "erased-1", "email": "erased-1@erased.invalid"}))
+
+	events, err := store.Load(ctx, "user-1", 0, 10)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	decoded, err := events[0].Decode()
+	require.NoError(t, err)
+	assert.Equal(t, "erased-1", decoded.(*domain.UserCreatedEvent).Name)
+	assert.Equal(t, "erased-1@erased.invalid", decoded.(*domain.UserCreatedEvent).Email)
+	// Fields missing from a payload are not added
+	assert.NotContains(t, string(events[1].Payload), "erased-1")
+
+	// Other aggregates and tenants are untouched
+	events, err = store.Load(ctx, "user-2", 0, 10)
+	require.NoError(t, err)
+	assert.Contains(t, string(events[0].Payload), "bob@example.com")
+	events, err = store.Load(tenantCtx(tenantB), "user-1", 0, 10)
+	require.NoError(t, err)
+	assert.Contains(t, string(events[0].Payload), "alice@example.com")
+}
+
+func testEventTransactionRollback(t *testing.T, store domain.EventStore, tm domain.TransactionManager) {
+	ctx := tenantCtx(tenantA)
+	errAbort := errors.New("abort")
+
+	err := tm.WithTransaction(ctx, func(txCtx context.Context) error {
+		require.NoError(t, store.Append(txCtx, []domain.DomainEvent{domain.NewUserCreatedEvent(1, "alice@example.com", "Alice")}))
+		events, err := store.Load(txCtx, "user-1", 0, 10)
+		require.NoError(t, err)
+		assert.Len(t, events, 1)
+		return errAbort
+	})
+	assert.ErrorIs(t, err, errAbort)
+
+	events, err := store.Load(ctx, "user-1", 0, 10)
+	require.NoError(t, err)
+	assert.Empty(t, events)
+
+	// Committed appends are kept
+	require.NoError(t, tm.WithTransaction(ctx, func(txCtx context.Context) error {
+		return store.Append(txCtx, []domain.DomainEvent{domain.NewUserCreatedEvent(1, "alice@example.com", "Alice")})
+	}))
+	events, err = store.Load(ctx, "user-1", 0, 10)
+	require.NoError(t, err)
+	assert.Len(t, events, 1)
+}
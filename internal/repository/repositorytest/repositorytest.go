@@ -0,0 +1,498 @@
+// Package repositorytest is the contract test suite of domain.UserRepository.
+// Every implementation runs the same scenarios, so they stay interchangeable:
+//
+//	repositorytest.Run(t, func(t *testing.T) (domain.UserRepository, domain.TransactionManager) {
+//		return newRepository(t, repositorytest.AttributeSchema()), newTransactionManager(t)
+//	})
+package repositorytest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"example.com/classic/internal/domain"
+	"example.com/classic/pkg/contextx"
+	"example.com/classic/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Factory creates an empty repository and the transaction manager it participates in.
+// It is called once per scenario.
+type Factory func(t *testing.T) (domain.UserRepository, domain.TransactionManager)
+
+const (
+	tenantA = "tenant-a"
+	tenantB = "tenant-b"
+)
+
+// AttributeSchema is the attribute schema the repositories under test are created with
+func AttributeSchema() *domain.AttributeSchema {
+	schema, err := domain.NewAttributeSchema([]domain.AttributeDefinition{
+		{Name: "plan", Type: domain.AttributeTypeString},
+		{Name: "seats", Type: domain.AttributeTypeInteger},
+		{Name: "score", Type: domain.AttributeTypeNumber},
+		{Name: "trial", Type: domain.AttributeTypeBoolean},
+	})
+	if err != nil {
+		panic(err)
+	}
+	return schema
+}
+
+// Run runs the contract scenarios against the repositories created by factory
+func Run(t *testing.T, factory Factory) {
+	scenarios := []struct {
+		name string
+		run  func(t *testing.T, repo domain.UserRepository, tm domain.TransactionManager)
+	}{
+		{"CreateAndGet", testCreateAndGet},
+		{"EmailUniqueness", testEmailUniqueness},
+		{"TenantIsolation", testTenantIsolation},
+		{"TenantRequired", testTenantRequired},
+		{"NotFound", testNotFound},
+		{"Update", testUpdate},
+		{"Delete", testDelete},
+		{"Pagination", testPagination},
+		{"Filters", testFilters},
+		{"AttributeFilters", testAttributeFilters},
+		{"AttributeTypes", testAttributeTypes},
+		{"Aggregate", testAggregate},
+		{"TransactionCommit", testTransactionCommit},
+		{"TransactionRollback", testTransactionRollback},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.name, func(t *testing.T) {
+			repo, tm := factory(t)
+			scenario.run(t, repo, tm)
+		})
+	}
+}
+
+func tenantCtx(tenant string) context.Context {
+	return contextx.WithTenantID(context.Background(), tenant)
+}
+
+// newUser builds an unsaved user entity
+func newUser(t *testing.T, name, email string, status domain.Status) *domain.User {
+	t.Helper()
+	nameVO, err := domain.NewName(name)
+	require.NoError(t, err)
+	emailVO, err := domain.NewEmail(email)
+	require.NoError(t, err)
+	password, err := domain.NewHashedPassword("$2a$10$contract.test.hashed.password")
+	require.NoError(t, err)
+
+	user, err := domain.NewUser(0, *nameVO, *emailVO, *password, status, time.Time{}, time.Time{})
+	require.NoError(t, err)
+	return user
+}
+
+// mustCreate saves a new user and returns it with its assigned ID
+func mustCreate(t *testing.T, ctx context.Context, repo domain.UserRepository, name, email string, status domain.Status) *domain.User {
+	t.Helper()
+	user := newUser(t, name, email, status)
+	require.NoError(t, repo.Create(ctx, user))
+	return user
+}
+
+func assertCode(t *testing.T, expected errors.ErrorCode, err error) {
+	t.Helper()
+	var bizErr *errors.Error
+	if assert.True(t, errors.As(err, &bizErr), "expected business error, got %v", err) {
+		assert.Equal(t, expected, bizErr.Code)
+	}
+}
+
+func testCreateAndGet(t *testing.T, repo domain.UserRepository, _ domain.TransactionManager) {
+	ctx := tenantCtx(tenantA)
+	user := newUser(t, "Alice", "alice@example.com", domain.StatusActive)
+	user.SetAttributes(domain.Attributes{"plan": "pro", "seats": int64(5)})
+
+	require.NoError(t, repo.Create(ctx, user))
+	assert.NotZero(t, user.ID())
+	assert.Equal(t, tenantA, user.TenantID())
+	assert.False(t, user.CreatedAt().IsZero())
+	assert.False(t, user.UpdatedAt().IsZero())
+
+	byID, err := repo.GetByID(ctx, user.ID())
+	require.NoError(t, err)
+	assert.Equal(t, user.ID(), byID.ID())
+	assert.Equal(t, tenantA, byID.TenantID())
+	assert.Equal(t, "Alice", byID.Name().String())
+	assert.Equal(t, "alice@example.com", byID.Email().String())
+	assert.Equal(t, domain.StatusActive, byID.Status())
+	assert.Equal(t, user.GetHashedPassword(), byID.GetHashedPassword())
+	assert.Equal(t, domain.Attributes{"plan": "pro", "seats": int64(5)}, byID.Attributes())
+
+	byEmail, err := repo.GetByEmail(ctx, "alice@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, user.ID(), byEmail.ID())
+
+	exists, err := repo.ExistsByEmail(ctx, "alice@example.com")
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	exists, err = repo.ExistsByEmail(ctx, "nobody@example.com")
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func testEmailUniqueness(t *testing.T, repo domain.UserRepository, _ domain.TransactionManager) {
+	ctx := tenantCtx(tenantA)
+	first := mustCreate(t, ctx, repo, "Alice", "alice@example.com", domain.StatusActive)
+
+	err := repo.Create(ctx, newUser(t, "Alice Again", "alice@example.com", domain.StatusActive))
+	assertCode(t, errors.ErrCodeUserAlreadyExists, err)
+
+	// Emails are unique per tenant only
+	other := newUser(t, "Alice", "alice@example.com", domain.StatusActive)
+	require.NoError(t, repo.Create(tenantCtx(tenantB), other))
+	assert.NotEqual(t, first.ID(), other.ID())
+
+	// Changing an email to one taken in the same tenant fails
+	second := mustCreate(t, ctx, repo, "Bob", "bob@example.com", domain.StatusActive)
+	email, err := domain.NewEmail("alice@example.com")
+	require.NoError(t, err)
+	require.NoError(t, second.UpdateProfile(second.Name(), *email))
+	assert.Error(t, repo.Update(ctx, second))
+
+	stored, err := repo.GetByID(ctx, second.ID())
+	require.NoError(t, err)
+	assert.Equal(t, "bob@example.com", stored.Email().String())
+}
+
+func testTenantIsolation(t *testing.T, repo domain.UserRepository, _ domain.TransactionManager) {
+	ctxA, ctxB := tenantCtx(tenantA), tenantCtx(tenantB)
+	user := mustCreate(t, ctxA, repo, "Alice", "alice@example.com", domain.StatusActive)
+	mustCreate(t, ctxB, repo, "Bob", "bob@example.com", domain.StatusActive)
+
+	_, err := repo.GetByID(ctxB, user.ID())
+	assertCode(t, errors.ErrCodeUserNotFound, err)
+
+	_, err = repo.GetByEmail(ctxB, "alice@example.com")
+	assertCode(t, errors.ErrCodeUserNotFound, err)
+
+	exists, err := repo.ExistsByEmail(ctxB, "alice@example.com")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	assertCode(t, errors.ErrCodeUserNotFound, repo.Update(ctxB, user))
+	assertCode(t, errors.ErrCodeUserNotFound, repo.Delete(ctxB, user.ID()))
+
+	users, total, err := repo.List(ctxB, domain.UserListParams{Page: 1, PageSize: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, users, 1)
+	assert.Equal(t, "bob@example.com", users[0].Email().String())
+
+	// The user is untouched in its own tenant
+	_, err = repo.GetByID(ctxA, user.ID())
+	assert.NoError(t, err)
+}
+
+func testTenantRequired(t *testing.T, repo domain.UserRepository, _ domain.TransactionManager) {
+	ctx := context.Background()
+
+	assertCode(t, errors.ErrCodeTenantRequired, repo.Create(ctx, newUser(t, "Alice", "alice@example.com", domain.StatusActive)))
+
+	_, err := repo.GetByID(ctx, 1)
+	assertCode(t, errors.ErrCodeTenantRequired, err)
+
+	_, _, err = repo.List(ctx, domain.UserListParams{Page: 1, PageSize: 10})
+	assertCode(t, errors.ErrCodeTenantRequired, err)
+}
+
+func testNotFound(t *testing.T, repo domain.UserRepository, _ domain.TransactionManager) {
+	ctx := tenantCtx(tenantA)
+
+	_, err := repo.GetByID(ctx, 4242)
+	assertCode(t, errors.ErrCodeUserNotFound, err)
+
+	_, err = repo.GetByEmail(ctx, "missing@example.com")
+	assertCode(t, errors.ErrCodeUserNotFound, err)
+
+	_, err = repo.GetAggregateByID(ctx, 4242)
+	assertCode(t, errors.ErrCodeUserNotFound, err)
+
+	missing := newUser(t, "Ghost", "ghost@example.com", domain.StatusActive)
+	missing.SetID(4242)
+	assertCode(t, errors.ErrCodeUserNotFound, repo.Update(ctx, missing))
+	assertCode(t, errors.ErrCodeUserNotFound, repo.Delete(ctx, 4242))
+}
+
+func testUpdate(t *testing.T, repo domain.UserRepository, _ domain.TransactionManager) {
+	ctx := tenantCtx(tenantA)
+	user := mustCreate(t, ctx, repo, "Alice", "alice@example.com", domain.StatusInactive)
+
+	name, err := domain.NewName("Alice Liddell")
+	require.NoError(t, err)
+	email, err := domain.NewEmail("liddell@example.com")
+	require.NoError(t, err)
+	require.NoError(t, user.UpdateProfile(*name, *email))
+	require.NoError(t, user.ChangeStatus(domain.StatusActive))
+	user.SetAttributes(domain.Attributes{"plan": "free"})
+	require.NoError(t, repo.Update(ctx, user))
+
+	stored, err := repo.GetByID(ctx, user.ID())
+	require.NoError(t, err)
+	assert.Equal(t, "Alice Liddell", stored.Name().String())
+	assert.Equal(t, "liddell@example.com", stored.Email().String())
+	assert.Equal(t, domain.StatusActive, stored.Status())
+	assert.Equal(t, domain.Attributes{"plan": "free"}, stored.Attributes())
+
+	// The old email is free again
+	exists, err := repo.ExistsByEmail(ctx, "alice@example.com")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	// Password changes are persisted; a user loaded without credentials keeps its hash
+	password, err := domain.NewHashedPassword("$2a$10$contract.test.changed.password")
+	require.NoError(t, err)
+	require.NoError(t, user.ChangePassword(*password))
+	require.NoError(t, repo.Update(ctx, user))
+	stored, err = repo.GetByID(ctx, user.ID())
+	require.NoError(t, err)
+	assert.Equal(t, password.String(), stored.GetHashedPassword())
+
+	stored.ClearSensitiveData()
+	require.NoError(t, repo.Update(ctx, stored))
+	stored, err = repo.GetByID(ctx, user.ID())
+	require.NoError(t, err)
+	assert.Equal(t, password.String(), stored.GetHashedPassword())
+
+	// Clearing attributes
+	user.SetAttributes(nil)
+	require.NoError(t, repo.Update(ctx, user))
+	stored, err = repo.GetByID(ctx, user.ID())
+	require.NoError(t, err)
+	assert.Empty(t, stored.Attributes())
+}
+
+func testDelete(t *testing.T, repo domain.UserRepository, _ domain.TransactionManager) {
+	ctx := tenantCtx(tenantA)
+	user := mustCreate(t, ctx, repo, "Alice", "alice@example.com", domain.StatusActive)
+	kept := mustCreate(t, ctx, repo, "Bob", "bob@example.com", domain.StatusActive)
+
+	require.NoError(t, repo.Delete(ctx, user.ID()))
+
+	_, err := repo.GetByID(ctx, user.ID())
+	assertCode(t, errors.ErrCodeUserNotFound, err)
+	assertCode(t, errors.ErrCodeUserNotFound, repo.Delete(ctx, user.ID()))
+
+	_, err = repo.GetByID(ctx, kept.ID())
+	assert.NoError(t, err)
+
+	// The email can be registered again
+	assert.NoError(t, repo.Create(ctx, newUser(t, "Alice", "alice@example.com", domain.StatusActive)))
+}
+
+func testPagination(t *testing.T, repo domain.UserRepository, _ domain.TransactionManager) {
+	ctx := tenantCtx(tenantA)
+	ids := make([]int, 5)
+	for i := range ids {
+		ids[i] = mustCreate(t, ctx, repo, fmt.Sprintf("User %d", i), fmt.Sprintf("user%d@example.com", i), domain.StatusActive).ID()
+	}
+
+	// Newest first
+	var seen []int
+	for page := 1; page <= 3; page++ {
+		users, total, err := repo.List(ctx, domain.UserListParams{Page: page, PageSize: 2})
+		require.NoError(t, err)
+		assert.Equal(t, int64(5), total)
+		for _, user := range users {
+			seen = append(seen, user.ID())
+		}
+		if page < 3 {
+			assert.Len(t, users, 2)
+		} else {
+			assert.Len(t, users, 1)
+		}
+	}
+	assert.Equal(t, []int{ids[4], ids[3], ids[2], ids[1], ids[0]}, seen)
+
+	users, total, err := repo.List(ctx, domain.UserListParams{Page: 4, PageSize: 2})
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), total)
+	assert.Empty(t, users)
+}
+
+func testFilters(t *testing.T, repo domain.UserRepository, _ domain.TransactionManager) {
+	ctx := tenantCtx(tenantA)
+	alice := mustCreate(t, ctx, repo, "Alice Smith", "alice@example.com", domain.StatusActive)
+	mustCreate(t, ctx, repo, "Bob Smith", "bob@corp.example", domain.StatusInactive)
+	mustCreate(t, ctx, repo, "Carol Jones", "carol@corp.example", domain.StatusActive)
+
+	status := func(s domain.Status) *domain.Status { return &s }
+	str := func(s string) *string { return &s }
+
+	tests := []struct {
+		name     string
+		params   domain.UserListParams
+		expected []string
+	}{
+		{"no filter", domain.UserListParams{}, []string{"carol@corp.example", "bob@corp.example", "alice@example.com"}},
+		{"by id", domain.UserListParams{ID: intPtr(alice.ID())}, []string{"alice@example.com"}},
+		{"name substring", domain.UserListParams{Name: str("Smith")}, []string{"bob@corp.example", "alice@example.com"}},
+		{"name case-insensitive", domain.UserListParams{Name: str("smith")}, []string{"bob@corp.example", "alice@example.com"}},
+		{"email substring", domain.UserListParams{Email: str("@corp.")}, []string{"carol@corp.example", "bob@corp.example"}},
+		{"status", domain.UserListParams{Status: status(domain.StatusActive)}, []string{"carol@corp.example", "alice@example.com"}},
+		{"combined", domain.UserListParams{Name: str("Smith"), Status: status(domain.StatusActive)}, []string{"alice@example.com"}},
+		{"no match", domain.UserListParams{Email: str("nobody")}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := tt.params
+			params.Page, params.PageSize = 1, 10
+			users, total, err := repo.List(ctx, params)
+			require.NoError(t, err)
+			assert.Equal(t, int64(len(tt.expected)), total)
+			assert.Equal(t, tt.expected, emails(users))
+		})
+	}
+}
+
+func testAttributeFilters(t *testing.T, repo domain.UserRepository, _ domain.TransactionManager) {
+	ctx := tenantCtx(tenantA)
+	create := func(name, email string, attrs domain.Attributes) {
+		user := newUser(t, name, email, domain.StatusActive)
+		user.SetAttributes(attrs)
+		require.NoError(t, repo.Create(ctx, user))
+	}
+	create("Alice", "alice@example.com", domain.Attributes{"plan": "pro", "seats": int64(5), "trial": false})
+	create("Bob", "bob@example.com", domain.Attributes{"plan": "pro", "seats": int64(2)})
+	create("Carol", "carol@example.com", domain.Attributes{"plan": "free"})
+	create("Dave", "dave@example.com", nil)
+
+	tests := []struct {
+		name     string
+		filters  []domain.AttributeFilter
+		expected []string
+	}{
+		{"string", []domain.AttributeFilter{{Name: "plan", Value: "pro"}}, []string{"bob@example.com", "alice@example.com"}},
+		{"integer", []domain.AttributeFilter{{Name: "seats", Value: int64(5)}}, []string{"alice@example.com"}},
+		{"boolean", []domain.AttributeFilter{{Name: "trial", Value: false}}, []string{"alice@example.com"}},
+		{"and", []domain.AttributeFilter{{Name: "plan", Value: "pro"}, {Name: "seats", Value: int64(2)}}, []string{"bob@example.com"}},
+		{"missing attribute", []domain.AttributeFilter{{Name: "region", Value: "eu"}}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users, total, err := repo.List(ctx, domain.UserListParams{Attributes: tt.filters, Page: 1, PageSize: 10})
+			require.NoError(t, err)
+			assert.Equal(t, int64(len(tt.expected)), total)
+			assert.Equal(t, tt.expected, emails(users))
+		})
+	}
+}
+
+func testAttributeTypes(t *testing.T, repo domain.UserRepository, _ domain.TransactionManager) {
+	ctx := tenantCtx(tenantA)
+	// A whole number stored for a number attribute reads back as float64, as it was written
+	want := domain.Attributes{"plan": "pro", "seats": int64(3), "score": float64(3), "trial": true}
+	user := newUser(t, "Alice", "alice@example.com", domain.StatusActive)
+	user.SetAttributes(want.Clone())
+	require.NoError(t, repo.Create(ctx, user))
+
+	byID, err := repo.GetByID(ctx, user.ID())
+	require.NoError(t, err)
+	assert.Equal(t, want, byID.Attributes())
+
+	users, _, err := repo.List(ctx, domain.UserListParams{
+		Attributes: []domain.AttributeFilter{{Name: "score", Value: float64(3)}}, Page: 1, PageSize: 10,
+	})
+	require.NoError(t, err)
+	require.Len(t, users, 1)
+	assert.Equal(t, want, users[0].Attributes())
+}
+
+func testAggregate(t *testing.T, repo domain.UserRepository, _ domain.TransactionManager) {
+	ctx := tenantCtx(tenantA)
+	aggregate := domain.RebuildUserAggregate(newUser(t, "Alice", "alice@example.com", domain.StatusInactive))
+
+	// Save creates, then updates
+	require.NoError(t, repo.Save(ctx, aggregate))
+	id := aggregate.User().ID()
+	assert.NotZero(t, id)
+
+	require.NoError(t, aggregate.User().ChangeStatus(domain.StatusActive))
+	require.NoError(t, repo.Save(ctx, aggregate))
+
+	byID, err := repo.GetAggregateByID(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, domain.StatusActive, byID.User().Status())
+
+	byEmail, err := repo.GetAggregateByEmail(ctx, "alice@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, id, byEmail.User().ID())
+}
+
+func testTransactionCommit(t *testing.T, repo domain.UserRepository, tm domain.TransactionManager) {
+	ctx := tenantCtx(tenantA)
+
+	var id int
+	err := tm.WithTransaction(ctx, func(txCtx context.Context) error {
+		user := mustCreate(t, txCtx, repo, "Alice", "alice@example.com", domain.StatusActive)
+		id = user.ID()
+
+		// Reads inside the transaction see its writes
+		_, err := repo.GetByID(txCtx, id)
+		require.NoError(t, err)
+		_, total, err := repo.List(txCtx, domain.UserListParams{Page: 1, PageSize: 10})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+		return nil
+	})
+	require.NoError(t, err)
+
+	_, err = repo.GetByID(ctx, id)
+	assert.NoError(t, err)
+}
+
+func testTransactionRollback(t *testing.T, repo domain.UserRepository, tm domain.TransactionManager) {
+	ctx := tenantCtx(tenantA)
+	kept := mustCreate(t, ctx, repo, "Bob", "bob@example.com", domain.StatusActive)
+
+	failure := fmt.Errorf("abort")
+	var id int
+	err := tm.WithTransaction(ctx, func(txCtx context.Context) error {
+		id = mustCreate(t, txCtx, repo, "Alice", "alice@example.com", domain.StatusActive).ID()
+
+		require.NoError(t, kept.ChangeStatus(domain.StatusBanned))
+		require.NoError(t, repo.Update(txCtx, kept))
+		require.NoError(t, repo.Delete(txCtx, kept.ID()))
+		return failure
+	})
+	assert.ErrorIs(t, err, failure)
+
+	// Nothing of the transaction is visible afterwards
+	_, err = repo.GetByID(ctx, id)
+	assertCode(t, errors.ErrCodeUserNotFound, err)
+	exists, err := repo.ExistsByEmail(ctx, "alice@example.com")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	stored, err := repo.GetByID(ctx, kept.ID())
+	require.NoError(t, err)
+	assert.Equal(t, domain.StatusActive, stored.Status())
+
+	_, total, err := repo.List(ctx, domain.UserListParams{Page: 1, PageSize: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+}
+
+func emails(users []*domain.User) []string {
+	var result []string
+	for _, user := range users {
+		result = append(result, user.Email().String())
+	}
+	return result
+}
+
+func intPtr(v int) *int {
+	return &v
+}
@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"example.com/classic/internal/data"
+	"example.com/classic/internal/data/db"
+	"example.com/classic/internal/domain"
+	"example.com/classic/pkg/errors"
+	"example.com/classic/pkg/logger"
+)
+
+// tenantRepositorySQLC implements TenantRepository using sqlc
+type tenantRepositorySQLC struct {
+	queries *db.Queries
+	log     logger.Logger
+}
+
+// NewTenantRepositorySQLC creates a new tenant repository using sqlc
+func NewTenantRepositorySQLC(dbtx db.DBTX, log logger.Logger) domain.TenantRepository {
+	return &tenantRepositorySQLC{
+		queries: db.New(dbtx),
+		log:     log,
+	}
+}
+
+// getQueries returns the appropriate queries (transactional or regular)
+func (r *tenantRepositorySQLC) getQueries(ctx context.Context) *db.Queries {
+	if tx, ok := domain.TxFromContext(ctx).(*sql.Tx); ok && tx != nil {
+		return r.queries.WithTx(tx)
+	}
+	return r.queries
+}
+
+// Create creates a new tenant
+func (r *tenantRepositorySQLC) Create(ctx context.Context, tenant *domain.Tenant) error {
+	r.log.Debug(ctx, "creating tenant", logger.F("id", tenant.ID()))
+
+	queries := r.getQueries(ctx)
+
+	if _, err := r.GetByID(ctx, tenant.ID()); err == nil {
+		return errors.ErrTenantAlreadyExists
+	} else if !errors.Is(err, errors.ErrTenantNotFound) {
+		return err
+	}
+
+	err := queries.CreateTenant(ctx, db.CreateTenantParams{
+		ID:        tenant.ID(),
+		Name:      tenant.Name(),
+		Status:    string(tenant.Status()),
+		CreatedAt: tenant.CreatedAt(),
+		UpdatedAt: tenant.UpdatedAt(),
+	})
+	if err != nil {
+		r.log.Error(ctx, "create tenant failed", logger.F("error", err))
+		if data.ClassifyError(err) == data.KindUniqueViolation {
+			return errors.ErrTenantAlreadyExists
+		}
+		return data.TranslateError(err, "create tenant failed")
+	}
+
+	r.log.Info(ctx, "tenant created successfully", logger.F("id", tenant.ID()))
+	return nil
+}
+
+// GetByID retrieves a tenant by ID
+func (r *tenantRepositorySQLC) GetByID(ctx context.Context, id string) (*domain.Tenant, error) {
+	queries := r.getQueries(ctx)
+
+	tenant, err := queries.GetTenantByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.ErrTenantNotFound
+		}
+		return nil, data.TranslateError(err, "get tenant by id failed")
+	}
+
+	return r.dbToDomain(tenant), nil
+}
+
+// Update updates a tenant
+func (r *tenantRepositorySQLC) Update(ctx context.Context, tenant *domain.Tenant) error {
+	r.log.Debug(ctx, "updating tenant", logger.F("id", tenant.ID()))
+
+	queries := r.getQueries(ctx)
+
+	if _, err := r.GetByID(ctx, tenant.ID()); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	err := queries.UpdateTenant(ctx, db.UpdateTenantParams{
+		Name:      tenant.Name(),
+		Status:    string(tenant.Status()),
+		UpdatedAt: now,
+		ID:        tenant.ID(),
+	})
+	if err != nil {
+		r.log.Error(ctx, "update tenant failed", logger.F("error", err))
+		return data.TranslateError(err, "update tenant failed")
+	}
+	tenant.SetUpdatedAt(now)
+
+	r.log.Info(ctx, "tenant updated successfully", logger.F("id", tenant.ID()))
+	return nil
+}
+
+// Delete deletes a tenant
+func (r *tenantRepositorySQLC) Delete(ctx context.Context, id string) error {
+	r.log.Debug(ctx, "deleting tenant", logger.F("id", id))
+
+	queries := r.getQueries(ctx)
+
+	if _, err := r.GetByID(ctx, id); err != nil {
+		return err
+	}
+
+	if err := queries.DeleteTenant(ctx, id); err != nil {
+		r.log.Error(ctx, "delete tenant failed", logger.F("error", err))
+		// Users were added after the service checked the tenant is empty
+		if data.ClassifyError(err) == data.KindForeignKeyViolation {
+			return errors.Wrap(err, errors.ErrCodeConflict, "tenant still has users")
+		}
+		return data.TranslateError(err, "delete tenant failed")
+	}
+
+	r.log.Info(ctx, "tenant deleted successfully", logger.F("id", id))
+	return nil
+}
+
+// List retrieves a paginated list of tenants
+func (r *tenantRepositorySQLC) List(ctx context.Context, page, pageSize int) ([]*domain.Tenant, int64, error) {
+	queries := r.getQueries(ctx)
+
+	total, err := queries.CountTenants(ctx)
+	if err != nil {
+		r.log.Error(ctx, "count tenants failed", logger.F("error", err))
+		return nil, 0, data.TranslateError(err, "count tenants failed")
+	}
+
+	tenants, err := queries.ListTenants(ctx, db.ListTenantsParams{
+		Limit:  int32(pageSize),
+		Offset: int32((page - 1) * pageSize),
+	})
+	if err != nil {
+		r.log.Error(ctx, "list tenants failed", logger.F("error", err))
+		return nil, 0, data.TranslateError(err, "list tenants failed")
+	}
+
+	result := make([]*domain.Tenant, len(tenants))
+	for i, tenant := range tenants {
+		result[i] = r.dbToDomain(tenant)
+	}
+	return result, total, nil
+}
+
+// dbToDomain converts db.Tenant to domain.Tenant
+func (r *tenantRepositorySQLC) dbToDomain(tenant db.Tenant) *domain.Tenant {
+	status := domain.TenantStatus(tenant.Status)
+	if !status.IsValid() {
+		status = domain.TenantStatusSuspended
+	}
+	return domain.RebuildTenant(tenant.ID, tenant.Name, status, tenant.CreatedAt, tenant.UpdatedAt)
+}
+
+// Ensure implementation
+var _ domain.TenantRepository = (*tenantRepositorySQLC)(nil)
@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+
+	"example.com/classic/internal/data"
+	"example.com/classic/internal/data/db"
+	"example.com/classic/internal/domain"
+	"example.com/classic/pkg/errors"
+	"example.com/classic/pkg/logger"
+)
+
+// defaultRekeyBatchSize rows read per batch when no batch size is given
+const defaultRekeyBatchSize = 500
+
+// UserRekeyResult outcome of a rekey run
+type UserRekeyResult struct {
+	// Scanned users of all tenants
+	Scanned int
+	// Rekeyed users whose PII was re-encrypted with the primary key
+	Rekeyed int
+	// Skipped users changed by the application while being rekeyed
+	Skipped int
+}
+
+// UserRekeyer re-encrypts the PII of existing users with the primary key and recomputes the blind index.
+// Runs are idempotent: users already encrypted with the primary key are left alone.
+type UserRekeyer struct {
+	queries *db.Queries
+	cipher  domain.PIICipher
+	log     logger.Logger
+}
+
+// NewUserRekeyer creates a rekeyer; cipher must have the new primary key
+func NewUserRekeyer(dbtx db.DBTX, cipher domain.PIICipher, log logger.Logger) *UserRekeyer {
+	return &UserRekeyer{
+		queries: db.New(dbtx),
+		cipher:  cipher,
+		log:     log,
+	}
+}
+
+// Rekey walks the users of all tenants in id order, batchSize rows at a time.
+// With dryRun it only counts the users that need rekeying.
+func (r *UserRekeyer) Rekey(ctx context.Context, batchSize int, dryRun bool) (*UserRekeyResult, error) {
+	if !r.cipher.Enabled() {
+		return nil, errors.New(errors.ErrCodeInvalidParam, "PII encryption is not configured")
+	}
+	if batchSize < 1 {
+		batchSize = defaultRekeyBatchSize
+	}
+
+	result := &UserRekeyResult{}
+	var afterID int32
+	for {
+		users, err := r.queries.ScanUsers(ctx, db.ScanUsersParams{AfterID: afterID, Limit: int32(batchSize)})
+		if err != nil {
+			return result, data.TranslateError(err, "scan users failed")
+		}
+		for _, user := range users {
+			afterID = user.ID
+			result.Scanned++
+
+			rekeyed, err := r.rekeyUser(ctx, user, dryRun)
+			if err != nil {
+				return result, err
+			}
+			switch {
+			case rekeyed:
+				result.Rekeyed++
+			case r.needsRekey(user):
+				result.Skipped++
+			}
+		}
+		if len(users) < batchSize {
+			break
+		}
+		r.log.Info(ctx, "rekey progress", logger.F("scanned", result.Scanned), logger.F("rekeyed", result.Rekeyed))
+	}
+	return result, nil
+}
+
+// needsRekey reports whether the stored row is plaintext, uses a retired key or has a stale blind index
+func (r *UserRekeyer) needsRekey(user db.User) bool {
+	if r.cipher.NeedsRekey(user.Name) || r.cipher.NeedsRekey(user.Email) {
+		return true
+	}
+	email, err := r.cipher.Decrypt(user.Email, userPIIAAD("email", user.TenantID, user.EmailIndex))
+	return err != nil || user.EmailIndex != r.cipher.BlindIndex(email)
+}
+
+// rekeyUser re-encrypts one user; it returns false when nothing was written
+func (r *UserRekeyer) rekeyUser(ctx context.Context, user db.User, dryRun bool) (bool, error) {
+	if !r.needsRekey(user) {
+		return false, nil
+	}
+	if dryRun {
+		return true, nil
+	}
+
+	// The stored values are bound to the stored blind index, the new ones to the recomputed index
+	name, err := r.cipher.Decrypt(user.Name, userPIIAAD("name", user.TenantID, user.EmailIndex))
+	if err != nil {
+		return false, errors.WrapInternalError(err, "decrypt user name failed")
+	}
+	email, err := r.cipher.Decrypt(user.Email, userPIIAAD("email", user.TenantID, user.EmailIndex))
+	if err != nil {
+		return false, errors.WrapInternalError(err, "decrypt user email failed")
+	}
+	emailIndex := r.cipher.BlindIndex(email)
+	encryptedName, err := r.cipher.Encrypt(name, userPIIAAD("name", user.TenantID, emailIndex))
+	if err != nil {
+		return false, errors.WrapInternalError(err, "encrypt user name failed")
+	}
+	encryptedEmail, err := r.cipher.Encrypt(email, userPIIAAD("email", user.TenantID, emailIndex))
+	if err != nil {
+		return false, errors.WrapInternalError(err, "encrypt user email failed")
+	}
+
+	updated, err := r.queries.UpdateUserPII(ctx, db.UpdateUserPIIParams{
+		Name:         encryptedName,
+		Email:        encryptedEmail,
+		EmailIndex:   emailIndex,
+		ID:           user.ID,
+		CurrentName:  user.Name,
+		CurrentEmail: user.Email,
+	})
+	if err != nil {
+		return false, data.TranslateError(err, "update user pii failed")
+	}
+	if updated == 0 {
+		// The application wrote the row in the meantime, with its current keyring
+		r.log.Warn(ctx, "user changed during rekey, skipped", logger.F("user_id", user.ID))
+		return false, nil
+	}
+	return true, nil
+}
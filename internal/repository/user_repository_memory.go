@@ -0,0 +1,399 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"example.com/classic/internal/data"
+	"example.com/classic/internal/data/db"
+	"example.com/classic/internal/domain"
+	"example.com/classic/pkg/errors"
+	"example.com/classic/pkg/logger"
+)
+
+// memoryUserState is the table of the in-memory repository; rows are stored like the users table
+type memoryUserState struct {
+	rows   map[int32]db.User
+	nextID int32
+}
+
+func (s *memoryUserState) clone() *memoryUserState {
+	rows := make(map[int32]db.User, len(s.rows))
+	for id, row := range s.rows {
+		rows[id] = row
+	}
+	return &memoryUserState{rows: rows, nextID: s.nextID}
+}
+
+// userRepositoryMemory implements UserRepository in memory (tests and local development).
+// A transaction holds the repository lock from its first use until commit or rollback,
+// so transactions are serializable and other callers wait for them.
+type userRepositoryMemory struct {
+	mu         sync.RWMutex
+	state      *memoryUserState
+	attrSchema *domain.AttributeSchema
+	log        logger.Logger
+}
+
+// NewUserRepositoryMemory creates a new in-memory user repository; use it with data.MemoryTransactionManager
+func NewUserRepositoryMemory(attrSchema *domain.AttributeSchema, log logger.Logger) domain.UserRepository {
+	return &userRepositoryMemory{
+		state:      &memoryUserState{rows: make(map[int32]db.User)},
+		attrSchema: attrSchema,
+		log:        log,
+	}
+}
+
+// memoryUserTx is the working copy of a transaction
+type memoryUserTx struct {
+	mu    sync.Mutex
+	repo  *userRepositoryMemory
+	state *memoryUserState
+}
+
+func (tx *memoryUserTx) Commit() {
+	tx.repo.state = tx.state
+	tx.repo.mu.Unlock()
+}
+
+func (tx *memoryUserTx) Rollback() {
+	tx.repo.mu.Unlock()
+}
+
+// view runs fn with read access to the state visible to ctx
+func (r *userRepositoryMemory) view(ctx context.Context, fn func(state *memoryUserState) error) error {
+	if tx, ok := domain.TxFromContext(ctx).(*data.MemoryTx); ok && tx != nil {
+		return r.update(ctx, fn)
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return fn(r.state)
+}
+
+// update runs fn with write access; outside a transaction the change is applied only if fn succeeds
+func (r *userRepositoryMemory) update(ctx context.Context, fn func(state *memoryUserState) error) error {
+	if tx, ok := domain.TxFromContext(ctx).(*data.MemoryTx); ok && tx != nil {
+		participant := tx.Enlist(r, func() data.MemoryTxParticipant {
+			r.mu.Lock()
+			return &memoryUserTx{repo: r, state: r.state.clone()}
+		}).(*memoryUserTx)
+		participant.mu.Lock()
+		defer participant.mu.Unlock()
+		return fn(participant.state)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	state := r.state.clone()
+	if err := fn(state); err != nil {
+		return err
+	}
+	r.state = state
+	return nil
+}
+
+// findByEmail returns the row with the email in the tenant
+func (s *memoryUserState) findByEmail(tenant, email string) (db.User, bool) {
+	for _, row := range s.rows {
+		if row.TenantID == tenant && row.Email == email {
+			return row, true
+		}
+	}
+	return db.User{}, false
+}
+
+// find returns the row with the ID in the tenant
+func (s *memoryUserState) find(tenant string, id int) (db.User, bool) {
+	row, ok := s.rows[int32(id)]
+	if !ok || row.TenantID != tenant {
+		return db.User{}, false
+	}
+	return row, true
+}
+
+// Create creates a new user
+func (r *userRepositoryMemory) Create(ctx context.Context, user *domain.User) error {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return err
+	}
+	attributes, err := marshalAttributes(user.Attributes())
+	if err != nil {
+		return errors.WrapInternalError(err, "marshal user attributes failed")
+	}
+
+	var created db.User
+	err = r.update(ctx, func(state *memoryUserState) error {
+		if _, ok := state.findByEmail(tenant, user.Email().String()); ok {
+			return errors.ErrUserAlreadyExists
+		}
+
+		now := time.Now()
+		state.nextID++
+		created = db.User{
+			ID:         state.nextID,
+			TenantID:   tenant,
+			Name:       user.Name().String(),
+			Email:      user.Email().String(),
+			Password:   user.GetHashedPassword(),
+			Status:     db.Status(user.Status()),
+			Attributes: attributes,
+			CreatedAt:  now,
+			UpdatedAt:  now,
+		}
+		state.rows[created.ID] = created
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	user.SetID(int(created.ID))
+	user.SetTenantID(created.TenantID)
+	user.SetCreatedAt(created.CreatedAt)
+	user.SetUpdatedAt(created.UpdatedAt)
+
+	r.log.Debug(ctx, "user created in memory", logger.F("user_id", user.ID()))
+	return nil
+}
+
+// GetByID retrieves a user by ID
+func (r *userRepositoryMemory) GetByID(ctx context.Context, id int) (*domain.User, error) {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var row db.User
+	err = r.view(ctx, func(state *memoryUserState) error {
+		var ok bool
+		if row, ok = state.find(tenant, id); !ok {
+			return errors.ErrUserNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return dbUserToDomain(row, r.attrSchema)
+}
+
+// GetByEmail retrieves a user by email
+func (r *userRepositoryMemory) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var row db.User
+	err = r.view(ctx, func(state *memoryUserState) error {
+		var ok bool
+		if row, ok = state.findByEmail(tenant, email); !ok {
+			return errors.ErrUserNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return dbUserToDomain(row, r.attrSchema)
+}
+
+// Update updates a user
+func (r *userRepositoryMemory) Update(ctx context.Context, user *domain.User) error {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return err
+	}
+	attributes, err := marshalAttributes(user.Attributes())
+	if err != nil {
+		return errors.WrapInternalError(err, "marshal user attributes failed")
+	}
+
+	var updatedAt time.Time
+	err = r.update(ctx, func(state *memoryUserState) error {
+		row, ok := state.find(tenant, user.ID())
+		if !ok {
+			return errors.ErrUserNotFound
+		}
+		// Same as the (tenant_id, email) unique key
+		if other, ok := state.findByEmail(tenant, user.Email().String()); ok && other.ID != row.ID {
+			return errors.ErrUserAlreadyExists
+		}
+
+		row.Name = user.Name().String()
+		row.Email = user.Email().String()
+		// An empty password keeps the stored hash, as in the UpdateUser query
+		if password := user.GetHashedPassword(); password != "" {
+			row.Password = password
+		}
+		row.Status = db.Status(user.Status())
+		row.Attributes = attributes
+		row.UpdatedAt = time.Now()
+		state.rows[row.ID] = row
+		updatedAt = row.UpdatedAt
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	user.SetUpdatedAt(updatedAt)
+	return nil
+}
+
+// Delete deletes a user
+func (r *userRepositoryMemory) Delete(ctx context.Context, id int) error {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.update(ctx, func(state *memoryUserState) error {
+		if _, ok := state.find(tenant, id); !ok {
+			return errors.ErrUserNotFound
+		}
+		delete(state.rows, int32(id))
+		return nil
+	})
+}
+
+// List retrieves a paginated list of users, with the same filters and order as the ListUsers query
+func (r *userRepositoryMemory) List(ctx context.Context, params domain.UserListParams) ([]*domain.User, int64, error) {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	attrFilters, err := toAttributeFilters(params.Attributes)
+	if err != nil {
+		return nil, 0, errors.WrapInternalError(err, "build attribute filters failed")
+	}
+
+	var matched []db.User
+	err = r.view(ctx, func(state *memoryUserState) error {
+		for _, row := range state.rows {
+			if row.TenantID == tenant && matchUserRow(row, params, attrFilters) {
+				matched = append(matched, row)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	sort.Slice(matched, func(i, j int) bool { return matched[i].ID > matched[j].ID })
+	total := int64(len(matched))
+
+	offset := (params.Page - 1) * params.PageSize
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > len(matched) {
+		offset = len(matched)
+	}
+	end := offset + params.PageSize
+	if params.PageSize < 0 || end > len(matched) {
+		end = len(matched)
+	}
+
+	result := make([]*domain.User, 0, end-offset)
+	for _, row := range matched[offset:end] {
+		user, err := dbUserToDomain(row, r.attrSchema)
+		if err != nil {
+			return nil, 0, errors.WrapInternalError(err, "convert domain user failed")
+		}
+		result = append(result, user)
+	}
+	return result, total, nil
+}
+
+// matchUserRow applies the List filters; name and email match case-insensitive substrings like LIKE
+func matchUserRow(row db.User, params domain.UserListParams, attrFilters []db.AttributeFilter) bool {
+	if params.ID != nil && row.ID != int32(*params.ID) {
+		return false
+	}
+	if params.Name != nil && !containsFold(row.Name, *params.Name) {
+		return false
+	}
+	if params.Email != nil && !containsFold(row.Email, *params.Email) {
+		return false
+	}
+	if params.Status != nil && row.Status != db.Status(*params.Status) {
+		return false
+	}
+	if len(attrFilters) == 0 {
+		return true
+	}
+
+	attributes, err := unmarshalAttributes(row.Attributes)
+	if err != nil {
+		return false
+	}
+	for i, filter := range attrFilters {
+		value, ok := attributes[params.Attributes[i].Name]
+		if !ok {
+			return false
+		}
+		encoded, err := json.Marshal(value)
+		if err != nil || !bytes.Equal(encoded, filter.Value) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
+
+// ExistsByEmail checks if an email exists
+func (r *userRepositoryMemory) ExistsByEmail(ctx context.Context, email string) (bool, error) {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	var exists bool
+	err = r.view(ctx, func(state *memoryUserState) error {
+		_, exists = state.findByEmail(tenant, email)
+		return nil
+	})
+	return exists, err
+}
+
+// Save saves an aggregate
+func (r *userRepositoryMemory) Save(ctx context.Context, aggregate *domain.UserAggregate) error {
+	user := aggregate.User()
+
+	if user.ID() == 0 {
+		return r.Create(ctx, user)
+	}
+	return r.Update(ctx, user)
+}
+
+// GetAggregateByID retrieves an aggregate by ID
+func (r *userRepositoryMemory) GetAggregateByID(ctx context.Context, id int) (*domain.UserAggregate, error) {
+	user, err := r.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return domain.RebuildUserAggregate(user), nil
+}
+
+// GetAggregateByEmail retrieves an aggregate by email
+func (r *userRepositoryMemory) GetAggregateByEmail(ctx context.Context, email string) (*domain.UserAggregate, error) {
+	user, err := r.GetByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	return domain.RebuildUserAggregate(user), nil
+}
+
+// Ensure implementation
+var _ domain.UserRepository = (*userRepositoryMemory)(nil)
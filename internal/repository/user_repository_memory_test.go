@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"example.com/classic/internal/data"
+	"example.com/classic/internal/domain"
+	"example.com/classic/internal/repository/repositorytest"
+	"example.com/classic/pkg/contextx"
+	"example.com/classic/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserRepositoryMemory_Contract(t *testing.T) {
+	log := logger.New("test", "error", false)
+	repositorytest.Run(t, func(t *testing.T) (domain.UserRepository, domain.TransactionManager) {
+		return NewUserRepositoryMemory(repositorytest.AttributeSchema(), log), data.NewMemoryTransactionManager()
+	})
+}
+
+func TestUserRepositoryMemory_Concurrent(t *testing.T) {
+	repo := NewUserRepositoryMemory(nil, logger.New("test", "error", false))
+	tm := data.NewMemoryTransactionManager()
+	ctx := contextx.WithTenantID(context.Background(), "default")
+
+	newUser := func(i int) *domain.User {
+		name, _ := domain.NewName(fmt.Sprintf("User %d", i))
+		email, _ := domain.NewEmail(fmt.Sprintf("user%d@example.com", i))
+		password, _ := domain.NewHashedPassword("hashed")
+		user, err := domain.NewUser(0, *name, *email, *password, domain.StatusActive, time.Time{}, time.Time{})
+		require.NoError(t, err)
+		return user
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// Half of the writers go through transactions
+			if i%2 == 0 {
+				assert.NoError(t, tm.WithTransaction(ctx, func(txCtx context.Context) error {
+					return repo.Create(txCtx, newUser(i))
+				}))
+				return
+			}
+			assert.NoError(t, repo.Create(ctx, newUser(i)))
+			_, _, err := repo.List(ctx, domain.UserListParams{Page: 1, PageSize: 10})
+			assert.NoError(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	users, total, err := repo.List(ctx, domain.UserListParams{Page: 1, PageSize: 100})
+	require.NoError(t, err)
+	assert.Equal(t, int64(50), total)
+
+	ids := make(map[int]bool)
+	for _, user := range users {
+		ids[user.ID()] = true
+	}
+	assert.Len(t, ids, 50)
+}
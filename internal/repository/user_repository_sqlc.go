@@ -1,28 +1,39 @@
 package repository
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
+	"example.com/classic/internal/data"
 	"example.com/classic/internal/data/db"
 	"example.com/classic/internal/domain"
+	"example.com/classic/pkg/contextx"
 	"example.com/classic/pkg/errors"
 	"example.com/classic/pkg/logger"
 )
 
-// userRepositorySQLC implements UserRepository using sqlc
+// userRepositorySQLC implements UserRepository using sqlc.
+// Name and email are encrypted with cipher; email lookups use its blind index.
+// Attributes are read back with the types of attrSchema.
 type userRepositorySQLC struct {
-	queries *db.Queries
-	log     logger.Logger
+	queries    *db.Queries
+	cipher     domain.PIICipher
+	attrSchema *domain.AttributeSchema
+	log        logger.Logger
 }
 
 // NewUserRepositorySQLC creates a new user repository using sqlc
-func NewUserRepositorySQLC(dbtx db.DBTX, log logger.Logger) domain.UserRepository {
+func NewUserRepositorySQLC(dbtx db.DBTX, cipher domain.PIICipher, attrSchema *domain.AttributeSchema, log logger.Logger) domain.UserRepository {
 	return &userRepositorySQLC{
-		queries: db.New(dbtx),
-		log:     log,
+		queries:    db.New(dbtx),
+		cipher:     cipher,
+		attrSchema: attrSchema,
+		log:        log,
 	}
 }
 
@@ -34,38 +45,64 @@ func (r *userRepositorySQLC) getQueries(ctx context.Context) *db.Queries {
 	return r.queries
 }
 
+// tenantID returns the tenant carried by the context; user queries never run unscoped
+func tenantID(ctx context.Context) (string, error) {
+	if id := contextx.GetTenantID(ctx); id != "" {
+		return id, nil
+	}
+	return "", errors.ErrTenantRequired
+}
+
 // Create creates a new user
 func (r *userRepositorySQLC) Create(ctx context.Context, user *domain.User) error {
 	r.log.Debug(ctx, "creating user", logger.F("email", user.Email().String()))
 
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return err
+	}
 	queries := r.getQueries(ctx)
 
-	// Check if email already exists
-	exists, err := queries.ExistsByEmail(ctx, user.Email().String())
+	// Check if email already exists (emails are unique per tenant, including rows not yet rekeyed)
+	emailIndex := r.cipher.BlindIndex(user.Email().String())
+	exists, err := queries.ExistsByEmail(ctx, db.ExistsByEmailParams{TenantID: tenant, EmailIndexes: r.cipher.LookupIndexes(user.Email().String())})
 	if err != nil {
-		return errors.WrapInternalError(err, "check email exists failed")
+		return data.TranslateError(err, "check email exists failed")
 	}
 	if exists {
 		return errors.ErrUserAlreadyExists
 	}
 
+	name, email, err := r.encryptPII(user, tenant, emailIndex)
+	if err != nil {
+		return err
+	}
+	attributes, err := marshalAttributes(user.Attributes())
+	if err != nil {
+		return errors.WrapInternalError(err, "marshal user attributes failed")
+	}
+
 	// Create user
 	now := time.Now()
 	created, err := queries.CreateUser(ctx, db.CreateUserParams{
-		Name:      user.Name().String(),
-		Email:     user.Email().String(),
-		Password:  user.GetHashedPassword(),
-		Status:    db.Status(user.Status()),
-		CreatedAt: now,
-		UpdatedAt: now,
+		TenantID:   tenant,
+		Name:       name,
+		Email:      email,
+		EmailIndex: emailIndex,
+		Password:   user.GetHashedPassword(),
+		Status:     db.Status(user.Status()),
+		Attributes: attributes,
+		CreatedAt:  now,
+		UpdatedAt:  now,
 	})
 	if err != nil {
 		r.log.Error(ctx, "create user failed", logger.F("error", err))
-		return errors.WrapInternalError(err, "create user failed")
+		return translateUserWriteError(err, "create user failed")
 	}
 
 	// Update domain object
 	user.SetID(int(created.ID))
+	user.SetTenantID(created.TenantID)
 	user.SetCreatedAt(created.CreatedAt)
 	user.SetUpdatedAt(created.UpdatedAt)
 
@@ -77,14 +114,18 @@ func (r *userRepositorySQLC) Create(ctx context.Context, user *domain.User) erro
 func (r *userRepositorySQLC) GetByID(ctx context.Context, id int) (*domain.User, error) {
 	r.log.Debug(ctx, "getting user by id", logger.F("user_id", id))
 
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
 	queries := r.getQueries(ctx)
 
-	user, err := queries.GetUserByID(ctx, int32(id))
+	user, err := queries.GetUserByID(ctx, db.GetUserByIDParams{TenantID: tenant, ID: int32(id)})
 	if err != nil {
-		if err == sql.ErrNoRows {
+		if errors.Is(err, sql.ErrNoRows) {
 			return nil, errors.ErrUserNotFound
 		}
-		return nil, errors.WrapInternalError(err, "get user by id failed")
+		return nil, data.TranslateError(err, "get user by id failed")
 	}
 
 	return r.dbToDomain(user)
@@ -94,14 +135,18 @@ func (r *userRepositorySQLC) GetByID(ctx context.Context, id int) (*domain.User,
 func (r *userRepositorySQLC) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
 	r.log.Debug(ctx, "getting user by email", logger.F("email", email))
 
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
 	queries := r.getQueries(ctx)
 
-	user, err := queries.GetUserByEmail(ctx, email)
+	user, err := queries.GetUserByEmail(ctx, db.GetUserByEmailParams{TenantID: tenant, EmailIndexes: r.cipher.LookupIndexes(email)})
 	if err != nil {
-		if err == sql.ErrNoRows {
+		if errors.Is(err, sql.ErrNoRows) {
 			return nil, errors.ErrUserNotFound
 		}
-		return nil, errors.WrapInternalError(err, "get user by email failed")
+		return nil, data.TranslateError(err, "get user by email failed")
 	}
 
 	return r.dbToDomain(user)
@@ -111,25 +156,42 @@ func (r *userRepositorySQLC) GetByEmail(ctx context.Context, email string) (*dom
 func (r *userRepositorySQLC) Update(ctx context.Context, user *domain.User) error {
 	r.log.Debug(ctx, "updating user", logger.F("user_id", user.ID()))
 
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return err
+	}
 	queries := r.getQueries(ctx)
 
 	// Check if user exists
-	_, err := r.GetByID(ctx, user.ID())
+	if _, err := r.GetByID(ctx, user.ID()); err != nil {
+		return err
+	}
+
+	emailIndex := r.cipher.BlindIndex(user.Email().String())
+	name, email, err := r.encryptPII(user, tenant, emailIndex)
 	if err != nil {
 		return err
 	}
+	attributes, err := marshalAttributes(user.Attributes())
+	if err != nil {
+		return errors.WrapInternalError(err, "marshal user attributes failed")
+	}
 
 	// Update user
 	updated, err := queries.UpdateUser(ctx, db.UpdateUserParams{
-		Name:      user.Name().String(),
-		Email:     user.Email().String(),
-		Status:    db.Status(user.Status()),
-		UpdatedAt: time.Now(),
-		ID:        int32(user.ID()),
+		Name:       name,
+		Email:      email,
+		EmailIndex: emailIndex,
+		Password:   user.GetHashedPassword(),
+		Status:     db.Status(user.Status()),
+		Attributes: attributes,
+		UpdatedAt:  time.Now(),
+		TenantID:   tenant,
+		ID:         int32(user.ID()),
 	})
 	if err != nil {
 		r.log.Error(ctx, "update user failed", logger.F("error", err))
-		return errors.WrapInternalError(err, "update user failed")
+		return translateUserWriteError(err, "update user failed")
 	}
 
 	user.SetUpdatedAt(updated.UpdatedAt)
@@ -142,18 +204,21 @@ func (r *userRepositorySQLC) Update(ctx context.Context, user *domain.User) erro
 func (r *userRepositorySQLC) Delete(ctx context.Context, id int) error {
 	r.log.Debug(ctx, "deleting user", logger.F("user_id", id))
 
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return err
+	}
 	queries := r.getQueries(ctx)
 
 	// Check if user exists
-	_, err := r.GetByID(ctx, id)
-	if err != nil {
+	if _, err := r.GetByID(ctx, id); err != nil {
 		return err
 	}
 
 	// Delete user
-	if err := queries.DeleteUser(ctx, int32(id)); err != nil {
+	if err := queries.DeleteUser(ctx, db.DeleteUserParams{TenantID: tenant, ID: int32(id)}); err != nil {
 		r.log.Error(ctx, "delete user failed", logger.F("error", err))
-		return errors.WrapInternalError(err, "delete user failed")
+		return data.TranslateError(err, "delete user failed")
 	}
 
 	r.log.Info(ctx, "user deleted successfully", logger.F("user_id", id))
@@ -164,36 +229,65 @@ func (r *userRepositorySQLC) Delete(ctx context.Context, id int) error {
 func (r *userRepositorySQLC) List(ctx context.Context, params domain.UserListParams) ([]*domain.User, int64, error) {
 	r.log.Debug(ctx, "listing users", logger.F("params", params))
 
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
 	queries := r.getQueries(ctx)
 
+	attrFilters, err := toAttributeFilters(params.Attributes)
+	if err != nil {
+		return nil, 0, errors.WrapInternalError(err, "build attribute filters failed")
+	}
+
 	// Build query params
 	dbParams := db.ListUsersParams{
-		ID:     db.ToNullInt32(params.ID),
-		Name:   db.ToNullString(params.Name),
-		Email:  db.ToNullString(params.Email),
-		Status: db.ToNullStatus((*db.Status)(params.Status)),
-		Limit:  int32(params.PageSize),
-		Offset: int32((params.Page - 1) * params.PageSize),
+		TenantID:   tenant,
+		ID:         db.ToNullInt32(params.ID),
+		Name:       db.ToNullString(params.Name),
+		Email:      db.ToNullString(params.Email),
+		Status:     db.ToNullStatus((*db.Status)(params.Status)),
+		Attributes: attrFilters,
+		Limit:      int32(params.PageSize),
+		Offset:     int32((params.Page - 1) * params.PageSize),
+	}
+
+	// Encrypted columns cannot be searched with LIKE: a full email address is matched
+	// exactly through its blind index, name and partial email filters after decryption
+	if r.cipher.Enabled() {
+		dbParams.Name, dbParams.Email = db.NullString{}, db.NullString{}
+		if params.Email != nil {
+			if _, err := domain.NewEmail(*params.Email); err == nil {
+				dbParams.EmailIndexes = r.cipher.LookupIndexes(*params.Email)
+				params.Email = nil
+			}
+		}
+		if params.Name != nil || params.Email != nil {
+			return r.listDecrypted(ctx, queries, dbParams, params)
+		}
 	}
 
 	// Get total count
 	countParams := db.CountUsersParams{
-		ID:     dbParams.ID,
-		Name:   dbParams.Name,
-		Email:  dbParams.Email,
-		Status: dbParams.Status,
+		TenantID:     dbParams.TenantID,
+		ID:           dbParams.ID,
+		Name:         dbParams.Name,
+		Email:        dbParams.Email,
+		EmailIndexes: dbParams.EmailIndexes,
+		Status:       dbParams.Status,
+		Attributes:   dbParams.Attributes,
 	}
 	total, err := queries.CountUsers(ctx, countParams)
 	if err != nil {
 		r.log.Error(ctx, "count users failed", logger.F("error", err))
-		return nil, 0, errors.WrapInternalError(err, "count users failed")
+		return nil, 0, data.TranslateError(err, "count users failed")
 	}
 
 	// Get users
 	users, err := queries.ListUsers(ctx, dbParams)
 	if err != nil {
 		r.log.Error(ctx, "list users failed", logger.F("error", err))
-		return nil, 0, errors.WrapInternalError(err, "list users failed")
+		return nil, 0, data.TranslateError(err, "list users failed")
 	}
 
 	// Convert to domain objects
@@ -214,8 +308,99 @@ func (r *userRepositorySQLC) List(ctx context.Context, params domain.UserListPar
 
 // ExistsByEmail checks if an email exists
 func (r *userRepositorySQLC) ExistsByEmail(ctx context.Context, email string) (bool, error) {
+	tenant, err := tenantID(ctx)
+	if err != nil {
+		return false, err
+	}
 	queries := r.getQueries(ctx)
-	return queries.ExistsByEmail(ctx, email)
+	exists, err := queries.ExistsByEmail(ctx, db.ExistsByEmailParams{TenantID: tenant, EmailIndexes: r.cipher.LookupIndexes(email)})
+	if err != nil {
+		return false, data.TranslateError(err, "check email exists failed")
+	}
+	return exists, nil
+}
+
+// maxDecryptedFilterRows bounds the users read to match name / email substrings on encrypted columns
+const maxDecryptedFilterRows = 10000
+
+// listDecrypted lists users with name / email substring filters by matching them after decryption.
+// It reads every user that matches the SQL filters, so it refuses to when there are more than maxDecryptedFilterRows.
+func (r *userRepositorySQLC) listDecrypted(ctx context.Context, queries *db.Queries, dbParams db.ListUsersParams, params domain.UserListParams) ([]*domain.User, int64, error) {
+	candidates, err := queries.CountUsers(ctx, db.CountUsersParams{
+		TenantID:     dbParams.TenantID,
+		ID:           dbParams.ID,
+		EmailIndexes: dbParams.EmailIndexes,
+		Status:       dbParams.Status,
+		Attributes:   dbParams.Attributes,
+	})
+	if err != nil {
+		r.log.Error(ctx, "count users failed", logger.F("error", err))
+		return nil, 0, data.TranslateError(err, "count users failed")
+	}
+	if candidates > maxDecryptedFilterRows {
+		return nil, 0, errors.New(errors.ErrCodeInvalidParam, fmt.Sprintf(
+			"name / email substring filters search at most %d users; filter by full email, status or attributes", maxDecryptedFilterRows))
+	}
+
+	dbParams.Limit, dbParams.Offset = maxDecryptedFilterRows, 0
+	users, err := queries.ListUsers(ctx, dbParams)
+	if err != nil {
+		r.log.Error(ctx, "list users failed", logger.F("error", err))
+		return nil, 0, data.TranslateError(err, "list users failed")
+	}
+
+	var matched []*domain.User
+	for _, user := range users {
+		domainUser, err := r.dbToDomain(user)
+		if err != nil {
+			return nil, 0, errors.WrapInternalError(err, "convert domain user failed")
+		}
+		if params.Name != nil && !containsFold(domainUser.Name().String(), *params.Name) {
+			continue
+		}
+		if params.Email != nil && !containsFold(domainUser.Email().String(), *params.Email) {
+			continue
+		}
+		matched = append(matched, domainUser)
+	}
+
+	total := int64(len(matched))
+	offset := (params.Page - 1) * params.PageSize
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > len(matched) {
+		offset = len(matched)
+	}
+	end := offset + params.PageSize
+	if params.PageSize < 0 || end > len(matched) {
+		end = len(matched)
+	}
+	return matched[offset:end], total, nil
+}
+
+// piiAAD identifies the row and column of an encrypted value; it is bound to the ciphertext
+// as additional data, so a value copied to another row or column fails to decrypt
+func piiAAD(table, column string, row ...string) string {
+	return table + "." + column + ":" + strings.Join(row, "/")
+}
+
+// userPIIAAD the users row is identified by its unique key, which is rewritten with the PII columns
+func userPIIAAD(column, tenantID, emailIndex string) string {
+	return piiAAD("users", column, tenantID, emailIndex)
+}
+
+// encryptPII returns the stored form of the user's name and email
+func (r *userRepositorySQLC) encryptPII(user *domain.User, tenantID, emailIndex string) (string, string, error) {
+	name, err := r.cipher.Encrypt(user.Name().String(), userPIIAAD("name", tenantID, emailIndex))
+	if err != nil {
+		return "", "", errors.WrapInternalError(err, "encrypt user name failed")
+	}
+	email, err := r.cipher.Encrypt(user.Email().String(), userPIIAAD("email", tenantID, emailIndex))
+	if err != nil {
+		return "", "", errors.WrapInternalError(err, "encrypt user email failed")
+	}
+	return name, email, nil
 }
 
 // Save saves an aggregate
@@ -246,8 +431,35 @@ func (r *userRepositorySQLC) GetAggregateByEmail(ctx context.Context, email stri
 	return domain.RebuildUserAggregate(user), nil
 }
 
-// dbToDomain converts db.User to domain.User
+// translateUserWriteError maps constraint violations of user writes: the email is taken
+// (a concurrent registration won the race after ExistsByEmail) or the tenant is gone
+func translateUserWriteError(err error, message string) error {
+	switch data.ClassifyError(err) {
+	case data.KindUniqueViolation:
+		return errors.ErrUserAlreadyExists
+	case data.KindForeignKeyViolation:
+		return errors.ErrTenantNotFound
+	default:
+		return data.TranslateError(err, message)
+	}
+}
+
+// dbToDomain decrypts the PII columns and converts db.User to domain.User
 func (r *userRepositorySQLC) dbToDomain(user db.User) (*domain.User, error) {
+	name, err := r.cipher.Decrypt(user.Name, userPIIAAD("name", user.TenantID, user.EmailIndex))
+	if err != nil {
+		return nil, fmt.Errorf("decrypt user name: %w", err)
+	}
+	email, err := r.cipher.Decrypt(user.Email, userPIIAAD("email", user.TenantID, user.EmailIndex))
+	if err != nil {
+		return nil, fmt.Errorf("decrypt user email: %w", err)
+	}
+	user.Name, user.Email = name, email
+	return dbUserToDomain(user, r.attrSchema)
+}
+
+// dbUserToDomain converts a plaintext db.User to domain.User; attributes are normalized with attrSchema
+func dbUserToDomain(user db.User, attrSchema *domain.AttributeSchema) (*domain.User, error) {
 	status := domain.Status(user.Status)
 	if !status.IsValid() {
 		status = domain.StatusInactive
@@ -268,7 +480,12 @@ func (r *userRepositorySQLC) dbToDomain(user db.User) (*domain.User, error) {
 		return nil, fmt.Errorf("invalid user password from database: %w", err)
 	}
 
-	return domain.NewUser(
+	attributes, err := unmarshalAttributes(user.Attributes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user attributes from database: %w", err)
+	}
+
+	domainUser, err := domain.NewUser(
 		int(user.ID),
 		*name,
 		*email,
@@ -277,6 +494,71 @@ func (r *userRepositorySQLC) dbToDomain(user db.User) (*domain.User, error) {
 		user.CreatedAt,
 		user.UpdatedAt,
 	)
+	if err != nil {
+		return nil, err
+	}
+	domainUser.SetTenantID(user.TenantID)
+	domainUser.SetAttributes(attrSchema.Normalize(attributes))
+	return domainUser, nil
+}
+
+// marshalAttributes encodes attributes for the JSON column; empty attributes are stored as NULL
+func marshalAttributes(attrs domain.Attributes) ([]byte, error) {
+	if len(attrs) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(attrs)
+}
+
+// unmarshalAttributes decodes the JSON column, keeping integers as int64;
+// the schema decides the type of whole numbers (see dbUserToDomain)
+func unmarshalAttributes(data []byte) (domain.Attributes, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var raw map[string]interface{}
+	if err := decoder.Decode(&raw); err != nil {
+		return nil, err
+	}
+	if len(raw) == 0 {
+		return nil, nil
+	}
+
+	attrs := make(domain.Attributes, len(raw))
+	for name, value := range raw {
+		if number, ok := value.(json.Number); ok {
+			if n, err := number.Int64(); err == nil {
+				value = n
+			} else if f, err := number.Float64(); err == nil {
+				value = f
+			}
+		}
+		attrs[name] = value
+	}
+	return attrs, nil
+}
+
+// toAttributeFilters converts domain attribute filters to JSON path / value pairs
+func toAttributeFilters(filters []domain.AttributeFilter) ([]db.AttributeFilter, error) {
+	if len(filters) == 0 {
+		return nil, nil
+	}
+	result := make([]db.AttributeFilter, len(filters))
+	for i, filter := range filters {
+		value, err := json.Marshal(filter.Value)
+		if err != nil {
+			return nil, err
+		}
+		// Attribute names are restricted by the schema, so quoting is enough
+		result[i] = db.AttributeFilter{
+			Path:  fmt.Sprintf(`$."%s"`, filter.Name),
+			Value: value,
+		}
+	}
+	return result, nil
 }
 
 // Ensure implementation
@@ -0,0 +1,291 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"example.com/classic/internal/data"
+	"example.com/classic/internal/domain"
+	"example.com/classic/internal/infrastructure/encryption"
+	"example.com/classic/internal/repository/repositorytest"
+	"example.com/classic/pkg/contextx"
+	"example.com/classic/pkg/errors"
+	"example.com/classic/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+)
+
+// sqliteSchema is internal/data/schema.sql translated to SQLite
+const sqliteSchema = `
+CREATE TABLE tenants (
+    id VARCHAR(64) PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+INSERT INTO tenants (id, name) VALUES ('default', 'Default'), ('tenant-a', 'Tenant A'), ('tenant-b', 'Tenant B');
+CREATE TABLE users (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    tenant_id VARCHAR(64) NOT NULL DEFAULT 'default',
+    name VARCHAR(512) NOT NULL,
+    email VARCHAR(512) NOT NULL,
+    email_index CHAR(64) NOT NULL,
+    password VARCHAR(255) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'inactive',
+    attributes JSON NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (tenant_id, email_index),
+    CONSTRAINT fk_users_tenant FOREIGN KEY (tenant_id) REFERENCES tenants (id)
+);
+CREATE INDEX idx_tenant_status ON users (tenant_id, status);
+CREATE TABLE events (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    tenant_id VARCHAR(64) NOT NULL,
+    aggregate_id VARCHAR(64) NOT NULL,
+    sequence_no INT NOT NULL,
+    type VARCHAR(100) NOT NULL,
+    payload JSON NOT NULL,
+    metadata JSON NULL,
+    occurred_at DATETIME NOT NULL,
+    UNIQUE (tenant_id, aggregate_id, sequence_no)
+);
+`
+
+// openSQLite opens a fresh file-backed SQLite database with the application tables
+// SQLite only enforces foreign keys with PRAGMA foreign_keys=ON, set here on every pooled connection
+func openSQLite(t *testing.T) *sql.DB {
+	t.Helper()
+	sqldb, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "users.db")+"?_pragma=busy_timeout(5000)&_pragma=foreign_keys(1)")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = sqldb.Close() })
+
+	_, err = sqldb.Exec(sqliteSchema)
+	require.NoError(t, err)
+	return sqldb
+}
+
+// testKeyring writes a keyring file with the given primary key; the blind index key is fixed
+func testKeyring(t *testing.T, primary string) domain.PIICipher {
+	t.Helper()
+	key := func(b byte) string { return base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{b}, 32)) }
+	content := fmt.Sprintf(`{"primary": %q, "keys": {"k1": %q, "k2": %q}, "blind_index_key": %q}`, primary, key(1), key(2), key(9))
+	path := filepath.Join(t.TempDir(), "keyring.json")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	cipher, err := encryption.LoadCipher(path)
+	require.NoError(t, err)
+	return cipher
+}
+
+func TestUserRepositorySQLC_Contract(t *testing.T) {
+	log := logger.New("test", "error", false)
+	repositorytest.Run(t, func(t *testing.T) (domain.UserRepository, domain.TransactionManager) {
+		sqldb := openSQLite(t)
+		return NewUserRepositorySQLC(sqldb, encryption.NewPlaintextCipher(), repositorytest.AttributeSchema(), log), data.NewTransactionManager(sqldb, log)
+	})
+}
+
+func TestUserRepositorySQLC_EncryptedContract(t *testing.T) {
+	log := logger.New("test", "error", false)
+	repositorytest.Run(t, func(t *testing.T) (domain.UserRepository, domain.TransactionManager) {
+		sqldb := openSQLite(t)
+		return NewUserRepositorySQLC(sqldb, testKeyring(t, "k1"), repositorytest.AttributeSchema(), log), data.NewTransactionManager(sqldb, log)
+	})
+}
+
+func TestUserRepositorySQLC_EncryptsPII(t *testing.T) {
+	log := logger.New("test", "error", false)
+	sqldb := openSQLite(t)
+	repo := NewUserRepositorySQLC(sqldb, testKeyring(t, "k1"), nil, log)
+	ctx := contextx.WithTenantID(context.Background(), "default")
+
+	user := newSQLCTestUser(t, "Alice", "alice@example.com")
+	require.NoError(t, repo.Create(ctx, user))
+
+	var name, email, emailIndex string
+	require.NoError(t, sqldb.QueryRow(`SELECT name, email, email_index FROM users WHERE id = ?`, user.ID()).Scan(&name, &email, &emailIndex))
+	assert.True(t, strings.HasPrefix(name, "enc:v2:k1:"))
+	assert.True(t, strings.HasPrefix(email, "enc:v2:k1:"))
+	assert.NotContains(t, email, "alice")
+	assert.Len(t, emailIndex, 64)
+
+	// Lookups and uniqueness go through the blind index, ignoring case
+	stored, err := repo.GetByEmail(ctx, "Alice@Example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "alice@example.com", stored.Email().String())
+	assert.ErrorIs(t, repo.Create(ctx, newSQLCTestUser(t, "Alice Again", "ALICE@example.com")), errors.ErrUserAlreadyExists)
+
+	// Ciphertexts are bound to their row and column: copied elsewhere they no longer decrypt
+	bob := newSQLCTestUser(t, "Bob", "bob@example.com")
+	require.NoError(t, repo.Create(ctx, bob))
+	_, err = sqldb.Exec(`UPDATE users SET name = ? WHERE id = ?`, name, bob.ID())
+	require.NoError(t, err)
+	_, err = repo.GetByID(ctx, bob.ID())
+	assert.Error(t, err)
+	_, err = sqldb.Exec(`UPDATE users SET name = ? WHERE id = ?`, email, user.ID())
+	require.NoError(t, err)
+	_, err = repo.GetByID(ctx, user.ID())
+	assert.Error(t, err)
+}
+
+func TestUserRepositorySQLC_EncryptedListFilters(t *testing.T) {
+	log := logger.New("test", "error", false)
+	sqldb := openSQLite(t)
+	repo := NewUserRepositorySQLC(sqldb, testKeyring(t, "k1"), nil, log)
+	ctx := contextx.WithTenantID(context.Background(), "default")
+	require.NoError(t, repo.Create(ctx, newSQLCTestUser(t, "Alice", "alice@example.com")))
+	require.NoError(t, repo.Create(ctx, newSQLCTestUser(t, "Malice", "malice@example.com")))
+
+	// A full address is matched exactly through the blind index
+	email := "Alice@Example.com"
+	users, total, err := repo.List(ctx, domain.UserListParams{Email: &email, Page: 1, PageSize: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	if assert.Len(t, users, 1) {
+		assert.Equal(t, "alice@example.com", users[0].Email().String())
+	}
+
+	// Substring filters decrypt at most maxDecryptedFilterRows users
+	_, err = sqldb.Exec(`WITH RECURSIVE seq(n) AS (SELECT 1 UNION ALL SELECT n + 1 FROM seq WHERE n < ?)
+		INSERT INTO users (tenant_id, name, email, email_index, password, status)
+		SELECT 'default', 'filler', 'filler', printf('filler-%d', n), 'x', 'active' FROM seq`, maxDecryptedFilterRows)
+	require.NoError(t, err)
+	name := "alice"
+	_, _, err = repo.List(ctx, domain.UserListParams{Name: &name, Page: 1, PageSize: 10})
+	var bizErr *errors.Error
+	require.True(t, errors.As(err, &bizErr))
+	assert.Equal(t, errors.ErrCodeInvalidParam, bizErr.Code)
+
+	users, total, err = repo.List(ctx, domain.UserListParams{Email: &email, Name: &name, Page: 1, PageSize: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Len(t, users, 1)
+}
+
+// staleExistsDB answers every email existence check with "not taken", as a registration
+// racing a concurrent one would see it
+type staleExistsDB struct {
+	*sql.DB
+}
+
+func (d staleExistsDB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	if strings.Contains(query, "SELECT EXISTS") {
+		return d.DB.QueryRowContext(ctx, "SELECT 0")
+	}
+	return d.DB.QueryRowContext(ctx, query, args...)
+}
+
+func TestUserRepositorySQLC_CreateRace(t *testing.T) {
+	log := logger.New("test", "error", false)
+	sqldb := openSQLite(t)
+	repo := NewUserRepositorySQLC(staleExistsDB{sqldb}, encryption.NewPlaintextCipher(), nil, log)
+	ctx := contextx.WithTenantID(context.Background(), "default")
+
+	require.NoError(t, repo.Create(ctx, newSQLCTestUser(t, "Alice", "alice@example.com")))
+
+	// The check passes, the unique key rejects the insert
+	err := repo.Create(ctx, newSQLCTestUser(t, "Alice Again", "alice@example.com"))
+	assert.ErrorIs(t, err, errors.ErrUserAlreadyExists)
+}
+
+func TestUserRepositorySQLC_UnknownTenant(t *testing.T) {
+	log := logger.New("test", "error", false)
+	sqldb := openSQLite(t)
+	repo := NewUserRepositorySQLC(sqldb, encryption.NewPlaintextCipher(), nil, log)
+
+	// The tenant resolver normally rejects unknown tenants; the foreign key is the last line of defence
+	err := repo.Create(contextx.WithTenantID(context.Background(), "initech"), newSQLCTestUser(t, "Alice", "alice@example.com"))
+	assert.ErrorIs(t, err, errors.ErrTenantNotFound)
+
+	var count int
+	require.NoError(t, sqldb.QueryRow(`SELECT COUNT(*) FROM users`).Scan(&count))
+	assert.Zero(t, count)
+}
+
+func TestUserRekeyer_Rekey(t *testing.T) {
+	log := logger.New("test", "error", false)
+	sqldb := openSQLite(t)
+	ctx := contextx.WithTenantID(context.Background(), "default")
+
+	// One user written before encryption was enabled, one with the retired key k1
+	plain := NewUserRepositorySQLC(sqldb, encryption.NewPlaintextCipher(), nil, log)
+	require.NoError(t, plain.Create(ctx, newSQLCTestUser(t, "Alice", "alice@example.com")))
+	old := NewUserRepositorySQLC(sqldb, testKeyring(t, "k1"), nil, log)
+	require.NoError(t, old.Create(ctx, newSQLCTestUser(t, "Bob", "bob@example.com")))
+
+	cipher := testKeyring(t, "k2")
+	rekeyer := NewUserRekeyer(sqldb, cipher, log)
+
+	// Until rekey runs, the plaintext row is found and its email taken through the unkeyed index
+	repo := NewUserRepositorySQLC(sqldb, cipher, nil, log)
+	alice, err := repo.GetByEmail(ctx, "alice@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "Alice", alice.Name().String())
+	exists, err := repo.ExistsByEmail(ctx, "Alice@example.com")
+	require.NoError(t, err)
+	assert.True(t, exists)
+	assert.ErrorIs(t, repo.Create(ctx, newSQLCTestUser(t, "Alice Again", "alice@example.com")), errors.ErrUserAlreadyExists)
+	email := "alice@example.com"
+	users, _, err := repo.List(ctx, domain.UserListParams{Email: &email, Page: 1, PageSize: 10})
+	require.NoError(t, err)
+	assert.Len(t, users, 1)
+
+	result, err := rekeyer.Rekey(ctx, 1, true)
+	require.NoError(t, err)
+	assert.Equal(t, &UserRekeyResult{Scanned: 2, Rekeyed: 2}, result)
+
+	result, err = rekeyer.Rekey(ctx, 1, false)
+	require.NoError(t, err)
+	assert.Equal(t, &UserRekeyResult{Scanned: 2, Rekeyed: 2}, result)
+
+	rows, err := sqldb.Query(`SELECT name, email FROM users`)
+	require.NoError(t, err)
+	defer rows.Close()
+	for rows.Next() {
+		var name, email string
+		require.NoError(t, rows.Scan(&name, &email))
+		assert.True(t, strings.HasPrefix(name, "enc:v2:k2:"))
+		assert.True(t, strings.HasPrefix(email, "enc:v2:k2:"))
+	}
+	require.NoError(t, rows.Err())
+
+	// Both users are readable and found by email with the new keyring
+	alice, err = repo.GetByEmail(ctx, "alice@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "Alice", alice.Name().String())
+	bob, err := repo.GetByEmail(ctx, "bob@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "Bob", bob.Name().String())
+
+	// A second run has nothing to do
+	result, err = rekeyer.Rekey(ctx, 10, false)
+	require.NoError(t, err)
+	assert.Equal(t, &UserRekeyResult{Scanned: 2}, result)
+
+	_, err = NewUserRekeyer(sqldb, encryption.NewPlaintextCipher(), log).Rekey(ctx, 10, false)
+	assert.Error(t, err)
+}
+
+func newSQLCTestUser(t *testing.T, name, email string) *domain.User {
+	t.Helper()
+	nameVO, err := domain.NewName(name)
+	require.NoError(t, err)
+	emailVO, err := domain.NewEmail(email)
+	require.NoError(t, err)
+	password, err := domain.NewHashedPassword("hashed")
+	require.NoError(t, err)
+	user, err := domain.NewUser(0, *nameVO, *emailVO, *password, domain.StatusActive, time.Time{}, time.Time{})
+	require.NoError(t, err)
+	return user
+}
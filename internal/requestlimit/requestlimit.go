@@ -0,0 +1,113 @@
+// Package requestlimit resolves the processing deadline and the maximum body size of a request.
+// Rules match HTTP route templates or gRPC methods the same way rate limit rules do; the first
+// matching rule applies and settings it leaves at zero use the defaults.
+package requestlimit
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"example.com/classic/internal/config"
+)
+
+// Limits applied to a request; zero means unlimited
+type Limits struct {
+	Timeout      time.Duration
+	MaxBodyBytes int64
+}
+
+// rule a configured rule with its parsed matchers
+type rule struct {
+	name    string
+	limits  Limits
+	routes  []routeMatcher
+	methods []string
+}
+
+type routeMatcher struct {
+	method string // empty matches every method
+	route  string
+}
+
+// Policy the configured defaults and rules
+type Policy struct {
+	defaults Limits
+	rules    []rule
+}
+
+// NewPolicy creates a policy from the request limits config.
+// It returns nil when no default and no rule is configured.
+func NewPolicy(cfg config.RequestLimitsConfig) (*Policy, error) {
+	if cfg.Timeout < 0 || cfg.MaxBodyBytes < 0 {
+		return nil, fmt.Errorf("request limits: timeout and max_body_bytes must not be negative")
+	}
+	if cfg.Timeout == 0 && cfg.MaxBodyBytes == 0 && len(cfg.Rules) == 0 {
+		return nil, nil
+	}
+
+	p := &Policy{defaults: Limits{Timeout: cfg.Timeout, MaxBodyBytes: cfg.MaxBodyBytes}}
+	for i, rc := range cfg.Rules {
+		r, err := parseRule(i, rc, p.defaults)
+		if err != nil {
+			return nil, err
+		}
+		p.rules = append(p.rules, r)
+	}
+	return p, nil
+}
+
+func parseRule(index int, rc config.RequestLimitRule, defaults Limits) (rule, error) {
+	name := rc.Name
+	if name == "" {
+		name = "rule" + strconv.Itoa(index)
+	}
+	if rc.Timeout < 0 || rc.MaxBodyBytes < 0 {
+		return rule{}, fmt.Errorf("request limit rule %q: timeout and max_body_bytes must not be negative", name)
+	}
+	if len(rc.Routes) == 0 && len(rc.Methods) == 0 {
+		return rule{}, fmt.Errorf("request limit rule %q: no routes or methods", name)
+	}
+
+	r := rule{name: name, limits: defaults, methods: rc.Methods}
+	if rc.Timeout > 0 {
+		r.limits.Timeout = rc.Timeout
+	}
+	if rc.MaxBodyBytes > 0 {
+		r.limits.MaxBodyBytes = rc.MaxBodyBytes
+	}
+	for _, route := range rc.Routes {
+		method, path, ok := strings.Cut(strings.TrimSpace(route), " ")
+		if !ok {
+			method, path = "", method
+		}
+		r.routes = append(r.routes, routeMatcher{method: strings.ToUpper(method), route: strings.TrimSpace(path)})
+	}
+	return r, nil
+}
+
+// ForRoute returns the limits of an HTTP request; route is the route template (c.FullPath())
+func (p *Policy) ForRoute(method, route string) Limits {
+	for _, r := range p.rules {
+		for _, m := range r.routes {
+			if (m.route == "*" || m.route == route) && (m.method == "" || m.method == method) {
+				return r.limits
+			}
+		}
+	}
+	return p.defaults
+}
+
+// ForMethod returns the limits of a gRPC call
+func (p *Policy) ForMethod(fullMethod string) Limits {
+	for _, r := range p.rules {
+		for _, method := range r.methods {
+			if method == "*" || method == fullMethod ||
+				(strings.HasSuffix(method, "/*") && strings.HasPrefix(fullMethod, strings.TrimSuffix(method, "*"))) {
+				return r.limits
+			}
+		}
+	}
+	return p.defaults
+}
@@ -0,0 +1,46 @@
+package requestlimit
+
+import (
+	"testing"
+	"time"
+
+	"example.com/classic/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicy_Match(t *testing.T) {
+	p, err := NewPolicy(config.RequestLimitsConfig{
+		Timeout:      10 * time.Second,
+		MaxBodyBytes: 1 << 20,
+		Rules: []config.RequestLimitRule{
+			{Name: "bulk", Routes: []string{"/api/v1/users:action"}, Methods: []string{"/user.UserService/ImportUsers"}, Timeout: 5 * time.Minute, MaxBodyBytes: 64 << 20},
+			{Name: "register", Routes: []string{"POST /api/v1/users"}, Timeout: 3 * time.Second},
+			{Name: "admin", Methods: []string{"/user.AdminService/*"}, MaxBodyBytes: 1024},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, Limits{Timeout: 5 * time.Minute, MaxBodyBytes: 64 << 20}, p.ForRoute("POST", "/api/v1/users:action"))
+	assert.Equal(t, Limits{Timeout: 5 * time.Minute, MaxBodyBytes: 64 << 20}, p.ForRoute("GET", "/api/v1/users:action"))
+	// Settings a rule leaves at zero use the defaults
+	assert.Equal(t, Limits{Timeout: 3 * time.Second, MaxBodyBytes: 1 << 20}, p.ForRoute("POST", "/api/v1/users"))
+	assert.Equal(t, Limits{Timeout: 10 * time.Second, MaxBodyBytes: 1 << 20}, p.ForRoute("GET", "/api/v1/users"))
+
+	assert.Equal(t, Limits{Timeout: 5 * time.Minute, MaxBodyBytes: 64 << 20}, p.ForMethod("/user.UserService/ImportUsers"))
+	assert.Equal(t, Limits{Timeout: 10 * time.Second, MaxBodyBytes: 1024}, p.ForMethod("/user.AdminService/Reset"))
+	assert.Equal(t, Limits{Timeout: 10 * time.Second, MaxBodyBytes: 1 << 20}, p.ForMethod("/user.UserService/GetByID"))
+}
+
+func TestNewPolicy_Invalid(t *testing.T) {
+	p, err := NewPolicy(config.RequestLimitsConfig{})
+	require.NoError(t, err)
+	assert.Nil(t, p)
+
+	_, err = NewPolicy(config.RequestLimitsConfig{Timeout: -time.Second})
+	assert.Error(t, err)
+	_, err = NewPolicy(config.RequestLimitsConfig{Rules: []config.RequestLimitRule{{Name: "empty", Timeout: time.Second}}})
+	assert.Error(t, err)
+	_, err = NewPolicy(config.RequestLimitsConfig{Rules: []config.RequestLimitRule{{Routes: []string{"*"}, MaxBodyBytes: -1}}})
+	assert.Error(t, err)
+}
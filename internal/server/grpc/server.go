@@ -4,23 +4,46 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"net/http"
+	"strings"
 	"time"
 
 	"example.com/classic/api/grpc/pb"
 	"example.com/classic/internal/config"
+	"example.com/classic/internal/idempotency"
+	"example.com/classic/internal/ratelimit"
+	"example.com/classic/internal/requestlimit"
+	"example.com/classic/internal/tenancy"
+	"example.com/classic/internal/tlsconfig"
 	"example.com/classic/pkg/contextx"
+	"example.com/classic/pkg/errors"
+	"example.com/classic/pkg/i18n"
 	"example.com/classic/pkg/logger"
+	"example.com/classic/pkg/metrics"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
 )
 
 // Server gRPC server
 type Server struct {
-	cfg       *config.Config
-	log       logger.Logger
-	grpcSrv   *grpc.Server
-	userSvc   pb.UserServiceServer
+	cfg         *config.Config
+	log         logger.Logger
+	grpcSrv     *grpc.Server
+	userSvc     pb.UserServiceServer
+	resolver    *tenancy.Resolver
+	metrics     *metrics.Metrics
+	limiter     *ratelimit.Limiter
+	idempotency *idempotency.Store
+	limits      *requestlimit.Policy
+	certs       *tlsconfig.Reloader
 }
 
 // NewServer creates a new gRPC server
@@ -28,38 +51,90 @@ func NewServer(
 	cfg *config.Config,
 	log logger.Logger,
 	userSvc pb.UserServiceServer,
+	resolver *tenancy.Resolver,
+	m *metrics.Metrics,
+	limiter *ratelimit.Limiter,
+	idempotencyStore *idempotency.Store,
+	limits *requestlimit.Policy,
+	certs *tlsconfig.Reloader,
 ) *Server {
-	return &Server{
-		cfg:     cfg,
-		log:     log,
-		userSvc: userSvc,
+	s := &Server{
+		cfg:         cfg,
+		log:         logger.Component(log, "grpc"),
+		userSvc:     userSvc,
+		resolver:    resolver,
+		metrics:     m,
+		limiter:     limiter,
+		idempotency: idempotencyStore,
+		limits:      limits,
+		certs:       certs,
 	}
+	s.grpcSrv = s.newGRPCServer()
+	return s
 }
 
-// Start starts the gRPC server
-func (s *Server) Start(ctx context.Context) error {
-	addr := fmt.Sprintf(":%d", s.cfg.GRPC.Port)
-	lis, err := net.Listen("tcp", addr)
-	if err != nil {
-		return fmt.Errorf("listen grpc: %w", err)
+// newGRPCServer creates the gRPC server with interceptors (metrics outermost, so tenant failures are counted,
+// then the conversion of business errors, so calls are counted with their gRPC status code)
+func (s *Server) newGRPCServer() *grpc.Server {
+	unary := []grpc.UnaryServerInterceptor{s.unaryInterceptor}
+	stream := []grpc.StreamServerInterceptor{s.streamInterceptor}
+	if s.idempotency != nil {
+		// After the tracing interceptor, which resolves the tenant that keys are scoped by
+		unary = append(unary, s.idempotencyUnaryInterceptor)
+	}
+	if s.limits != nil {
+		unary = append([]grpc.UnaryServerInterceptor{s.requestLimitUnaryInterceptor}, unary...)
+		stream = append([]grpc.StreamServerInterceptor{s.requestLimitStreamInterceptor}, stream...)
+	}
+	if s.limiter != nil {
+		unary = append([]grpc.UnaryServerInterceptor{s.rateLimitUnaryInterceptor}, unary...)
+		stream = append([]grpc.StreamServerInterceptor{s.rateLimitStreamInterceptor}, stream...)
+	}
+	unary = append([]grpc.UnaryServerInterceptor{s.errorStatusUnaryInterceptor}, unary...)
+	stream = append([]grpc.StreamServerInterceptor{s.errorStatusStreamInterceptor}, stream...)
+	if s.metrics != nil {
+		unary = append([]grpc.UnaryServerInterceptor{s.metricsUnaryInterceptor}, unary...)
+		stream = append([]grpc.StreamServerInterceptor{s.metricsStreamInterceptor}, stream...)
 	}
-
-	// Create gRPC server with interceptors
 	opts := []grpc.ServerOption{
-		grpc.UnaryInterceptor(s.unaryInterceptor),
-		grpc.StreamInterceptor(s.streamInterceptor),
+		grpc.ChainUnaryInterceptor(unary...),
+		grpc.ChainStreamInterceptor(stream...),
+	}
+	// 共用 HTTP 端口时由 HTTP 服务器终止 TLS
+	if s.certs != nil && !s.SharedPort() {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(s.certs.ServerConfig("h2"))))
 	}
-	s.grpcSrv = grpc.NewServer(opts...)
+	srv := grpc.NewServer(opts...)
 
 	// Register services
-	pb.RegisterUserServiceServer(s.grpcSrv, s.userSvc)
+	pb.RegisterUserServiceServer(srv, s.userSvc)
 
 	// Enable reflection for development
 	if s.cfg.IsDevelopment() {
-		reflection.Register(s.grpcSrv)
+		reflection.Register(srv)
 	}
+	return srv
+}
 
-	s.log.Info(ctx, "gRPC server starting", logger.F("addr", addr))
+// SharedPort reports whether gRPC is served by the HTTP listener (grpc.shared_port)
+func (s *Server) SharedPort() bool {
+	return s.cfg.GRPC.SharedPort
+}
+
+// Start starts the gRPC server; with a shared port there is no listener, calls arrive through ServeHTTP
+func (s *Server) Start(ctx context.Context) error {
+	if s.SharedPort() {
+		s.log.Info(ctx, "gRPC server sharing the HTTP port", logger.F("addr", s.cfg.HTTP.Address))
+		return nil
+	}
+
+	addr := fmt.Sprintf(":%d", s.cfg.GRPC.Port)
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("listen grpc: %w", err)
+	}
+
+	s.log.Info(ctx, "gRPC server starting", logger.F("addr", addr), logger.F("tls", s.certs != nil))
 
 	go func() {
 		if err := s.grpcSrv.Serve(lis); err != nil {
@@ -70,12 +145,28 @@ func (s *Server) Start(ctx context.Context) error {
 	return nil
 }
 
+// ServeHTTP serves a gRPC request received by the HTTP server (shared port).
+// The HTTP read/write timeouts are lifted: gRPC calls are bounded by request_limits instead.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rc := http.NewResponseController(w)
+	_ = rc.SetReadDeadline(time.Time{})
+	_ = rc.SetWriteDeadline(time.Time{})
+	s.grpcSrv.ServeHTTP(w, r)
+}
+
 // Stop stops the gRPC server
 func (s *Server) Stop(ctx context.Context) error {
-	if s.grpcSrv != nil {
-		s.log.Info(ctx, "stopping gRPC server")
-		s.grpcSrv.GracefulStop()
+	if s.grpcSrv == nil {
+		return nil
+	}
+	s.log.Info(ctx, "stopping gRPC server")
+	if s.SharedPort() {
+		// The HTTP server shutdown has already drained the calls it routed here;
+		// GracefulStop does not support ServeHTTP transports, so close whatever outlived the deadline
+		s.grpcSrv.Stop()
+		return nil
 	}
+	s.grpcSrv.GracefulStop()
 	return nil
 }
 
@@ -90,6 +181,7 @@ func (s *Server) unaryInterceptor(
 
 	// 从 metadata 提取追踪信息
 	ctx = s.extractTraceContext(ctx)
+	ctx = s.negotiateLocale(ctx)
 
 	// 设置操作名称
 	ctx = contextx.WithOperationName(ctx, info.FullMethod)
@@ -98,6 +190,15 @@ func (s *Server) unaryInterceptor(
 	// 创建子 span
 	ctx = contextx.ChildSpan(ctx)
 
+	// 解析租户
+	ctx, err := s.resolveTenant(ctx)
+	if err != nil {
+		s.log.Warn(ctx, "resolve tenant failed",
+			logger.String("method", info.FullMethod),
+			logger.Err(err))
+		return nil, err
+	}
+
 	s.log.Debug(ctx, "gRPC request started",
 		logger.String("method", info.FullMethod))
 
@@ -129,10 +230,20 @@ func (s *Server) streamInterceptor(
 
 	// 从 metadata 提取追踪信息
 	ctx := s.extractTraceContext(ss.Context())
+	ctx = s.negotiateLocale(ctx)
 	ctx = contextx.WithOperationName(ctx, info.FullMethod)
 	ctx = contextx.WithServiceName(ctx, s.cfg.Service)
 	ctx = contextx.ChildSpan(ctx)
 
+	// 解析租户
+	ctx, err := s.resolveTenant(ctx)
+	if err != nil {
+		s.log.Warn(ctx, "resolve tenant failed",
+			logger.String("method", info.FullMethod),
+			logger.Err(err))
+		return err
+	}
+
 	// 包装 ServerStream 以传递新的 context
 	wrapped := &wrappedServerStream{
 		ServerStream: ss,
@@ -142,7 +253,7 @@ func (s *Server) streamInterceptor(
 	s.log.Debug(ctx, "gRPC stream started",
 		logger.String("method", info.FullMethod))
 
-	err := handler(srv, wrapped)
+	err = handler(srv, wrapped)
 
 	latency := time.Since(start)
 	if err != nil {
@@ -159,8 +270,308 @@ func (s *Server) streamInterceptor(
 	return err
 }
 
+// metricsUnaryInterceptor records calls per method and status code
+func (s *Server) metricsUnaryInterceptor(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	s.metrics.ObserveGRPC(info.FullMethod, status.Code(err).String(), time.Since(start))
+	return resp, err
+}
+
+// metricsStreamInterceptor records streams per method and status code
+func (s *Server) metricsStreamInterceptor(
+	srv interface{},
+	ss grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	start := time.Now()
+	err := handler(srv, ss)
+	s.metrics.ObserveGRPC(info.FullMethod, status.Code(err).String(), time.Since(start))
+	return err
+}
+
+// errorStatusUnaryInterceptor converts business errors returned by the handlers to gRPC status
+func (s *Server) errorStatusUnaryInterceptor(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	resp, err := handler(ctx, req)
+	if err != nil {
+		err = errorStatus(s.negotiateLocale(ctx), err)
+	}
+	return resp, err
+}
+
+// errorStatusStreamInterceptor converts business errors returned by the stream handlers to gRPC status
+func (s *Server) errorStatusStreamInterceptor(
+	srv interface{},
+	ss grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	err := handler(srv, ss)
+	if err != nil {
+		err = errorStatus(s.negotiateLocale(ss.Context()), err)
+	}
+	return err
+}
+
+// rateLimitUnaryInterceptor rejects calls over the configured limits with ResourceExhausted
+func (s *Server) rateLimitUnaryInterceptor(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	result, matched := s.limiter.Allow(ctx, s.rateLimitRequest(ctx, info.FullMethod))
+	if matched {
+		_ = grpc.SetHeader(ctx, rateLimitMetadata(result))
+		if !result.Allowed {
+			return nil, status.Error(codes.ResourceExhausted, errors.ErrTooManyRequest.Message)
+		}
+	}
+	return handler(ctx, req)
+}
+
+// rateLimitStreamInterceptor rejects streams over the configured limits with ResourceExhausted
+func (s *Server) rateLimitStreamInterceptor(
+	srv interface{},
+	ss grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	result, matched := s.limiter.Allow(ss.Context(), s.rateLimitRequest(ss.Context(), info.FullMethod))
+	if matched {
+		_ = ss.SetHeader(rateLimitMetadata(result))
+		if !result.Allowed {
+			return status.Error(codes.ResourceExhausted, errors.ErrTooManyRequest.Message)
+		}
+	}
+	return handler(srv, ss)
+}
+
+// requestLimitUnaryInterceptor caps the deadline of the call and rejects request messages over the size limit
+func (s *Server) requestLimitUnaryInterceptor(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	limits := s.limits.ForMethod(info.FullMethod)
+	if msg, ok := req.(proto.Message); ok && limits.MaxBodyBytes > 0 && int64(proto.Size(msg)) > limits.MaxBodyBytes {
+		return nil, status.Error(codes.ResourceExhausted, errors.ErrRequestTooLarge.Message)
+	}
+	if limits.Timeout <= 0 {
+		return handler(ctx, req)
+	}
+
+	// A shorter deadline sent by the client is kept
+	ctx, cancel := context.WithTimeout(ctx, limits.Timeout)
+	defer cancel()
+	resp, err := handler(ctx, req)
+	return resp, deadlineStatus(ctx, err)
+}
+
+// requestLimitStreamInterceptor caps the deadline of the stream and rejects received messages over the size limit
+func (s *Server) requestLimitStreamInterceptor(
+	srv interface{},
+	ss grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	limits := s.limits.ForMethod(info.FullMethod)
+	ctx := ss.Context()
+	if limits.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, limits.Timeout)
+		defer cancel()
+	}
+
+	err := handler(srv, &limitedServerStream{
+		wrappedServerStream: wrappedServerStream{ServerStream: ss, ctx: ctx},
+		maxMsgBytes:         limits.MaxBodyBytes,
+	})
+	return deadlineStatus(ctx, err)
+}
+
+// deadlineStatus reports a call that failed after its deadline passed as DeadlineExceeded
+func deadlineStatus(ctx context.Context, err error) error {
+	if err == nil || !errors.Is(ctx.Err(), context.DeadlineExceeded) || status.Code(err) == codes.DeadlineExceeded {
+		return err
+	}
+	return status.Error(codes.DeadlineExceeded, errors.ErrRequestTimeout.Message)
+}
+
+// rateLimitRequest builds the rate limit keys from the peer address and metadata
+func (s *Server) rateLimitRequest(ctx context.Context, fullMethod string) ratelimit.Request {
+	req := ratelimit.Request{GRPC: true, Route: fullMethod}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		req.ClientIP = p.Addr.String()
+		if host, _, err := net.SplitHostPort(req.ClientIP); err == nil {
+			req.ClientIP = host
+		}
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		req.Subject = s.resolver.Subject(firstValue(md, "authorization"))
+		req.APIKey = firstValue(md, s.limiter.APIKeyHeader())
+	}
+	return req
+}
+
+// rateLimitMetadata converts the rate limit headers to (lowercase) response metadata
+func rateLimitMetadata(result ratelimit.Result) metadata.MD {
+	md := metadata.MD{}
+	for name, value := range result.Headers() {
+		md.Set(name, value)
+	}
+	return md
+}
+
+// idempotencyUnaryInterceptor runs a call carrying an idempotency key once and replays its
+// result on retries. Reusing a key for a different request fails with InvalidArgument, a retry
+// while the first call is still running with Aborted.
+func (s *Server) idempotencyUnaryInterceptor(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	key := firstValue(md, s.cfg.Idempotency.Header)
+	msg, ok := req.(proto.Message)
+	if key == "" || !ok {
+		return handler(ctx, req)
+	}
+
+	storeKey, err := s.idempotency.Key(contextx.GetTenantID(ctx), s.resolver.Subject(firstValue(md, "authorization")), key)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, idempotency.ErrInvalidKey.Message)
+	}
+	body, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	fingerprint := idempotency.Fingerprint([]byte(info.FullMethod), body)
+
+	token, replay, err := s.idempotency.Begin(ctx, storeKey, fingerprint)
+	switch {
+	case errors.Is(err, idempotency.ErrMismatch):
+		return nil, status.Error(codes.InvalidArgument, idempotency.ErrMismatch.Message)
+	case errors.Is(err, idempotency.ErrInProgress):
+		return nil, status.Error(codes.Aborted, idempotency.ErrInProgress.Message)
+	case err != nil:
+		s.log.Warn(ctx, "idempotency store unavailable, processing call without it", logger.Err(err))
+		return handler(ctx, req)
+	case replay != nil:
+		_ = grpc.SetHeader(ctx, metadata.Pairs("idempotent-replayed", "true"))
+		return replayResponse(info.FullMethod, replay)
+	}
+
+	// Server errors, panics and oversized responses release the key so that the call can be retried
+	completed := false
+	defer func() {
+		if completed {
+			return
+		}
+		if err := s.idempotency.Release(context.WithoutCancel(ctx), storeKey, token); err != nil {
+			s.log.Warn(ctx, "release idempotency key failed", logger.Err(err))
+		}
+	}()
+
+	resp, err := handler(ctx, req)
+
+	var record idempotency.Record
+	switch {
+	case err != nil && !replayableError(err):
+		return resp, err
+	case err != nil:
+		st := status.Convert(err)
+		record = idempotency.Record{Status: int(st.Code()), Body: []byte(st.Message())}
+	default:
+		out, ok := resp.(proto.Message)
+		if !ok {
+			return resp, err
+		}
+		data, marshalErr := proto.Marshal(out)
+		if marshalErr != nil || len(data) > s.idempotency.MaxBodyBytes() {
+			return resp, err
+		}
+		record = idempotency.Record{Status: int(codes.OK), Body: data}
+	}
+
+	completed = true
+	if storeErr := s.idempotency.Complete(context.WithoutCancel(ctx), storeKey, token, fingerprint, record); storeErr != nil {
+		s.log.Warn(ctx, "store idempotent response failed", logger.Err(storeErr))
+	}
+	return resp, err
+}
+
+// replayableError reports whether a failed call is final and its error may be replayed;
+// server-side and transient failures are not stored so that the call can be retried
+func replayableError(err error) bool {
+	if st, ok := status.FromError(err); ok {
+		switch st.Code() {
+		case codes.InvalidArgument, codes.NotFound, codes.AlreadyExists, codes.PermissionDenied,
+			codes.FailedPrecondition, codes.OutOfRange, codes.Unauthenticated:
+			return true
+		}
+		return false
+	}
+	var bizErr *errors.Error
+	return errors.As(err, &bizErr) && bizErr.Code != errors.ErrCodeInternalError && !errors.IsRetryable(err)
+}
+
+// replayResponse rebuilds the stored result of a call; the response type is looked up from
+// the method descriptor in the proto registry
+func replayResponse(fullMethod string, record *idempotency.Record) (interface{}, error) {
+	if code := codes.Code(record.Status); code != codes.OK {
+		return nil, status.Error(code, string(record.Body))
+	}
+
+	service, method, _ := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	desc, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(service))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "find service %s: %v", service, err)
+	}
+	serviceDesc, ok := desc.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, status.Errorf(codes.Internal, "%s is not a service", service)
+	}
+	methodDesc := serviceDesc.Methods().ByName(protoreflect.Name(method))
+	if methodDesc == nil {
+		return nil, status.Errorf(codes.Internal, "find method %s", fullMethod)
+	}
+	msgType, err := protoregistry.GlobalTypes.FindMessageByName(methodDesc.Output().FullName())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "find response type of %s: %v", fullMethod, err)
+	}
+
+	resp := msgType.New().Interface()
+	if err := proto.Unmarshal(record.Body, resp); err != nil {
+		return nil, status.Errorf(codes.Internal, "unmarshal stored response: %v", err)
+	}
+	return resp, nil
+}
+
 // extractTraceContext extracts trace context from gRPC metadata
 func (s *Server) extractTraceContext(ctx context.Context) context.Context {
+	// 双向 TLS 校验过的客户端身份
+	if p, ok := peer.FromContext(ctx); ok {
+		if info, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+			if identity := tlsconfig.PeerIdentity(&info.State); identity != "" {
+				ctx = contextx.WithClientIdentity(ctx, identity)
+			}
+		}
+	}
+
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
 		return contextx.WithTraceID(ctx, contextx.GenerateTraceID())
@@ -188,6 +599,82 @@ func (s *Server) extractTraceContext(ctx context.Context) context.Context {
 	return ctx
 }
 
+// negotiateLocale picks the message locale from accept-language metadata
+func (s *Server) negotiateLocale(ctx context.Context) context.Context {
+	var acceptLanguage string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		acceptLanguage = strings.Join(md.Get("accept-language"), ",")
+	}
+	return contextx.WithLocale(ctx, i18n.Default().Match(acceptLanguage, s.cfg.I18n.DefaultLocale))
+}
+
+// resolveTenant resolves the tenant from metadata (authorization, tenant header, :authority)
+func (s *Server) resolveTenant(ctx context.Context) (context.Context, error) {
+	var src tenancy.Source
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		src.Authorization = firstValue(md, "authorization")
+		src.Header = firstValue(md, s.resolver.HeaderName())
+		src.Host = firstValue(md, ":authority")
+	}
+
+	tenantID, err := s.resolver.Resolve(ctx, src)
+	if err != nil {
+		return ctx, errorStatus(ctx, err)
+	}
+	return contextx.WithTenantID(ctx, tenantID), nil
+}
+
+// errorStatus converts an error to a gRPC status with a localized message; errors that
+// already carry a status are returned unchanged
+func errorStatus(ctx context.Context, err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	var bizErr *errors.Error
+	if !errors.As(err, &bizErr) {
+		if st := status.FromContextError(err); st.Code() != codes.Unknown {
+			return st.Err()
+		}
+		return status.Error(codes.Internal, err.Error())
+	}
+	return status.Error(statusCode(bizErr.Code), i18n.ErrorMessage(ctx, bizErr))
+}
+
+// statusCode maps a business error code to the gRPC status code (see writeError for HTTP)
+func statusCode(code errors.ErrorCode) codes.Code {
+	switch code {
+	case errors.ErrCodeInvalidParam, errors.ErrCodeTenantRequired, errors.ErrCodeInvalidPassword,
+		errors.ErrCodeInvalidEmail, errors.ErrCodeUnsupportedMediaType:
+		return codes.InvalidArgument
+	case errors.ErrCodeNotFound, errors.ErrCodeUserNotFound, errors.ErrCodeTenantNotFound:
+		return codes.NotFound
+	case errors.ErrCodeConflict, errors.ErrCodeUserAlreadyExists, errors.ErrCodeTenantAlreadyExists:
+		return codes.AlreadyExists
+	case errors.ErrCodeUnprocessableEntity:
+		return codes.FailedPrecondition
+	case errors.ErrCodeUnauthorized:
+		return codes.Unauthenticated
+	case errors.ErrCodeForbidden, errors.ErrCodeTenantInactive:
+		return codes.PermissionDenied
+	case errors.ErrCodeTooManyRequest, errors.ErrCodeRequestTooLarge:
+		return codes.ResourceExhausted
+	case errors.ErrCodeServiceUnavailable:
+		return codes.Unavailable
+	case errors.ErrCodeRequestTimeout:
+		return codes.DeadlineExceeded
+	default:
+		return codes.Internal
+	}
+}
+
+// firstValue returns the first metadata value of a key (keys are lowercase in metadata)
+func firstValue(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
 // wrappedServerStream wraps ServerStream to pass context
 type wrappedServerStream struct {
 	grpc.ServerStream
@@ -197,3 +684,19 @@ type wrappedServerStream struct {
 func (w *wrappedServerStream) Context() context.Context {
 	return w.ctx
 }
+
+// limitedServerStream rejects received messages larger than maxMsgBytes
+type limitedServerStream struct {
+	wrappedServerStream
+	maxMsgBytes int64
+}
+
+func (w *limitedServerStream) RecvMsg(m interface{}) error {
+	if err := w.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	if msg, ok := m.(proto.Message); ok && w.maxMsgBytes > 0 && int64(proto.Size(msg)) > w.maxMsgBytes {
+		return status.Error(codes.ResourceExhausted, errors.ErrRequestTooLarge.Message)
+	}
+	return nil
+}
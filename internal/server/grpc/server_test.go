@@ -0,0 +1,131 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"example.com/classic/api/grpc/pb"
+	"example.com/classic/internal/config"
+	"example.com/classic/internal/domain"
+	"example.com/classic/internal/tenancy"
+	"example.com/classic/pkg/errors"
+	"example.com/classic/pkg/logger"
+	"example.com/classic/pkg/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// fakeTenantRepository serves the active default tenant
+type fakeTenantRepository struct {
+	domain.TenantRepository
+}
+
+func (fakeTenantRepository) GetByID(_ context.Context, id string) (*domain.Tenant, error) {
+	if id != "default" {
+		return nil, errors.ErrTenantNotFound
+	}
+	now := time.Now()
+	return domain.RebuildTenant(id, "Default", domain.TenantStatusActive, now, now), nil
+}
+
+// failingUserService returns the business error registered for the requested ID
+type failingUserService struct {
+	pb.UnimplementedUserServiceServer
+	errs map[int32]error
+}
+
+func (f *failingUserService) GetByID(_ context.Context, req *pb.GetByIDRequest) (*pb.UserResponse, error) {
+	if err := f.errs[req.Id]; err != nil {
+		return nil, err
+	}
+	return &pb.UserResponse{Id: req.Id}, nil
+}
+
+func (f *failingUserService) ExportUsers(_ *pb.ListRequest, _ pb.UserService_ExportUsersServer) error {
+	return errors.New(errors.ErrCodeServiceUnavailable, "database unavailable")
+}
+
+func TestServer_ErrorStatusMetrics(t *testing.T) {
+	cfg := &config.Config{
+		Service: "test",
+		Tenancy: config.TenancyConfig{DefaultTenant: "default", Header: "X-Tenant-ID"},
+	}
+	m := metrics.New(metrics.Options{})
+	svc := &failingUserService{errs: map[int32]error{
+		2: errors.ErrUserNotFound,
+		3: errors.ErrTenantNotFound,
+		4: errors.ErrUserAlreadyExists,
+		5: errors.WrapInvalidParam(nil, "name is required"),
+		6: errors.ErrTooManyRequest,
+		7: errors.New(errors.ErrCodeServiceUnavailable, "database unavailable"),
+		8: fmt.Errorf("get user: %w", errors.ErrUserNotFound),
+	}}
+	server := NewServer(cfg, logger.New("test", "error", false), svc,
+		tenancy.NewResolver(cfg, fakeTenantRepository{}), m, nil, nil, nil, nil)
+
+	lis := bufconn.Listen(1 << 20)
+	go func() { _ = server.grpcSrv.Serve(lis) }()
+	t.Cleanup(server.grpcSrv.Stop)
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	client := pb.NewUserServiceClient(conn)
+
+	tests := []struct {
+		id   int32
+		want codes.Code
+	}{
+		{1, codes.OK},
+		{2, codes.NotFound},
+		{3, codes.NotFound},
+		{4, codes.AlreadyExists},
+		{5, codes.InvalidArgument},
+		{6, codes.ResourceExhausted},
+		{7, codes.Unavailable},
+		{8, codes.NotFound},
+	}
+	for _, tt := range tests {
+		_, err := client.GetByID(context.Background(), &pb.GetByIDRequest{Id: tt.id})
+		assert.Equal(t, tt.want, status.Code(err), "id %d", tt.id)
+	}
+
+	// Messages are localized like the HTTP responses
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "accept-language", "zh-CN")
+	_, err = client.GetByID(ctx, &pb.GetByIDRequest{Id: 2})
+	assert.Equal(t, "用户不存在", status.Convert(err).Message())
+
+	stream, err := client.ExportUsers(context.Background(), &pb.ListRequest{})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body, _ := io.ReadAll(rec.Body)
+	for _, want := range []string{
+		`grpc_server_handled_total{code="OK",method="/user.UserService/GetByID"} 1`,
+		`grpc_server_handled_total{code="NotFound",method="/user.UserService/GetByID"} 4`,
+		`grpc_server_handled_total{code="AlreadyExists",method="/user.UserService/GetByID"} 1`,
+		`grpc_server_handled_total{code="InvalidArgument",method="/user.UserService/GetByID"} 1`,
+		`grpc_server_handled_total{code="ResourceExhausted",method="/user.UserService/GetByID"} 1`,
+		`grpc_server_handled_total{code="Unavailable",method="/user.UserService/GetByID"} 1`,
+		`grpc_server_handled_total{code="Unavailable",method="/user.UserService/ExportUsers"} 1`,
+	} {
+		assert.Contains(t, string(body), want)
+	}
+	assert.NotContains(t, string(body), `code="Unknown"`)
+}
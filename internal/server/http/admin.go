@@ -0,0 +1,158 @@
+package http
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"net/http/pprof"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"example.com/classic/pkg/errors"
+	"example.com/classic/pkg/logger"
+	"example.com/classic/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// adminPrefix 运维调试接口的路径前缀
+const adminPrefix = "/admin"
+
+// newAdminEngine 运维调试接口 (admin.enabled)：pprof、运行时日志级别、构建信息与脱敏后的生效配置。
+// 使用独立的 gin 引擎，不经过 API 的限流、请求超时、CORS 等中间件
+func (s *Server) newAdminEngine() *gin.Engine {
+	engine := gin.New()
+	engine.Use(gin.Recovery(), s.tracingMiddleware(), s.accessLogMiddleware())
+	if s.config.Admin.Token != "" {
+		engine.Use(requireToken(s.config.Admin.Token))
+	}
+
+	admin := engine.Group(adminPrefix)
+	{
+		admin.GET("/buildinfo", s.buildInfo)      // 构建信息
+		admin.GET("/config", s.effectiveConfig)   // 生效配置 (已脱敏)
+		admin.GET("/log/level", s.logLevels)      // 日志级别
+		admin.PUT("/log/level", s.changeLogLevel) // 修改日志级别
+
+		// CPU profile 与 trace 按 seconds 参数持续采集，不受 http.write_timeout 限制
+		debugGroup := admin.Group("/debug/pprof", liftWriteDeadline)
+		debugGroup.GET("/", gin.WrapF(pprof.Index))
+		debugGroup.GET("/cmdline", gin.WrapF(pprof.Cmdline))
+		debugGroup.GET("/profile", gin.WrapF(pprof.Profile))
+		debugGroup.GET("/symbol", gin.WrapF(pprof.Symbol))
+		debugGroup.POST("/symbol", gin.WrapF(pprof.Symbol))
+		debugGroup.GET("/trace", gin.WrapF(pprof.Trace))
+		debugGroup.GET("/:profile", func(c *gin.Context) {
+			pprof.Handler(c.Param("profile")).ServeHTTP(c.Writer, c.Request)
+		})
+	}
+	return engine
+}
+
+// withAdmin 在同一端口上分流：/admin 下的请求交给运维接口，其余交给 API
+func withAdmin(admin, api http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == adminPrefix || strings.HasPrefix(r.URL.Path, adminPrefix+"/") {
+			admin.ServeHTTP(w, r)
+			return
+		}
+		api.ServeHTTP(w, r)
+	})
+}
+
+// liftWriteDeadline 取消写超时
+func liftWriteDeadline(c *gin.Context) {
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+	c.Next()
+}
+
+// BuildInfo 构建信息
+type BuildInfo struct {
+	Service      string `json:"service"`
+	Version      string `json:"version"`
+	Environment  string `json:"environment"`
+	GoVersion    string `json:"go_version"`
+	Module       string `json:"module"`
+	Revision     string `json:"revision,omitempty"`      // VCS 提交
+	RevisionTime string `json:"revision_time,omitempty"` // VCS 提交时间
+	Modified     bool   `json:"modified"`                // 构建时工作区有未提交的修改
+}
+
+// buildInfo 返回服务版本与二进制的构建信息
+func (s *Server) buildInfo(c *gin.Context) {
+	info := BuildInfo{
+		Service:     s.config.Service,
+		Version:     s.config.Version,
+		Environment: string(s.config.Environment),
+	}
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		info.GoVersion = bi.GoVersion
+		info.Module = bi.Main.Path
+		for _, setting := range bi.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				info.Revision = setting.Value
+			case "vcs.time":
+				info.RevisionTime = setting.Value
+			case "vcs.modified":
+				info.Modified = setting.Value == "true"
+			}
+		}
+	}
+	response.Success(c, info)
+}
+
+// effectiveConfig 返回生效配置，密码、令牌等敏感项已脱敏
+func (s *Server) effectiveConfig(c *gin.Context) {
+	response.Success(c, s.config.Redacted())
+}
+
+// logLevels 返回全局日志级别与各组件的覆盖
+func (s *Server) logLevels(c *gin.Context) {
+	response.Success(c, logger.GetLevels())
+}
+
+// LogLevelRequest 修改日志级别请求
+type LogLevelRequest struct {
+	// Component 为空时修改全局级别
+	Component string `json:"component"`
+	// Level debug | info | warn | error；修改组件时为空表示取消覆盖
+	Level string `json:"level"`
+}
+
+// changeLogLevel 运行时修改日志级别，立即生效，重启后恢复 log.level
+func (s *Server) changeLogLevel(c *gin.Context) {
+	var req LogLevelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.InvalidParam(c, "invalid request body")
+		return
+	}
+
+	var err error
+	if req.Component == "" {
+		err = logger.SetLevel(req.Level)
+	} else {
+		err = logger.SetComponentLevel(req.Component, req.Level)
+	}
+	if err != nil {
+		response.InvalidParam(c, err.Error())
+		return
+	}
+
+	s.log.Warn(c.Request.Context(), "log level changed",
+		logger.String("log_component", req.Component),
+		logger.String("level", req.Level))
+	response.Success(c, logger.GetLevels())
+}
+
+// requireToken 校验请求头 X-Admin-Token
+func requireToken(expected string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := c.GetHeader("X-Admin-Token")
+		if expected == "" || subtle.ConstantTimeCompare([]byte(token), []byte(expected)) != 1 {
+			response.Forbidden(c, errors.ErrForbidden)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,118 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"example.com/classic/internal/config"
+	"example.com/classic/internal/handler"
+	"example.com/classic/pkg/logger"
+	"example.com/classic/pkg/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminEndpoints(t *testing.T) {
+	cfg := &config.Config{
+		Environment: config.EnvProduction,
+		Service:     "classic-api",
+		Version:     "1.2.3",
+		HTTP:        config.HTTPConfig{ReadTimeout: 10 * time.Second},
+		DB:          config.DBConfig{Driver: "mysql", Password: "db-secret"},
+		Tenancy:     config.TenancyConfig{JWTSecret: "jwt-secret"},
+		Admin:       config.AdminConfig{Enabled: true, Token: "admin-secret"},
+	}
+	server := NewServer(cfg, logger.New("test", "error", false), nil, metrics.New(metrics.Options{}), nil, nil, nil, nil, nil,
+		&handler.UserHandler{}, &handler.UserBatchHandler{}, &handler.UserPrivacyHandler{}, &handler.UserHistoryHandler{},
+		&handler.TenantHandler{}, &handler.ProjectionHandler{}, nil, nil)
+	t.Cleanup(func() {
+		_ = logger.SetComponentLevel("grpc", "")
+		_ = logger.SetLevel("error")
+	})
+
+	serve := func(method, target, body, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		if token != "" {
+			req.Header.Set("X-Admin-Token", token)
+		}
+		w := httptest.NewRecorder()
+		server.server.Handler.ServeHTTP(w, req)
+		return w
+	}
+	decode := func(t *testing.T, w *httptest.ResponseRecorder) map[string]any {
+		t.Helper()
+		var resp struct {
+			Data map[string]any `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp.Data
+	}
+
+	t.Run("token required", func(t *testing.T) {
+		assert.Equal(t, http.StatusForbidden, serve("GET", "/admin/config", "", "").Code)
+		assert.Equal(t, http.StatusForbidden, serve("GET", "/admin/config", "", "wrong").Code)
+	})
+
+	t.Run("api routes unaffected", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, serve("GET", "/openapi.json", "", "").Code)
+	})
+
+	t.Run("effective config is redacted", func(t *testing.T) {
+		w := serve("GET", "/admin/config", "", "admin-secret")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.NotContains(t, w.Body.String(), "db-secret")
+		assert.NotContains(t, w.Body.String(), "jwt-secret")
+		assert.NotContains(t, w.Body.String(), "admin-secret")
+
+		data := decode(t, w)
+		db := data["db"].(map[string]any)
+		assert.Equal(t, config.RedactedValue, db["password"])
+		assert.Equal(t, "mysql", db["driver"])
+		// Unset secrets stay empty, so a missing password is visible
+		assert.Equal(t, "", data["redis"].(map[string]any)["password"])
+		assert.Equal(t, "10s", data["http"].(map[string]any)["read_timeout"])
+	})
+
+	t.Run("build info", func(t *testing.T) {
+		w := serve("GET", "/admin/buildinfo", "", "admin-secret")
+		require.Equal(t, http.StatusOK, w.Code)
+		data := decode(t, w)
+		assert.Equal(t, "classic-api", data["service"])
+		assert.Equal(t, "1.2.3", data["version"])
+		assert.NotEmpty(t, data["go_version"])
+	})
+
+	t.Run("log level", func(t *testing.T) {
+		w := serve("PUT", "/admin/log/level", `{"component":"grpc","level":"debug"}`, "admin-secret")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, map[string]any{"grpc": "debug"}, decode(t, w)["components"])
+
+		w = serve("PUT", "/admin/log/level", `{"level":"warn"}`, "admin-secret")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "warn", decode(t, w)["level"])
+
+		w = serve("GET", "/admin/log/level", "", "admin-secret")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, map[string]any{"level": "warn", "components": map[string]any{"grpc": "debug"}}, decode(t, w))
+
+		w = serve("PUT", "/admin/log/level", `{"level":"verbose"}`, "admin-secret")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("pprof", func(t *testing.T) {
+		w := serve("GET", "/admin/debug/pprof/", "", "admin-secret")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "goroutine")
+
+		w = serve("GET", "/admin/debug/pprof/goroutine?debug=1", "", "admin-secret")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "goroutine profile")
+
+		w = serve("GET", "/admin/debug/pprof/unknown", "", "admin-secret")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
@@ -0,0 +1,231 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"example.com/classic/internal/config"
+	"github.com/gin-gonic/gin"
+)
+
+// 默认的跨域方法与响应头
+var (
+	defaultCORSMethods       = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
+	defaultCORSExposeHeaders = []string{"X-Trace-ID", "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "RateLimit-Policy", "Retry-After", "Idempotent-Replayed", "Content-Language"}
+)
+
+// docsContentSecurityPolicy /docs 页面使用的 CSP (允许内联脚本和同源请求)
+const docsContentSecurityPolicy = "default-src 'self'; script-src 'unsafe-inline'; style-src 'unsafe-inline'; connect-src 'self'; frame-ancestors 'none'"
+
+// corsPolicy 预先计算好响应头的跨域策略
+type corsPolicy struct {
+	path          string
+	anyOrigin     bool
+	origins       []string
+	wildcards     []originWildcard
+	methods       string
+	headers       string
+	reflectHeader bool
+	exposeHeaders string
+	credentials   bool
+	maxAge        string
+}
+
+// originWildcard 通配子域名来源 "https://*.example.com" 拆分后的前后缀
+type originWildcard struct {
+	prefix string
+	suffix string
+}
+
+// newCORSPolicy 编译跨域策略；未配置的方法和请求头使用内置列表
+func newCORSPolicy(path string, cfg config.CORSPolicy, defaultHeaders []string) *corsPolicy {
+	p := &corsPolicy{
+		path:          path,
+		methods:       strings.Join(orDefault(cfg.AllowMethods, defaultCORSMethods), ", "),
+		exposeHeaders: strings.Join(orDefault(cfg.ExposeHeaders, defaultCORSExposeHeaders), ", "),
+		credentials:   cfg.AllowCredentials,
+	}
+	for _, origin := range cfg.AllowOrigins {
+		origin = strings.ToLower(strings.TrimSpace(origin))
+		switch {
+		case origin == "*":
+			p.anyOrigin = true
+		case strings.Contains(origin, "*"):
+			prefix, suffix, _ := strings.Cut(origin, "*")
+			p.wildcards = append(p.wildcards, originWildcard{prefix: prefix, suffix: suffix})
+		case origin != "":
+			p.origins = append(p.origins, origin)
+		}
+	}
+	headers := orDefault(cfg.AllowHeaders, defaultHeaders)
+	if len(headers) == 1 && headers[0] == "*" {
+		p.reflectHeader = true
+	} else {
+		p.headers = strings.Join(headers, ", ")
+	}
+	if cfg.MaxAge > 0 {
+		p.maxAge = strconv.Itoa(int(cfg.MaxAge.Seconds()))
+	}
+	return p
+}
+
+// allowOrigin 返回 Access-Control-Allow-Origin 的值，不允许时为空
+func (p *corsPolicy) allowOrigin(origin string) string {
+	if p.anyOrigin {
+		return "*"
+	}
+	lower := strings.ToLower(origin)
+	for _, allowed := range p.origins {
+		if lower == allowed {
+			return origin
+		}
+	}
+	for _, w := range p.wildcards {
+		// 通配符只匹配子域名部分，不匹配路径和端口
+		if host, ok := strings.CutPrefix(lower, w.prefix); ok && len(host) > len(w.suffix) && strings.HasSuffix(host, w.suffix) &&
+			!strings.ContainsAny(host[:len(host)-len(w.suffix)], "/:") {
+			return origin
+		}
+	}
+	return ""
+}
+
+// varyOrigin 响应是否随 Origin 变化 (除 "*" 外都回显来源)
+func (p *corsPolicy) varyOrigin() bool {
+	return !p.anyOrigin
+}
+
+// corsPolicies 默认策略与按路径前缀覆盖的策略
+type corsPolicies struct {
+	fallback *corsPolicy
+	routes   []*corsPolicy
+}
+
+// match 返回路径前缀最长的路由策略，没有匹配时返回默认策略
+func (ps *corsPolicies) match(path string) *corsPolicy {
+	matched := ps.fallback
+	for _, p := range ps.routes {
+		if strings.HasPrefix(path, p.path) && (matched == ps.fallback || len(p.path) > len(matched.path)) {
+			matched = p
+		}
+	}
+	return matched
+}
+
+// newCORSPolicies 根据 http.cors 配置编译全部跨域策略
+func (s *Server) newCORSPolicies() *corsPolicies {
+	cfg := s.config.HTTP.CORS
+	defaultHeaders := []string{"Origin", "Content-Type", "Content-Length", "Accept-Encoding", "Accept-Language", "X-CSRF-Token",
+		"Authorization", "X-Trace-ID", s.resolver.HeaderName(), s.config.Idempotency.Header}
+
+	policies := &corsPolicies{fallback: newCORSPolicy("", cfg.CORSPolicy, defaultHeaders)}
+	for _, route := range cfg.Routes {
+		policies.routes = append(policies.routes, newCORSPolicy(route.Path, route.CORSPolicy, defaultHeaders))
+	}
+	return policies
+}
+
+// corsMiddleware CORS 中间件：按来源白名单回显 Access-Control-Allow-Origin，处理预检请求
+func (s *Server) corsMiddleware() gin.HandlerFunc {
+	policies := s.newCORSPolicies()
+
+	return func(c *gin.Context) {
+		policy := policies.match(c.Request.URL.Path)
+		header := c.Writer.Header()
+		if policy.varyOrigin() {
+			header.Add("Vary", "Origin")
+		}
+
+		origin := c.GetHeader("Origin")
+		preflight := c.Request.Method == http.MethodOptions
+		allowed := ""
+		if origin != "" {
+			allowed = policy.allowOrigin(origin)
+		}
+		if origin != "" && allowed == "" {
+			// 不在白名单中的来源：预检直接拒绝，普通请求不带 CORS 头 (由浏览器拦截)
+			if preflight && c.GetHeader("Access-Control-Request-Method") != "" {
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+			c.Next()
+			return
+		}
+
+		if allowed != "" {
+			header.Set("Access-Control-Allow-Origin", allowed)
+			if policy.credentials {
+				header.Set("Access-Control-Allow-Credentials", "true")
+			}
+		}
+
+		if preflight {
+			header.Add("Vary", "Access-Control-Request-Method")
+			header.Add("Vary", "Access-Control-Request-Headers")
+			header.Set("Access-Control-Allow-Methods", policy.methods)
+			if policy.reflectHeader {
+				if requested := c.GetHeader("Access-Control-Request-Headers"); requested != "" {
+					header.Set("Access-Control-Allow-Headers", requested)
+				}
+			} else {
+				header.Set("Access-Control-Allow-Headers", policy.headers)
+			}
+			if policy.maxAge != "" {
+				header.Set("Access-Control-Max-Age", policy.maxAge)
+			}
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+
+		if allowed != "" {
+			header.Set("Access-Control-Expose-Headers", policy.exposeHeaders)
+		}
+		c.Next()
+	}
+}
+
+// securityHeadersMiddleware 安全响应头中间件 (HSTS、nosniff、frame options、CSP、Referrer-Policy)
+func (s *Server) securityHeadersMiddleware() gin.HandlerFunc {
+	cfg := s.config.HTTP.SecurityHeaders
+
+	var hsts string
+	if cfg.HSTSMaxAge > 0 {
+		hsts = "max-age=" + strconv.Itoa(int(cfg.HSTSMaxAge.Seconds()))
+		if cfg.HSTSIncludeSubdomains {
+			hsts += "; includeSubDomains"
+		}
+		if cfg.HSTSPreload {
+			hsts += "; preload"
+		}
+	}
+
+	return func(c *gin.Context) {
+		header := c.Writer.Header()
+		// HSTS 只能通过 HTTPS 发送 (RFC 6797)
+		if hsts != "" && (c.Request.TLS != nil || strings.EqualFold(c.GetHeader("X-Forwarded-Proto"), "https")) {
+			header.Set("Strict-Transport-Security", hsts)
+		}
+		if cfg.ContentTypeNosniff {
+			header.Set("X-Content-Type-Options", "nosniff")
+		}
+		if cfg.FrameOptions != "" {
+			header.Set("X-Frame-Options", cfg.FrameOptions)
+		}
+		if cfg.ContentSecurityPolicy != "" {
+			header.Set("Content-Security-Policy", cfg.ContentSecurityPolicy)
+		}
+		if cfg.ReferrerPolicy != "" {
+			header.Set("Referrer-Policy", cfg.ReferrerPolicy)
+		}
+		c.Next()
+	}
+}
+
+// orDefault 配置为空时使用默认值
+func orDefault(values, defaults []string) []string {
+	if len(values) == 0 {
+		return defaults
+	}
+	return values
+}
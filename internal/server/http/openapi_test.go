@@ -0,0 +1,120 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"example.com/classic/api/openapi"
+	"example.com/classic/internal/config"
+	"example.com/classic/internal/handler"
+	"example.com/classic/pkg/logger"
+	"example.com/classic/pkg/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestServer(t *testing.T, env config.Environment) *Server {
+	t.Helper()
+	cfg := &config.Config{
+		Environment: env,
+		HTTP:        config.HTTPConfig{EnableHealth: true, EnableMetrics: true},
+		Metrics:     config.MetricsConfig{Path: "/metrics"},
+	}
+	log := logger.New("test", "error", false)
+	return NewServer(cfg, log, nil, metrics.New(metrics.Options{}), nil, nil, nil, nil, nil,
+		&handler.UserHandler{}, &handler.UserBatchHandler{}, &handler.UserPrivacyHandler{}, &handler.UserHistoryHandler{},
+		&handler.TenantHandler{}, &handler.ProjectionHandler{}, handler.NewEventStreamHandler(nil, time.Second, log), nil)
+}
+
+// routePattern matches the OpenAPI paths a Gin route serves; parameters match any segment text
+func routePattern(path string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString("^")
+	for i, segment := range strings.Split(path, "/") {
+		if i > 0 {
+			b.WriteString("/")
+		}
+		// A parameter may follow a literal prefix, as in the custom methods "/users:action"
+		if idx := strings.IndexAny(segment, ":*"); idx >= 0 {
+			b.WriteString(regexp.QuoteMeta(segment[:idx]))
+			b.WriteString(`[^/]+`)
+			continue
+		}
+		b.WriteString(regexp.QuoteMeta(segment))
+	}
+	b.WriteString("$")
+	return regexp.MustCompile(b.String())
+}
+
+func TestOpenAPI_CoversRoutes(t *testing.T) {
+	server := newTestServer(t, config.EnvDevelopment)
+
+	var spec struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	require.NoError(t, json.Unmarshal(openapi.Spec, &spec))
+
+	// Endpoints serving the spec and metrics are not part of the API
+	undocumented := map[string]bool{"/openapi.json": true, "/docs": true, "/metrics": true}
+
+	served := make(map[string]bool)
+	for _, route := range server.engine.Routes() {
+		if undocumented[route.Path] {
+			continue
+		}
+		pattern := routePattern(route.Path)
+		found := false
+		for path, operations := range spec.Paths {
+			if _, ok := operations[strings.ToLower(route.Method)]; ok && pattern.MatchString(path) {
+				served[route.Method+" "+path] = true
+				found = true
+			}
+		}
+		assert.True(t, found, "route %s %s is missing from the OpenAPI spec; annotate its handler and run go generate ./api/openapi",
+			route.Method, route.Path)
+	}
+
+	for path, operations := range spec.Paths {
+		for method := range operations {
+			op := strings.ToUpper(method) + " " + path
+			assert.True(t, served[op], "OpenAPI operation %s is not served by any route", op)
+		}
+	}
+}
+
+func TestOpenAPI_Endpoints(t *testing.T) {
+	server := newTestServer(t, config.EnvDevelopment)
+
+	w := httptest.NewRecorder()
+	server.engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	var doc map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &doc))
+	assert.Equal(t, "3.1.0", doc["openapi"])
+
+	// With the gateway the user routes are described by the proto
+	gateway := newTestServer(t, config.EnvDevelopment)
+	gateway.config.HTTP.UserAPI = config.UserAPIGateway
+	w = httptest.NewRecorder()
+	gateway.engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, openapi.GatewaySpec, w.Body.Bytes())
+	assert.Contains(t, w.Body.String(), `"UserService.Register"`)
+
+	w = httptest.NewRecorder()
+	server.engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "/openapi.json")
+
+	// The docs page is only served in development
+	production := newTestServer(t, config.EnvProduction)
+	w = httptest.NewRecorder()
+	production.engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
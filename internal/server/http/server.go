@@ -1,27 +1,52 @@
 package http
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
 	"net/http"
+	"os"
+	"strings"
 	"time"
 
+	"example.com/classic/api/openapi"
 	"example.com/classic/internal/config"
 	"example.com/classic/internal/handler"
+	"example.com/classic/internal/idempotency"
+	"example.com/classic/internal/ratelimit"
+	"example.com/classic/internal/requestlimit"
+	"example.com/classic/internal/tenancy"
+	"example.com/classic/internal/tlsconfig"
 	"example.com/classic/pkg/contextx"
+	"example.com/classic/pkg/errors"
+	"example.com/classic/pkg/health"
+	"example.com/classic/pkg/i18n"
 	"example.com/classic/pkg/logger"
+	"example.com/classic/pkg/metrics"
+	"example.com/classic/pkg/response"
 	"github.com/gin-gonic/gin"
 )
 
 // Server HTTP 服务器
 type Server struct {
-	engine *gin.Engine
-	server *http.Server
-	config *config.Config
-	log    logger.Logger
+	engine      *gin.Engine
+	server      *http.Server
+	adminServer *http.Server // admin.address 独立监听；为 nil 时运维接口与 API 共用端口或未启用
+	config      *config.Config
+	resolver    *tenancy.Resolver
+	metrics     *metrics.Metrics
+	health      *health.Registry
+	limiter     *ratelimit.Limiter
+	idempotency *idempotency.Store
+	limits      *requestlimit.Policy
+	certs       *tlsconfig.Reloader
+	log         logger.Logger
 }
 
 // NewServer 创建 HTTP 服务器实例
-func NewServer(cfg *config.Config, log logger.Logger, userHandler *handler.UserHandler) *Server {
+func NewServer(cfg *config.Config, log logger.Logger, resolver *tenancy.Resolver, m *metrics.Metrics, checks *health.Registry, limiter *ratelimit.Limiter, idempotencyStore *idempotency.Store, limits *requestlimit.Policy, certs *tlsconfig.Reloader, userHandler *handler.UserHandler, batchHandler *handler.UserBatchHandler, privacyHandler *handler.UserPrivacyHandler, historyHandler *handler.UserHistoryHandler, tenantHandler *handler.TenantHandler, projectionHandler *handler.ProjectionHandler, eventsHandler *handler.EventStreamHandler, userGateway *handler.UserGateway) *Server {
 	// 设置 Gin 模式
 	if cfg.IsDevelopment() {
 		gin.SetMode(gin.DebugMode)
@@ -30,12 +55,24 @@ func NewServer(cfg *config.Config, log logger.Logger, userHandler *handler.UserH
 	}
 
 	engine := gin.New()
+	// 客户端地址只从可信代理的转发头读取，否则任何客户端都能伪造 X-Forwarded-For 绕过按 IP 限流
+	// 配置已校验；若仍然无效，gin 会保留信任所有代理的默认值，因此回退为不信任任何代理
+	if err := engine.SetTrustedProxies(cfg.HTTP.TrustedProxies); err != nil {
+		_ = engine.SetTrustedProxies(nil)
+	}
 
 	// 创建服务器实例
 	server := &Server{
-		engine: engine,
-		config: cfg,
-		log:    log,
+		engine:      engine,
+		config:      cfg,
+		resolver:    resolver,
+		metrics:     m,
+		health:      checks,
+		limiter:     limiter,
+		idempotency: idempotencyStore,
+		limits:      limits,
+		certs:       certs,
+		log:         logger.Component(log, "http"),
 		server: &http.Server{
 			Addr:           cfg.HTTP.Address,
 			Handler:        engine,
@@ -45,52 +82,185 @@ func NewServer(cfg *config.Config, log logger.Logger, userHandler *handler.UserH
 			MaxHeaderBytes: cfg.HTTP.MaxHeaderBytes,
 		},
 	}
+	if certs != nil {
+		server.server.TLSConfig = certs.ServerConfig("h2", "http/1.1")
+	}
+
+	// 运维调试接口：独立监听，或在同一端口的 /admin 下 (由令牌保护)
+	if cfg.Admin.Enabled {
+		admin := server.newAdminEngine()
+		if cfg.Admin.Address != "" {
+			server.adminServer = &http.Server{
+				Addr:              cfg.Admin.Address,
+				Handler:           admin,
+				ReadHeaderTimeout: cfg.HTTP.ReadTimeout,
+				IdleTimeout:       cfg.HTTP.IdleTimeout,
+			}
+		} else {
+			server.server.Handler = withAdmin(admin, engine)
+		}
+	}
+
+	// 关闭时结束事件推送的长连接，否则优雅关闭要等到超时
+	if eventsHandler != nil {
+		server.server.RegisterOnShutdown(eventsHandler.Close)
+	}
 
 	// 配置中间件和路由
 	server.setupMiddleware()
-	server.setupRoutes(userHandler)
+	server.setupRoutes(userHandler, batchHandler, privacyHandler, historyHandler, tenantHandler, projectionHandler, eventsHandler, userGateway)
 
 	return server
 }
 
 // setupMiddleware 配置中间件
 func (s *Server) setupMiddleware() {
+	// 指标中间件 (在恢复中间件之外，panic 也计为 500)
+	if s.metrics != nil {
+		s.engine.Use(s.metricsMiddleware())
+	}
+
 	// 恢复中间件
 	s.engine.Use(gin.Recovery())
 
-	// 链路追踪中间件 (最先执行)
+	// 链路追踪中间件 (在指标和恢复中间件之后，其余中间件都在 span 内执行)
 	s.engine.Use(s.tracingMiddleware())
 
+	// 语言协商中间件 (错误消息按请求语言本地化)
+	s.engine.Use(s.localeMiddleware())
+
 	// 访问日志中间件
 	s.engine.Use(s.accessLogMiddleware())
 
-	// CORS 中间件
+	// 错误响应格式 (RFC 7807 problem+json)
+	s.engine.Use(response.ProblemDetails(response.ProblemOptions{
+		Enabled:  s.config.HTTP.ProblemDetails,
+		TypeBase: s.config.HTTP.ProblemTypeBase,
+	}))
+
+	// 安全响应头中间件
+	if s.config.HTTP.SecurityHeaders.Enabled {
+		s.engine.Use(s.securityHeadersMiddleware())
+	}
+
+	// CORS 中间件 (来源白名单与按路由的策略见 http.cors)
 	if s.config.HTTP.EnableCORS {
 		s.engine.Use(s.corsMiddleware())
 	}
+
+	// 限流中间件 (在 CORS 之后，预检请求不计数)
+	if s.limiter != nil {
+		s.engine.Use(s.rateLimitMiddleware())
+	}
+
+	// 请求时限与请求体上限 (在幂等键中间件读取请求体之前)
+	if s.limits != nil {
+		s.engine.Use(s.requestLimitMiddleware())
+	}
 }
 
 // setupRoutes 配置路由
-func (s *Server) setupRoutes(userHandler *handler.UserHandler) {
+func (s *Server) setupRoutes(userHandler *handler.UserHandler, batchHandler *handler.UserBatchHandler, privacyHandler *handler.UserPrivacyHandler, historyHandler *handler.UserHistoryHandler, tenantHandler *handler.TenantHandler, projectionHandler *handler.ProjectionHandler, eventsHandler *handler.EventStreamHandler, userGateway *handler.UserGateway) {
 	// 健康检查
-	s.engine.GET("/health", s.healthCheck)
+	if s.config.HTTP.EnableHealth {
+		s.engine.GET("/health", s.healthCheck)
+		s.engine.GET("/livez", s.livez)
+		s.engine.GET("/readyz", s.readyz)
+	}
+
+	// Prometheus 指标
+	if s.config.HTTP.EnableMetrics && s.metrics != nil {
+		s.engine.GET(s.config.Metrics.Path, gin.WrapH(s.metrics.Handler()))
+	}
+
+	// OpenAPI 文档 (由 cmd/openapi 根据 handler 注解生成)，文档页面仅在开发环境提供
+	s.engine.GET("/openapi.json", s.openAPISpec)
+	if s.config.IsDevelopment() {
+		s.engine.GET("/docs", s.apiDocs)
+	}
 
-	// API v1 路由组
-	v1 := s.engine.Group("/api/v1")
+	// 管理接口 (不属于任何租户，由管理令牌保护)
+	admin := s.engine.Group("/api/v1/admin", s.adminMiddleware())
+	{
+		admin.POST("/tenants", tenantHandler.Create)             // 创建租户
+		admin.GET("/tenants", tenantHandler.List)                // 租户列表
+		admin.GET("/tenants/:id", tenantHandler.GetByID)         // 获取租户
+		admin.PUT("/tenants/:id", tenantHandler.Update)          // 更新租户
+		admin.DELETE("/tenants/:id", tenantHandler.Delete)       // 删除租户
+		admin.GET("/tenants/:id/users", tenantHandler.ListUsers) // 租户用户列表
+
+		admin.GET("/projections", projectionHandler.List)                 // 投影列表
+		admin.POST("/projections/:name/replay", projectionHandler.Replay) // 重放事件重建投影
+
+		if s.config.HTTP.EnableHealth {
+			admin.GET("/health", s.healthDetails) // 含错误信息的健康检查结果
+		}
+	}
+
+	// http.user_api=gateway 时，proto 中有 HTTP 注解的接口改由 grpc-gateway 提供，路径不变
+	gateway := func(h gin.HandlerFunc) gin.HandlerFunc {
+		if userGateway == nil {
+			return h
+		}
+		return userGateway.Handle
+	}
+
+	// API v1 路由组 (按租户隔离)
+	v1 := s.engine.Group("/api/v1", s.tenantMiddleware())
+	if s.idempotency != nil {
+		v1.Use(s.idempotencyMiddleware())
+	}
 	{
 		// 用户相关路由
 		users := v1.Group("/users")
 		{
-			users.POST("", userHandler.Register)                 // 用户注册
-			users.GET("", userHandler.List)                      // 用户列表
-			users.GET("/:id", userHandler.GetByID)               // 获取用户
-			users.PUT("/:id", userHandler.Update)                // 更新用户
-			users.DELETE("/:id", userHandler.Delete)             // 删除用户
-			users.PATCH("/:id/status", userHandler.ChangeStatus) // 改变用户状态
+			users.POST("", gateway(userHandler.Register))                 // 用户注册
+			users.GET("", gateway(userHandler.List))                      // 用户列表
+			users.GET("/:id", gateway(userHandler.GetByID))               // 获取用户
+			users.PUT("/:id", gateway(userHandler.Update))                // 更新用户
+			users.DELETE("/:id", gateway(userHandler.Delete))             // 删除用户
+			users.PATCH("/:id/status", gateway(userHandler.ChangeStatus)) // 改变用户状态
+			users.GET("/:id/export", privacyHandler.ExportPersonalData)   // 导出个人数据 (GDPR)
+			users.POST("/:id/erasure", privacyHandler.RequestErasure)     // 擦除个人数据 (GDPR)
+			users.GET("/:id/history", gateway(historyHandler.GetHistory)) // 用户事件历史
+		}
+
+		// 用户集合自定义方法 (/users:import, /users:export, /users:batchDelete ...)
+		v1.POST("/users:action", customMethods(map[string]gin.HandlerFunc{
+			"import":            userHandler.Import,                      // 批量导入
+			"batchChangeStatus": gateway(batchHandler.BatchChangeStatus), // 批量改变状态
+			"batchDelete":       gateway(batchHandler.BatchDelete),       // 批量删除
+		}))
+		v1.GET("/users:action", customMethods(map[string]gin.HandlerFunc{
+			"export": userHandler.Export, // 批量导出
+		}))
+
+		// 异步批量任务进度
+		v1.GET("/batchJobs/:id", gateway(batchHandler.GetJob))
+
+		// 领域事件推送 (SSE)
+		if eventsHandler != nil {
+			v1.GET(eventStreamRoute, eventsHandler.Stream)
 		}
 	}
 }
 
+// eventStreamRoute 领域事件推送的路由 (相对 /api/v1)
+const eventStreamRoute = "/events/stream"
+
+// customMethods 分发 "/collection:method" 形式的自定义方法
+// gin 的路由树同一位置只允许一个参数，因此由该处理器按方法名分发
+func customMethods(methods map[string]gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		action := strings.TrimPrefix(c.Param("action"), ":")
+		if handler, ok := methods[action]; ok {
+			handler(c)
+			return
+		}
+		response.NotFound(c, errors.ErrNotFound)
+	}
+}
+
 // tracingMiddleware 链路追踪中间件 (增强版)
 func (s *Server) tracingMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -116,6 +286,9 @@ func (s *Server) tracingMiddleware() gin.HandlerFunc {
 		ctx = contextx.WithUserAgent(ctx, c.Request.UserAgent())
 		ctx = contextx.WithServiceName(ctx, s.config.Service)
 		ctx = contextx.WithOperationName(ctx, c.Request.Method+" "+c.Request.URL.Path)
+		if identity := tlsconfig.PeerIdentity(c.Request.TLS); identity != "" {
+			ctx = contextx.WithClientIdentity(ctx, identity)
+		}
 
 		// 如果有父 span，设置 parent_span_id
 		if parentSpanID != "" {
@@ -132,6 +305,71 @@ func (s *Server) tracingMiddleware() gin.HandlerFunc {
 	}
 }
 
+// localeMiddleware 按 Accept-Language 协商消息语言并写入请求上下文
+func (s *Server) localeMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		locale := i18n.Default().Match(c.GetHeader("Accept-Language"), s.config.I18n.DefaultLocale)
+		c.Request = c.Request.WithContext(contextx.WithLocale(c.Request.Context(), locale))
+		c.Header("Content-Language", locale)
+		c.Writer.Header().Add("Vary", "Accept-Language")
+		c.Next()
+	}
+}
+
+// tenantMiddleware 租户解析中间件
+// 依次从 JWT、租户请求头、子域名解析租户，写入 contextx
+func (s *Server) tenantMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		tenantID, err := s.resolver.Resolve(ctx, tenancy.Source{
+			Authorization: c.GetHeader("Authorization"),
+			Header:        c.GetHeader(s.resolver.HeaderName()),
+			Host:          c.Request.Host,
+		})
+		if err != nil {
+			s.log.Warn(ctx, "resolve tenant failed", logger.Err(err))
+			abortWithError(c, err)
+			return
+		}
+
+		c.Request = c.Request.WithContext(contextx.WithTenantID(ctx, tenantID))
+		c.Next()
+	}
+}
+
+// adminMiddleware 管理接口鉴权中间件，未配置管理令牌时管理接口关闭
+func (s *Server) adminMiddleware() gin.HandlerFunc {
+	return requireToken(s.config.Tenancy.AdminToken)
+}
+
+// abortWithError 按业务错误码写入响应并终止后续处理
+func abortWithError(c *gin.Context, err error) {
+	defer c.Abort()
+
+	var bizErr *errors.Error
+	if !errors.As(err, &bizErr) {
+		response.InternalServerError(c, errors.WrapInternalError(err, "unknown error"))
+		return
+	}
+	switch bizErr.Code {
+	case errors.ErrCodeInvalidParam, errors.ErrCodeTenantRequired:
+		response.BadRequest(c, bizErr)
+	case errors.ErrCodeUnauthorized:
+		response.Unauthorized(c, bizErr)
+	case errors.ErrCodeForbidden, errors.ErrCodeTenantInactive:
+		response.Forbidden(c, bizErr)
+	case errors.ErrCodeNotFound, errors.ErrCodeTenantNotFound:
+		response.NotFound(c, bizErr)
+	case errors.ErrCodeRequestTooLarge:
+		response.RequestEntityTooLarge(c, bizErr)
+	case errors.ErrCodeRequestTimeout:
+		response.GatewayTimeout(c, bizErr)
+	default:
+		response.InternalServerError(c, bizErr)
+	}
+}
+
 // accessLogMiddleware 访问日志中间件
 func (s *Server) accessLogMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -183,45 +421,401 @@ func (s *Server) accessLogMiddleware() gin.HandlerFunc {
 	}
 }
 
-// corsMiddleware CORS 中间件
-func (s *Server) corsMiddleware() gin.HandlerFunc {
+// metricsMiddleware 按路由模板与状态码记录请求数与延迟
+func (s *Server) metricsMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		// 使用路由模板而非原始路径，避免标签基数随 ID 增长
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		s.metrics.ObserveHTTP(c.Request.Method, route, c.Writer.Status(), time.Since(start))
+	}
+}
+
+// rateLimitMiddleware 按配置的规则限流，超限返回 429
+func (s *Server) rateLimitMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		c.Header("Access-Control-Allow-Origin", "*")
-		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, PATCH, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, X-Trace-ID")
-		c.Header("Access-Control-Expose-Headers", "X-Trace-ID")
+		result, matched := s.limiter.Allow(c.Request.Context(), ratelimit.Request{
+			Method:   c.Request.Method,
+			Route:    c.FullPath(),
+			ClientIP: c.ClientIP(),
+			Subject:  s.resolver.Subject(c.GetHeader("Authorization")),
+			APIKey:   c.GetHeader(s.limiter.APIKeyHeader()),
+		})
+		if !matched {
+			c.Next()
+			return
+		}
 
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(http.StatusNoContent)
+		for name, value := range result.Headers() {
+			c.Header(name, value)
+		}
+		if !result.Allowed {
+			response.TooManyRequests(c, errors.ErrTooManyRequest)
+			c.Abort()
 			return
 		}
+		c.Next()
+	}
+}
+
+// requestLimitMiddleware 按路由设置请求处理时限与请求体上限
+// 时限作为 context deadline 传入 service 与数据库调用；处理器因超时返回而未写出响应时补写 504
+func (s *Server) requestLimitMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limits := s.limits.ForRoute(c.Request.Method, c.FullPath())
+
+		if limits.MaxBodyBytes > 0 && c.Request.Body != nil && c.Request.Body != http.NoBody {
+			// 声明了长度的请求直接拒绝，分块传输的请求在读取超限时由绑定返回 413
+			if c.Request.ContentLength > limits.MaxBodyBytes {
+				abortWithError(c, errors.ErrRequestTooLarge)
+				return
+			}
+			c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limits.MaxBodyBytes)
+		}
+
+		// 事件推送是长连接，不受请求时限约束
+		if limits.Timeout <= 0 || c.FullPath() == "/api/v1"+eventStreamRoute {
+			c.Next()
+			return
+		}
+		ctx, cancel := context.WithTimeout(c.Request.Context(), limits.Timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
 
 		c.Next()
+
+		if !c.Writer.Written() && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			response.GatewayTimeout(c, errors.ErrRequestTimeout)
+		}
 	}
 }
 
-// healthCheck 健康检查
+// idempotencyMiddleware 幂等键中间件
+// 携带幂等键的 POST/PUT/PATCH 请求只执行一次，重试时回放首次的响应；
+// 同一个键用于不同请求返回 422，首次请求仍在处理时返回 409
+func (s *Server) idempotencyMiddleware() gin.HandlerFunc {
+	maxBodyBytes := s.idempotency.MaxBodyBytes()
+
+	return func(c *gin.Context) {
+		key := c.GetHeader(s.config.Idempotency.Header)
+		if key == "" || !idempotentMethod(c.Request.Method) {
+			c.Next()
+			return
+		}
+
+		ctx := c.Request.Context()
+		storeKey, err := s.idempotency.Key(contextx.GetTenantID(ctx), s.resolver.Subject(c.GetHeader("Authorization")), key)
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+
+		// 请求体读取后还原；大小只受路由的 request_limits 约束
+		body, digest, err := readIdempotentBody(c.Request.Body, maxBodyBytes)
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			abortWithError(c, errors.Wrap(err, errors.ErrCodeRequestTooLarge, errors.ErrRequestTooLarge.Message))
+			return
+		}
+		if err != nil {
+			abortWithError(c, errors.WrapInvalidParam(err, "read request body failed"))
+			return
+		}
+		defer body.Close()
+		c.Request.Body = body
+		// 同一键换用不同的响应格式视为不同请求，避免以另一种格式重放
+		contentType := response.Negotiate(c).ContentType()
+		fingerprint := idempotency.Fingerprint([]byte(c.Request.Method), []byte(c.Request.URL.RequestURI()), []byte(contentType), digest)
+
+		token, replay, err := s.idempotency.Begin(ctx, storeKey, fingerprint)
+		switch {
+		case errors.Is(err, idempotency.ErrMismatch):
+			response.UnprocessableEntity(c, idempotency.ErrMismatch)
+			c.Abort()
+			return
+		case errors.Is(err, idempotency.ErrInProgress):
+			response.Conflict(c, idempotency.ErrInProgress)
+			c.Abort()
+			return
+		case err != nil:
+			// Redis 不可用时不阻断请求，只是失去重试保护
+			s.log.Warn(ctx, "idempotency store unavailable, processing request without it", logger.Err(err))
+			c.Next()
+			return
+		case replay != nil:
+			c.Header("Idempotent-Replayed", "true")
+			c.Data(replay.Status, replay.ContentType, replay.Body)
+			c.Abort()
+			return
+		}
+
+		writer := &captureWriter{ResponseWriter: c.Writer, limit: maxBodyBytes}
+		c.Writer = writer
+
+		// 服务端错误、panic 或响应过大时释放键，允许客户端重试
+		completed := false
+		defer func() {
+			if completed {
+				return
+			}
+			if err := s.idempotency.Release(context.WithoutCancel(ctx), storeKey, token); err != nil {
+				s.log.Warn(ctx, "release idempotency key failed", logger.Err(err))
+			}
+		}()
+
+		c.Next()
+
+		status := writer.Status()
+		if status >= http.StatusInternalServerError || writer.overflow {
+			return
+		}
+		completed = true
+		if err := s.idempotency.Complete(context.WithoutCancel(ctx), storeKey, token, fingerprint, idempotency.Record{
+			Status:      status,
+			ContentType: writer.Header().Get("Content-Type"),
+			Body:        writer.body.Bytes(),
+		}); err != nil {
+			s.log.Warn(ctx, "store idempotent response failed", logger.Err(err))
+		}
+	}
+}
+
+// readIdempotentBody 读取幂等请求的请求体，返回还原的请求体与用于指纹的内容。
+// 不超过 memLimit 的请求体保留在内存中，以原文计算指纹；更大的 (如批量导入) 写入临时文件，
+// 以 SHA-256 摘要计算指纹，关闭时删除临时文件
+func readIdempotentBody(r io.Reader, memLimit int) (io.ReadCloser, []byte, error) {
+	head, err := io.ReadAll(io.LimitReader(r, int64(memLimit)+1))
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(head) <= memLimit {
+		return io.NopCloser(bytes.NewReader(head)), head, nil
+	}
+
+	file, err := os.CreateTemp("", "idempotent-body-*")
+	if err != nil {
+		return nil, nil, fmt.Errorf("create request body file: %w", err)
+	}
+	body := &tempFileBody{File: file}
+	digest := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(file, digest), io.MultiReader(bytes.NewReader(head), r)); err != nil {
+		_ = body.Close()
+		return nil, nil, err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		_ = body.Close()
+		return nil, nil, fmt.Errorf("rewind request body file: %w", err)
+	}
+	return body, digest.Sum(nil), nil
+}
+
+// tempFileBody 临时文件中的请求体，关闭时删除文件
+type tempFileBody struct {
+	*os.File
+}
+
+func (b *tempFileBody) Close() error {
+	_ = b.File.Close()
+	if err := os.Remove(b.Name()); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// idempotentMethod 支持幂等键的方法 (GET/DELETE 本身即幂等)
+func idempotentMethod(method string) bool {
+	return method == http.MethodPost || method == http.MethodPut || method == http.MethodPatch
+}
+
+// captureWriter 写出响应的同时保留一份副本，超过上限后不再保留
+type captureWriter struct {
+	gin.ResponseWriter
+	body     bytes.Buffer
+	limit    int
+	overflow bool
+}
+
+func (w *captureWriter) Write(b []byte) (int, error) {
+	w.capture(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *captureWriter) WriteString(s string) (int, error) {
+	w.capture([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *captureWriter) capture(b []byte) {
+	if w.overflow {
+		return
+	}
+	if w.body.Len()+len(b) > w.limit {
+		w.overflow = true
+		w.body.Reset()
+		return
+	}
+	w.body.Write(b)
+}
+
+// healthCheck 健康检查 (兼容旧探针，结果同 /readyz，附带服务信息)
+// @Summary Health check
+// @Description Readiness report with the service name and version, for existing monitors
+// @Tags Health
+// @Produce json
+// @Success 200 {object} health.Summary
+// @Failure 503 {object} health.Summary
+// @Router /health [get]
 func (s *Server) healthCheck(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{
-		"status":    "ok",
+	report := s.health.Readiness(c.Request.Context())
+	c.JSON(probeStatus(report), gin.H{
+		"status":    report.Status,
+		"checks":    report.Summary().Checks,
 		"timestamp": time.Now().Format(time.RFC3339),
 		"service":   s.config.Service,
 		"version":   s.config.Version,
 	})
 }
 
+// livez 存活探针：仅检查进程自身，依赖故障不应导致重启
+// @Summary Liveness probe
+// @Description Checks the process itself; dependency outages never fail it
+// @Tags Health
+// @Produce json
+// @Success 200 {object} health.Summary
+// @Failure 503 {object} health.Summary
+// @Router /livez [get]
+func (s *Server) livez(c *gin.Context) {
+	report := s.health.Liveness(c.Request.Context())
+	c.JSON(probeStatus(report), report.Summary())
+}
+
+// readyz 就绪探针：检查数据库、Redis、任务队列等依赖；优雅关闭期间返回失败
+// @Summary Readiness probe
+// @Description Checks the database, Redis and the task queue; fails while the server is shutting down
+// @Tags Health
+// @Produce json
+// @Success 200 {object} health.Summary
+// @Failure 503 {object} health.Summary
+// @Router /readyz [get]
+func (s *Server) readyz(c *gin.Context) {
+	report := s.health.Readiness(c.Request.Context())
+	c.JSON(probeStatus(report), report.Summary())
+}
+
+// HealthDetails 探针的完整检查结果，含耗时与错误信息
+type HealthDetails struct {
+	Liveness  health.Report `json:"liveness"`
+	Readiness health.Report `json:"readiness"`
+}
+
+// healthDetails 返回含错误信息的检查结果 (公开探针只返回检查名与状态)
+// @Summary Health check details
+// @Description Liveness and readiness reports with the latency and error of each check (admin)
+// @Tags Health
+// @Produce json
+// @Param X-Admin-Token header string true "admin token"
+// @Success 200 {object} response.Response{data=HealthDetails}
+// @Failure 403 {object} response.Response
+// @Router /api/v1/admin/health [get]
+func (s *Server) healthDetails(c *gin.Context) {
+	ctx := c.Request.Context()
+	response.Success(c, HealthDetails{
+		Liveness:  s.health.Liveness(ctx),
+		Readiness: s.health.Readiness(ctx),
+	})
+}
+
+// openAPISpec 返回嵌入的 OpenAPI 文档；http.user_api=gateway 时用户接口按 proto JSON 映射描述
+func (s *Server) openAPISpec(c *gin.Context) {
+	spec := openapi.Spec
+	if s.config.HTTP.UserAPI == config.UserAPIGateway {
+		spec = openapi.GatewaySpec
+	}
+	c.Data(http.StatusOK, "application/json", spec)
+}
+
+// apiDocs 返回渲染 /openapi.json 的文档页面
+func (s *Server) apiDocs(c *gin.Context) {
+	// 文档页面内联脚本和样式，并请求同源的 /openapi.json 与接口
+	if s.config.HTTP.SecurityHeaders.Enabled && s.config.HTTP.SecurityHeaders.ContentSecurityPolicy != "" {
+		c.Header("Content-Security-Policy", docsContentSecurityPolicy)
+	}
+	c.Data(http.StatusOK, "text/html; charset=utf-8", openapi.DocsPage)
+}
+
+// probeStatus 探针通过返回 200，否则返回 503
+func probeStatus(report health.Report) int {
+	if report.Up() {
+		return http.StatusOK
+	}
+	return http.StatusServiceUnavailable
+}
+
 // Start 启动服务器
 func (s *Server) Start() error {
-	s.log.Info(context.Background(), "HTTP server starting", logger.F("address", s.config.HTTP.Address))
+	s.log.Info(context.Background(), "HTTP server starting", logger.F("address", s.config.HTTP.Address), logger.F("tls", s.certs != nil))
+	if s.adminServer != nil {
+		go func() {
+			s.log.Info(context.Background(), "admin server starting", logger.F("address", s.adminServer.Addr))
+			if err := s.adminServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				s.log.Error(context.Background(), "admin server error", logger.Err(err))
+			}
+		}()
+	}
+	if s.certs != nil {
+		// 证书由 TLSConfig 按握手提供
+		return s.server.ListenAndServeTLS("", "")
+	}
 	return s.server.ListenAndServe()
 }
 
 // Stop 停止服务器
+// 先让 /readyz 失败并继续服务 health.shutdown_delay，负载均衡摘除流量后再关闭连接
 func (s *Server) Stop(ctx context.Context) error {
+	s.log.Info(ctx, "HTTP server draining", logger.Duration("delay", s.config.Health.ShutdownDelay))
+	s.health.Shutdown()
+	if s.config.HTTP.EnableHealth && s.config.Health.ShutdownDelay > 0 {
+		select {
+		case <-time.After(s.config.Health.ShutdownDelay):
+		case <-ctx.Done():
+		}
+	}
+
 	s.log.Info(ctx, "HTTP server stopping")
+	if s.adminServer != nil {
+		// 运维接口最后关闭，排空期间仍可诊断
+		defer s.adminServer.Shutdown(ctx)
+	}
 	return s.server.Shutdown(ctx)
 }
 
+// ServeGRPC 在同一端口上提供 gRPC (grpc.shared_port)：HTTP/2 且 Content-Type 为 application/grpc 的请求
+// 交给 grpcHandler，其余交给 gin；明文连接同时接受 HTTP/1.1 与 h2c (prior knowledge)。须在 Start 之前调用。
+// Stop 时 Shutdown 会等待进行中的 gRPC 调用结束
+func (s *Server) ServeGRPC(grpcHandler http.Handler) {
+	s.server.Handler = multiplex(grpcHandler, s.server.Handler)
+	protocols := new(http.Protocols)
+	protocols.SetHTTP1(true)
+	protocols.SetHTTP2(true)
+	protocols.SetUnencryptedHTTP2(true)
+	s.server.Protocols = protocols
+}
+
+// multiplex 按协议与 Content-Type 分流 gRPC 与 HTTP 请求
+func multiplex(grpcHandler, httpHandler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ProtoMajor == 2 && strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc") {
+			grpcHandler.ServeHTTP(w, r)
+			return
+		}
+		httpHandler.ServeHTTP(w, r)
+	})
+}
 
 // GetHTTPServer 获取 HTTP 服务器实例
 func (s *Server) GetHTTPServer() *http.Server {
@@ -0,0 +1,534 @@
+package http
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"example.com/classic/internal/config"
+	"example.com/classic/internal/handler"
+	"example.com/classic/internal/idempotency"
+	"example.com/classic/internal/ratelimit"
+	"example.com/classic/internal/requestlimit"
+	"example.com/classic/internal/tenancy"
+	"example.com/classic/pkg/contextx"
+	"example.com/classic/pkg/errors"
+	"example.com/classic/pkg/health"
+	"example.com/classic/pkg/i18n"
+	"example.com/classic/pkg/logger"
+	"example.com/classic/pkg/metrics"
+	"example.com/classic/pkg/response"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestLocaleMiddleware(t *testing.T) {
+	server := newTestServer(t, config.EnvProduction)
+
+	tests := []struct {
+		acceptLanguage string
+		defaultLocale  string
+		wantLocale     string
+		wantMsg        string
+	}{
+		{"", "", i18n.English, "forbidden"},
+		{"zh-CN,zh;q=0.9", "", i18n.SimplifiedChinese, "无权访问"},
+		{"fr", i18n.SimplifiedChinese, i18n.SimplifiedChinese, "无权访问"},
+	}
+	for _, tt := range tests {
+		server.config.I18n.DefaultLocale = tt.defaultLocale
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/tenants", nil)
+		req.Header.Set("Accept-Language", tt.acceptLanguage)
+		w := httptest.NewRecorder()
+		server.engine.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Equal(t, tt.wantLocale, w.Header().Get("Content-Language"))
+		assert.Contains(t, w.Header().Values("Vary"), "Accept-Language")
+		var body response.Response
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, tt.wantMsg, body.Msg, "Accept-Language %q", tt.acceptLanguage)
+	}
+}
+
+func TestCORSMiddleware(t *testing.T) {
+	server := newTestServer(t, config.EnvProduction)
+	server.config.Idempotency.Header = "Idempotency-Key"
+	server.resolver = tenancy.NewResolver(server.config, nil)
+	server.config.HTTP.CORS = config.CORSConfig{
+		CORSPolicy: config.CORSPolicy{
+			AllowOrigins: []string{"https://app.example.com", "https://*.example.org"},
+			MaxAge:       10 * time.Minute,
+		},
+		Routes: []config.CORSRoute{
+			{Path: "/api/v1/admin", CORSPolicy: config.CORSPolicy{AllowOrigins: []string{"https://admin.example.com"}, AllowCredentials: true}},
+		},
+	}
+	engine := gin.New()
+	engine.Use(server.corsMiddleware())
+	engine.Any("/*path", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	serve := func(method, path, origin string, header map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		for k, v := range header {
+			req.Header.Set(k, v)
+		}
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, req)
+		return w
+	}
+
+	tests := []struct {
+		path    string
+		origin  string
+		allowed bool
+	}{
+		{"/api/v1/users", "https://app.example.com", true},
+		{"/api/v1/users", "https://APP.example.com", true},
+		{"/api/v1/users", "https://a.b.example.org", true},
+		{"/api/v1/users", "https://example.org", false},
+		{"/api/v1/users", "https://evil.com/.example.org", false},
+		{"/api/v1/users", "http://app.example.com", false},
+		{"/api/v1/admin/tenants", "https://admin.example.com", true},
+		{"/api/v1/admin/tenants", "https://app.example.com", false},
+	}
+	for _, tt := range tests {
+		w := serve(http.MethodGet, tt.path, tt.origin, nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Header().Values("Vary"), "Origin")
+		if !tt.allowed {
+			assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"), "%s %s", tt.path, tt.origin)
+			continue
+		}
+		assert.Equal(t, tt.origin, w.Header().Get("Access-Control-Allow-Origin"), "%s %s", tt.path, tt.origin)
+		assert.Contains(t, w.Header().Get("Access-Control-Expose-Headers"), "X-Trace-ID")
+	}
+
+	// Credentials are only allowed by the admin route policy
+	w := serve(http.MethodGet, "/api/v1/admin/tenants", "https://admin.example.com", nil)
+	assert.Equal(t, "true", w.Header().Get("Access-Control-Allow-Credentials"))
+	w = serve(http.MethodGet, "/api/v1/users", "https://app.example.com", nil)
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Credentials"))
+
+	preflight := map[string]string{"Access-Control-Request-Method": http.MethodPost, "Access-Control-Request-Headers": "Content-Type"}
+	w = serve(http.MethodOptions, "/api/v1/users", "https://app.example.com", preflight)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "600", w.Header().Get("Access-Control-Max-Age"))
+	assert.Contains(t, w.Header().Get("Access-Control-Allow-Methods"), http.MethodPatch)
+	assert.Contains(t, w.Header().Get("Access-Control-Allow-Headers"), "Idempotency-Key")
+	assert.Subset(t, w.Header().Values("Vary"), []string{"Origin", "Access-Control-Request-Method", "Access-Control-Request-Headers"})
+
+	w = serve(http.MethodOptions, "/api/v1/users", "https://evil.com", preflight)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// Requests without an Origin are not cross-origin
+	w = serve(http.MethodGet, "/api/v1/users", "", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestCORSMiddleware_AnyOrigin(t *testing.T) {
+	server := newTestServer(t, config.EnvProduction)
+	server.config.Idempotency.Header = "Idempotency-Key"
+	server.resolver = tenancy.NewResolver(server.config, nil)
+	server.config.HTTP.CORS = config.CORSConfig{CORSPolicy: config.CORSPolicy{AllowOrigins: []string{"*"}}}
+	engine := gin.New()
+	engine.Use(server.corsMiddleware())
+	engine.GET("/ping", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+	req.Header.Set("Origin", "https://anywhere.example")
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+	assert.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.NotContains(t, w.Header().Values("Vary"), "Origin")
+
+	// "*" with credentials is rejected by the configuration
+	server.config.HTTP.Address = ":8080"
+	server.config.HTTP.UserAPI = config.UserAPIGin
+	server.config.HTTP.CORS.AllowCredentials = true
+	assert.ErrorContains(t, server.config.Validate(), "allow_credentials")
+}
+
+func TestSecurityHeadersMiddleware(t *testing.T) {
+	server := newTestServer(t, config.EnvProduction)
+	server.config.HTTP.SecurityHeaders = config.SecurityHeadersConfig{
+		Enabled:               true,
+		HSTSMaxAge:            180 * 24 * time.Hour,
+		HSTSIncludeSubdomains: true,
+		ContentTypeNosniff:    true,
+		FrameOptions:          "DENY",
+		ContentSecurityPolicy: "default-src 'none'",
+		ReferrerPolicy:        "no-referrer",
+	}
+	engine := gin.New()
+	engine.Use(server.securityHeadersMiddleware())
+	engine.GET("/ping", func(c *gin.Context) { c.Status(http.StatusOK) })
+	engine.GET("/docs", server.apiDocs)
+
+	req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+	assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+	assert.Equal(t, "DENY", w.Header().Get("X-Frame-Options"))
+	assert.Equal(t, "default-src 'none'", w.Header().Get("Content-Security-Policy"))
+	assert.Equal(t, "no-referrer", w.Header().Get("Referrer-Policy"))
+	// HSTS is only sent over HTTPS
+	assert.Empty(t, w.Header().Get("Strict-Transport-Security"))
+
+	req.Header.Set("X-Forwarded-Proto", "https")
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+	assert.Equal(t, "max-age=15552000; includeSubDomains", w.Header().Get("Strict-Transport-Security"))
+
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs", nil))
+	assert.Equal(t, docsContentSecurityPolicy, w.Header().Get("Content-Security-Policy"))
+}
+
+func TestRequestLimitMiddleware(t *testing.T) {
+	server := newTestServer(t, config.EnvProduction)
+	limits, err := requestlimit.NewPolicy(config.RequestLimitsConfig{
+		Timeout:      20 * time.Millisecond,
+		MaxBodyBytes: 8,
+		Rules: []config.RequestLimitRule{
+			{Routes: []string{"POST /upload"}, MaxBodyBytes: 1024},
+		},
+	})
+	require.NoError(t, err)
+	server.limits = limits
+
+	engine := gin.New()
+	engine.Use(server.requestLimitMiddleware())
+	echo := func(c *gin.Context) {
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.Status(http.StatusBadRequest)
+			return
+		}
+		_, hasDeadline := c.Request.Context().Deadline()
+		c.JSON(http.StatusOK, gin.H{"bytes": len(body), "deadline": hasDeadline})
+	}
+	engine.POST("/echo", echo)
+	engine.POST("/upload", echo)
+	engine.GET("/slow", func(c *gin.Context) {
+		// Handlers that give up when the context is done leave the response to the middleware
+		<-c.Request.Context().Done()
+	})
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/echo", strings.NewReader("1234")))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"bytes": 4, "deadline": true}`, w.Body.String())
+
+	// The declared length is checked before the handler runs
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/echo", strings.NewReader("123456789")))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	var body response.Response
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, int(errors.ErrCodeRequestTooLarge), body.Code)
+
+	// Bodies without a declared length fail while being read
+	req := httptest.NewRequest(http.MethodPost, "/echo", io.NopCloser(strings.NewReader("123456789")))
+	req.ContentLength = -1
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Route rules override the defaults
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/upload", strings.NewReader("123456789")))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/slow", nil))
+	assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, int(errors.ErrCodeRequestTimeout), body.Code)
+	assert.Equal(t, "request timed out", body.Msg)
+}
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	server := newTestServer(t, config.EnvProduction)
+	server.config.Idempotency = config.IdempotencyConfig{
+		Enabled: true, Header: "Idempotency-Key", Prefix: "test:idem", TTL: time.Hour, LockTTL: time.Minute, MaxBodyBytes: 64,
+	}
+	server.idempotency = idempotency.NewStore(server.config.Idempotency, client)
+	server.resolver = tenancy.NewResolver(server.config, nil)
+
+	calls := 0
+	engine := gin.New()
+	engine.Use(server.idempotencyMiddleware())
+	engine.POST("/orders", func(c *gin.Context) {
+		calls++
+		response.Success(c, gin.H{"calls": calls})
+	})
+	send := func(key, accept, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(body))
+		req.Header.Set("Idempotency-Key", key)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, req)
+		return w
+	}
+
+	first := send("k1", "", `{"n":1}`)
+	assert.Equal(t, http.StatusOK, first.Code)
+	replay := send("k1", "application/json", `{"n":1}`)
+	assert.Equal(t, "true", replay.Header().Get("Idempotent-Replayed"))
+	assert.Equal(t, first.Body.String(), replay.Body.String())
+	assert.Equal(t, 1, calls)
+
+	// Another response format is another request: the JSON response is not replayed as msgpack
+	w := send("k1", response.MsgpackContentType, `{"n":1}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(t, 1, calls)
+
+	// Bodies over max_body_bytes are fingerprinted by their digest
+	large := `{"n":"` + strings.Repeat("x", 64) + `"}`
+	assert.Equal(t, http.StatusOK, send("k2", "", large).Code)
+	assert.Equal(t, "true", send("k2", "", large).Header().Get("Idempotent-Replayed"))
+	assert.Equal(t, http.StatusUnprocessableEntity, send("k2", "", large+" ").Code)
+	assert.Equal(t, 2, calls)
+}
+
+func TestIdempotencyMiddleware_BulkRoute(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	server := newTestServer(t, config.EnvProduction)
+	server.config.Idempotency = config.IdempotencyConfig{
+		Enabled: true, Header: "Idempotency-Key", Prefix: "test:idem", TTL: time.Hour, LockTTL: time.Minute, MaxBodyBytes: 1 << 20,
+	}
+	server.idempotency = idempotency.NewStore(server.config.Idempotency, client)
+	server.resolver = tenancy.NewResolver(server.config, nil)
+	limits, err := requestlimit.NewPolicy(config.RequestLimitsConfig{
+		MaxBodyBytes: 1 << 20,
+		Rules: []config.RequestLimitRule{
+			{Name: "bulk", Routes: []string{"/api/v1/users:action"}, MaxBodyBytes: 256 << 20},
+		},
+	})
+	require.NoError(t, err)
+	server.limits = limits
+
+	calls := 0
+	var received int64
+	engine := gin.New()
+	engine.Use(server.requestLimitMiddleware(), server.idempotencyMiddleware())
+	handle := func(c *gin.Context) {
+		calls++
+		received, _ = io.Copy(io.Discard, c.Request.Body)
+		response.Success(c, gin.H{"received": received})
+	}
+	engine.POST("/api/v1/users:action", handle)
+	engine.POST("/api/v1/users", handle)
+	send := func(path, key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Idempotency-Key", key)
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, req)
+		return w
+	}
+
+	// A keyed import above idempotency.max_body_bytes is bounded by the route limit only
+	body := strings.Repeat("name,email\n", (2<<20)/11)
+	first := send("/api/v1/users:import", "bulk-1", body)
+	assert.Equal(t, http.StatusOK, first.Code)
+	assert.Equal(t, int64(len(body)), received)
+	replay := send("/api/v1/users:import", "bulk-1", body)
+	assert.Equal(t, "true", replay.Header().Get("Idempotent-Replayed"))
+	assert.Equal(t, first.Body.String(), replay.Body.String())
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, http.StatusUnprocessableEntity, send("/api/v1/users:import", "bulk-1", body+"x,y\n").Code)
+
+	// Other routes keep the default limit
+	w := send("/api/v1/users", "single-1", body)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	var resp response.Response
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, int(errors.ErrCodeRequestTooLarge), resp.Code)
+	assert.Equal(t, 1, calls)
+}
+
+func TestServeGRPC_SharedPort(t *testing.T) {
+	server := newTestServer(t, config.EnvProduction)
+	server.health = health.NewRegistry(health.Options{})
+
+	grpcSrv := grpc.NewServer()
+	healthpb.RegisterHealthServer(grpcSrv, grpchealth.NewServer())
+	server.ServeGRPC(grpcSrv)
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() { _ = server.server.Serve(lis) }()
+	addr := lis.Addr().String()
+
+	// gRPC over h2c
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	resp, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.Status)
+
+	// HTTP/1.1 and h2c requests still reach gin
+	h2c := new(http.Protocols)
+	h2c.SetUnencryptedHTTP2(true)
+	for proto, client := range map[int]*http.Client{
+		1: {},
+		2: {Transport: &http.Transport{Protocols: h2c}},
+	} {
+		res, err := client.Get("http://" + addr + "/livez")
+		require.NoError(t, err)
+		res.Body.Close()
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, proto, res.ProtoMajor)
+		client.CloseIdleConnections()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, server.Stop(ctx))
+	grpcSrv.Stop()
+}
+
+func TestTracingMiddleware_ClientIdentity(t *testing.T) {
+	server := newTestServer(t, config.EnvProduction)
+
+	engine := gin.New()
+	engine.Use(server.tracingMiddleware())
+	engine.GET("/whoami", func(c *gin.Context) {
+		c.String(http.StatusOK, contextx.GetClientIdentity(c.Request.Context()))
+	})
+
+	spiffeID, _ := url.Parse("spiffe://example.org/ns/default/sa/billing")
+	req := httptest.NewRequest(http.MethodGet, "/whoami", nil)
+	req.TLS = &tls.ConnectionState{
+		VerifiedChains: [][]*x509.Certificate{{{URIs: []*url.URL{spiffeID}}}},
+	}
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+	assert.Equal(t, "spiffe://example.org/ns/default/sa/billing", w.Body.String())
+
+	// Unverified or plaintext connections carry no identity
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/whoami", nil))
+	assert.Empty(t, w.Body.String())
+}
+
+func TestRateLimitMiddleware_ForwardedFor(t *testing.T) {
+	newServer := func(trustedProxies []string) *Server {
+		cfg := &config.Config{
+			Environment: config.EnvProduction,
+			HTTP:        config.HTTPConfig{TrustedProxies: trustedProxies},
+			RateLimit: config.RateLimitConfig{Enabled: true, Rules: []config.RateLimitRule{
+				{Name: "per-ip", Routes: []string{"GET /ping"}, Key: "ip", Limit: 1, Period: time.Minute},
+			}},
+		}
+		log := logger.New("test", "error", false)
+		limiter, err := ratelimit.NewLimiter(cfg.RateLimit, ratelimit.NewMemoryStore(), log)
+		require.NoError(t, err)
+		server := NewServer(cfg, log, tenancy.NewResolver(cfg, nil), metrics.New(metrics.Options{}), nil, limiter, nil, nil, nil,
+			&handler.UserHandler{}, &handler.UserBatchHandler{}, &handler.UserPrivacyHandler{}, &handler.UserHistoryHandler{},
+			&handler.TenantHandler{}, &handler.ProjectionHandler{}, handler.NewEventStreamHandler(nil, time.Second, log), nil)
+		server.engine.GET("/ping", func(c *gin.Context) {
+			c.String(http.StatusOK, contextx.GetClientIP(c.Request.Context()))
+		})
+		return server
+	}
+	send := func(server *Server, forwardedFor string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+		req.RemoteAddr = "10.0.0.1:4321"
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		w := httptest.NewRecorder()
+		server.engine.ServeHTTP(w, req)
+		return w
+	}
+
+	// Without trusted proxies a forged X-Forwarded-For is ignored: the peer is still limited
+	server := newServer(nil)
+	w := send(server, "203.0.113.1")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "10.0.0.1", w.Body.String())
+	assert.Equal(t, http.StatusTooManyRequests, send(server, "203.0.113.2").Code)
+
+	// Behind a trusted proxy each forwarded client is limited on its own
+	server = newServer([]string{"10.0.0.0/8"})
+	w = send(server, "203.0.113.1")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "203.0.113.1", w.Body.String())
+	assert.Equal(t, http.StatusOK, send(server, "203.0.113.2").Code)
+	assert.Equal(t, http.StatusTooManyRequests, send(server, "203.0.113.1").Code)
+}
+
+func TestHealthProbes_HideCheckErrors(t *testing.T) {
+	cfg := &config.Config{
+		Environment: config.EnvProduction,
+		HTTP:        config.HTTPConfig{EnableHealth: true},
+		Tenancy:     config.TenancyConfig{AdminToken: "secret"},
+	}
+	log := logger.New("test", "error", false)
+	server := NewServer(cfg, log, nil, metrics.New(metrics.Options{}), nil, nil, nil, nil, nil,
+		&handler.UserHandler{}, &handler.UserBatchHandler{}, &handler.UserPrivacyHandler{}, &handler.UserHistoryHandler{},
+		&handler.TenantHandler{}, &handler.ProjectionHandler{}, handler.NewEventStreamHandler(nil, time.Second, log), nil)
+	server.health = health.NewRegistry(health.Options{})
+	server.health.AddReadinessCheck("database", func(ctx context.Context) error {
+		return fmt.Errorf("dial tcp 10.1.2.3:3306: connection refused")
+	})
+	get := func(path, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if token != "" {
+			req.Header.Set("X-Admin-Token", token)
+		}
+		w := httptest.NewRecorder()
+		server.engine.ServeHTTP(w, req)
+		return w
+	}
+
+	// Public probes report the check names and statuses only
+	for _, path := range []string{"/readyz", "/health"} {
+		w := get(path, "")
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code, path)
+		assert.NotContains(t, w.Body.String(), "10.1.2.3", path)
+		var body struct {
+			Status health.Status            `json:"status"`
+			Checks map[string]health.Status `json:"checks"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body), path)
+		assert.Equal(t, map[string]health.Status{"database": health.StatusDown}, body.Checks, path)
+	}
+
+	// The errors are available behind the admin token
+	assert.Equal(t, http.StatusForbidden, get("/api/v1/admin/health", "").Code)
+	w := get("/api/v1/admin/health", "secret")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "dial tcp 10.1.2.3:3306: connection refused")
+}
@@ -0,0 +1,51 @@
+package dto
+
+import (
+	"time"
+
+	"example.com/classic/internal/domain"
+)
+
+// CreateTenantParams 创建租户参数（service层入参，与传输层解耦）
+type CreateTenantParams struct {
+	ID   string
+	Name string
+}
+
+// UpdateTenantParams 更新租户参数（service层入参，与传输层解耦）
+type UpdateTenantParams struct {
+	Name   *string
+	Status *domain.TenantStatus
+}
+
+// TenantDTO tenant data transfer object for API responses
+type TenantDTO struct {
+	ID        string              `json:"id"`
+	Name      string              `json:"name"`
+	Status    domain.TenantStatus `json:"status"`
+	CreatedAt time.Time           `json:"created_at"`
+	UpdatedAt time.Time           `json:"updated_at"`
+}
+
+// TenantDTOFromTenant creates TenantDTO from domain Tenant entity
+func TenantDTOFromTenant(tenant *domain.Tenant) *TenantDTO {
+	if tenant == nil {
+		return nil
+	}
+	return &TenantDTO{
+		ID:        tenant.ID(),
+		Name:      tenant.Name(),
+		Status:    tenant.Status(),
+		CreatedAt: tenant.CreatedAt(),
+		UpdatedAt: tenant.UpdatedAt(),
+	}
+}
+
+// TenantDTOFromTenants creates TenantDTO slice from domain Tenant slice
+func TenantDTOFromTenants(tenants []*domain.Tenant) []*TenantDTO {
+	dtos := make([]*TenantDTO, len(tenants))
+	for i, tenant := range tenants {
+		dtos[i] = TenantDTOFromTenant(tenant)
+	}
+	return dtos
+}
@@ -0,0 +1,93 @@
+package dto
+
+import (
+	"example.com/classic/internal/domain"
+)
+
+// BatchOperation 批量操作类型
+type BatchOperation string
+
+const (
+	BatchOpChangeStatus BatchOperation = "change_status"
+	BatchOpDelete       BatchOperation = "delete"
+)
+
+// BatchSelector 批量操作的目标用户：显式 ID 列表或 List 过滤条件（二选一）
+// Filter 中的分页参数会被忽略
+type BatchSelector struct {
+	IDs    []int
+	Filter *UserQueryParams
+}
+
+// BatchItemResult 单个用户的处理结果
+type BatchItemResult struct {
+	UserID  int    `json:"user_id"`
+	Success bool   `json:"success"`
+	Code    int    `json:"code,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// BatchResult 批量操作结果（异步任务执行中时表示当前进度）
+// 同步执行时 Items 包含每个用户的结果；异步任务只保留失败的用户，成功的只计数
+type BatchResult struct {
+	Operation BatchOperation     `json:"operation"`
+	Total     int                `json:"total"`
+	Processed int                `json:"processed"`
+	Succeeded int                `json:"succeeded"`
+	Failed    int                `json:"failed"`
+	Items     []*BatchItemResult `json:"items"`
+}
+
+// Record 记录单个用户结果并累加计数
+func (r *BatchResult) Record(item *BatchItemResult) {
+	r.Items = append(r.Items, item)
+	r.Count(item)
+}
+
+// Count 只累加计数，不保留单个用户结果
+func (r *BatchResult) Count(item *BatchItemResult) {
+	r.Processed++
+	if item.Success {
+		r.Succeeded++
+	} else {
+		r.Failed++
+	}
+}
+
+// BatchJobState 批量任务状态
+type BatchJobState string
+
+const (
+	BatchJobPending   BatchJobState = "pending"
+	BatchJobRunning   BatchJobState = "running"
+	BatchJobCompleted BatchJobState = "completed"
+	BatchJobFailed    BatchJobState = "failed"
+)
+
+// BatchJob 异步批量任务
+type BatchJob struct {
+	ID     string        `json:"id"`
+	State  BatchJobState `json:"state"`
+	Result *BatchResult  `json:"result,omitempty"`
+	Error  string        `json:"error,omitempty"`
+}
+
+// BatchOutcome 批量操作的返回：小批量同步执行返回 Result，大批量返回异步 Job
+type BatchOutcome struct {
+	Result *BatchResult `json:"result,omitempty"`
+	Job    *BatchJob    `json:"job,omitempty"`
+}
+
+// BatchJobPayload 异步批量任务载荷；目标 ID 在入队时确定
+type BatchJobPayload struct {
+	// TenantID 提交任务的租户，worker 执行时恢复到上下文中
+	TenantID string `json:"tenant_id"`
+	// Locale 提交请求协商的语言，用于本地化任务发出的通知
+	Locale    string         `json:"locale,omitempty"`
+	Operation BatchOperation `json:"operation"`
+	Status    domain.Status  `json:"status,omitempty"`
+	IDs       []int          `json:"ids"`
+}
+
+// BatchProgressFunc 接收异步任务的执行进度
+type BatchProgressFunc func(progress *BatchResult) error
@@ -8,12 +8,14 @@ import (
 
 // UserDTO user data transfer object for API responses
 type UserDTO struct {
-	ID        int          `json:"id"`
-	Name      string       `json:"name"`
-	Email     string       `json:"email"`
-	Status    domain.Status `json:"status"`
-	CreatedAt time.Time    `json:"created_at"`
-	UpdatedAt time.Time    `json:"updated_at"`
+	ID         int                    `json:"id"`
+	TenantID   string                 `json:"tenant_id"`
+	Name       string                 `json:"name"`
+	Email      string                 `json:"email"`
+	Status     domain.Status          `json:"status"`
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+	CreatedAt  time.Time              `json:"created_at"`
+	UpdatedAt  time.Time              `json:"updated_at"`
 }
 
 // FromUser creates UserDTO from domain User entity
@@ -22,12 +24,14 @@ func UserDTOFromUser(user *domain.User) *UserDTO {
 		return nil
 	}
 	return &UserDTO{
-		ID:        user.ID(),
-		Name:      user.Name().String(),
-		Email:     user.Email().String(),
-		Status:    user.Status(),
-		CreatedAt: user.CreatedAt(),
-		UpdatedAt: user.UpdatedAt(),
+		ID:         user.ID(),
+		TenantID:   user.TenantID(),
+		Name:       user.Name().String(),
+		Email:      user.Email().String(),
+		Status:     user.Status(),
+		Attributes: user.Attributes(),
+		CreatedAt:  user.CreatedAt(),
+		UpdatedAt:  user.UpdatedAt(),
 	}
 }
 
@@ -0,0 +1,46 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"example.com/classic/internal/domain"
+)
+
+// UserEvent 用户历史中的一个事件
+type UserEvent struct {
+	Sequence   int                  `json:"sequence"`
+	Type       string               `json:"type"`
+	Payload    json.RawMessage      `json:"payload"`
+	Metadata   domain.EventMetadata `json:"metadata"`
+	OccurredAt time.Time            `json:"occurred_at"`
+}
+
+// UserHistory 用户事件历史的一页
+type UserHistory struct {
+	UserID int          `json:"user_id"`
+	Events []*UserEvent `json:"events"`
+	// NextSequence 下一页的 after_sequence，没有更多事件时为 0
+	NextSequence int `json:"next_sequence,omitempty"`
+}
+
+// UserEventFromStored converts a stored event to UserEvent
+func UserEventFromStored(event *domain.StoredEvent) *UserEvent {
+	return &UserEvent{
+		Sequence:   event.Sequence,
+		Type:       event.Type,
+		Payload:    event.Payload,
+		Metadata:   event.Metadata,
+		OccurredAt: event.OccurredAt,
+	}
+}
+
+// ProjectionStatus 投影状态
+type ProjectionStatus struct {
+	Name string `json:"name"`
+	// Position 最后应用的事件位置
+	Position int64 `json:"position"`
+	// Events 最近一次重放应用的事件数
+	Events     int64      `json:"events"`
+	ReplayedAt *time.Time `json:"replayed_at,omitempty"`
+}
@@ -0,0 +1,94 @@
+package dto
+
+import (
+	"context"
+	"fmt"
+)
+
+// ImportAction 导入行的处理结果
+type ImportAction string
+
+const (
+	ImportActionCreated   ImportAction = "created"
+	ImportActionUpdated   ImportAction = "updated"
+	ImportActionUnchanged ImportAction = "unchanged"
+	ImportActionFailed    ImportAction = "failed"
+)
+
+// ImportOptions 批量导入选项（service层入参，与传输层解耦）
+type ImportOptions struct {
+	// DryRun 只校验不落库，结果中的 Action 表示实际执行时将发生的操作
+	DryRun bool
+	// Upsert 邮箱已存在时更新用户而不是报冲突
+	Upsert bool
+}
+
+// ImportRow 导入的一行用户数据
+// Password 与 HashedPassword 二选一；HashedPassword 必须由当前配置的哈希算法生成
+// Attributes 为 nil 表示该行未提供自定义属性：新建用户按空属性校验，更新时保留原有属性
+type ImportRow struct {
+	Line           int
+	Name           string
+	Email          string
+	Password       string
+	HashedPassword string
+	Attributes     map[string]interface{}
+}
+
+// RowError 单行解析错误，导入会记录失败并继续处理后续行
+type RowError struct {
+	Line int
+	Err  error
+}
+
+func (e *RowError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// UserImportSource 导入数据源（CSV、NDJSON、gRPC 流等）
+// Next 在数据读完时返回 io.EOF；返回 *RowError 表示该行无效但可以继续读取
+type UserImportSource interface {
+	Next(ctx context.Context) (*ImportRow, error)
+}
+
+// ImportRowResult 单行导入结果
+type ImportRowResult struct {
+	Line   int          `json:"line"`
+	Email  string       `json:"email,omitempty"`
+	Action ImportAction `json:"action"`
+	UserID int          `json:"user_id,omitempty"`
+	Code   int          `json:"code,omitempty"`
+	Error  string       `json:"error,omitempty"`
+}
+
+// ImportResultSink 接收逐行导入结果，返回错误会中止导入
+type ImportResultSink func(result *ImportRowResult) error
+
+// ImportSummary 导入汇总
+type ImportSummary struct {
+	Total     int  `json:"total"`
+	Created   int  `json:"created"`
+	Updated   int  `json:"updated"`
+	Unchanged int  `json:"unchanged"`
+	Failed    int  `json:"failed"`
+	DryRun    bool `json:"dry_run"`
+}
+
+// Record 根据单行结果累加计数
+func (s *ImportSummary) Record(result *ImportRowResult) {
+	s.Total++
+	switch result.Action {
+	case ImportActionCreated:
+		s.Created++
+	case ImportActionUpdated:
+		s.Updated++
+	case ImportActionUnchanged:
+		s.Unchanged++
+	case ImportActionFailed:
+		s.Failed++
+	}
+}
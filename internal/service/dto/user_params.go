@@ -4,9 +4,10 @@ import "example.com/classic/internal/domain"
 
 // RegisterParams 用户注册参数（service层入参，与传输层解耦）
 type RegisterParams struct {
-	Name     string
-	Email    string
-	Password string
+	Name       string
+	Email      string
+	Password   string
+	Attributes map[string]interface{}
 }
 
 // UpdateParams 用户更新参数（service层入参，与传输层解耦）
@@ -14,14 +15,18 @@ type UpdateParams struct {
 	Name   *string
 	Email  *string
 	Status *domain.Status
+	// Attributes 按 merge-patch 语义合并：覆盖给出的键，值为 nil 时删除该属性；nil map 表示不修改
+	Attributes map[string]interface{}
 }
 
 // UserQueryParams 用户列表查询参数（service层入参，与传输层解耦）
 type UserQueryParams struct {
-	ID       *int
-	Name     *string
-	Email    *string
-	Status   *domain.Status
-	Page     int
-	PageSize int
+	ID     *int
+	Name   *string
+	Email  *string
+	Status *domain.Status
+	// Attributes 自定义属性等值过滤（属性名 -> 原始字符串值，按 schema 类型解析）
+	Attributes map[string]string
+	Page       int
+	PageSize   int
 }
@@ -0,0 +1,33 @@
+package dto
+
+import (
+	"time"
+)
+
+// PersonalDataFormatVersion 个人数据导出格式版本，格式变化时递增
+const PersonalDataFormatVersion = 1
+
+// PersonalDataExport 用户个人数据导出（GDPR 第 15/20 条）
+type PersonalDataExport struct {
+	FormatVersion int       `json:"format_version"`
+	UserID        int       `json:"user_id"`
+	TenantID      string    `json:"tenant_id"`
+	GeneratedAt   time.Time `json:"generated_at"`
+	// Profile 用户资料（不含密码哈希）
+	Profile *UserDTO `json:"profile"`
+	// Sections 其他数据来源的分区，按分区名索引
+	Sections map[string]interface{} `json:"sections"`
+}
+
+// ErasureJob 个人数据擦除任务
+type ErasureJob struct {
+	ID     string `json:"id"`
+	UserID int    `json:"user_id"`
+}
+
+// ErasureJobPayload 擦除任务载荷
+type ErasureJobPayload struct {
+	// TenantID 提交任务的租户，worker 执行时恢复到上下文中
+	TenantID string `json:"tenant_id"`
+	UserID   int    `json:"user_id"`
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"example.com/classic/internal/domain"
+	"example.com/classic/internal/service/dto"
+	"example.com/classic/pkg/errors"
+	"example.com/classic/pkg/logger"
+	"example.com/classic/pkg/tracer"
+)
+
+// replayBatchSize number of events read from the store per page during a replay
+const replayBatchSize = 500
+
+// ProjectionService rebuilds read-model projections from the event store
+type ProjectionService interface {
+	// List returns the status of the registered projections
+	List(ctx context.Context) []*dto.ProjectionStatus
+	// Replay resets a projection and applies every stored event to it, in global order
+	Replay(ctx context.Context, name string) (*dto.ProjectionStatus, error)
+}
+
+// registeredProjection a projection and the status of its last replay
+type registeredProjection struct {
+	// replaying allows one replay of the projection at a time
+	replaying  sync.Mutex
+	projection domain.Projection
+	status     dto.ProjectionStatus
+}
+
+// projectionService projection service implementation
+type projectionService struct {
+	eventStore  domain.EventStore
+	projections []*registeredProjection
+	// mu guards the statuses
+	mu  sync.RWMutex
+	log logger.Logger
+}
+
+// NewProjectionService creates projection service instance
+func NewProjectionService(eventStore domain.EventStore, projections []domain.Projection, log logger.Logger) ProjectionService {
+	registered := make([]*registeredProjection, len(projections))
+	for i, projection := range projections {
+		registered[i] = &registeredProjection{
+			projection: projection,
+			status:     dto.ProjectionStatus{Name: projection.Name()},
+		}
+	}
+	return &projectionService{
+		eventStore:  eventStore,
+		projections: registered,
+		log:         log,
+	}
+}
+
+// List returns the status of the registered projections
+func (s *projectionService) List(ctx context.Context) []*dto.ProjectionStatus {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make([]*dto.ProjectionStatus, len(s.projections))
+	for i, registered := range s.projections {
+		status := registered.status
+		result[i] = &status
+	}
+	return result
+}
+
+// Replay resets the projection and applies every stored event to it
+func (s *projectionService) Replay(ctx context.Context, name string) (*dto.ProjectionStatus, error) {
+	span, ctx := tracer.ServiceSpan(ctx, s.log, "ReplayProjection")
+	defer span.End()
+
+	registered := s.find(name)
+	if registered == nil {
+		return nil, errors.New(errors.ErrCodeNotFound, fmt.Sprintf("projection not found: %s", name))
+	}
+	if !registered.replaying.TryLock() {
+		return nil, errors.New(errors.ErrCodeConflict, fmt.Sprintf("projection %s is already being replayed", name))
+	}
+	defer registered.replaying.Unlock()
+
+	s.log.Info(ctx, "projection replay started", logger.String("projection", name))
+
+	projection := registered.projection
+	if err := projection.Reset(ctx); err != nil {
+		span.EndWithError(err)
+		return nil, errors.WrapInternalError(err, "reset projection failed")
+	}
+
+	var position, applied int64
+	for {
+		events, err := s.eventStore.ReadAll(ctx, position, replayBatchSize)
+		if err != nil {
+			span.EndWithError(err)
+			return nil, err
+		}
+		for _, event := range events {
+			if err := projection.Apply(ctx, event); err != nil {
+				span.EndWithError(err)
+				return nil, errors.WrapInternalError(err, fmt.Sprintf("apply event %d failed", event.Position))
+			}
+			position = event.Position
+			applied++
+		}
+		if len(events) < replayBatchSize {
+			break
+		}
+	}
+
+	replayedAt := time.Now()
+	s.mu.Lock()
+	registered.status = dto.ProjectionStatus{
+		Name:       name,
+		Position:   position,
+		Events:     applied,
+		ReplayedAt: &replayedAt,
+	}
+	status := registered.status
+	s.mu.Unlock()
+
+	s.log.Info(ctx, "projection replay finished",
+		logger.String("projection", name),
+		logger.F("events", applied),
+		logger.F("position", position))
+	return &status, nil
+}
+
+// find returns the projection registered under name
+func (s *projectionService) find(name string) *registeredProjection {
+	for _, registered := range s.projections {
+		if registered.projection.Name() == name {
+			return registered
+		}
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+
+	"example.com/classic/internal/domain"
+	"example.com/classic/internal/service/dto"
+	"example.com/classic/pkg/contextx"
+	"example.com/classic/pkg/errors"
+	"example.com/classic/pkg/logger"
+	"example.com/classic/pkg/tracer"
+)
+
+// TenantService defines the tenant administration interface
+type TenantService interface {
+	Create(ctx context.Context, params *dto.CreateTenantParams) (*domain.Tenant, error)
+	GetByID(ctx context.Context, id string) (*domain.Tenant, error)
+	Update(ctx context.Context, id string, params *dto.UpdateTenantParams) (*domain.Tenant, error)
+	Delete(ctx context.Context, id string) error
+	List(ctx context.Context, page, pageSize int) ([]*domain.Tenant, int64, error)
+}
+
+// tenantService tenant service implementation (application service layer)
+// Tenants cached by the request pipeline are invalidated when they change.
+type tenantService struct {
+	tenantRepo  domain.TenantRepository
+	userRepo    domain.UserRepository
+	tenantCache domain.TenantCache
+	log         logger.Logger
+}
+
+// NewTenantService creates tenant service instance
+func NewTenantService(
+	tenantRepo domain.TenantRepository,
+	userRepo domain.UserRepository,
+	tenantCache domain.TenantCache,
+	log logger.Logger,
+) TenantService {
+	return &tenantService{
+		tenantRepo:  tenantRepo,
+		userRepo:    userRepo,
+		tenantCache: tenantCache,
+		log:         log,
+	}
+}
+
+// Create creates a new tenant
+func (s *tenantService) Create(ctx context.Context, params *dto.CreateTenantParams) (*domain.Tenant, error) {
+	span, ctx := tracer.ServiceSpan(ctx, s.log, "CreateTenant")
+	defer span.End()
+
+	tenant, err := domain.NewTenant(params.ID, params.Name)
+	if err != nil {
+		return nil, errors.New(errors.ErrCodeInvalidParam, err.Error())
+	}
+
+	if err := s.tenantRepo.Create(ctx, tenant); err != nil {
+		span.EndWithError(err)
+		return nil, err
+	}
+
+	s.log.Info(ctx, "租户创建完成", logger.String("tenant", tenant.ID()))
+	return tenant, nil
+}
+
+// GetByID retrieves a tenant by ID
+func (s *tenantService) GetByID(ctx context.Context, id string) (*domain.Tenant, error) {
+	span, ctx := tracer.ServiceSpan(ctx, s.log, "GetTenant")
+	defer span.End()
+
+	return s.tenantRepo.GetByID(ctx, id)
+}
+
+// Update renames a tenant or changes its status
+func (s *tenantService) Update(ctx context.Context, id string, params *dto.UpdateTenantParams) (*domain.Tenant, error) {
+	span, ctx := tracer.ServiceSpan(ctx, s.log, "UpdateTenant")
+	defer span.End()
+
+	tenant, err := s.tenantRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if params.Name != nil {
+		if err := tenant.Rename(*params.Name); err != nil {
+			return nil, errors.New(errors.ErrCodeInvalidParam, err.Error())
+		}
+	}
+	if params.Status != nil {
+		if err := tenant.ChangeStatus(*params.Status); err != nil {
+			return nil, errors.New(errors.ErrCodeInvalidParam, err.Error())
+		}
+	}
+
+	if err := s.tenantRepo.Update(ctx, tenant); err != nil {
+		span.EndWithError(err)
+		return nil, err
+	}
+	s.tenantCache.Invalidate(id)
+
+	s.log.Info(ctx, "tenant updated successfully",
+		logger.String("tenant", id),
+		logger.String("status", string(tenant.Status())))
+	return tenant, nil
+}
+
+// Delete deletes a tenant that no longer has users
+func (s *tenantService) Delete(ctx context.Context, id string) error {
+	span, ctx := tracer.ServiceSpan(ctx, s.log, "DeleteTenant")
+	defer span.End()
+
+	tenant, err := s.tenantRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	// 用户查询按上下文中的租户隔离，这里切换到目标租户统计
+	_, userCount, err := s.userRepo.List(contextx.WithTenantID(ctx, id), domain.UserListParams{Page: 1, PageSize: 1})
+	if err != nil {
+		return err
+	}
+	if err := tenant.CanBeDeleted(userCount); err != nil {
+		return errors.New(errors.ErrCodeConflict, err.Error())
+	}
+
+	if err := s.tenantRepo.Delete(ctx, id); err != nil {
+		span.EndWithError(err)
+		return err
+	}
+	s.tenantCache.Invalidate(id)
+
+	s.log.Info(ctx, "tenant deleted successfully", logger.String("tenant", id))
+	return nil
+}
+
+// List lists tenants
+func (s *tenantService) List(ctx context.Context, page, pageSize int) ([]*domain.Tenant, int64, error) {
+	span, ctx := tracer.ServiceSpan(ctx, s.log, "ListTenants")
+	defer span.End()
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+	return s.tenantRepo.List(ctx, page, pageSize)
+}
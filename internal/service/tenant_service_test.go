@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"example.com/classic/internal/domain"
+	"example.com/classic/internal/repository"
+	"example.com/classic/internal/service/dto"
+	"example.com/classic/pkg/errors"
+	"example.com/classic/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryTenantRepository keeps tenants in a map
+type memoryTenantRepository struct {
+	domain.TenantRepository
+	tenants map[string]*domain.Tenant
+}
+
+func (r *memoryTenantRepository) GetByID(_ context.Context, id string) (*domain.Tenant, error) {
+	tenant, ok := r.tenants[id]
+	if !ok {
+		return nil, errors.ErrTenantNotFound
+	}
+	return tenant, nil
+}
+
+func (r *memoryTenantRepository) Update(_ context.Context, tenant *domain.Tenant) error {
+	r.tenants[tenant.ID()] = tenant
+	return nil
+}
+
+func (r *memoryTenantRepository) Delete(_ context.Context, id string) error {
+	delete(r.tenants, id)
+	return nil
+}
+
+// recordingTenantCache records the invalidated tenants
+type recordingTenantCache struct {
+	invalidated []string
+}
+
+func (c *recordingTenantCache) Invalidate(id string) {
+	c.invalidated = append(c.invalidated, id)
+}
+
+func TestTenantService_InvalidatesCache(t *testing.T) {
+	log := logger.New("test", "error", false)
+	now := time.Now()
+	tenants := &memoryTenantRepository{tenants: map[string]*domain.Tenant{
+		"acme": domain.RebuildTenant("acme", "Acme", domain.TenantStatusActive, now, now),
+	}}
+	cache := &recordingTenantCache{}
+	svc := NewTenantService(tenants, repository.NewUserRepositoryMemory(nil, log), cache, log)
+	ctx := context.Background()
+
+	// A suspended tenant must not be served from the cache
+	status := domain.TenantStatusSuspended
+	tenant, err := svc.Update(ctx, "acme", &dto.UpdateTenantParams{Status: &status})
+	require.NoError(t, err)
+	assert.Equal(t, domain.TenantStatusSuspended, tenant.Status())
+	assert.Equal(t, []string{"acme"}, cache.invalidated)
+
+	require.NoError(t, svc.Delete(ctx, "acme"))
+	assert.Equal(t, []string{"acme", "acme"}, cache.invalidated)
+
+	// Failed changes leave the cache alone
+	_, err = svc.Update(ctx, "acme", &dto.UpdateTenantParams{Status: &status})
+	assert.ErrorIs(t, err, errors.ErrTenantNotFound)
+	assert.Len(t, cache.invalidated, 2)
+}
@@ -0,0 +1,337 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"example.com/classic/internal/domain"
+	"example.com/classic/internal/service/dto"
+	"example.com/classic/internal/taskqueue"
+	"example.com/classic/pkg/contextx"
+	"example.com/classic/pkg/errors"
+	"example.com/classic/pkg/logger"
+	"example.com/classic/pkg/tracer"
+)
+
+// TaskTypeUserBatch 异步批量用户操作任务类型
+const TaskTypeUserBatch = "user_batch"
+
+// batchProgressInterval 异步任务每处理多少个用户上报一次进度
+const batchProgressInterval = 50
+
+// batchJobMaxItems 异步任务结果最多保留的失败用户数；进度按间隔整体写回，结果需保持有界
+const batchJobMaxItems = 1000
+
+// BatchOptions 批量操作配置
+type BatchOptions struct {
+	// AsyncThreshold 目标用户数超过该值时转为异步任务执行
+	AsyncThreshold int
+	// MaxSize 单次批量操作允许的最大用户数
+	MaxSize int
+	// JobRetention 异步任务完成后结果保留时长
+	JobRetention time.Duration
+}
+
+// UserBatchService bulk administrative actions on users
+type UserBatchService interface {
+	ChangeStatus(ctx context.Context, selector *dto.BatchSelector, status domain.Status) (*dto.BatchOutcome, error)
+	Delete(ctx context.Context, selector *dto.BatchSelector) (*dto.BatchOutcome, error)
+	GetJob(ctx context.Context, id string) (*dto.BatchJob, error)
+	RunJob(ctx context.Context, payload *dto.BatchJobPayload, progress dto.BatchProgressFunc) (*dto.BatchResult, error)
+}
+
+// userBatchService user batch service implementation
+type userBatchService struct {
+	userRepo       domain.UserRepository
+	attrSchema     *domain.AttributeSchema
+	txManager      domain.TransactionManager
+	eventStore     domain.EventStore
+	eventPublisher domain.EventPublisher
+	taskQueue      taskqueue.TaskQueue
+	opts           BatchOptions
+	log            logger.Logger
+}
+
+// NewUserBatchService creates user batch service instance
+func NewUserBatchService(
+	userRepo domain.UserRepository,
+	attrSchema *domain.AttributeSchema,
+	txManager domain.TransactionManager,
+	eventStore domain.EventStore,
+	eventPublisher domain.EventPublisher,
+	taskQueue taskqueue.TaskQueue,
+	opts BatchOptions,
+	log logger.Logger,
+) UserBatchService {
+	return &userBatchService{
+		userRepo:       userRepo,
+		attrSchema:     attrSchema,
+		txManager:      txManager,
+		eventStore:     eventStore,
+		eventPublisher: eventPublisher,
+		taskQueue:      taskQueue,
+		opts:           opts,
+		log:            log,
+	}
+}
+
+// ChangeStatus changes the status of every selected user
+func (s *userBatchService) ChangeStatus(ctx context.Context, selector *dto.BatchSelector, status domain.Status) (*dto.BatchOutcome, error) {
+	span, ctx := tracer.ServiceSpan(ctx, s.log, "BatchChangeStatus")
+	defer span.End()
+
+	if !status.IsValid() {
+		return nil, errors.New(errors.ErrCodeInvalidParam, "invalid status: "+string(status))
+	}
+
+	return s.submit(ctx, selector, &dto.BatchJobPayload{
+		Operation: dto.BatchOpChangeStatus,
+		Status:    status,
+	})
+}
+
+// Delete deletes every selected user
+func (s *userBatchService) Delete(ctx context.Context, selector *dto.BatchSelector) (*dto.BatchOutcome, error) {
+	span, ctx := tracer.ServiceSpan(ctx, s.log, "BatchDelete")
+	defer span.End()
+
+	return s.submit(ctx, selector, &dto.BatchJobPayload{
+		Operation: dto.BatchOpDelete,
+	})
+}
+
+// submit resolves the target users, then runs small batches inline and enqueues large ones
+func (s *userBatchService) submit(ctx context.Context, selector *dto.BatchSelector, payload *dto.BatchJobPayload) (*dto.BatchOutcome, error) {
+	ids, err := s.resolveIDs(ctx, selector)
+	if err != nil {
+		return nil, err
+	}
+	payload.IDs = ids
+	payload.TenantID = contextx.GetTenantID(ctx)
+	payload.Locale = contextx.GetLocale(ctx)
+
+	s.log.Info(ctx, "批量操作用户",
+		logger.String("operation", string(payload.Operation)),
+		logger.Int("count", len(ids)))
+
+	if len(ids) <= s.opts.AsyncThreshold {
+		result, err := s.RunJob(ctx, payload, nil)
+		if err != nil {
+			return nil, err
+		}
+		return &dto.BatchOutcome{Result: result}, nil
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, errors.WrapInternalError(err, "marshal batch payload failed")
+	}
+
+	info, err := s.taskQueue.Enqueue(ctx, &taskqueue.Task{Type: TaskTypeUserBatch, Payload: data},
+		taskqueue.WithMaxRetry(0),
+		taskqueue.WithRetention(s.opts.JobRetention))
+	if err != nil {
+		return nil, errors.WrapInternalError(err, "enqueue batch job failed")
+	}
+
+	s.log.Info(ctx, "batch job enqueued", logger.String("job_id", info.ID))
+	return &dto.BatchOutcome{Job: &dto.BatchJob{
+		ID:    info.ID,
+		State: dto.BatchJobPending,
+		Result: &dto.BatchResult{
+			Operation: payload.Operation,
+			Total:     len(ids),
+		},
+	}}, nil
+}
+
+// resolveIDs returns the explicit IDs (deduplicated) or every user matching the filter
+func (s *userBatchService) resolveIDs(ctx context.Context, selector *dto.BatchSelector) ([]int, error) {
+	if selector == nil || (len(selector.IDs) == 0) == (selector.Filter == nil) {
+		return nil, errors.New(errors.ErrCodeInvalidParam, "exactly one of ids or filter is required")
+	}
+
+	if len(selector.IDs) > 0 {
+		if len(selector.IDs) > s.opts.MaxSize {
+			return nil, s.tooLarge()
+		}
+		seen := make(map[int]struct{}, len(selector.IDs))
+		ids := make([]int, 0, len(selector.IDs))
+		for _, id := range selector.IDs {
+			if _, ok := seen[id]; ok {
+				continue
+			}
+			seen[id] = struct{}{}
+			ids = append(ids, id)
+		}
+		return ids, nil
+	}
+
+	params, err := toListParams(s.attrSchema, selector.Filter)
+	if err != nil {
+		return nil, err
+	}
+	params.Page = 1
+	params.PageSize = exportBatchSize
+
+	var ids []int
+	for {
+		users, total, err := s.userRepo.List(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		if total > int64(s.opts.MaxSize) {
+			return nil, s.tooLarge()
+		}
+		for _, user := range users {
+			ids = append(ids, user.ID())
+		}
+		if len(users) < params.PageSize || int64(params.Page*params.PageSize) >= total {
+			break
+		}
+		params.Page++
+	}
+	return ids, nil
+}
+
+func (s *userBatchService) tooLarge() error {
+	return errors.New(errors.ErrCodeInvalidParam, fmt.Sprintf("batch selects more than %d users", s.opts.MaxSize))
+}
+
+// GetJob returns the state and progress of an asynchronous batch job
+func (s *userBatchService) GetJob(ctx context.Context, id string) (*dto.BatchJob, error) {
+	span, ctx := tracer.ServiceSpan(ctx, s.log, "GetBatchJob")
+	defer span.End()
+
+	info, err := s.taskQueue.GetTaskInfo(ctx, taskqueue.DefaultEnqueueOptions().Queue, id)
+	if err != nil {
+		if errors.Is(err, taskqueue.ErrTaskNotFound) {
+			return nil, errors.New(errors.ErrCodeNotFound, "batch job not found")
+		}
+		return nil, errors.WrapInternalError(err, "get batch job failed")
+	}
+	// Jobs of other tenants are reported as not found
+	var payload dto.BatchJobPayload
+	if info.Type != TaskTypeUserBatch || json.Unmarshal(info.Payload, &payload) != nil ||
+		payload.TenantID != contextx.GetTenantID(ctx) {
+		return nil, errors.New(errors.ErrCodeNotFound, "batch job not found")
+	}
+
+	job := &dto.BatchJob{ID: info.ID, Error: info.LastErr}
+	switch info.State {
+	case taskqueue.TaskStateActive, taskqueue.TaskStateRetry:
+		job.State = dto.BatchJobRunning
+	case taskqueue.TaskStateCompleted:
+		job.State = dto.BatchJobCompleted
+	case taskqueue.TaskStateFailed:
+		job.State = dto.BatchJobFailed
+	default:
+		job.State = dto.BatchJobPending
+	}
+
+	if len(info.Result) > 0 {
+		var result dto.BatchResult
+		if err := json.Unmarshal(info.Result, &result); err != nil {
+			return nil, errors.WrapInternalError(err, "decode batch job result failed")
+		}
+		job.Result = &result
+	}
+	return job, nil
+}
+
+// RunJob applies the operation to every user in the payload; progress may be nil
+func (s *userBatchService) RunJob(ctx context.Context, payload *dto.BatchJobPayload, progress dto.BatchProgressFunc) (*dto.BatchResult, error) {
+	span, ctx := tracer.ServiceSpan(ctx, s.log, "RunBatchJob")
+	defer span.End()
+
+	var apply func(ctx context.Context, aggregate *domain.UserAggregate) error
+	switch payload.Operation {
+	case dto.BatchOpChangeStatus:
+		apply = func(ctx context.Context, aggregate *domain.UserAggregate) error {
+			return aggregate.ChangeStatus(payload.Status)
+		}
+	case dto.BatchOpDelete:
+		apply = func(ctx context.Context, aggregate *domain.UserAggregate) error {
+			return aggregate.MarkDeleted()
+		}
+	default:
+		return nil, errors.New(errors.ErrCodeInvalidParam, "unknown batch operation: "+string(payload.Operation))
+	}
+
+	result := &dto.BatchResult{
+		Operation: payload.Operation,
+		Total:     len(payload.IDs),
+		Items:     []*dto.BatchItemResult{},
+	}
+
+	for i, id := range payload.IDs {
+		if err := ctx.Err(); err != nil {
+			span.EndWithError(err)
+			return result, err
+		}
+
+		// Asynchronous jobs rewrite the whole result on every progress report: keep only (a bounded number of) failures
+		item := s.processOne(ctx, id, payload.Operation, apply)
+		if progress == nil || (!item.Success && len(result.Items) < batchJobMaxItems) {
+			result.Record(item)
+		} else {
+			result.Count(item)
+		}
+
+		if progress != nil && ((i+1)%batchProgressInterval == 0 || i+1 == len(payload.IDs)) {
+			if err := progress(result); err != nil {
+				s.log.Warn(ctx, "failed to report batch progress", logger.Err(err))
+			}
+		}
+	}
+
+	s.log.Info(ctx, "批量操作完成",
+		logger.String("operation", string(payload.Operation)),
+		logger.Int("succeeded", result.Succeeded),
+		logger.Int("failed", result.Failed))
+	return result, nil
+}
+
+// processOne loads one aggregate, applies the domain rule and persists it in its own transaction
+func (s *userBatchService) processOne(ctx context.Context, id int, op dto.BatchOperation, apply func(context.Context, *domain.UserAggregate) error) *dto.BatchItemResult {
+	item := &dto.BatchItemResult{UserID: id}
+
+	var events []domain.DomainEvent
+	err := s.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		aggregate, err := s.userRepo.GetAggregateByID(txCtx, id)
+		if err != nil {
+			return err
+		}
+		if err := apply(txCtx, aggregate); err != nil {
+			return errors.New(errors.ErrCodeInvalidParam, err.Error())
+		}
+
+		if op == dto.BatchOpDelete {
+			err = s.userRepo.Delete(txCtx, id)
+		} else {
+			err = s.userRepo.Save(txCtx, aggregate)
+		}
+		if err != nil {
+			return err
+		}
+
+		events = aggregate.Events()
+		return s.eventStore.Append(txCtx, events)
+	})
+	if err != nil {
+		item.Code, item.Error = errorDetail(err)
+		return item
+	}
+
+	// 事务提交后再发布事件
+	if len(events) > 0 {
+		if err := s.eventPublisher.PublishBatch(withTenant(ctx, withLocale(ctx, events))); err != nil {
+			s.log.Warn(ctx, "failed to publish domain events", logger.Err(err))
+		}
+	}
+
+	item.Success = true
+	return item
+}
@@ -0,0 +1,219 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"example.com/classic/internal/domain"
+	"example.com/classic/internal/service/dto"
+	"example.com/classic/internal/taskqueue"
+	"example.com/classic/pkg/contextx"
+	"example.com/classic/pkg/errors"
+	"example.com/classic/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockTaskQueue mock task queue
+type MockTaskQueue struct {
+	mock.Mock
+}
+
+func (m *MockTaskQueue) Enqueue(ctx context.Context, task *taskqueue.Task, opts ...taskqueue.Option) (*taskqueue.TaskResult, error) {
+	args := m.Called(ctx, task)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*taskqueue.TaskResult), args.Error(1)
+}
+
+func (m *MockTaskQueue) EnqueueIn(ctx context.Context, task *taskqueue.Task, delay time.Duration, opts ...taskqueue.Option) (*taskqueue.TaskResult, error) {
+	return m.Enqueue(ctx, task)
+}
+
+func (m *MockTaskQueue) EnqueueAt(ctx context.Context, task *taskqueue.Task, processAt time.Time, opts ...taskqueue.Option) (*taskqueue.TaskResult, error) {
+	return m.Enqueue(ctx, task)
+}
+
+func (m *MockTaskQueue) GetTaskInfo(ctx context.Context, queue, id string) (*taskqueue.TaskInfo, error) {
+	args := m.Called(ctx, queue, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*taskqueue.TaskInfo), args.Error(1)
+}
+
+func (m *MockTaskQueue) RegisterHandler(taskType string, handler taskqueue.Handler) error {
+	return m.Called(taskType, handler).Error(0)
+}
+
+func (m *MockTaskQueue) Start(ctx context.Context) error {
+	return m.Called(ctx).Error(0)
+}
+
+func (m *MockTaskQueue) Stop(ctx context.Context) error {
+	return m.Called(ctx).Error(0)
+}
+
+func newTestBatchService(mockRepo *MockUserRepository, mockQueue *MockTaskQueue, mockEventPub *MockEventPublisher) UserBatchService {
+	mockTxManager := new(MockTransactionManager)
+	mockTxManager.On("WithTransaction", mock.Anything, mock.Anything)
+	log := logger.New("test", "debug", true)
+	return NewUserBatchService(mockRepo, nil, mockTxManager, newMockEventStore(), mockEventPub, mockQueue, BatchOptions{
+		AsyncThreshold: 2,
+		MaxSize:        5,
+		JobRetention:   time.Hour,
+	}, log)
+}
+
+func TestUserBatchService_ChangeStatus(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockQueue := new(MockTaskQueue)
+	mockEventPub := new(MockEventPublisher)
+	svc := newTestBatchService(mockRepo, mockQueue, mockEventPub)
+
+	banned := createTestAggregate(2, "Banned User", "banned@example.com")
+	_ = banned.Ban()
+	banned.ClearEvents()
+
+	mockRepo.On("GetAggregateByID", mock.Anything, 1).Return(createTestAggregate(1, "Spam User", "spam@example.com"), nil)
+	mockRepo.On("GetAggregateByID", mock.Anything, 2).Return(banned, nil)
+	mockRepo.On("Save", mock.Anything, mock.AnythingOfType("*domain.UserAggregate")).Return(nil)
+	mockEventPub.On("PublishBatch", mock.Anything).Return(nil)
+
+	// Duplicate IDs are processed once; a small batch runs inline
+	outcome, err := svc.ChangeStatus(context.Background(), &dto.BatchSelector{IDs: []int{1, 2, 1}}, domain.StatusBanned)
+
+	assert.NoError(t, err)
+	assert.Nil(t, outcome.Job)
+	assert.Equal(t, 2, outcome.Result.Total)
+	assert.Equal(t, 1, outcome.Result.Succeeded)
+	assert.Equal(t, 1, outcome.Result.Failed)
+	assert.True(t, outcome.Result.Items[0].Success)
+	assert.Equal(t, int(errors.ErrCodeInvalidParam), outcome.Result.Items[1].Code)
+	mockRepo.AssertNumberOfCalls(t, "Save", 1)
+	mockEventPub.AssertNumberOfCalls(t, "PublishBatch", 1)
+}
+
+func TestUserBatchService_DeleteByFilter(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockQueue := new(MockTaskQueue)
+	mockEventPub := new(MockEventPublisher)
+	svc := newTestBatchService(mockRepo, mockQueue, mockEventPub)
+
+	status := domain.StatusInactive
+	users := []*domain.User{
+		createTestUser(1, "User One", "one@example.com"),
+		createTestUser(2, "User Two", "two@example.com"),
+		createTestUser(3, "User Three", "three@example.com"),
+	}
+	mockRepo.On("List", mock.Anything, mock.AnythingOfType("domain.UserListParams")).Return(users, int64(3), nil)
+
+	var enqueued *taskqueue.Task
+	mockQueue.On("Enqueue", mock.Anything, mock.AnythingOfType("*taskqueue.Task")).
+		Run(func(args mock.Arguments) { enqueued = args.Get(1).(*taskqueue.Task) }).
+		Return(&taskqueue.TaskResult{ID: "job-1", Queue: "default", Type: TaskTypeUserBatch}, nil)
+
+	// Above the async threshold the batch is queued with the resolved IDs
+	outcome, err := svc.Delete(context.Background(), &dto.BatchSelector{Filter: &dto.UserQueryParams{Status: &status}})
+
+	assert.NoError(t, err)
+	assert.Nil(t, outcome.Result)
+	assert.Equal(t, "job-1", outcome.Job.ID)
+	assert.Equal(t, dto.BatchJobPending, outcome.Job.State)
+
+	var payload dto.BatchJobPayload
+	assert.NoError(t, json.Unmarshal(enqueued.Payload, &payload))
+	assert.Equal(t, dto.BatchOpDelete, payload.Operation)
+	assert.Equal(t, []int{1, 2, 3}, payload.IDs)
+	mockRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+func TestUserBatchService_RunJobProgress(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockEventPub := new(MockEventPublisher)
+	svc := newTestBatchService(mockRepo, new(MockTaskQueue), mockEventPub)
+
+	banned := createTestAggregate(2, "Banned User", "banned@example.com")
+	_ = banned.Ban()
+	banned.ClearEvents()
+
+	mockRepo.On("GetAggregateByID", mock.Anything, 1).Return(createTestAggregate(1, "Spam User", "spam@example.com"), nil)
+	mockRepo.On("GetAggregateByID", mock.Anything, 2).Return(banned, nil)
+	mockRepo.On("GetAggregateByID", mock.Anything, 3).Return(createTestAggregate(3, "Spam User", "spam3@example.com"), nil)
+	mockRepo.On("Save", mock.Anything, mock.AnythingOfType("*domain.UserAggregate")).Return(nil)
+	var published []domain.DomainEvent
+	mockEventPub.On("PublishBatch", mock.Anything).
+		Run(func(args mock.Arguments) { published = append(published, args.Get(0).([]domain.DomainEvent)...) }).
+		Return(nil)
+
+	// The worker restores the locale of the request, so notifications are localized like ChangeStatus
+	ctx := contextx.WithLocale(contextx.WithTenantID(context.Background(), "acme"), "zh-CN")
+	var reports []dto.BatchResult
+	result, err := svc.RunJob(ctx, &dto.BatchJobPayload{Operation: dto.BatchOpChangeStatus, Status: domain.StatusBanned, IDs: []int{1, 2, 3}},
+		func(progress *dto.BatchResult) error {
+			reports = append(reports, *progress)
+			return nil
+		})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, result.Processed)
+	assert.Equal(t, 2, result.Succeeded)
+	assert.Equal(t, 1, result.Failed)
+	// Asynchronous results keep the failed users only
+	assert.Len(t, result.Items, 1)
+	assert.Equal(t, 2, result.Items[0].UserID)
+	assert.Len(t, reports, 1)
+
+	assert.Len(t, published, 2)
+	for _, event := range published {
+		changed, ok := event.(*domain.UserStatusChangedEvent)
+		if assert.True(t, ok) {
+			assert.Equal(t, "zh-CN", changed.Locale)
+		}
+	}
+}
+
+func TestUserBatchService_InvalidSelector(t *testing.T) {
+	svc := newTestBatchService(new(MockUserRepository), new(MockTaskQueue), new(MockEventPublisher))
+
+	_, err := svc.Delete(context.Background(), &dto.BatchSelector{})
+	assert.Error(t, err)
+
+	_, err = svc.Delete(context.Background(), &dto.BatchSelector{IDs: []int{1, 2, 3, 4, 5, 6}})
+	assert.Error(t, err)
+}
+
+func TestUserBatchService_GetJob(t *testing.T) {
+	mockQueue := new(MockTaskQueue)
+	svc := newTestBatchService(new(MockUserRepository), mockQueue, new(MockEventPublisher))
+
+	payload, _ := json.Marshal(&dto.BatchJobPayload{Operation: dto.BatchOpDelete, IDs: []int{1}, TenantID: "acme"})
+	progress, _ := json.Marshal(&dto.BatchResult{Operation: dto.BatchOpDelete, Total: 300, Processed: 150})
+	mockQueue.On("GetTaskInfo", mock.Anything, "default", "job-1").Return(&taskqueue.TaskInfo{
+		ID:      "job-1",
+		Type:    TaskTypeUserBatch,
+		State:   taskqueue.TaskStateActive,
+		Payload: payload,
+		Result:  progress,
+	}, nil)
+	mockQueue.On("GetTaskInfo", mock.Anything, "default", "missing").Return(nil, taskqueue.ErrTaskNotFound)
+
+	ctx := contextx.WithTenantID(context.Background(), "acme")
+	job, err := svc.GetJob(ctx, "job-1")
+	assert.NoError(t, err)
+	assert.Equal(t, dto.BatchJobRunning, job.State)
+	assert.Equal(t, 150, job.Result.Processed)
+
+	_, err = svc.GetJob(ctx, "missing")
+	var appErr *errors.Error
+	assert.True(t, errors.As(err, &appErr))
+	assert.Equal(t, errors.ErrCodeNotFound, appErr.Code)
+
+	// Jobs of another tenant are not visible
+	_, err = svc.GetJob(contextx.WithTenantID(context.Background(), "other"), "job-1")
+	assert.True(t, errors.As(err, &appErr))
+	assert.Equal(t, errors.ErrCodeNotFound, appErr.Code)
+}
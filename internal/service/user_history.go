@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"math"
+
+	"example.com/classic/internal/domain"
+	"example.com/classic/internal/service/dto"
+	"example.com/classic/pkg/errors"
+	"example.com/classic/pkg/logger"
+	"example.com/classic/pkg/tracer"
+)
+
+const (
+	defaultHistoryLimit = 50
+	maxHistoryLimit     = 200
+)
+
+// UserHistoryService reads the event history of users
+type UserHistoryService interface {
+	// GetHistory returns up to limit events after afterSequence, oldest first
+	GetHistory(ctx context.Context, id, afterSequence, limit int) (*dto.UserHistory, error)
+}
+
+// userHistoryService user history service implementation
+type userHistoryService struct {
+	userRepo   domain.UserRepository
+	eventStore domain.EventStore
+	log        logger.Logger
+}
+
+// NewUserHistoryService creates user history service instance
+func NewUserHistoryService(userRepo domain.UserRepository, eventStore domain.EventStore, log logger.Logger) UserHistoryService {
+	return &userHistoryService{
+		userRepo:   userRepo,
+		eventStore: eventStore,
+		log:        log,
+	}
+}
+
+// GetHistory returns a page of the user's events; the history outlives deleted users
+func (s *userHistoryService) GetHistory(ctx context.Context, id, afterSequence, limit int) (*dto.UserHistory, error) {
+	span, ctx := tracer.ServiceSpan(ctx, s.log, "GetHistory")
+	defer span.End()
+
+	if afterSequence < 0 {
+		return nil, errors.New(errors.ErrCodeInvalidParam, "after_sequence must not be negative")
+	}
+	if limit < 1 {
+		limit = defaultHistoryLimit
+	}
+	if limit > maxHistoryLimit {
+		limit = maxHistoryLimit
+	}
+
+	// One extra event tells whether there is a next page
+	events, err := s.eventStore.Load(ctx, domain.AggregateID("user", id), afterSequence, limit+1)
+	if err != nil {
+		span.EndWithError(err)
+		return nil, err
+	}
+	// Users created before the event store have no history
+	if len(events) == 0 && afterSequence == 0 {
+		if _, err := s.userRepo.GetByID(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+
+	history := &dto.UserHistory{UserID: id, Events: make([]*dto.UserEvent, 0, len(events))}
+	if len(events) > limit {
+		events = events[:limit]
+		history.NextSequence = events[limit-1].Sequence
+	}
+	for _, event := range events {
+		history.Events = append(history.Events, dto.UserEventFromStored(event))
+	}
+	return history, nil
+}
+
+// eventHistorySource contributes the event history to personal data exports
+type eventHistorySource struct {
+	eventStore domain.EventStore
+}
+
+// NewEventHistorySource creates the "events" personal data source
+func NewEventHistorySource(eventStore domain.EventStore) domain.PersonalDataSource {
+	return &eventHistorySource{eventStore: eventStore}
+}
+
+// Section returns the archive section name
+func (s *eventHistorySource) Section() string {
+	return "events"
+}
+
+// Collect returns every event of the user
+func (s *eventHistorySource) Collect(ctx context.Context, userID int) (interface{}, error) {
+	events, err := s.eventStore.Load(ctx, domain.AggregateID("user", userID), 0, math.MaxInt32)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*dto.UserEvent, len(events))
+	for i, event := range events {
+		result[i] = dto.UserEventFromStored(event)
+	}
+	return result, nil
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"example.com/classic/internal/domain"
+	"example.com/classic/internal/repository"
+	"example.com/classic/pkg/contextx"
+	"example.com/classic/pkg/errors"
+	"example.com/classic/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserHistoryService_GetHistory(t *testing.T) {
+	log := logger.New("test", "error", false)
+	repo := repository.NewUserRepositoryMemory(nil, log)
+	store := repository.NewEventStoreMemory(log)
+	svc := NewUserHistoryService(repo, store, log)
+	ctx := contextx.WithTenantID(context.Background(), "acme")
+
+	user := createTestUser(0, "Alice", "alice@example.com")
+	require.NoError(t, repo.Create(ctx, user))
+	require.NoError(t, store.Append(ctx, []domain.DomainEvent{
+		domain.NewUserCreatedEvent(user.ID(), "alice@example.com", "Alice"),
+		domain.NewUserUpdatedEvent(user.ID(), "alice@example.com", "Alice B"),
+		domain.NewUserStatusChangedEvent(user.ID(), "alice@example.com", "Alice B", domain.StatusActive, domain.StatusBanned),
+	}))
+
+	t.Run("Paging", func(t *testing.T) {
+		history, err := svc.GetHistory(ctx, user.ID(), 0, 2)
+		require.NoError(t, err)
+		require.Len(t, history.Events, 2)
+		assert.Equal(t, user.ID(), history.UserID)
+		assert.Equal(t, domain.EventTypeUserCreated, history.Events[0].Type)
+		assert.Equal(t, 2, history.NextSequence)
+
+		history, err = svc.GetHistory(ctx, user.ID(), history.NextSequence, 2)
+		require.NoError(t, err)
+		require.Len(t, history.Events, 1)
+		assert.Equal(t, 3, history.Events[0].Sequence)
+		assert.Equal(t, domain.EventTypeUserStatusChanged, history.Events[0].Type)
+		assert.Zero(t, history.NextSequence)
+	})
+
+	t.Run("DeletedUser", func(t *testing.T) {
+		deleted := createTestUser(0, "Bob", "bob@example.com")
+		require.NoError(t, repo.Create(ctx, deleted))
+		require.NoError(t, store.Append(ctx, []domain.DomainEvent{domain.NewUserDeletedEvent(deleted.ID(), "bob@example.com", "Bob")}))
+		require.NoError(t, repo.Delete(ctx, deleted.ID()))
+
+		history, err := svc.GetHistory(ctx, deleted.ID(), 0, 10)
+		require.NoError(t, err)
+		assert.Len(t, history.Events, 1)
+	})
+
+	t.Run("UserWithoutEvents", func(t *testing.T) {
+		legacy := createTestUser(0, "Carol", "carol@example.com")
+		require.NoError(t, repo.Create(ctx, legacy))
+
+		history, err := svc.GetHistory(ctx, legacy.ID(), 0, 10)
+		require.NoError(t, err)
+		assert.Empty(t, history.Events)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		_, err := svc.GetHistory(ctx, 999, 0, 10)
+		assert.ErrorIs(t, err, errors.ErrUserNotFound)
+
+		// Other tenants cannot see the history
+		_, err = svc.GetHistory(contextx.WithTenantID(context.Background(), "other"), user.ID(), 0, 10)
+		assert.ErrorIs(t, err, errors.ErrUserNotFound)
+	})
+
+	t.Run("InvalidAfterSequence", func(t *testing.T) {
+		_, err := svc.GetHistory(ctx, user.ID(), -1, 10)
+		var appErr *errors.Error
+		require.True(t, errors.As(err, &appErr))
+		assert.Equal(t, errors.ErrCodeInvalidParam, appErr.Code)
+	})
+}
+
+// countingProjection counts the applied events per type
+type countingProjection struct {
+	resets int
+	counts map[string]int
+}
+
+func (p *countingProjection) Name() string { return "user_counts" }
+
+func (p *countingProjection) Reset(_ context.Context) error {
+	p.resets++
+	p.counts = make(map[string]int)
+	return nil
+}
+
+func (p *countingProjection) Apply(_ context.Context, event *domain.StoredEvent) error {
+	if _, err := event.Decode(); err != nil {
+		return err
+	}
+	p.counts[event.Type]++
+	return nil
+}
+
+func TestProjectionService_Replay(t *testing.T) {
+	log := logger.New("test", "error", false)
+	store := repository.NewEventStoreMemory(log)
+	projection := &countingProjection{}
+	svc := NewProjectionService(store, []domain.Projection{projection}, log)
+
+	acme := contextx.WithTenantID(context.Background(), "acme")
+	other := contextx.WithTenantID(context.Background(), "other")
+	require.NoError(t, store.Append(acme, []domain.DomainEvent{
+		domain.NewUserCreatedEvent(1, "alice@example.com", "Alice"),
+		domain.NewUserDeletedEvent(1, "alice@example.com", "Alice"),
+	}))
+	require.NoError(t, store.Append(other, []domain.DomainEvent{domain.NewUserCreatedEvent(1, "bob@example.com", "Bob")}))
+
+	statuses := svc.List(context.Background())
+	require.Len(t, statuses, 1)
+	assert.Equal(t, "user_counts", statuses[0].Name)
+	assert.Nil(t, statuses[0].ReplayedAt)
+
+	status, err := svc.Replay(context.Background(), "user_counts")
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), status.Events)
+	assert.Equal(t, int64(3), status.Position)
+	assert.NotNil(t, status.ReplayedAt)
+	assert.Equal(t, map[string]int{domain.EventTypeUserCreated: 2, domain.EventTypeUserDeleted: 1}, projection.counts)
+
+	// A second replay starts from scratch
+	_, err = svc.Replay(context.Background(), "user_counts")
+	require.NoError(t, err)
+	assert.Equal(t, 2, projection.resets)
+	assert.Equal(t, 2, projection.counts[domain.EventTypeUserCreated])
+	assert.Equal(t, int64(3), svc.List(context.Background())[0].Events)
+
+	_, err = svc.Replay(context.Background(), "missing")
+	var appErr *errors.Error
+	require.True(t, errors.As(err, &appErr))
+	assert.Equal(t, errors.ErrCodeNotFound, appErr.Code)
+}
@@ -0,0 +1,288 @@
+package service
+
+import (
+	"context"
+	"io"
+	"reflect"
+
+	"example.com/classic/internal/domain"
+	"example.com/classic/internal/service/dto"
+	"example.com/classic/pkg/errors"
+	"example.com/classic/pkg/logger"
+	"example.com/classic/pkg/tracer"
+)
+
+// exportBatchSize 导出时每次从仓储读取的记录数
+const exportBatchSize = 100
+
+// Import imports users row by row; every row gets its own result and transaction
+func (s *userService) Import(ctx context.Context, source dto.UserImportSource, opts dto.ImportOptions, sink dto.ImportResultSink) (*dto.ImportSummary, error) {
+	span, ctx := tracer.ServiceSpan(ctx, s.log, "Import")
+	defer span.End()
+
+	s.log.Info(ctx, "批量导入用户开始",
+		logger.Bool("dry_run", opts.DryRun),
+		logger.Bool("upsert", opts.Upsert))
+
+	summary := &dto.ImportSummary{DryRun: opts.DryRun}
+	for {
+		if err := ctx.Err(); err != nil {
+			span.EndWithError(err)
+			return summary, err
+		}
+
+		row, err := source.Next(ctx)
+		if err == io.EOF {
+			break
+		}
+
+		var result *dto.ImportRowResult
+		if rowErr, ok := err.(*dto.RowError); ok {
+			result = &dto.ImportRowResult{Line: rowErr.Line}
+			failImportRow(result, errors.Wrap(rowErr.Err, errors.ErrCodeInvalidParam, rowErr.Err.Error()))
+		} else if err != nil {
+			span.EndWithError(err)
+			return summary, errors.WrapInvalidParam(err, "read import source failed")
+		} else {
+			result = s.importRow(ctx, row, opts)
+		}
+
+		summary.Record(result)
+		if err := sink(result); err != nil {
+			span.EndWithError(err)
+			return summary, err
+		}
+	}
+
+	s.log.Info(ctx, "批量导入用户完成",
+		logger.Int("total", summary.Total),
+		logger.Int("created", summary.Created),
+		logger.Int("updated", summary.Updated),
+		logger.Int("failed", summary.Failed))
+
+	return summary, nil
+}
+
+// importRow imports a single row; dry-run rows are evaluated without a transaction
+func (s *userService) importRow(ctx context.Context, row *dto.ImportRow, opts dto.ImportOptions) *dto.ImportRowResult {
+	result := &dto.ImportRowResult{Line: row.Line, Email: row.Email}
+
+	apply := func(txCtx context.Context) error {
+		return s.applyImportRow(txCtx, row, opts, result)
+	}
+
+	var err error
+	if opts.DryRun {
+		err = apply(ctx)
+	} else {
+		err = s.txManager.WithTransaction(ctx, apply)
+	}
+	if err != nil {
+		s.log.Debug(ctx, "import row failed", logger.Int("line", row.Line), logger.Err(err))
+		failImportRow(result, err)
+	}
+	return result
+}
+
+// applyImportRow creates or (with upsert) updates the user described by row
+func (s *userService) applyImportRow(ctx context.Context, row *dto.ImportRow, opts dto.ImportOptions, result *dto.ImportRowResult) error {
+	existing, err := s.userRepo.GetAggregateByEmail(ctx, row.Email)
+	if err != nil && !errors.Is(err, errors.ErrUserNotFound) {
+		return err
+	}
+
+	var aggregate *domain.UserAggregate
+	if existing == nil {
+		aggregate, err = s.newImportAggregate(row)
+		if err != nil {
+			return errors.Wrap(err, errors.ErrCodeInvalidParam, err.Error())
+		}
+		// 与 Register 一致：未提供属性时也要校验，缺少必填属性的行失败
+		if err := aggregate.User().ChangeAttributes(s.attrSchema, row.Attributes); err != nil {
+			return errors.Wrap(err, errors.ErrCodeInvalidParam, err.Error())
+		}
+		result.Action = dto.ImportActionCreated
+	} else {
+		if !opts.Upsert {
+			return errors.ErrUserAlreadyExists
+		}
+		aggregate = existing
+		result.UserID = existing.ID()
+
+		changed, err := s.mergeImportRow(existing, row)
+		if err != nil {
+			return errors.Wrap(err, errors.ErrCodeInvalidParam, err.Error())
+		}
+		if !changed {
+			result.Action = dto.ImportActionUnchanged
+			return nil
+		}
+		result.Action = dto.ImportActionUpdated
+	}
+
+	if opts.DryRun {
+		return nil
+	}
+
+	if err := s.userRepo.Save(ctx, aggregate); err != nil {
+		return err
+	}
+	if existing == nil {
+		aggregate.MarkCreated()
+		result.UserID = aggregate.ID()
+	}
+	if err := s.eventStore.Append(ctx, aggregate.Events()); err != nil {
+		return err
+	}
+
+	if aggregate.HasEvents() {
+		if err := s.eventPublisher.PublishBatch(withTenant(ctx, aggregate.Events())); err != nil {
+			s.log.Warn(ctx, "failed to publish domain events", logger.Err(err))
+		}
+		aggregate.ClearEvents()
+	}
+	return nil
+}
+
+// newImportAggregate validates the row through the user factory
+func (s *userService) newImportAggregate(row *dto.ImportRow) (*domain.UserAggregate, error) {
+	if row.HashedPassword != "" {
+		return s.userFactory.CreateNewUserWithHashedPassword(row.Name, row.Email, row.HashedPassword)
+	}
+	return s.userFactory.CreateNewUser(row.Name, row.Email, row.Password)
+}
+
+// mergeImportRow applies the row onto an existing aggregate and reports whether anything changed
+// Rows without credentials keep the existing password, rows without attributes the existing attributes
+func (s *userService) mergeImportRow(aggregate *domain.UserAggregate, row *dto.ImportRow) (bool, error) {
+	changed, err := s.mergeImportProfile(aggregate, row)
+	if err != nil {
+		return false, err
+	}
+
+	attrsChanged, err := s.mergeImportAttributes(aggregate, row.Attributes)
+	if err != nil {
+		return false, err
+	}
+	return changed || attrsChanged, nil
+}
+
+// mergeImportProfile applies the name and credentials of the row
+func (s *userService) mergeImportProfile(aggregate *domain.UserAggregate, row *dto.ImportRow) (bool, error) {
+	user := aggregate.User()
+
+	if row.Password == "" && row.HashedPassword == "" {
+		name, err := domain.NewName(row.Name)
+		if err != nil {
+			return false, err
+		}
+		if name.Equals(user.Name()) {
+			return false, nil
+		}
+		return true, aggregate.UpdateProfile(*name, user.Email())
+	}
+
+	// 通过工厂校验整行并得到哈希后的密码
+	candidate, err := s.newImportAggregate(row)
+	if err != nil {
+		return false, err
+	}
+
+	if !candidate.User().Name().Equals(user.Name()) {
+		if err := aggregate.UpdateProfile(candidate.User().Name(), user.Email()); err != nil {
+			return false, err
+		}
+	}
+
+	hashedPassword, err := domain.NewHashedPassword(candidate.User().GetHashedPassword())
+	if err != nil {
+		return false, err
+	}
+	return true, aggregate.ChangePassword(*hashedPassword)
+}
+
+// mergeImportAttributes merge-patches the row attributes like Update does: a nil value removes the attribute
+// The merged attributes are validated against the schema, so required attributes cannot be dropped
+func (s *userService) mergeImportAttributes(aggregate *domain.UserAggregate, patch map[string]interface{}) (bool, error) {
+	if patch == nil {
+		return false, nil
+	}
+
+	current := aggregate.User().Attributes()
+	attrs := current.Clone()
+	if attrs == nil {
+		attrs = make(domain.Attributes, len(patch))
+	}
+	for name, value := range patch {
+		attrs[name] = value
+	}
+
+	normalized, err := s.attrSchema.Validate(attrs)
+	if err != nil {
+		return false, err
+	}
+	if len(normalized) == len(current) && (len(current) == 0 || reflect.DeepEqual(normalized, current)) {
+		return false, nil
+	}
+	return true, aggregate.UpdateAttributes(s.attrSchema, normalized)
+}
+
+// failImportRow marks the row result as failed
+func failImportRow(result *dto.ImportRowResult, err error) {
+	result.Action = dto.ImportActionFailed
+	result.Code, result.Error = errorDetail(err)
+}
+
+// errorDetail extracts the business error code and message reported per row/item
+func errorDetail(err error) (int, string) {
+	var appErr *errors.Error
+	if errors.As(err, &appErr) {
+		return int(appErr.Code), appErr.Message
+	}
+	return int(errors.ErrCodeInternalError), err.Error()
+}
+
+// Export streams every user matching the List filters to fn, page by page
+func (s *userService) Export(ctx context.Context, query *dto.UserQueryParams, fn func(user *domain.User) error) error {
+	span, ctx := tracer.ServiceSpan(ctx, s.log, "Export")
+	defer span.End()
+
+	params, err := toListParams(s.attrSchema, query)
+	if err != nil {
+		span.EndWithError(err)
+		return err
+	}
+	params.Page = 1
+	params.PageSize = exportBatchSize
+
+	exported := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			span.EndWithError(err)
+			return err
+		}
+
+		users, total, err := s.userRepo.List(ctx, params)
+		if err != nil {
+			span.EndWithError(err)
+			return err
+		}
+
+		for _, user := range users {
+			user.ClearSensitiveData()
+			if err := fn(user); err != nil {
+				span.EndWithError(err)
+				return err
+			}
+		}
+		exported += len(users)
+
+		if len(users) < params.PageSize || int64(params.Page*params.PageSize) >= total {
+			break
+		}
+		params.Page++
+	}
+
+	s.log.Info(ctx, "users exported", logger.Int("count", exported))
+	return nil
+}
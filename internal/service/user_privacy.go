@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"example.com/classic/internal/domain"
+	"example.com/classic/internal/service/dto"
+	"example.com/classic/internal/taskqueue"
+	"example.com/classic/pkg/contextx"
+	"example.com/classic/pkg/errors"
+	"example.com/classic/pkg/logger"
+	"example.com/classic/pkg/tracer"
+)
+
+// TaskTypeUserErase 用户个人数据擦除任务类型
+const TaskTypeUserErase = "user_erase"
+
+// erasureMaxRetry 擦除是幂等的，失败后可安全重试
+const erasureMaxRetry = 5
+
+// UserPrivacyService data-subject requests: personal-data export and erasure
+type UserPrivacyService interface {
+	// ExportPersonalData collects everything held on a user
+	ExportPersonalData(ctx context.Context, id int) (*dto.PersonalDataExport, error)
+	// RequestErasure validates the request and enqueues the erasure job
+	RequestErasure(ctx context.Context, id int) (*dto.ErasureJob, error)
+	// Erase pseudonymizes the user; erasing an erased user is a no-op
+	Erase(ctx context.Context, id int) error
+}
+
+// userPrivacyService user privacy service implementation
+type userPrivacyService struct {
+	userRepo       domain.UserRepository
+	txManager      domain.TransactionManager
+	eventStore     domain.EventStore
+	eventPublisher domain.EventPublisher
+	taskQueue      taskqueue.TaskQueue
+	sources        []domain.PersonalDataSource
+	log            logger.Logger
+}
+
+// NewUserPrivacyService creates user privacy service instance
+func NewUserPrivacyService(
+	userRepo domain.UserRepository,
+	txManager domain.TransactionManager,
+	eventStore domain.EventStore,
+	eventPublisher domain.EventPublisher,
+	taskQueue taskqueue.TaskQueue,
+	sources []domain.PersonalDataSource,
+	log logger.Logger,
+) UserPrivacyService {
+	return &userPrivacyService{
+		userRepo:       userRepo,
+		txManager:      txManager,
+		eventStore:     eventStore,
+		eventPublisher: eventPublisher,
+		taskQueue:      taskQueue,
+		sources:        sources,
+		log:            log,
+	}
+}
+
+// ExportPersonalData collects the profile and every personal data source section
+func (s *userPrivacyService) ExportPersonalData(ctx context.Context, id int) (*dto.PersonalDataExport, error) {
+	span, ctx := tracer.ServiceSpan(ctx, s.log, "ExportPersonalData")
+	defer span.End()
+
+	user, err := s.userRepo.GetByID(ctx, id)
+	if err != nil {
+		span.EndWithError(err)
+		return nil, err
+	}
+	user.ClearSensitiveData()
+
+	export := &dto.PersonalDataExport{
+		FormatVersion: dto.PersonalDataFormatVersion,
+		UserID:        user.ID(),
+		TenantID:      user.TenantID(),
+		GeneratedAt:   time.Now().UTC(),
+		Profile:       dto.UserDTOFromUser(user),
+		Sections:      make(map[string]interface{}, len(s.sources)),
+	}
+	for _, source := range s.sources {
+		data, err := source.Collect(ctx, id)
+		if err != nil {
+			span.EndWithError(err)
+			return nil, errors.WrapInternalError(err, "collect personal data section "+source.Section()+" failed")
+		}
+		export.Sections[source.Section()] = data
+	}
+
+	s.log.Info(ctx, "个人数据导出完成",
+		logger.Int("user_id", id),
+		logger.Int("sections", len(export.Sections)))
+	return export, nil
+}
+
+// RequestErasure enqueues the erasure of an existing, not yet erased user
+func (s *userPrivacyService) RequestErasure(ctx context.Context, id int) (*dto.ErasureJob, error) {
+	span, ctx := tracer.ServiceSpan(ctx, s.log, "RequestErasure")
+	defer span.End()
+
+	user, err := s.userRepo.GetByID(ctx, id)
+	if err != nil {
+		span.EndWithError(err)
+		return nil, err
+	}
+	if user.IsErased() {
+		return nil, errors.New(errors.ErrCodeConflict, "user is already erased")
+	}
+
+	data, err := json.Marshal(&dto.ErasureJobPayload{
+		TenantID: contextx.GetTenantID(ctx),
+		UserID:   id,
+	})
+	if err != nil {
+		return nil, errors.WrapInternalError(err, "marshal erasure payload failed")
+	}
+
+	info, err := s.taskQueue.Enqueue(ctx, &taskqueue.Task{Type: TaskTypeUserErase, Payload: data},
+		taskqueue.WithMaxRetry(erasureMaxRetry))
+	if err != nil {
+		span.EndWithError(err)
+		return nil, errors.WrapInternalError(err, "enqueue erasure job failed")
+	}
+
+	s.log.Info(ctx, "erasure job enqueued",
+		logger.Int("user_id", id),
+		logger.String("job_id", info.ID))
+	return &dto.ErasureJob{ID: info.ID, UserID: id}, nil
+}
+
+// Erase pseudonymizes name and email and drops credentials; the row and its ID are kept
+func (s *userPrivacyService) Erase(ctx context.Context, id int) error {
+	span, ctx := tracer.ServiceSpan(ctx, s.log, "Erase")
+	defer span.End()
+
+	var events []domain.DomainEvent
+	err := s.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		aggregate, err := s.userRepo.GetAggregateByID(txCtx, id)
+		if err != nil {
+			return err
+		}
+		// 重试时已擦除，视为成功
+		if aggregate.User().IsErased() {
+			return nil
+		}
+
+		pseudonym, email, err := domain.NewErasurePseudonym()
+		if err != nil {
+			return errors.WrapInternalError(err, "generate pseudonym failed")
+		}
+		if err := aggregate.Erase(pseudonym, email); err != nil {
+			return errors.New(errors.ErrCodeInvalidParam, err.Error())
+		}
+		if err := s.userRepo.Save(txCtx, aggregate); err != nil {
+			return err
+		}
+		// 已存储事件中的个人数据同样替换为假名
+		if err := s.eventStore.Redact(txCtx, domain.AggregateID("user", id), domain.ErasedEventFields(pseudonym, email)); err != nil {
+			return err
+		}
+
+		events = aggregate.Events()
+		return s.eventStore.Append(txCtx, events)
+	})
+	if err != nil {
+		span.EndWithError(err)
+		return err
+	}
+
+	// 事务提交后再发布事件
+	if len(events) > 0 {
+		if err := s.eventPublisher.PublishBatch(withTenant(ctx, events)); err != nil {
+			s.log.Warn(ctx, "failed to publish domain events", logger.Err(err))
+		}
+	}
+
+	s.log.Info(ctx, "用户个人数据已擦除", logger.Int("user_id", id))
+	return nil
+}
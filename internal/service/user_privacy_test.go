@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"example.com/classic/internal/data"
+	"example.com/classic/internal/domain"
+	"example.com/classic/internal/repository"
+	"example.com/classic/internal/service/dto"
+	"example.com/classic/internal/taskqueue"
+	"example.com/classic/pkg/contextx"
+	"example.com/classic/pkg/errors"
+	"example.com/classic/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// staticDataSource personal data source returning fixed data
+type staticDataSource struct {
+	section string
+	data    interface{}
+}
+
+func (s *staticDataSource) Section() string { return s.section }
+
+func (s *staticDataSource) Collect(_ context.Context, _ int) (interface{}, error) {
+	return s.data, nil
+}
+
+// newTestPrivacyService wires the privacy service to an in-memory repository holding one user
+func newTestPrivacyService(t *testing.T, queue *MockTaskQueue, publisher *MockEventPublisher) (UserPrivacyService, domain.UserRepository, domain.EventStore, context.Context, *domain.User) {
+	t.Helper()
+	log := logger.New("test", "error", false)
+	repo := repository.NewUserRepositoryMemory(nil, log)
+	store := repository.NewEventStoreMemory(log)
+	ctx := contextx.WithTenantID(context.Background(), "acme")
+
+	user := createTestUser(0, "Alice", "alice@example.com")
+	user.SetAttributes(domain.Attributes{"plan": "pro"})
+	require.NoError(t, repo.Create(ctx, user))
+
+	sources := []domain.PersonalDataSource{&staticDataSource{section: "sessions", data: []string{"s1"}}}
+	svc := NewUserPrivacyService(repo, data.NewMemoryTransactionManager(), store, publisher, queue, sources, log)
+	return svc, repo, store, ctx, user
+}
+
+func TestUserPrivacyService_ExportPersonalData(t *testing.T) {
+	svc, _, _, ctx, user := newTestPrivacyService(t, new(MockTaskQueue), new(MockEventPublisher))
+
+	export, err := svc.ExportPersonalData(ctx, user.ID())
+	require.NoError(t, err)
+	assert.Equal(t, user.ID(), export.UserID)
+	assert.Equal(t, "acme", export.TenantID)
+	assert.Equal(t, "alice@example.com", export.Profile.Email)
+	assert.Equal(t, "pro", export.Profile.Attributes["plan"])
+	assert.Equal(t, []string{"s1"}, export.Sections["sessions"])
+
+	// Credentials are never exported
+	encoded, err := json.Marshal(export)
+	require.NoError(t, err)
+	assert.NotContains(t, string(encoded), user.GetHashedPassword())
+
+	// Users of other tenants are not found
+	_, err = svc.ExportPersonalData(contextx.WithTenantID(context.Background(), "other"), user.ID())
+	assert.ErrorIs(t, err, errors.ErrUserNotFound)
+}
+
+func TestUserPrivacyService_RequestErasure(t *testing.T) {
+	queue := new(MockTaskQueue)
+	svc, _, _, ctx, user := newTestPrivacyService(t, queue, new(MockEventPublisher))
+
+	queue.On("Enqueue", mock.Anything, mock.MatchedBy(func(task *taskqueue.Task) bool {
+		var payload dto.ErasureJobPayload
+		return task.Type == TaskTypeUserErase &&
+			json.Unmarshal(task.Payload, &payload) == nil &&
+			payload.TenantID == "acme" && payload.UserID == user.ID()
+	})).Return(&taskqueue.TaskResult{ID: "job-1"}, nil)
+
+	job, err := svc.RequestErasure(ctx, user.ID())
+	require.NoError(t, err)
+	assert.Equal(t, "job-1", job.ID)
+	queue.AssertExpectations(t)
+
+	_, err = svc.RequestErasure(ctx, 999)
+	assert.ErrorIs(t, err, errors.ErrUserNotFound)
+}
+
+func TestUserPrivacyService_Erase(t *testing.T) {
+	publisher := new(MockEventPublisher)
+	svc, repo, store, ctx, user := newTestPrivacyService(t, new(MockTaskQueue), publisher)
+	require.NoError(t, store.Append(ctx, []domain.DomainEvent{domain.NewUserCreatedEvent(user.ID(), "alice@example.com", "Alice")}))
+
+	publisher.On("PublishBatch", mock.MatchedBy(func(events []domain.DomainEvent) bool {
+		if len(events) != 1 {
+			return false
+		}
+		event, ok := events[0].(*domain.UserErasedEvent)
+		return ok && event.UserID == user.ID() && event.TenantID == "acme"
+	})).Return(nil).Once()
+
+	require.NoError(t, svc.Erase(ctx, user.ID()))
+
+	erased, err := repo.GetByID(ctx, user.ID())
+	require.NoError(t, err)
+	assert.Equal(t, user.ID(), erased.ID())
+	assert.Equal(t, domain.StatusErased, erased.Status())
+	assert.True(t, strings.HasPrefix(erased.Name().String(), "erased-"))
+	assert.True(t, strings.HasSuffix(erased.Email().String(), "@"+domain.ErasedEmailDomain))
+	assert.NotContains(t, erased.Email().String(), "alice")
+	assert.NotEqual(t, user.GetHashedPassword(), erased.GetHashedPassword())
+	assert.Empty(t, erased.Attributes())
+
+	// Stored events are pseudonymized and the erasure is recorded
+	events, err := store.Load(ctx, domain.AggregateID("user", user.ID()), 0, 10)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.NotContains(t, string(events[0].Payload), "alice")
+	assert.NotContains(t, string(events[0].Payload), "Alice")
+	created, err := events[0].Decode()
+	require.NoError(t, err)
+	assert.Equal(t, erased.Email().String(), created.(*domain.UserCreatedEvent).Email)
+	assert.Equal(t, domain.EventTypeUserErased, events[1].Type)
+
+	// The original email is free again
+	exists, err := repo.ExistsByEmail(ctx, "alice@example.com")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	// Retries are no-ops and publish nothing
+	require.NoError(t, svc.Erase(ctx, user.ID()))
+	publisher.AssertExpectations(t)
+
+	// An erased user cannot be erased again or edited
+	_, err = svc.RequestErasure(ctx, user.ID())
+	var appErr *errors.Error
+	require.True(t, errors.As(err, &appErr))
+	assert.Equal(t, errors.ErrCodeConflict, appErr.Code)
+	assert.Error(t, erased.ChangeStatus(domain.StatusActive))
+}
@@ -5,6 +5,7 @@ import (
 
 	"example.com/classic/internal/domain"
 	"example.com/classic/internal/service/dto"
+	"example.com/classic/pkg/contextx"
 	"example.com/classic/pkg/errors"
 	"example.com/classic/pkg/logger"
 	"example.com/classic/pkg/tracer"
@@ -18,13 +19,17 @@ type UserService interface {
 	Delete(ctx context.Context, id int) error
 	List(ctx context.Context, query *dto.UserQueryParams) ([]*domain.User, int64, error)
 	ChangeStatus(ctx context.Context, id int, status domain.Status) error
+	Import(ctx context.Context, source dto.UserImportSource, opts dto.ImportOptions, sink dto.ImportResultSink) (*dto.ImportSummary, error)
+	Export(ctx context.Context, query *dto.UserQueryParams, fn func(user *domain.User) error) error
 }
 
 // userService user service implementation (application service layer)
 type userService struct {
 	userRepo       domain.UserRepository
 	userFactory    domain.UserFactory
+	attrSchema     *domain.AttributeSchema
 	txManager      domain.TransactionManager
+	eventStore     domain.EventStore
 	eventPublisher domain.EventPublisher
 	log            logger.Logger
 }
@@ -33,14 +38,18 @@ type userService struct {
 func NewUserService(
 	userRepo domain.UserRepository,
 	userFactory domain.UserFactory,
+	attrSchema *domain.AttributeSchema,
 	txManager domain.TransactionManager,
+	eventStore domain.EventStore,
 	eventPublisher domain.EventPublisher,
 	log logger.Logger,
 ) UserService {
 	return &userService{
 		userRepo:       userRepo,
 		userFactory:    userFactory,
+		attrSchema:     attrSchema,
 		txManager:      txManager,
+		eventStore:     eventStore,
 		eventPublisher: eventPublisher,
 		log:            log,
 	}
@@ -66,6 +75,11 @@ func (s *userService) Register(ctx context.Context, params *dto.RegisterParams)
 		if err != nil {
 			factorySpan.EndWithError(err)
 			s.log.Warn(ctx, "创建用户聚合失败", logger.Err(err))
+			return errors.WrapInvalidParam(err, err.Error())
+		}
+		// 自定义属性按 schema 校验（必填属性在注册时即需提供）
+		if err := aggregate.User().ChangeAttributes(s.attrSchema, params.Attributes); err != nil {
+			factorySpan.EndWithError(err)
 			return errors.New(errors.ErrCodeInvalidParam, err.Error())
 		}
 		factorySpan.End()
@@ -92,13 +106,19 @@ func (s *userService) Register(ctx context.Context, params *dto.RegisterParams)
 		}
 		saveSpan.End()
 
+		aggregate.MarkCreated()
 		user = aggregate.User()
 
-		// 4. Publish domain events (decoupled business logic)
+		// 4. Append domain events to the event store (same transaction)
+		if err := s.eventStore.Append(txCtx, aggregate.Events()); err != nil {
+			return err
+		}
+
+		// 5. Publish domain events (decoupled business logic)
 		// Event handlers (e.g. welcome email) are triggered via EventPublisher
 		if aggregate.HasEvents() {
 			eventSpan, _ := tracer.StartSpan(txCtx, s.log, "event:PublishBatch")
-			if err := s.eventPublisher.PublishBatch(aggregate.Events()); err != nil {
+			if err := s.eventPublisher.PublishBatch(withTenant(ctx, withLocale(ctx, aggregate.Events()))); err != nil {
 				eventSpan.EndWithError(err)
 				s.log.Warn(ctx, "failed to publish domain events", logger.Err(err))
 			} else {
@@ -166,7 +186,7 @@ func (s *userService) Update(ctx context.Context, id int, params *dto.UpdatePara
 		if params.Name != nil {
 			nameVO, err := domain.NewName(*params.Name)
 			if err != nil {
-				return nil, errors.New(errors.ErrCodeInvalidParam, err.Error())
+				return nil, errors.WrapInvalidParam(err, err.Error())
 			}
 			name = *nameVO
 		} else {
@@ -176,7 +196,7 @@ func (s *userService) Update(ctx context.Context, id int, params *dto.UpdatePara
 		if params.Email != nil {
 			emailVO, err := domain.NewEmail(*params.Email)
 			if err != nil {
-				return nil, errors.New(errors.ErrCodeInvalidParam, err.Error())
+				return nil, errors.WrapInvalidParam(err, err.Error())
 			}
 			email = *emailVO
 
@@ -193,7 +213,7 @@ func (s *userService) Update(ctx context.Context, id int, params *dto.UpdatePara
 		}
 
 		if err := aggregate.UpdateProfile(name, email); err != nil {
-			return nil, errors.WrapInternalError(err, "failed to update profile")
+			return nil, errors.New(errors.ErrCodeInvalidParam, err.Error())
 		}
 	}
 
@@ -204,14 +224,34 @@ func (s *userService) Update(ctx context.Context, id int, params *dto.UpdatePara
 		}
 	}
 
-	// 4. 持久化
-	if err := s.userRepo.Save(ctx, aggregate); err != nil {
+	// 4. 更新自定义属性（merge-patch：nil 值删除属性）
+	if params.Attributes != nil {
+		attrs := aggregate.User().Attributes()
+		if attrs == nil {
+			attrs = make(domain.Attributes, len(params.Attributes))
+		}
+		for name, value := range params.Attributes {
+			attrs[name] = value
+		}
+		if err := aggregate.UpdateAttributes(s.attrSchema, attrs); err != nil {
+			return nil, errors.New(errors.ErrCodeInvalidParam, err.Error())
+		}
+	}
+
+	// 5. 持久化（聚合与事件在同一事务中）
+	err = s.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		if err := s.userRepo.Save(txCtx, aggregate); err != nil {
+			return err
+		}
+		return s.eventStore.Append(txCtx, aggregate.Events())
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	// 5. 发布领域事件
+	// 6. 发布领域事件
 	if aggregate.HasEvents() {
-		if err := s.eventPublisher.PublishBatch(aggregate.Events()); err != nil {
+		if err := s.eventPublisher.PublishBatch(withTenant(ctx, aggregate.Events())); err != nil {
 			s.log.Warn(ctx, "failed to publish domain events", logger.Err(err))
 		}
 		aggregate.ClearEvents()
@@ -238,16 +278,28 @@ func (s *userService) Delete(ctx context.Context, id int) error {
 		return err
 	}
 
-	// 2. 检查是否可以删除（业务规则）
-	if err := aggregate.CanBeDeleted(); err != nil {
+	// 2. 检查是否可以删除（业务规则）并记录删除事件
+	if err := aggregate.MarkDeleted(); err != nil {
 		return errors.New(errors.ErrCodeInvalidParam, err.Error())
 	}
 
-	// 3. 执行删除
-	if err := s.userRepo.Delete(ctx, id); err != nil {
+	// 3. 执行删除并记录事件
+	err = s.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		if err := s.userRepo.Delete(txCtx, id); err != nil {
+			return err
+		}
+		return s.eventStore.Append(txCtx, aggregate.Events())
+	})
+	if err != nil {
 		return err
 	}
 
+	// 4. 发布领域事件
+	if err := s.eventPublisher.PublishBatch(withTenant(ctx, aggregate.Events())); err != nil {
+		s.log.Warn(ctx, "failed to publish domain events", logger.Err(err))
+	}
+	aggregate.ClearEvents()
+
 	s.log.Info(ctx, "user deleted successfully", logger.F("user_id", id))
 	return nil
 }
@@ -266,13 +318,10 @@ func (s *userService) List(ctx context.Context, query *dto.UserQueryParams) ([]*
 	s.normalizeQuery(query)
 
 	// Convert to domain params
-	params := domain.UserListParams{
-		ID:       query.ID,
-		Name:     query.Name,
-		Email:    query.Email,
-		Status:   query.Status,
-		Page:     query.Page,
-		PageSize: query.PageSize,
+	params, err := toListParams(s.attrSchema, query)
+	if err != nil {
+		span.EndWithError(err)
+		return nil, 0, err
 	}
 
 	// Query user list
@@ -313,15 +362,21 @@ func (s *userService) ChangeStatus(ctx context.Context, id int, status domain.St
 		return errors.New(errors.ErrCodeInvalidParam, err.Error())
 	}
 
-	// 3. 持久化
-	if err := s.userRepo.Save(ctx, aggregate); err != nil {
+	// 3. 持久化（聚合与事件在同一事务中）
+	err = s.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		if err := s.userRepo.Save(txCtx, aggregate); err != nil {
+			return err
+		}
+		return s.eventStore.Append(txCtx, aggregate.Events())
+	})
+	if err != nil {
 		return err
 	}
 
 	// 4. 发布领域事件（解耦业务逻辑）
 	// Event handlers (e.g. status change notification) are triggered via EventPublisher
 	if aggregate.HasEvents() {
-		if err := s.eventPublisher.PublishBatch(aggregate.Events()); err != nil {
+		if err := s.eventPublisher.PublishBatch(withTenant(ctx, withLocale(ctx, aggregate.Events()))); err != nil {
 			s.log.Warn(ctx, "failed to publish domain events", logger.F("error", err))
 		}
 		aggregate.ClearEvents()
@@ -333,6 +388,33 @@ func (s *userService) ChangeStatus(ctx context.Context, id int, status domain.St
 	return nil
 }
 
+// withLocale records the negotiated request locale on events that notify the user,
+// so their emails are rendered in the user's language
+func withLocale(ctx context.Context, events []domain.DomainEvent) []domain.DomainEvent {
+	locale := contextx.GetLocale(ctx)
+	for _, event := range events {
+		switch e := event.(type) {
+		case *domain.UserCreatedEvent:
+			e.Locale = locale
+		case *domain.UserStatusChangedEvent:
+			e.Locale = locale
+		}
+	}
+	return events
+}
+
+// withTenant sets the tenant of the request on the events, so that subscribers
+// (the SSE event stream) deliver them to that tenant only
+func withTenant(ctx context.Context, events []domain.DomainEvent) []domain.DomainEvent {
+	tenantID := contextx.GetTenantID(ctx)
+	for _, event := range events {
+		if e, ok := event.(domain.TenantEvent); ok {
+			e.SetTenant(tenantID)
+		}
+	}
+	return events
+}
+
 // normalizeQuery normalizes query parameters
 func (s *userService) normalizeQuery(query *dto.UserQueryParams) {
 	if query.Page < 1 {
@@ -341,4 +423,25 @@ func (s *userService) normalizeQuery(query *dto.UserQueryParams) {
 	if query.PageSize < 1 || query.PageSize > 100 {
 		query.PageSize = 20
 	}
-}
\ No newline at end of file
+}
+
+// toListParams converts service query params to domain list params,
+// parsing attribute filters against the schema
+func toListParams(schema *domain.AttributeSchema, query *dto.UserQueryParams) (domain.UserListParams, error) {
+	params := domain.UserListParams{
+		ID:       query.ID,
+		Name:     query.Name,
+		Email:    query.Email,
+		Status:   query.Status,
+		Page:     query.Page,
+		PageSize: query.PageSize,
+	}
+	for name, raw := range query.Attributes {
+		filter, err := schema.ParseFilter(name, raw)
+		if err != nil {
+			return params, errors.New(errors.ErrCodeInvalidParam, err.Error())
+		}
+		params.Attributes = append(params.Attributes, *filter)
+	}
+	return params, nil
+}
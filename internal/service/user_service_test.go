@@ -2,14 +2,18 @@ package service
 
 import (
 	"context"
+	"io"
 	"testing"
 	"time"
 
 	"example.com/classic/internal/domain"
+	"example.com/classic/internal/infrastructure/hashing"
 	"example.com/classic/internal/service/dto"
+	"example.com/classic/pkg/errors"
 	"example.com/classic/pkg/logger"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
 )
 
 // MockUserRepository 模拟用户仓储
@@ -150,7 +154,7 @@ func TestUserService_Register(t *testing.T) {
 			// Create service instance
 			mockEventPub := new(MockEventPublisher)
 			mockEventPub.On("PublishBatch", mock.Anything).Return(nil)
-			svc := NewUserService(mockRepo, mockFactory, mockTxManager, mockEventPub, log)
+			svc := NewUserService(mockRepo, mockFactory, nil, mockTxManager, newMockEventStore(), mockEventPub, log)
 
 			// Setup transaction manager mock - just record the call (callback is executed directly)
 			mockTxManager.On("WithTransaction", mock.Anything, mock.Anything).Once()
@@ -182,7 +186,7 @@ func TestUserService_GetByID(t *testing.T) {
 	mockTxManager := new(MockTransactionManager)
 	mockEventPub := new(MockEventPublisher)
 	log := logger.New("test", "debug", true)
-	svc := NewUserService(mockRepo, nil, mockTxManager, mockEventPub, log)
+	svc := NewUserService(mockRepo, nil, nil, mockTxManager, newMockEventStore(), mockEventPub, log)
 
 	// Setup mock behavior
 	mockRepo.On("GetByID", mock.Anything, 1).Return(createTestUser(1, "Test User", "test@example.com"), nil)
@@ -202,7 +206,7 @@ func TestUserService_Update(t *testing.T) {
 	mockTxManager := new(MockTransactionManager)
 	mockEventPub := new(MockEventPublisher)
 	log := logger.New("test", "debug", true)
-	svc := NewUserService(mockRepo, nil, mockTxManager, mockEventPub, log)
+	svc := NewUserService(mockRepo, nil, nil, mockTxManager, newMockEventStore(), mockEventPub, log)
 
 	// Update request
 	newName := "New Name"
@@ -211,6 +215,7 @@ func TestUserService_Update(t *testing.T) {
 	}
 
 	// Setup mock behavior
+	mockTxManager.On("WithTransaction", mock.Anything, mock.Anything)
 	mockRepo.On("GetAggregateByID", mock.Anything, 1).Return(createTestAggregate(1, "Old Name", "old@example.com"), nil)
 	mockRepo.On("Save", mock.Anything, mock.AnythingOfType("*domain.UserAggregate")).Return(nil)
 	mockEventPub.On("PublishBatch", mock.Anything).Return(nil)
@@ -225,6 +230,219 @@ func TestUserService_Update(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestUserService_Import(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockFactory := new(MockUserFactory)
+	mockTxManager := new(MockTransactionManager)
+	mockEventPub := new(MockEventPublisher)
+	log := logger.New("test", "debug", true)
+	svc := NewUserService(mockRepo, mockFactory, nil, mockTxManager, newMockEventStore(), mockEventPub, log)
+
+	source := &sliceImportSource{rows: []*dto.ImportRow{
+		{Line: 2, Name: "New User", Email: "new@example.com", Password: "password123"},
+		{Line: 3, Name: "Renamed", Email: "old@example.com"},
+		{Line: 4, Name: "Dup", Email: "dup@example.com", Password: "password123"},
+	}}
+
+	mockRepo.On("GetAggregateByEmail", mock.Anything, "new@example.com").Return(nil, errors.ErrUserNotFound)
+	mockRepo.On("GetAggregateByEmail", mock.Anything, "old@example.com").Return(createTestAggregate(7, "Old Name", "old@example.com"), nil)
+	mockRepo.On("GetAggregateByEmail", mock.Anything, "dup@example.com").Return(createTestAggregate(8, "Dup", "dup@example.com"), nil)
+	mockFactory.On("CreateNewUser", "New User", "new@example.com", "password123").Return(createTestAggregate(0, "New User", "new@example.com"), nil)
+
+	// Dry run: nothing is saved and no transaction is opened
+	var results []*dto.ImportRowResult
+	summary, err := svc.Import(context.Background(), source, dto.ImportOptions{DryRun: true, Upsert: false}, func(result *dto.ImportRowResult) error {
+		results = append(results, result)
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, summary.Total)
+	assert.Equal(t, 1, summary.Created)
+	assert.Equal(t, 2, summary.Failed)
+	assert.Equal(t, dto.ImportActionCreated, results[0].Action)
+	assert.Equal(t, int(errors.ErrCodeUserAlreadyExists), results[1].Code)
+	mockRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	mockTxManager.AssertNotCalled(t, "WithTransaction", mock.Anything, mock.Anything)
+
+	// Upsert: existing users are updated, unchanged rows are reported as such
+	source.pos = 0
+	results = nil
+	mockTxManager.On("WithTransaction", mock.Anything, mock.Anything)
+	mockRepo.On("Save", mock.Anything, mock.AnythingOfType("*domain.UserAggregate")).Return(nil)
+	mockEventPub.On("PublishBatch", mock.Anything).Return(nil)
+	mockFactory.On("CreateNewUser", "Dup", "dup@example.com", "password123").Return(createTestAggregate(0, "Dup", "dup@example.com"), nil)
+
+	summary, err = svc.Import(context.Background(), source, dto.ImportOptions{Upsert: true}, func(result *dto.ImportRowResult) error {
+		results = append(results, result)
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, summary.Created)
+	assert.Equal(t, 2, summary.Updated)
+	assert.Equal(t, 0, summary.Failed)
+	assert.Equal(t, 7, results[1].UserID)
+	mockRepo.AssertNumberOfCalls(t, "Save", 3)
+}
+
+func TestUserService_ImportHashedPassword(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	log := logger.New("test", "debug", true)
+	factory := domain.NewUserFactory(hashing.NewBcryptPasswordHasher())
+	svc := NewUserService(mockRepo, factory, nil, new(MockTransactionManager), newMockEventStore(), new(MockEventPublisher), log)
+
+	strong, err := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.DefaultCost)
+	assert.NoError(t, err)
+	weak, err := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	assert.NoError(t, err)
+
+	source := &sliceImportSource{rows: []*dto.ImportRow{
+		{Line: 2, Name: "Strong", Email: "strong@example.com", HashedPassword: string(strong)},
+		{Line: 3, Name: "Plain", Email: "plain@example.com", HashedPassword: "password123"},
+		{Line: 4, Name: "Weak", Email: "weak@example.com", HashedPassword: string(weak)},
+	}}
+	mockRepo.On("GetAggregateByEmail", mock.Anything, mock.Anything).Return(nil, errors.ErrUserNotFound)
+
+	var results []*dto.ImportRowResult
+	summary, err := svc.Import(context.Background(), source, dto.ImportOptions{DryRun: true}, func(result *dto.ImportRowResult) error {
+		results = append(results, result)
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, summary.Created)
+	assert.Equal(t, 2, summary.Failed)
+	assert.Equal(t, dto.ImportActionCreated, results[0].Action)
+	for _, result := range results[1:] {
+		assert.Equal(t, dto.ImportActionFailed, result.Action)
+		assert.Equal(t, int(errors.ErrCodeInvalidParam), result.Code)
+		assert.Contains(t, result.Error, "invalid password hash")
+	}
+}
+
+func TestUserService_ImportAttributes(t *testing.T) {
+	schema, err := domain.NewAttributeSchema([]domain.AttributeDefinition{
+		{Name: "plan", Type: domain.AttributeTypeString, Required: true, Enum: []string{"free", "pro"}},
+		{Name: "seats", Type: domain.AttributeTypeInteger},
+	})
+	assert.NoError(t, err)
+
+	mockRepo := new(MockUserRepository)
+	mockFactory := new(MockUserFactory)
+	log := logger.New("test", "debug", true)
+	svc := NewUserService(mockRepo, mockFactory, schema, new(MockTransactionManager), newMockEventStore(), new(MockEventPublisher), log)
+
+	existing := createTestAggregate(7, "Old", "old@example.com")
+	existing.User().SetAttributes(domain.Attributes{"plan": "free"})
+	mockRepo.On("GetAggregateByEmail", mock.Anything, "old@example.com").Return(existing, nil)
+	mockRepo.On("GetAggregateByEmail", mock.Anything, mock.Anything).Return(nil, errors.ErrUserNotFound)
+	mockFactory.On("CreateNewUser", "Pro", "pro@example.com", "password123").Return(createTestAggregate(0, "Pro", "pro@example.com"), nil)
+	mockFactory.On("CreateNewUser", "Bare", "bare@example.com", "password123").Return(createTestAggregate(0, "Bare", "bare@example.com"), nil)
+
+	source := &sliceImportSource{rows: []*dto.ImportRow{
+		{Line: 2, Name: "Pro", Email: "pro@example.com", Password: "password123", Attributes: map[string]interface{}{"plan": "pro", "seats": float64(3)}},
+		{Line: 3, Name: "Bare", Email: "bare@example.com", Password: "password123"},
+		{Line: 4, Name: "Old", Email: "old@example.com", Attributes: map[string]interface{}{"plan": nil}},
+		{Line: 5, Name: "Old", Email: "old@example.com", Attributes: map[string]interface{}{"plan": "free"}},
+		{Line: 6, Name: "Old", Email: "old@example.com", Attributes: map[string]interface{}{"seats": float64(2)}},
+	}}
+
+	var results []*dto.ImportRowResult
+	summary, err := svc.Import(context.Background(), source, dto.ImportOptions{DryRun: true, Upsert: true}, func(result *dto.ImportRowResult) error {
+		results = append(results, result)
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, summary.Created)
+	assert.Equal(t, 1, summary.Updated)
+	assert.Equal(t, 2, summary.Failed)
+	assert.Equal(t, dto.ImportActionCreated, results[0].Action)
+
+	// A missing required attribute fails the row, both on insert and when the upsert drops it
+	for _, result := range results[1:3] {
+		assert.Equal(t, dto.ImportActionFailed, result.Action)
+		assert.Equal(t, int(errors.ErrCodeInvalidParam), result.Code)
+		assert.Contains(t, result.Error, "attribute plan is required")
+	}
+
+	// Upserts merge-patch the existing attributes
+	assert.Equal(t, dto.ImportActionUnchanged, results[3].Action)
+	assert.Equal(t, dto.ImportActionUpdated, results[4].Action)
+	assert.Equal(t, domain.Attributes{"plan": "free", "seats": int64(2)}, existing.User().Attributes())
+}
+
+func TestUserService_Attributes(t *testing.T) {
+	schema, err := domain.NewAttributeSchema([]domain.AttributeDefinition{
+		{Name: "plan", Type: domain.AttributeTypeString, Enum: []string{"free", "pro"}},
+		{Name: "seats", Type: domain.AttributeTypeInteger},
+		{Name: "department", Type: domain.AttributeTypeString, Pattern: `^[a-z]+$`},
+	})
+	assert.NoError(t, err)
+
+	mockRepo := new(MockUserRepository)
+	mockTxManager := new(MockTransactionManager)
+	mockEventPub := new(MockEventPublisher)
+	log := logger.New("test", "debug", true)
+	svc := NewUserService(mockRepo, nil, schema, mockTxManager, newMockEventStore(), mockEventPub, log)
+
+	existing := createTestAggregate(1, "Test User", "test@example.com")
+	existing.User().SetAttributes(domain.Attributes{"plan": "free", "department": "sales"})
+	mockTxManager.On("WithTransaction", mock.Anything, mock.Anything)
+	mockRepo.On("GetAggregateByID", mock.Anything, 1).Return(existing, nil)
+	mockRepo.On("Save", mock.Anything, mock.AnythingOfType("*domain.UserAggregate")).Return(nil)
+	mockEventPub.On("PublishBatch", mock.Anything).Return(nil)
+
+	// Merge patch: keys are overwritten, null removes, JSON numbers become integers
+	user, err := svc.Update(context.Background(), 1, &dto.UpdateParams{
+		Attributes: map[string]interface{}{"plan": "pro", "seats": float64(5), "department": nil},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, domain.Attributes{"plan": "pro", "seats": int64(5)}, user.Attributes())
+
+	// Enum, pattern, type and unknown attributes are rejected by the domain
+	for _, attrs := range []map[string]interface{}{
+		{"plan": "gold"},
+		{"department": "R&D"},
+		{"seats": 1.5},
+		{"unknown": "x"},
+	} {
+		_, err := svc.Update(context.Background(), 1, &dto.UpdateParams{Attributes: attrs})
+		var appErr *errors.Error
+		assert.True(t, errors.As(err, &appErr), "attrs %v", attrs)
+		assert.Equal(t, errors.ErrCodeInvalidParam, appErr.Code)
+	}
+
+	// List filters are parsed with the attribute type
+	mockRepo.On("List", mock.Anything, mock.MatchedBy(func(params domain.UserListParams) bool {
+		return len(params.Attributes) == 1 && params.Attributes[0].Value == int64(5)
+	})).Return([]*domain.User{user}, int64(1), nil)
+
+	users, total, err := svc.List(context.Background(), &dto.UserQueryParams{Attributes: map[string]string{"seats": "5"}})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Len(t, users, 1)
+
+	_, _, err = svc.List(context.Background(), &dto.UserQueryParams{Attributes: map[string]string{"seats": "many"}})
+	assert.Error(t, err)
+}
+
+// sliceImportSource feeds import rows from memory
+type sliceImportSource struct {
+	rows []*dto.ImportRow
+	pos  int
+}
+
+func (s *sliceImportSource) Next(ctx context.Context) (*dto.ImportRow, error) {
+	if s.pos >= len(s.rows) {
+		return nil, io.EOF
+	}
+	row := s.rows[s.pos]
+	s.pos++
+	return row, nil
+}
+
 // Helper functions
 func stringPtr(s string) *string {
 	return &s
@@ -245,6 +463,44 @@ func (m *MockEventPublisher) PublishBatch(events []domain.DomainEvent) error {
 	return args.Error(0)
 }
 
+// MockEventStore mock event store
+type MockEventStore struct {
+	mock.Mock
+}
+
+// newMockEventStore returns an event store mock accepting every append
+func newMockEventStore() *MockEventStore {
+	store := new(MockEventStore)
+	store.On("Append", mock.Anything, mock.Anything).Return(nil)
+	return store
+}
+
+func (m *MockEventStore) Append(ctx context.Context, events []domain.DomainEvent) error {
+	args := m.Called(ctx, events)
+	return args.Error(0)
+}
+
+func (m *MockEventStore) Load(ctx context.Context, aggregateID string, afterSequence, limit int) ([]*domain.StoredEvent, error) {
+	args := m.Called(ctx, aggregateID, afterSequence, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.StoredEvent), args.Error(1)
+}
+
+func (m *MockEventStore) ReadAll(ctx context.Context, afterPosition int64, limit int) ([]*domain.StoredEvent, error) {
+	args := m.Called(ctx, afterPosition, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.StoredEvent), args.Error(1)
+}
+
+func (m *MockEventStore) Redact(ctx context.Context, aggregateID string, fields map[string]interface{}) error {
+	args := m.Called(ctx, aggregateID, fields)
+	return args.Error(0)
+}
+
 // MockUserFactory mock user factory
 func NewMockUserFactory() *MockUserFactory {
 	return &MockUserFactory{}
@@ -262,6 +518,14 @@ func (m *MockUserFactory) CreateNewUser(name, email, password string) (*domain.U
 	return args.Get(0).(*domain.UserAggregate), args.Error(1)
 }
 
+func (m *MockUserFactory) CreateNewUserWithHashedPassword(name, email, hashedPassword string) (*domain.UserAggregate, error) {
+	args := m.Called(name, email, hashedPassword)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.UserAggregate), args.Error(1)
+}
+
 // createTestUser creates a test user entity
 func createTestUser(id int, name, email string) *domain.User {
 	nameVO, _ := domain.NewName(name)
@@ -5,15 +5,26 @@ package taskqueue
 
 import (
 	"context"
+	"errors"
+	"io"
 	"time"
 )
 
+// ErrTaskNotFound is returned by GetTaskInfo when the task does not exist
+// (never enqueued, or already removed after its retention period).
+var ErrTaskNotFound = errors.New("task not found")
+
 // Task represents a unit of work to be processed asynchronously.
 type Task struct {
 	// Type identifies the kind of task (e.g., "email:welcome", "cleanup:data")
 	Type string
 	// Payload contains the task data as bytes
 	Payload []byte
+	// ID is the task identifier; set only while the task is being processed
+	ID string
+	// ResultWriter stores the task result (e.g. progress of a long-running job).
+	// Each write replaces the previous result. Nil when not supported by the backend.
+	ResultWriter io.Writer
 }
 
 // TaskResult contains information about a queued task.
@@ -26,6 +37,33 @@ type TaskResult struct {
 	Type string
 }
 
+// TaskState describes where a task is in its lifecycle.
+type TaskState string
+
+const (
+	TaskStatePending   TaskState = "pending"
+	TaskStateActive    TaskState = "active"
+	TaskStateRetry     TaskState = "retry"
+	TaskStateCompleted TaskState = "completed"
+	TaskStateFailed    TaskState = "failed"
+)
+
+// TaskInfo describes the current state of a queued task.
+type TaskInfo struct {
+	ID    string
+	Queue string
+	Type  string
+	State TaskState
+	// Payload is the payload the task was enqueued with
+	Payload []byte
+	// Result holds the latest data written through Task.ResultWriter
+	Result []byte
+	// LastErr is the error message of the last failed attempt
+	LastErr string
+	// CompletedAt is zero until the task completes
+	CompletedAt time.Time
+}
+
 // Handler processes tasks of a specific type.
 type Handler interface {
 	// Process handles the task and returns an error if processing failed.
@@ -52,6 +90,9 @@ type TaskQueue interface {
 	// EnqueueAt adds a task to the queue for processing at the specified time.
 	EnqueueAt(ctx context.Context, task *Task, processAt time.Time, opts ...Option) (*TaskResult, error)
 
+	// GetTaskInfo returns the current state of a task. Returns ErrTaskNotFound if it does not exist.
+	GetTaskInfo(ctx context.Context, queue, id string) (*TaskInfo, error)
+
 	// RegisterHandler registers a handler for a specific task type.
 	RegisterHandler(taskType string, handler Handler) error
 
@@ -79,6 +120,8 @@ type EnqueueOptions struct {
 	Unique bool
 	// TaskID specifies a custom task ID
 	TaskID string
+	// Retention keeps completed tasks (and their results) inspectable for this long
+	Retention time.Duration
 }
 
 // WithQueue sets the queue name.
@@ -123,6 +166,13 @@ func WithTaskID(id string) Option {
 	}
 }
 
+// WithRetention keeps the completed task around for the given duration.
+func WithRetention(retention time.Duration) Option {
+	return func(o *EnqueueOptions) {
+		o.Retention = retention
+	}
+}
+
 // DefaultEnqueueOptions returns the default enqueue options.
 func DefaultEnqueueOptions() *EnqueueOptions {
 	return &EnqueueOptions{
@@ -0,0 +1,179 @@
+// Package tenancy resolves the tenant of an incoming request.
+// The HTTP and gRPC pipelines both use the Resolver and carry the result in contextx.
+package tenancy
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"example.com/classic/internal/config"
+	"example.com/classic/internal/domain"
+	"example.com/classic/pkg/errors"
+)
+
+// Source is the tenant-related request data of one request
+type Source struct {
+	// Authorization is the raw Authorization header ("Bearer <jwt>")
+	Authorization string
+	// Header is the value of the configured tenant header
+	Header string
+	// Host is the request host, possibly with a port
+	Host string
+}
+
+// Resolver resolves and validates the tenant of a request
+type Resolver struct {
+	cfg     config.TenancyConfig
+	tenants domain.TenantRepository
+	now     func() time.Time
+
+	mu    sync.RWMutex
+	cache map[string]cachedTenant
+}
+
+type cachedTenant struct {
+	tenant    *domain.Tenant
+	expiresAt time.Time
+}
+
+// NewResolver creates a tenant resolver
+func NewResolver(cfg *config.Config, tenants domain.TenantRepository) *Resolver {
+	return &Resolver{
+		cfg:     cfg.Tenancy,
+		tenants: tenants,
+		now:     time.Now,
+		cache:   make(map[string]cachedTenant),
+	}
+}
+
+// HeaderName returns the configured tenant header
+func (r *Resolver) HeaderName() string {
+	return r.cfg.Header
+}
+
+// Resolve returns the ID of the active tenant the request belongs to.
+// Order: JWT claim, tenant header, subdomain, default tenant. When the token
+// carries a tenant, an explicit header must agree with it. With require_jwt the
+// header and subdomain are ignored, since any client can set them.
+func (r *Resolver) Resolve(ctx context.Context, src Source) (string, error) {
+	tokenTenant, err := r.fromToken(src.Authorization)
+	if err != nil {
+		return "", err
+	}
+
+	var tenantID string
+	switch {
+	case tokenTenant != "":
+		if src.Header != "" && src.Header != tokenTenant {
+			return "", errors.New(errors.ErrCodeForbidden, "tenant header does not match token")
+		}
+		tenantID = tokenTenant
+	case r.cfg.RequireJWT:
+	case src.Header != "":
+		tenantID = src.Header
+	default:
+		tenantID = r.fromHost(src.Host)
+	}
+	if tenantID == "" {
+		tenantID = r.cfg.DefaultTenant
+	}
+	if tenantID == "" {
+		return "", errors.ErrTenantRequired
+	}
+	if err := domain.ValidateTenantID(tenantID); err != nil {
+		return "", errors.ErrTenantNotFound
+	}
+
+	tenant, err := r.lookup(ctx, tenantID)
+	if err != nil {
+		return "", err
+	}
+	if !tenant.IsActive() {
+		return "", errors.ErrTenantInactive
+	}
+	return tenant.ID(), nil
+}
+
+// fromToken extracts the tenant claim from a bearer token; JWT is ignored without a secret
+func (r *Resolver) fromToken(authorization string) (string, error) {
+	if r.cfg.JWTSecret == "" || authorization == "" {
+		return "", nil
+	}
+	token, ok := strings.CutPrefix(authorization, "Bearer ")
+	if !ok {
+		return "", nil
+	}
+
+	claims, err := verifyJWT(strings.TrimSpace(token), []byte(r.cfg.JWTSecret), r.now())
+	if err != nil {
+		return "", errors.Wrap(err, errors.ErrCodeUnauthorized, "invalid token")
+	}
+	tenantID, _ := claims[r.cfg.JWTClaim].(string)
+	return tenantID, nil
+}
+
+// Subject returns the "sub" claim of a valid bearer token, or "" when the request is not authenticated
+func (r *Resolver) Subject(authorization string) string {
+	if r.cfg.JWTSecret == "" || authorization == "" {
+		return ""
+	}
+	token, ok := strings.CutPrefix(authorization, "Bearer ")
+	if !ok {
+		return ""
+	}
+	claims, err := verifyJWT(strings.TrimSpace(token), []byte(r.cfg.JWTSecret), r.now())
+	if err != nil {
+		return ""
+	}
+	subject, _ := claims["sub"].(string)
+	return subject
+}
+
+// fromHost returns the subdomain directly below the configured base domain
+func (r *Resolver) fromHost(host string) string {
+	if r.cfg.BaseDomain == "" || host == "" {
+		return ""
+	}
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	sub, ok := strings.CutSuffix(strings.ToLower(host), "."+strings.ToLower(r.cfg.BaseDomain))
+	if !ok || sub == "" || strings.Contains(sub, ".") {
+		return ""
+	}
+	return sub
+}
+
+// lookup loads a tenant, caching it for the configured TTL
+func (r *Resolver) lookup(ctx context.Context, id string) (*domain.Tenant, error) {
+	now := r.now()
+
+	r.mu.RLock()
+	entry, ok := r.cache[id]
+	r.mu.RUnlock()
+	if ok && now.Before(entry.expiresAt) {
+		return entry.tenant, nil
+	}
+
+	tenant, err := r.tenants.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.cfg.CacheTTL > 0 {
+		r.mu.Lock()
+		r.cache[id] = cachedTenant{tenant: tenant, expiresAt: now.Add(r.cfg.CacheTTL)}
+		r.mu.Unlock()
+	}
+	return tenant, nil
+}
+
+// Invalidate drops a cached tenant so status changes apply immediately on this instance
+func (r *Resolver) Invalidate(id string) {
+	r.mu.Lock()
+	delete(r.cache, id)
+	r.mu.Unlock()
+}
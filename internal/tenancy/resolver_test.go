@@ -0,0 +1,177 @@
+package tenancy
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"example.com/classic/internal/config"
+	"example.com/classic/internal/domain"
+	"example.com/classic/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTenantRepository in-memory tenant repository for resolver tests
+type fakeTenantRepository struct {
+	domain.TenantRepository
+	tenants map[string]*domain.Tenant
+	gets    int
+}
+
+func (f *fakeTenantRepository) GetByID(_ context.Context, id string) (*domain.Tenant, error) {
+	f.gets++
+	if tenant, ok := f.tenants[id]; ok {
+		return tenant, nil
+	}
+	return nil, errors.ErrTenantNotFound
+}
+
+func newTestResolver(t *testing.T) (*Resolver, *fakeTenantRepository) {
+	t.Helper()
+	now := time.Now()
+	repo := &fakeTenantRepository{tenants: map[string]*domain.Tenant{
+		"default": domain.RebuildTenant("default", "Default", domain.TenantStatusActive, now, now),
+		"acme":    domain.RebuildTenant("acme", "Acme", domain.TenantStatusActive, now, now),
+		"globex":  domain.RebuildTenant("globex", "Globex", domain.TenantStatusActive, now, now),
+		"closed":  domain.RebuildTenant("closed", "Closed", domain.TenantStatusSuspended, now, now),
+	}}
+	cfg := &config.Config{Tenancy: config.TenancyConfig{
+		DefaultTenant: "default",
+		Header:        "X-Tenant-ID",
+		BaseDomain:    "example.com",
+		JWTSecret:     "secret",
+		JWTClaim:      "tenant_id",
+		CacheTTL:      time.Minute,
+	}}
+	return NewResolver(cfg, repo), repo
+}
+
+func signToken(t *testing.T, secret string, claims map[string]interface{}) string {
+	t.Helper()
+	header, _ := json.Marshal(map[string]string{"alg": "HS256", "typ": "JWT"})
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(signingInput))
+	return "Bearer " + signingInput + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func errorCode(err error) errors.ErrorCode {
+	var bizErr *errors.Error
+	if errors.As(err, &bizErr) {
+		return bizErr.Code
+	}
+	return errors.ErrCodeInternalError
+}
+
+func TestResolver_Resolve(t *testing.T) {
+	resolver, _ := newTestResolver(t)
+	ctx := context.Background()
+	acmeToken := signToken(t, "secret", map[string]interface{}{"tenant_id": "acme", "exp": time.Now().Add(time.Hour).Unix()})
+
+	tests := []struct {
+		name     string
+		src      Source
+		expected string
+		code     errors.ErrorCode
+	}{
+		{name: "jwt claim", src: Source{Authorization: acmeToken}, expected: "acme"},
+		{name: "jwt claim wins over subdomain", src: Source{Authorization: acmeToken, Host: "globex.example.com"}, expected: "acme"},
+		{name: "matching header", src: Source{Authorization: acmeToken, Header: "acme"}, expected: "acme"},
+		{name: "header", src: Source{Header: "globex"}, expected: "globex"},
+		{name: "subdomain", src: Source{Host: "globex.example.com:8080"}, expected: "globex"},
+		{name: "nested subdomain is ignored", src: Source{Host: "a.globex.example.com"}, expected: "default"},
+		{name: "default tenant", src: Source{}, expected: "default"},
+		{name: "header mismatch", src: Source{Authorization: acmeToken, Header: "globex"}, code: errors.ErrCodeForbidden},
+		{name: "bad signature", src: Source{Authorization: signToken(t, "other", map[string]interface{}{"tenant_id": "acme"})}, code: errors.ErrCodeUnauthorized},
+		{name: "expired token", src: Source{Authorization: signToken(t, "secret", map[string]interface{}{"tenant_id": "acme", "exp": time.Now().Add(-time.Minute).Unix()})}, code: errors.ErrCodeUnauthorized},
+		{name: "unknown tenant", src: Source{Header: "initech"}, code: errors.ErrCodeTenantNotFound},
+		{name: "invalid tenant id", src: Source{Header: "Not A Tenant"}, code: errors.ErrCodeTenantNotFound},
+		{name: "suspended tenant", src: Source{Header: "closed"}, code: errors.ErrCodeTenantInactive},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tenantID, err := resolver.Resolve(ctx, tt.src)
+			if tt.code != 0 {
+				assert.Error(t, err)
+				assert.Equal(t, tt.code, errorCode(err))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, tenantID)
+		})
+	}
+}
+
+func TestResolver_RequireJWT(t *testing.T) {
+	resolver, _ := newTestResolver(t)
+	resolver.cfg.RequireJWT = true
+	ctx := context.Background()
+	acmeToken := signToken(t, "secret", map[string]interface{}{"tenant_id": "acme"})
+
+	// Header and subdomain are ignored without a verified token
+	for _, src := range []Source{{Header: "globex"}, {Host: "globex.example.com"}} {
+		tenantID, err := resolver.Resolve(ctx, src)
+		assert.NoError(t, err)
+		assert.Equal(t, "default", tenantID)
+	}
+
+	tenantID, err := resolver.Resolve(ctx, Source{Authorization: acmeToken, Host: "globex.example.com"})
+	assert.NoError(t, err)
+	assert.Equal(t, "acme", tenantID)
+
+	_, err = resolver.Resolve(ctx, Source{Authorization: acmeToken, Header: "globex"})
+	assert.Equal(t, errors.ErrCodeForbidden, errorCode(err))
+
+	// Without a default tenant, unauthenticated requests are rejected
+	resolver.cfg.DefaultTenant = ""
+	_, err = resolver.Resolve(ctx, Source{Header: "globex"})
+	assert.Equal(t, errors.ErrCodeTenantRequired, errorCode(err))
+}
+
+func TestResolver_RequireJWTWithoutSecret(t *testing.T) {
+	resolver, _ := newTestResolver(t)
+	resolver.cfg.RequireJWT = true
+	resolver.cfg.JWTSecret = ""
+
+	// Single-tenant deployment: nothing selects another tenant
+	for _, src := range []Source{{Header: "globex"}, {Host: "globex.example.com"}, {Authorization: signToken(t, "secret", map[string]interface{}{"tenant_id": "acme"})}} {
+		tenantID, err := resolver.Resolve(context.Background(), src)
+		assert.NoError(t, err)
+		assert.Equal(t, "default", tenantID)
+	}
+}
+
+func TestResolver_Cache(t *testing.T) {
+	resolver, repo := newTestResolver(t)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		_, err := resolver.Resolve(ctx, Source{Header: "acme"})
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 1, repo.gets)
+
+	// 状态变更后失效缓存立即生效
+	require.NoError(t, repo.tenants["acme"].ChangeStatus(domain.TenantStatusSuspended))
+	resolver.Invalidate("acme")
+	_, err := resolver.Resolve(ctx, Source{Header: "acme"})
+	assert.Equal(t, errors.ErrCodeTenantInactive, errorCode(err))
+	assert.Equal(t, 2, repo.gets)
+}
+
+func TestResolver_Subject(t *testing.T) {
+	resolver, _ := newTestResolver(t)
+
+	assert.Equal(t, "user-42", resolver.Subject(signToken(t, "secret", map[string]interface{}{"sub": "user-42"})))
+	assert.Empty(t, resolver.Subject(signToken(t, "wrong", map[string]interface{}{"sub": "user-42"})))
+	assert.Empty(t, resolver.Subject(signToken(t, "secret", map[string]interface{}{"tenant_id": "acme"})))
+	assert.Empty(t, resolver.Subject(""))
+}
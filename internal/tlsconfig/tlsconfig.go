@@ -0,0 +1,220 @@
+// Package tlsconfig builds the server-side TLS configuration of the HTTP and gRPC servers.
+// The certificate, the private key and the client CA bundle are read from disk and reloaded
+// when the files change, so rotated certificates are picked up without a restart; a reload
+// that fails keeps serving the previous certificates.
+package tlsconfig
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"example.com/classic/internal/config"
+	"example.com/classic/pkg/logger"
+	"github.com/fsnotify/fsnotify"
+)
+
+// reloadDelay 合并一次证书更新产生的多个文件事件 (证书与私钥通常先后写入)
+const reloadDelay = 100 * time.Millisecond
+
+// Reloader holds the certificates loaded last and reloads them when the files change
+type Reloader struct {
+	cfg        config.TLSConfig
+	minVersion uint16
+	log        logger.Logger
+	current    atomic.Pointer[certificates]
+	watcher    *fsnotify.Watcher
+}
+
+// certificates 一次加载的证书与客户端 CA
+type certificates struct {
+	cert      *tls.Certificate
+	clientCAs *x509.CertPool // nil 表示不校验客户端证书
+}
+
+// NewReloader loads the configured certificates and watches their files.
+// It returns nil when TLS is disabled; Close stops watching.
+func NewReloader(cfg config.TLSConfig, log logger.Logger) (*Reloader, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+
+	r := &Reloader{cfg: cfg, minVersion: tls.VersionTLS12, log: log}
+	if cfg.MinVersion == "1.3" {
+		r.minVersion = tls.VersionTLS13
+	}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("tls: watch certificates: %w", err)
+	}
+	// 监听所在目录而不是文件本身：原子替换 (rename、Kubernetes Secret 的 ..data 符号链接) 会使文件监听失效
+	for _, dir := range r.dirs() {
+		if err := watcher.Add(dir); err != nil {
+			_ = watcher.Close()
+			return nil, fmt.Errorf("tls: watch %s: %w", dir, err)
+		}
+	}
+	r.watcher = watcher
+	go r.watch()
+	return r, nil
+}
+
+// MutualTLS reports whether clients must present a certificate signed by the client CA
+func (r *Reloader) MutualTLS() bool {
+	return r.cfg.ClientCAFile != ""
+}
+
+// Reload reads the certificate, the key and the client CA bundle again.
+// The previous certificates stay in use when an error is returned.
+func (r *Reloader) Reload() error {
+	cert, err := tls.LoadX509KeyPair(r.cfg.CertFile, r.cfg.KeyFile)
+	if err != nil {
+		return fmt.Errorf("tls: load key pair: %w", err)
+	}
+	next := &certificates{cert: &cert}
+
+	if r.cfg.ClientCAFile != "" {
+		data, err := os.ReadFile(r.cfg.ClientCAFile)
+		if err != nil {
+			return fmt.Errorf("tls: read client ca: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return fmt.Errorf("tls: no certificate found in %s", r.cfg.ClientCAFile)
+		}
+		next.clientCAs = pool
+	}
+
+	r.current.Store(next)
+	return nil
+}
+
+// ServerConfig returns the TLS config of a server offering nextProtos through ALPN.
+// Every handshake uses the certificates loaded last.
+func (r *Reloader) ServerConfig(nextProtos ...string) *tls.Config {
+	return &tls.Config{
+		MinVersion: r.minVersion,
+		NextProtos: nextProtos,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			current := r.current.Load()
+			cfg := &tls.Config{
+				MinVersion:   r.minVersion,
+				NextProtos:   nextProtos,
+				Certificates: []tls.Certificate{*current.cert},
+			}
+			if current.clientCAs != nil {
+				cfg.ClientAuth = tls.RequireAndVerifyClientCert
+				cfg.ClientCAs = current.clientCAs
+			}
+			return cfg, nil
+		},
+	}
+}
+
+// Close stops watching the certificate files
+func (r *Reloader) Close() error {
+	if r == nil || r.watcher == nil {
+		return nil
+	}
+	return r.watcher.Close()
+}
+
+// PeerIdentity returns the identity of a verified client certificate: its SPIFFE ID, else its
+// first URI SAN, else its subject common name. It is empty when no client certificate was verified.
+func PeerIdentity(state *tls.ConnectionState) string {
+	if state == nil || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return ""
+	}
+	leaf := state.VerifiedChains[0][0]
+	for _, uri := range leaf.URIs {
+		if uri.Scheme == "spiffe" {
+			return uri.String()
+		}
+	}
+	if len(leaf.URIs) > 0 {
+		return leaf.URIs[0].String()
+	}
+	return leaf.Subject.CommonName
+}
+
+// files 需要监听的文件
+func (r *Reloader) files() []string {
+	files := []string{r.cfg.CertFile, r.cfg.KeyFile}
+	if r.cfg.ClientCAFile != "" {
+		files = append(files, r.cfg.ClientCAFile)
+	}
+	for i, file := range files {
+		files[i] = filepath.Clean(file)
+	}
+	return files
+}
+
+// dirs 文件所在目录 (去重)
+func (r *Reloader) dirs() []string {
+	seen := make(map[string]bool)
+	var dirs []string
+	for _, file := range r.files() {
+		if dir := filepath.Dir(file); !seen[dir] {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// affects 文件事件是否可能改变证书：监听的文件本身，或 Kubernetes 原子更新使用的 ..data 等条目
+func (r *Reloader) affects(name string) bool {
+	name = filepath.Clean(name)
+	if strings.HasPrefix(filepath.Base(name), "..") {
+		return true
+	}
+	for _, file := range r.files() {
+		if name == file {
+			return true
+		}
+	}
+	return false
+}
+
+// watch 文件变更后 (合并 reloadDelay 内的事件) 重新加载证书，直到 Close
+func (r *Reloader) watch() {
+	ctx := context.Background()
+	timer := time.NewTimer(reloadDelay)
+	timer.Stop()
+
+	for {
+		select {
+		case event, ok := <-r.watcher.Events:
+			if !ok {
+				timer.Stop()
+				return
+			}
+			if event.Has(fsnotify.Chmod) || !r.affects(event.Name) {
+				continue
+			}
+			timer.Reset(reloadDelay)
+		case err, ok := <-r.watcher.Errors:
+			if !ok {
+				timer.Stop()
+				return
+			}
+			r.log.Warn(ctx, "tls certificate watcher error", logger.Err(err))
+		case <-timer.C:
+			if err := r.Reload(); err != nil {
+				r.log.Warn(ctx, "tls certificate reload failed, keeping the previous certificates", logger.Err(err))
+				continue
+			}
+			r.log.Info(ctx, "tls certificates reloaded", logger.String("cert_file", r.cfg.CertFile))
+		}
+	}
+}
@@ -0,0 +1,250 @@
+package tlsconfig
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"io"
+	"math/big"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"example.com/classic/internal/config"
+	"example.com/classic/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testCA a certificate authority generated for the test
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue signs a leaf certificate; it returns the PEM certificate and key
+func (ca *testCA) issue(t *testing.T, serial int64, tmpl *x509.Certificate) ([]byte, []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl.SerialNumber = big.NewInt(serial)
+	tmpl.NotBefore = time.Now().Add(-time.Hour)
+	tmpl.NotAfter = time.Now().Add(time.Hour)
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func (ca *testCA) serverCert(t *testing.T, serial int64) ([]byte, []byte) {
+	return ca.issue(t, serial, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "localhost"},
+		DNSNames:    []string{"localhost"},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	})
+}
+
+func (ca *testCA) clientCert(t *testing.T, tmpl *x509.Certificate) tls.Certificate {
+	tmpl.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+	certPEM, keyPEM := ca.issue(t, 100, tmpl)
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	require.NoError(t, err)
+	return cert
+}
+
+func writeFile(t *testing.T, path string, data []byte) {
+	t.Helper()
+	// Write then rename, the way certificate managers replace files
+	tmp := path + ".tmp"
+	require.NoError(t, os.WriteFile(tmp, data, 0o600))
+	require.NoError(t, os.Rename(tmp, path))
+}
+
+// handshake result seen by the server
+type handshake struct {
+	identity string
+	err      error
+}
+
+// serve accepts TLS connections and reports each server handshake
+func serve(t *testing.T, cfg *tls.Config) (string, <-chan handshake) {
+	t.Helper()
+	lis, err := tls.Listen("tcp", "127.0.0.1:0", cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = lis.Close() })
+
+	results := make(chan handshake, 16)
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			tlsConn := conn.(*tls.Conn)
+			err = tlsConn.Handshake()
+			state := tlsConn.ConnectionState()
+			results <- handshake{identity: PeerIdentity(&state), err: err}
+			_ = conn.Close()
+		}
+	}()
+	return lis.Addr().String(), results
+}
+
+// dial completes a handshake as a client and returns the serial number of the server certificate
+func dial(addr string, roots *x509.CertPool, certs ...tls.Certificate) (int64, error) {
+	conn, err := tls.DialWithDialer(&net.Dialer{Timeout: time.Second}, "tcp", addr, &tls.Config{
+		ServerName:   "localhost",
+		RootCAs:      roots,
+		Certificates: certs,
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+	// TLS 1.3 clients finish before the server checks their certificate; wait for the server's verdict
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	if _, err := conn.Read(make([]byte, 1)); err != nil && !errors.Is(err, io.EOF) {
+		return 0, err
+	}
+	return conn.ConnectionState().PeerCertificates[0].SerialNumber.Int64(), nil
+}
+
+func TestNewReloader_Disabled(t *testing.T) {
+	r, err := NewReloader(config.TLSConfig{}, logger.New("test", "error", false))
+	require.NoError(t, err)
+	assert.Nil(t, r)
+	assert.NoError(t, r.Close())
+}
+
+func TestReloader_ReloadsOnChange(t *testing.T) {
+	ca := newTestCA(t)
+	dir := t.TempDir()
+	cfg := config.TLSConfig{
+		Enabled:  true,
+		CertFile: filepath.Join(dir, "tls.crt"),
+		KeyFile:  filepath.Join(dir, "tls.key"),
+	}
+	certPEM, keyPEM := ca.serverCert(t, 1)
+	writeFile(t, cfg.CertFile, certPEM)
+	writeFile(t, cfg.KeyFile, keyPEM)
+
+	r, err := NewReloader(cfg, logger.New("test", "error", false))
+	require.NoError(t, err)
+	defer r.Close()
+	assert.False(t, r.MutualTLS())
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	addr, _ := serve(t, r.ServerConfig("http/1.1"))
+
+	serial, err := dial(addr, roots)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), serial)
+
+	// Rotated certificates are served without a restart
+	certPEM, keyPEM = ca.serverCert(t, 2)
+	writeFile(t, cfg.KeyFile, keyPEM)
+	writeFile(t, cfg.CertFile, certPEM)
+	assert.Eventually(t, func() bool {
+		serial, err := dial(addr, roots)
+		return err == nil && serial == 2
+	}, 5*time.Second, 20*time.Millisecond)
+
+	// A broken update keeps the previous certificates
+	writeFile(t, cfg.CertFile, []byte("not a certificate"))
+	assert.Error(t, r.Reload())
+	serial, err = dial(addr, roots)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), serial)
+}
+
+func TestReloader_MutualTLS(t *testing.T) {
+	ca := newTestCA(t)
+	dir := t.TempDir()
+	cfg := config.TLSConfig{
+		Enabled:      true,
+		CertFile:     filepath.Join(dir, "tls.crt"),
+		KeyFile:      filepath.Join(dir, "tls.key"),
+		MinVersion:   "1.3",
+		ClientCAFile: filepath.Join(dir, "ca.crt"),
+	}
+	certPEM, keyPEM := ca.serverCert(t, 1)
+	writeFile(t, cfg.CertFile, certPEM)
+	writeFile(t, cfg.KeyFile, keyPEM)
+	writeFile(t, cfg.ClientCAFile, ca.pem)
+
+	r, err := NewReloader(cfg, logger.New("test", "error", false))
+	require.NoError(t, err)
+	defer r.Close()
+	assert.True(t, r.MutualTLS())
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	addr, results := serve(t, r.ServerConfig("http/1.1"))
+
+	t.Run("spiffe id", func(t *testing.T) {
+		spiffeID, _ := url.Parse("spiffe://example.org/ns/default/sa/billing")
+		client := ca.clientCert(t, &x509.Certificate{Subject: pkix.Name{CommonName: "billing"}, URIs: []*url.URL{spiffeID}})
+
+		_, err := dial(addr, roots, client)
+		require.NoError(t, err)
+		result := <-results
+		require.NoError(t, result.err)
+		assert.Equal(t, "spiffe://example.org/ns/default/sa/billing", result.identity)
+	})
+
+	t.Run("common name", func(t *testing.T) {
+		client := ca.clientCert(t, &x509.Certificate{Subject: pkix.Name{CommonName: "reporting"}})
+
+		_, err := dial(addr, roots, client)
+		require.NoError(t, err)
+		result := <-results
+		require.NoError(t, result.err)
+		assert.Equal(t, "reporting", result.identity)
+	})
+
+	t.Run("no client certificate", func(t *testing.T) {
+		_, err := dial(addr, roots)
+		assert.Error(t, err)
+		assert.Error(t, (<-results).err)
+	})
+
+	t.Run("untrusted client certificate", func(t *testing.T) {
+		client := newTestCA(t).clientCert(t, &x509.Certificate{Subject: pkix.Name{CommonName: "intruder"}})
+
+		_, err := dial(addr, roots, client)
+		assert.Error(t, err)
+		assert.Error(t, (<-results).err)
+	})
+}
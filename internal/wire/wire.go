@@ -5,7 +5,7 @@ package wire
 import (
 	"context"
 	"database/sql"
-	"net/http"
+	"sync"
 
 	"github.com/google/wire"
 
@@ -13,17 +13,28 @@ import (
 	"example.com/classic/internal/config"
 	"example.com/classic/internal/data"
 	"example.com/classic/internal/data/db"
+	"example.com/classic/internal/data/redis"
 	"example.com/classic/internal/data/store/sqlstore"
 	"example.com/classic/internal/domain"
+	"example.com/classic/internal/eventstream"
 	"example.com/classic/internal/handler"
+	"example.com/classic/internal/idempotency"
+	"example.com/classic/internal/infrastructure/encryption"
 	"example.com/classic/internal/infrastructure/hashing"
 	"example.com/classic/internal/infrastructure/messaging"
 	"example.com/classic/internal/job/asynq"
+	"example.com/classic/internal/ratelimit"
+	"example.com/classic/internal/requestlimit"
 	"example.com/classic/internal/repository"
 	grpcserver "example.com/classic/internal/server/grpc"
 	httpserver "example.com/classic/internal/server/http"
 	"example.com/classic/internal/service"
+	"example.com/classic/internal/taskqueue"
+	"example.com/classic/internal/tenancy"
+	"example.com/classic/internal/tlsconfig"
+	"example.com/classic/pkg/health"
 	"example.com/classic/pkg/logger"
+	"example.com/classic/pkg/metrics"
 )
 
 // ============================================================
@@ -38,33 +49,90 @@ var LoggerSet = wire.NewSet(
 	provideLogger,
 )
 
+var MetricsSet = wire.NewSet(
+	provideMetrics,
+)
+
 var DataLayerSet = wire.NewSet(
 	sqlstore.New,
 	provideSQLDB,
 	provideDBTX,
 )
 
+var RedisSet = wire.NewSet(
+	provideRedisClient,
+)
+
+var HealthSet = wire.NewSet(
+	provideHealthRegistry,
+)
+
+var RateLimitSet = wire.NewSet(
+	provideRateLimiter,
+)
+
+var IdempotencySet = wire.NewSet(
+	provideIdempotencyStore,
+)
+
+var RequestLimitSet = wire.NewSet(
+	provideRequestLimits,
+)
+
+var TLSSet = wire.NewSet(
+	provideTLSReloader,
+)
+
 var TaskQueueSet = wire.NewSet(
 	asynq.New,
+	provideTaskQueue,
 	provideEventPublisher,
+	provideEventStreamPublisher,
+)
+
+var EventStreamSet = wire.NewSet(
+	provideEventStreamHub,
+	provideEventStreamHandler,
 )
 
 var DomainSet = wire.NewSet(
 	providePasswordHasher,
 	provideUserFactory,
+	provideAttributeSchema,
 	provideTransactionManager,
+	providePIICipher,
 )
 
 var RepositorySet = wire.NewSet(
 	provideUserRepository,
+	provideTenantRepository,
+	provideEventStore,
 )
 
 var ServiceSet = wire.NewSet(
 	service.NewUserService,
+	provideUserBatchService,
+	service.NewTenantService,
+	service.NewUserPrivacyService,
+	providePersonalDataSources,
+	service.NewUserHistoryService,
+	service.NewProjectionService,
+	provideProjections,
+)
+
+var TenancySet = wire.NewSet(
+	tenancy.NewResolver,
+	wire.Bind(new(domain.TenantCache), new(*tenancy.Resolver)),
 )
 
 var HTTPHandlerSet = wire.NewSet(
 	handler.NewUserHandler,
+	handler.NewUserBatchHandler,
+	handler.NewUserPrivacyHandler,
+	handler.NewUserHistoryHandler,
+	handler.NewTenantHandler,
+	handler.NewProjectionHandler,
+	provideUserGateway,
 )
 
 var GRPCHandlerSet = wire.NewSet(
@@ -73,29 +141,56 @@ var GRPCHandlerSet = wire.NewSet(
 
 var HTTPServerSet = wire.NewSet(
 	httpserver.NewServer,
-	provideHTTPServer,
 )
 
 var GRPCServerSet = wire.NewSet(
 	grpcserver.NewServer,
 )
 
+var WorkerSet = wire.NewSet(
+	handler.NewUserJobHandler,
+	provideWorker,
+)
+
+// Worker is the asynq worker with the application job handlers registered
+type Worker struct {
+	Queue   *asynq.Queue
+	Metrics *metrics.Metrics
+}
+
+// processMetrics is shared by the injectors of a process: cmd/api builds the HTTP and
+// gRPC servers separately, and /metrics on the HTTP server reports both
+var (
+	processMetrics     *metrics.Metrics
+	processMetricsOnce sync.Once
+)
+
 // ============================================================
 // Application Initialization Functions
 // ============================================================
 
 // InitHTTPServer initializes HTTP Server with cleanup function
 // Returns: HTTP server, cleanup function, error
-func InitHTTPServer(ctx context.Context) (*http.Server, func(), error) {
+func InitHTTPServer(ctx context.Context) (*httpserver.Server, func(), error) {
 	wire.Build(
 		ConfigSet,
 		LoggerSet,
+		MetricsSet,
 		DataLayerSet,
+		RedisSet,
+		HealthSet,
+		RateLimitSet,
+		IdempotencySet,
+		RequestLimitSet,
+		TLSSet,
 		TaskQueueSet,
 		DomainSet,
 		RepositorySet,
 		ServiceSet,
+		TenancySet,
+		EventStreamSet,
 		HTTPHandlerSet,
+		GRPCHandlerSet,
 		HTTPServerSet,
 	)
 	return nil, nil, nil
@@ -107,17 +202,42 @@ func InitGRPCServer(ctx context.Context) (*grpcserver.Server, func(), error) {
 	wire.Build(
 		ConfigSet,
 		LoggerSet,
+		MetricsSet,
 		DataLayerSet,
+		RedisSet,
+		RateLimitSet,
+		IdempotencySet,
+		RequestLimitSet,
+		TLSSet,
 		TaskQueueSet,
 		DomainSet,
 		RepositorySet,
 		ServiceSet,
+		TenancySet,
 		GRPCHandlerSet,
 		GRPCServerSet,
 	)
 	return nil, nil, nil
 }
 
+// InitWorker initializes the asynq worker with job handlers registered
+// Returns: worker, cleanup function, error
+func InitWorker(ctx context.Context) (*Worker, func(), error) {
+	wire.Build(
+		ConfigSet,
+		LoggerSet,
+		MetricsSet,
+		DataLayerSet,
+		RedisSet,
+		TaskQueueSet,
+		DomainSet,
+		RepositorySet,
+		ServiceSet,
+		WorkerSet,
+	)
+	return nil, nil, nil
+}
+
 // ============================================================
 // Provider Functions
 // ============================================================
@@ -129,6 +249,22 @@ func provideLogger(cfg *config.Config) logger.Logger {
 	return log
 }
 
+// provideMetrics provides the process-wide metrics; nil when metrics are disabled
+func provideMetrics(cfg *config.Config) *metrics.Metrics {
+	if !cfg.Metrics.Enabled {
+		return nil
+	}
+	processMetricsOnce.Do(func() {
+		processMetrics = metrics.New(metrics.Options{
+			Namespace: cfg.Metrics.Namespace,
+			Buckets:   cfg.Metrics.LatencyBuckets,
+			GoRuntime: cfg.Metrics.GoRuntime,
+			DBStats:   cfg.Metrics.DBStats,
+		})
+	})
+	return processMetrics
+}
+
 // providePasswordHasher provides password hasher
 func providePasswordHasher() domain.PasswordHasher {
 	return hashing.NewBcryptPasswordHasher()
@@ -139,37 +275,190 @@ func provideUserFactory(hasher domain.PasswordHasher) domain.UserFactory {
 	return domain.NewUserFactory(hasher)
 }
 
+// provideAttributeSchema builds the custom attribute schema from config
+func provideAttributeSchema(cfg *config.Config) (*domain.AttributeSchema, error) {
+	definitions := make([]domain.AttributeDefinition, len(cfg.Attributes))
+	for i, attr := range cfg.Attributes {
+		definitions[i] = domain.AttributeDefinition{
+			Name:     attr.Name,
+			Type:     domain.AttributeType(attr.Type),
+			Required: attr.Required,
+			Pattern:  attr.Pattern,
+			Enum:     attr.Enum,
+		}
+	}
+	return domain.NewAttributeSchema(definitions)
+}
+
 // provideTransactionManager provides transaction manager
 func provideTransactionManager(sqldb *sql.DB, log logger.Logger) domain.TransactionManager {
 	return data.NewTransactionManager(sqldb, log)
 }
 
+// providePIICipher provides the PII cipher from the configured keyring
+func providePIICipher(cfg *config.Config) (domain.PIICipher, error) {
+	return encryption.LoadCipher(cfg.PII.KeyringFile)
+}
+
 // provideUserRepository provides user repository using sqlc
-func provideUserRepository(dbtx db.DBTX, log logger.Logger) domain.UserRepository {
-	return repository.NewUserRepositorySQLC(dbtx, log)
+func provideUserRepository(dbtx db.DBTX, cipher domain.PIICipher, attrSchema *domain.AttributeSchema, log logger.Logger) domain.UserRepository {
+	return repository.NewUserRepositorySQLC(dbtx, cipher, attrSchema, logger.Component(log, "repository"))
+}
+
+// provideEventStore provides the domain event store using sqlc
+func provideEventStore(dbtx db.DBTX, cipher domain.PIICipher, log logger.Logger) domain.EventStore {
+	return repository.NewEventStoreSQLC(dbtx, cipher, logger.Component(log, "repository"))
+}
+
+// provideTenantRepository provides tenant repository using sqlc
+func provideTenantRepository(dbtx db.DBTX, log logger.Logger) domain.TenantRepository {
+	return repository.NewTenantRepositorySQLC(dbtx, logger.Component(log, "repository"))
 }
 
 // provideUserGRPCHandler provides user gRPC handler
-func provideUserGRPCHandler(userSvc service.UserService, log logger.Logger) pb.UserServiceServer {
-	return handler.NewUserGRPCHandler(userSvc, log)
+func provideUserGRPCHandler(userSvc service.UserService, batchSvc service.UserBatchService, historySvc service.UserHistoryService, log logger.Logger) pb.UserServiceServer {
+	return handler.NewUserGRPCHandler(userSvc, batchSvc, historySvc, log)
 }
 
-// provideHTTPServer provides HTTP server
-func provideHTTPServer(server *httpserver.Server) *http.Server {
-	return server.GetHTTPServer()
+// provideUserGateway provides the REST gateway of the gRPC user service; nil when the gin handlers serve the user API
+func provideUserGateway(cfg *config.Config, server pb.UserServiceServer, log logger.Logger) (*handler.UserGateway, error) {
+	if cfg.HTTP.UserAPI != config.UserAPIGateway {
+		return nil, nil
+	}
+	return handler.NewUserGateway(server, log)
 }
 
-// provideSQLDB provides sql.DB
-func provideSQLDB(store *sqlstore.Store) *sql.DB {
+// provideUserBatchService provides user batch service
+func provideUserBatchService(
+	userRepo domain.UserRepository,
+	attrSchema *domain.AttributeSchema,
+	txManager domain.TransactionManager,
+	eventStore domain.EventStore,
+	eventPublisher domain.EventPublisher,
+	taskQueue taskqueue.TaskQueue,
+	cfg *config.Config,
+	log logger.Logger,
+) service.UserBatchService {
+	return service.NewUserBatchService(userRepo, attrSchema, txManager, eventStore, eventPublisher, taskQueue, service.BatchOptions{
+		AsyncThreshold: cfg.Batch.AsyncThreshold,
+		MaxSize:        cfg.Batch.MaxSize,
+		JobRetention:   cfg.Batch.JobRetention,
+	}, log)
+}
+
+// providePersonalDataSources lists the stores contributing to personal data exports
+func providePersonalDataSources(eventStore domain.EventStore) []domain.PersonalDataSource {
+	return []domain.PersonalDataSource{
+		service.NewEventHistorySource(eventStore),
+	}
+}
+
+// provideProjections lists the read-model projections that can be rebuilt from the event store
+func provideProjections() []domain.Projection {
+	return nil
+}
+
+// provideSQLDB provides sql.DB and reports its pool stats
+func provideSQLDB(store *sqlstore.Store, m *metrics.Metrics) *sql.DB {
+	m.ObserveDB(store.DB)
 	return store.DB
 }
 
+// provideRedisClient provides the Redis client, closed on cleanup
+func provideRedisClient(cfg *config.Config, log logger.Logger) (*redis.Client, func(), error) {
+	client, err := redis.New(cfg, log)
+	if err != nil {
+		return nil, nil, err
+	}
+	return client, func() { _ = client.Close() }, nil
+}
+
+// provideHealthRegistry registers the readiness checks of the dependencies
+func provideHealthRegistry(cfg *config.Config, store *sqlstore.Store, rdb *redis.Client, queue *asynq.Queue) *health.Registry {
+	registry := health.NewRegistry(health.Options{
+		Timeout:  cfg.Health.Timeout,
+		CacheTTL: cfg.Health.CacheTTL,
+	})
+	registry.AddReadinessCheck("database", store.Ping)
+	registry.AddReadinessCheck("redis", rdb.Ping)
+	registry.AddReadinessCheck("task_queue", queue.Ping)
+	return registry
+}
+
+// provideRateLimiter provides the rate limiter, sharing counters through Redis; nil without rules
+func provideRateLimiter(cfg *config.Config, rdb *redis.Client, log logger.Logger) (*ratelimit.Limiter, error) {
+	return ratelimit.NewLimiter(cfg.RateLimit, ratelimit.NewRedisStore(rdb.GetClient()), log)
+}
+
+// provideIdempotencyStore provides the idempotency key store in Redis; nil when disabled
+func provideIdempotencyStore(cfg *config.Config, rdb *redis.Client) *idempotency.Store {
+	return idempotency.NewStore(cfg.Idempotency, rdb.GetClient())
+}
+
+// provideRequestLimits provides the per-route request timeouts and body limits; nil when none is configured
+func provideRequestLimits(cfg *config.Config) (*requestlimit.Policy, error) {
+	return requestlimit.NewPolicy(cfg.RequestLimits)
+}
+
+// provideTLSReloader provides the server certificates, reloaded on change; nil when TLS is disabled
+func provideTLSReloader(cfg *config.Config, log logger.Logger) (*tlsconfig.Reloader, func(), error) {
+	certs, err := tlsconfig.NewReloader(cfg.TLS, log)
+	if err != nil {
+		return nil, nil, err
+	}
+	return certs, func() { _ = certs.Close() }, nil
+}
+
 // provideDBTX provides DBTX interface for sqlc
 func provideDBTX(sqldb *sql.DB) db.DBTX {
 	return sqldb
 }
 
-// provideEventPublisher provides event publisher
-func provideEventPublisher(taskQueue *asynq.Queue, log logger.Logger) domain.EventPublisher {
-	return messaging.NewAsynqEventPublisher(taskQueue, log)
+// provideTaskQueue provides the task queue abstraction
+func provideTaskQueue(queue *asynq.Queue) taskqueue.TaskQueue {
+	return queue
+}
+
+// provideEventPublisher provides event publisher, forwarding the events to the event stream when it is enabled
+func provideEventPublisher(taskQueue taskqueue.TaskQueue, stream *eventstream.Publisher, log logger.Logger) domain.EventPublisher {
+	return messaging.NewAsynqEventPublisher(taskQueue, log, stream.Processors()...)
+}
+
+// provideEventStreamPublisher provides the event stream publisher on the Redis channel; nil when the stream is disabled
+func provideEventStreamPublisher(cfg *config.Config, rdb *redis.Client) *eventstream.Publisher {
+	return eventstream.NewPublisher(cfg.EventStream, rdb.GetClient())
+}
+
+// provideEventStreamHub provides the hub fanning the stream out to the SSE clients, stopped on cleanup; nil when the stream is disabled
+func provideEventStreamHub(cfg *config.Config, rdb *redis.Client, log logger.Logger) (*eventstream.Hub, func()) {
+	hub := eventstream.NewHub(cfg.EventStream, rdb.GetClient(), logger.Component(log, "eventstream"))
+	if hub == nil {
+		return nil, func() {}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		hub.Run(ctx)
+	}()
+	return hub, func() {
+		cancel()
+		<-done
+	}
+}
+
+// provideEventStreamHandler provides the SSE event stream handler; nil when the stream is disabled
+func provideEventStreamHandler(cfg *config.Config, hub *eventstream.Hub, log logger.Logger) *handler.EventStreamHandler {
+	if hub == nil {
+		return nil
+	}
+	return handler.NewEventStreamHandler(hub, cfg.EventStream.Heartbeat, log)
+}
+
+// provideWorker registers the job handlers on the queue
+func provideWorker(queue *asynq.Queue, jobHandler *handler.UserJobHandler, m *metrics.Metrics) (*Worker, error) {
+	if err := jobHandler.Register(queue); err != nil {
+		return nil, err
+	}
+	return &Worker{Queue: queue, Metrics: m}, nil
 }
\ No newline at end of file
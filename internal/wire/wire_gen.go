@@ -12,20 +12,31 @@ import (
 	"example.com/classic/api/grpc/pb"
 	"example.com/classic/internal/config"
 	"example.com/classic/internal/data"
-	"example.com/classic/internal/infrastructure/hashing"
 	"example.com/classic/internal/data/db"
+	"example.com/classic/internal/data/redis"
 	"example.com/classic/internal/data/store/sqlstore"
 	"example.com/classic/internal/domain"
+	"example.com/classic/internal/eventstream"
 	"example.com/classic/internal/handler"
+	"example.com/classic/internal/idempotency"
+	"example.com/classic/internal/infrastructure/encryption"
+	"example.com/classic/internal/infrastructure/hashing"
 	"example.com/classic/internal/infrastructure/messaging"
 	"example.com/classic/internal/job/asynq"
+	"example.com/classic/internal/ratelimit"
 	"example.com/classic/internal/repository"
+	"example.com/classic/internal/requestlimit"
 	"example.com/classic/internal/server/grpc"
-	http2 "example.com/classic/internal/server/http"
+	"example.com/classic/internal/server/http"
 	"example.com/classic/internal/service"
+	"example.com/classic/internal/taskqueue"
+	"example.com/classic/internal/tenancy"
+	"example.com/classic/internal/tlsconfig"
+	"example.com/classic/pkg/health"
 	"example.com/classic/pkg/logger"
+	"example.com/classic/pkg/metrics"
 	"github.com/google/wire"
-	"net/http"
+	"sync"
 )
 
 // Injectors from wire.go:
@@ -42,22 +53,86 @@ func InitHTTPServer(ctx context.Context) (*http.Server, func(), error) {
 	if err != nil {
 		return nil, nil, err
 	}
-	db := provideSQLDB(store)
+	metrics := provideMetrics(configConfig)
+	db := provideSQLDB(store, metrics)
 	dbtx := provideDBTX(db)
-	userRepository := provideUserRepository(dbtx, logger)
+	tenantRepository := provideTenantRepository(dbtx, logger)
+	resolver := tenancy.NewResolver(configConfig, tenantRepository)
+	client, cleanup, err := provideRedisClient(configConfig, logger)
+	if err != nil {
+		return nil, nil, err
+	}
+	queue, err := asynq.New(configConfig, logger, metrics)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	registry := provideHealthRegistry(configConfig, store, client, queue)
+	limiter, err := provideRateLimiter(configConfig, client, logger)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	idempotencyStore := provideIdempotencyStore(configConfig, client)
+	policy, err := provideRequestLimits(configConfig)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	reloader, cleanup2, err := provideTLSReloader(configConfig, logger)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	piiCipher, err := providePIICipher(configConfig)
+	if err != nil {
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	attributeSchema, err := provideAttributeSchema(configConfig)
+	if err != nil {
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	userRepository := provideUserRepository(dbtx, piiCipher, attributeSchema, logger)
 	passwordHasher := providePasswordHasher()
 	userFactory := provideUserFactory(passwordHasher)
 	transactionManager := provideTransactionManager(db, logger)
-	queue, err := asynq.New(configConfig, logger)
+	eventStore := provideEventStore(dbtx, piiCipher, logger)
+	taskQueue := provideTaskQueue(queue)
+	publisher := provideEventStreamPublisher(configConfig, client)
+	eventPublisher := provideEventPublisher(taskQueue, publisher, logger)
+	userService := service.NewUserService(userRepository, userFactory, attributeSchema, transactionManager, eventStore, eventPublisher, logger)
+	userHandler := handler.NewUserHandler(userService, logger)
+	userBatchService := provideUserBatchService(userRepository, attributeSchema, transactionManager, eventStore, eventPublisher, taskQueue, configConfig, logger)
+	userBatchHandler := handler.NewUserBatchHandler(userBatchService, logger)
+	v := providePersonalDataSources(eventStore)
+	userPrivacyService := service.NewUserPrivacyService(userRepository, transactionManager, eventStore, eventPublisher, taskQueue, v, logger)
+	userPrivacyHandler := handler.NewUserPrivacyHandler(userPrivacyService, logger)
+	userHistoryService := service.NewUserHistoryService(userRepository, eventStore, logger)
+	userHistoryHandler := handler.NewUserHistoryHandler(userHistoryService, logger)
+	tenantService := service.NewTenantService(tenantRepository, userRepository, resolver, logger)
+	tenantHandler := handler.NewTenantHandler(tenantService, userService, logger)
+	v2 := provideProjections()
+	projectionService := service.NewProjectionService(eventStore, v2, logger)
+	projectionHandler := handler.NewProjectionHandler(projectionService, logger)
+	hub, cleanup3 := provideEventStreamHub(configConfig, client, logger)
+	eventStreamHandler := provideEventStreamHandler(configConfig, hub, logger)
+	userServiceServer := provideUserGRPCHandler(userService, userBatchService, userHistoryService, logger)
+	userGateway, err := provideUserGateway(configConfig, userServiceServer, logger)
 	if err != nil {
+		cleanup3()
+		cleanup2()
+		cleanup()
 		return nil, nil, err
 	}
-	eventPublisher := provideEventPublisher(queue, logger)
-	userService := service.NewUserService(userRepository, userFactory, transactionManager, eventPublisher, logger)
-	userHandler := handler.NewUserHandler(userService, logger)
-	server := http2.NewServer(configConfig, logger, userHandler)
-	httpServer := provideHTTPServer(server)
-	return httpServer, func() {
+	server := http.NewServer(configConfig, logger, resolver, metrics, registry, limiter, idempotencyStore, policy, reloader, userHandler, userBatchHandler, userPrivacyHandler, userHistoryHandler, tenantHandler, projectionHandler, eventStreamHandler, userGateway)
+	return server, func() {
+		cleanup3()
+		cleanup2()
+		cleanup()
 	}, nil
 }
 
@@ -73,21 +148,110 @@ func InitGRPCServer(ctx context.Context) (*grpc.Server, func(), error) {
 	if err != nil {
 		return nil, nil, err
 	}
-	db := provideSQLDB(store)
+	metrics := provideMetrics(configConfig)
+	db := provideSQLDB(store, metrics)
 	dbtx := provideDBTX(db)
-	userRepository := provideUserRepository(dbtx, logger)
+	piiCipher, err := providePIICipher(configConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+	attributeSchema, err := provideAttributeSchema(configConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+	userRepository := provideUserRepository(dbtx, piiCipher, attributeSchema, logger)
 	passwordHasher := providePasswordHasher()
 	userFactory := provideUserFactory(passwordHasher)
 	transactionManager := provideTransactionManager(db, logger)
-	queue, err := asynq.New(configConfig, logger)
+	eventStore := provideEventStore(dbtx, piiCipher, logger)
+	queue, err := asynq.New(configConfig, logger, metrics)
+	if err != nil {
+		return nil, nil, err
+	}
+	taskQueue := provideTaskQueue(queue)
+	client, cleanup, err := provideRedisClient(configConfig, logger)
+	if err != nil {
+		return nil, nil, err
+	}
+	publisher := provideEventStreamPublisher(configConfig, client)
+	eventPublisher := provideEventPublisher(taskQueue, publisher, logger)
+	userService := service.NewUserService(userRepository, userFactory, attributeSchema, transactionManager, eventStore, eventPublisher, logger)
+	userBatchService := provideUserBatchService(userRepository, attributeSchema, transactionManager, eventStore, eventPublisher, taskQueue, configConfig, logger)
+	userHistoryService := service.NewUserHistoryService(userRepository, eventStore, logger)
+	userServiceServer := provideUserGRPCHandler(userService, userBatchService, userHistoryService, logger)
+	tenantRepository := provideTenantRepository(dbtx, logger)
+	resolver := tenancy.NewResolver(configConfig, tenantRepository)
+	limiter, err := provideRateLimiter(configConfig, client, logger)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	idempotencyStore := provideIdempotencyStore(configConfig, client)
+	policy, err := provideRequestLimits(configConfig)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	reloader, cleanup2, err := provideTLSReloader(configConfig, logger)
 	if err != nil {
+		cleanup()
 		return nil, nil, err
 	}
-	eventPublisher := provideEventPublisher(queue, logger)
-	userService := service.NewUserService(userRepository, userFactory, transactionManager, eventPublisher, logger)
-	userServiceServer := provideUserGRPCHandler(userService, logger)
-	server := grpc.NewServer(configConfig, logger, userServiceServer)
+	server := grpc.NewServer(configConfig, logger, userServiceServer, resolver, metrics, limiter, idempotencyStore, policy, reloader)
 	return server, func() {
+		cleanup2()
+		cleanup()
+	}, nil
+}
+
+// InitWorker initializes the asynq worker with job handlers registered
+// Returns: worker, cleanup function, error
+func InitWorker(ctx context.Context) (*Worker, func(), error) {
+	configConfig, err := config.Load()
+	if err != nil {
+		return nil, nil, err
+	}
+	logger := provideLogger(configConfig)
+	metrics := provideMetrics(configConfig)
+	queue, err := asynq.New(configConfig, logger, metrics)
+	if err != nil {
+		return nil, nil, err
+	}
+	store, err := sqlstore.New(ctx, configConfig, logger)
+	if err != nil {
+		return nil, nil, err
+	}
+	db := provideSQLDB(store, metrics)
+	dbtx := provideDBTX(db)
+	piiCipher, err := providePIICipher(configConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+	attributeSchema, err := provideAttributeSchema(configConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+	userRepository := provideUserRepository(dbtx, piiCipher, attributeSchema, logger)
+	transactionManager := provideTransactionManager(db, logger)
+	eventStore := provideEventStore(dbtx, piiCipher, logger)
+	taskQueue := provideTaskQueue(queue)
+	client, cleanup, err := provideRedisClient(configConfig, logger)
+	if err != nil {
+		return nil, nil, err
+	}
+	publisher := provideEventStreamPublisher(configConfig, client)
+	eventPublisher := provideEventPublisher(taskQueue, publisher, logger)
+	userBatchService := provideUserBatchService(userRepository, attributeSchema, transactionManager, eventStore, eventPublisher, taskQueue, configConfig, logger)
+	v := providePersonalDataSources(eventStore)
+	userPrivacyService := service.NewUserPrivacyService(userRepository, transactionManager, eventStore, eventPublisher, taskQueue, v, logger)
+	userJobHandler := handler.NewUserJobHandler(userBatchService, userPrivacyService, logger)
+	worker, err := provideWorker(queue, userJobHandler, metrics)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	return worker, func() {
+		cleanup()
 	}, nil
 }
 
@@ -99,34 +263,91 @@ var LoggerSet = wire.NewSet(
 	provideLogger,
 )
 
+var MetricsSet = wire.NewSet(
+	provideMetrics,
+)
+
 var DataLayerSet = wire.NewSet(sqlstore.New, provideSQLDB,
 	provideDBTX,
 )
 
-var TaskQueueSet = wire.NewSet(asynq.New, provideEventPublisher)
+var RedisSet = wire.NewSet(
+	provideRedisClient,
+)
+
+var HealthSet = wire.NewSet(
+	provideHealthRegistry,
+)
+
+var RateLimitSet = wire.NewSet(
+	provideRateLimiter,
+)
+
+var IdempotencySet = wire.NewSet(
+	provideIdempotencyStore,
+)
+
+var RequestLimitSet = wire.NewSet(
+	provideRequestLimits,
+)
+
+var TLSSet = wire.NewSet(
+	provideTLSReloader,
+)
+
+var TaskQueueSet = wire.NewSet(asynq.New, provideTaskQueue,
+	provideEventPublisher,
+	provideEventStreamPublisher,
+)
+
+var EventStreamSet = wire.NewSet(
+	provideEventStreamHub,
+	provideEventStreamHandler,
+)
 
 var DomainSet = wire.NewSet(
 	providePasswordHasher,
 	provideUserFactory,
+	provideAttributeSchema,
 	provideTransactionManager,
+	providePIICipher,
 )
 
 var RepositorySet = wire.NewSet(
 	provideUserRepository,
+	provideTenantRepository,
+	provideEventStore,
 )
 
-var ServiceSet = wire.NewSet(service.NewUserService)
+var ServiceSet = wire.NewSet(service.NewUserService, provideUserBatchService, service.NewTenantService, service.NewUserPrivacyService, providePersonalDataSources, service.NewUserHistoryService, service.NewProjectionService, provideProjections)
+
+var TenancySet = wire.NewSet(tenancy.NewResolver, wire.Bind(new(domain.TenantCache), new(*tenancy.Resolver)))
 
-var HTTPHandlerSet = wire.NewSet(handler.NewUserHandler)
+var HTTPHandlerSet = wire.NewSet(handler.NewUserHandler, handler.NewUserBatchHandler, handler.NewUserPrivacyHandler, handler.NewUserHistoryHandler, handler.NewTenantHandler, handler.NewProjectionHandler, provideUserGateway)
 
 var GRPCHandlerSet = wire.NewSet(
 	provideUserGRPCHandler,
 )
 
-var HTTPServerSet = wire.NewSet(http2.NewServer, provideHTTPServer)
+var HTTPServerSet = wire.NewSet(http.NewServer)
 
 var GRPCServerSet = wire.NewSet(grpc.NewServer)
 
+var WorkerSet = wire.NewSet(handler.NewUserJobHandler, provideWorker)
+
+// Worker is the asynq worker with the application job handlers registered
+type Worker struct {
+	Queue   *asynq.Queue
+	Metrics *metrics.Metrics
+}
+
+// processMetrics is shared by the injectors of a process: cmd/api builds the HTTP and
+// gRPC servers separately, and /metrics on the HTTP server reports both
+var (
+	processMetrics     *metrics.Metrics
+	processMetricsOnce sync.Once
+)
+
 // provideLogger provides logger instance
 func provideLogger(cfg *config.Config) logger.Logger {
 	log := logger.New(cfg.Service, cfg.Log.Level, cfg.IsDevelopment())
@@ -134,6 +355,22 @@ func provideLogger(cfg *config.Config) logger.Logger {
 	return log
 }
 
+// provideMetrics provides the process-wide metrics; nil when metrics are disabled
+func provideMetrics(cfg *config.Config) *metrics.Metrics {
+	if !cfg.Metrics.Enabled {
+		return nil
+	}
+	processMetricsOnce.Do(func() {
+		processMetrics = metrics.New(metrics.Options{
+			Namespace: cfg.Metrics.Namespace,
+			Buckets:   cfg.Metrics.LatencyBuckets,
+			GoRuntime: cfg.Metrics.GoRuntime,
+			DBStats:   cfg.Metrics.DBStats,
+		})
+	})
+	return processMetrics
+}
+
 // providePasswordHasher provides password hasher
 func providePasswordHasher() domain.PasswordHasher {
 	return hashing.NewBcryptPasswordHasher()
@@ -144,37 +381,188 @@ func provideUserFactory(hasher domain.PasswordHasher) domain.UserFactory {
 	return domain.NewUserFactory(hasher)
 }
 
+// provideAttributeSchema builds the custom attribute schema from config
+func provideAttributeSchema(cfg *config.Config) (*domain.AttributeSchema, error) {
+	definitions := make([]domain.AttributeDefinition, len(cfg.Attributes))
+	for i, attr := range cfg.Attributes {
+		definitions[i] = domain.AttributeDefinition{
+			Name:     attr.Name,
+			Type:     domain.AttributeType(attr.Type),
+			Required: attr.Required,
+			Pattern:  attr.Pattern,
+			Enum:     attr.Enum,
+		}
+	}
+	return domain.NewAttributeSchema(definitions)
+}
+
 // provideTransactionManager provides transaction manager
 func provideTransactionManager(sqldb *sql.DB, log logger.Logger) domain.TransactionManager {
 	return data.NewTransactionManager(sqldb, log)
 }
 
+// providePIICipher provides the PII cipher from the configured keyring
+func providePIICipher(cfg *config.Config) (domain.PIICipher, error) {
+	return encryption.LoadCipher(cfg.PII.KeyringFile)
+}
+
 // provideUserRepository provides user repository using sqlc
-func provideUserRepository(dbtx db.DBTX, log logger.Logger) domain.UserRepository {
-	return repository.NewUserRepositorySQLC(dbtx, log)
+func provideUserRepository(dbtx db.DBTX, cipher domain.PIICipher, attrSchema *domain.AttributeSchema, log logger.Logger) domain.UserRepository {
+	return repository.NewUserRepositorySQLC(dbtx, cipher, attrSchema, logger.Component(log, "repository"))
+}
+
+// provideEventStore provides the domain event store using sqlc
+func provideEventStore(dbtx db.DBTX, cipher domain.PIICipher, log logger.Logger) domain.EventStore {
+	return repository.NewEventStoreSQLC(dbtx, cipher, logger.Component(log, "repository"))
+}
+
+// provideTenantRepository provides tenant repository using sqlc
+func provideTenantRepository(dbtx db.DBTX, log logger.Logger) domain.TenantRepository {
+	return repository.NewTenantRepositorySQLC(dbtx, logger.Component(log, "repository"))
 }
 
 // provideUserGRPCHandler provides user gRPC handler
-func provideUserGRPCHandler(userSvc service.UserService, log logger.Logger) pb.UserServiceServer {
-	return handler.NewUserGRPCHandler(userSvc, log)
+func provideUserGRPCHandler(userSvc service.UserService, batchSvc service.UserBatchService, historySvc service.UserHistoryService, log logger.Logger) pb.UserServiceServer {
+	return handler.NewUserGRPCHandler(userSvc, batchSvc, historySvc, log)
+}
+
+// provideUserGateway provides the REST gateway of the gRPC user service; nil when the gin handlers serve the user API
+func provideUserGateway(cfg *config.Config, server pb.UserServiceServer, log logger.Logger) (*handler.UserGateway, error) {
+	if cfg.HTTP.UserAPI != config.UserAPIGateway {
+		return nil, nil
+	}
+	return handler.NewUserGateway(server, log)
 }
 
-// provideHTTPServer provides HTTP server
-func provideHTTPServer(server *http2.Server) *http.Server {
-	return server.GetHTTPServer()
+// provideUserBatchService provides user batch service
+func provideUserBatchService(
+	userRepo domain.UserRepository,
+	attrSchema *domain.AttributeSchema,
+	txManager domain.TransactionManager,
+	eventStore domain.EventStore,
+	eventPublisher domain.EventPublisher,
+	taskQueue taskqueue.TaskQueue,
+	cfg *config.Config,
+	log logger.Logger,
+) service.UserBatchService {
+	return service.NewUserBatchService(userRepo, attrSchema, txManager, eventStore, eventPublisher, taskQueue, service.BatchOptions{
+		AsyncThreshold: cfg.Batch.AsyncThreshold,
+		MaxSize:        cfg.Batch.MaxSize,
+		JobRetention:   cfg.Batch.JobRetention,
+	}, log)
 }
 
-// provideSQLDB provides sql.DB
-func provideSQLDB(store *sqlstore.Store) *sql.DB {
+// providePersonalDataSources lists the stores contributing to personal data exports
+func providePersonalDataSources(eventStore domain.EventStore) []domain.PersonalDataSource {
+	return []domain.PersonalDataSource{service.NewEventHistorySource(eventStore)}
+}
+
+// provideProjections lists the read-model projections that can be rebuilt from the event store
+func provideProjections() []domain.Projection {
+	return nil
+}
+
+// provideSQLDB provides sql.DB and reports its pool stats
+func provideSQLDB(store *sqlstore.Store, m *metrics.Metrics) *sql.DB {
+	m.ObserveDB(store.DB)
 	return store.DB
 }
 
+// provideRedisClient provides the Redis client, closed on cleanup
+func provideRedisClient(cfg *config.Config, log logger.Logger) (*redis.Client, func(), error) {
+	client, err := redis.New(cfg, log)
+	if err != nil {
+		return nil, nil, err
+	}
+	return client, func() { _ = client.Close() }, nil
+}
+
+// provideHealthRegistry registers the readiness checks of the dependencies
+func provideHealthRegistry(cfg *config.Config, store *sqlstore.Store, rdb *redis.Client, queue *asynq.Queue) *health.Registry {
+	registry := health.NewRegistry(health.Options{
+		Timeout:  cfg.Health.Timeout,
+		CacheTTL: cfg.Health.CacheTTL,
+	})
+	registry.AddReadinessCheck("database", store.Ping)
+	registry.AddReadinessCheck("redis", rdb.Ping)
+	registry.AddReadinessCheck("task_queue", queue.Ping)
+	return registry
+}
+
+// provideRateLimiter provides the rate limiter, sharing counters through Redis; nil without rules
+func provideRateLimiter(cfg *config.Config, rdb *redis.Client, log logger.Logger) (*ratelimit.Limiter, error) {
+	return ratelimit.NewLimiter(cfg.RateLimit, ratelimit.NewRedisStore(rdb.GetClient()), log)
+}
+
+// provideIdempotencyStore provides the idempotency key store in Redis; nil when disabled
+func provideIdempotencyStore(cfg *config.Config, rdb *redis.Client) *idempotency.Store {
+	return idempotency.NewStore(cfg.Idempotency, rdb.GetClient())
+}
+
+// provideRequestLimits provides the per-route request timeouts and body limits; nil when none is configured
+func provideRequestLimits(cfg *config.Config) (*requestlimit.Policy, error) {
+	return requestlimit.NewPolicy(cfg.RequestLimits)
+}
+
+// provideTLSReloader provides the server certificates, reloaded on change; nil when TLS is disabled
+func provideTLSReloader(cfg *config.Config, log logger.Logger) (*tlsconfig.Reloader, func(), error) {
+	certs, err := tlsconfig.NewReloader(cfg.TLS, log)
+	if err != nil {
+		return nil, nil, err
+	}
+	return certs, func() { _ = certs.Close() }, nil
+}
+
 // provideDBTX provides DBTX interface for sqlc
 func provideDBTX(sqldb *sql.DB) db.DBTX {
 	return sqldb
 }
 
-// provideEventPublisher provides event publisher
-func provideEventPublisher(taskQueue *asynq.Queue, log logger.Logger) domain.EventPublisher {
-	return messaging.NewAsynqEventPublisher(taskQueue, log)
+// provideTaskQueue provides the task queue abstraction
+func provideTaskQueue(queue *asynq.Queue) taskqueue.TaskQueue {
+	return queue
+}
+
+// provideEventPublisher provides event publisher, forwarding the events to the event stream when it is enabled
+func provideEventPublisher(taskQueue taskqueue.TaskQueue, stream *eventstream.Publisher, log logger.Logger) domain.EventPublisher {
+	return messaging.NewAsynqEventPublisher(taskQueue, log, stream.Processors()...)
+}
+
+// provideEventStreamPublisher provides the event stream publisher on the Redis channel; nil when the stream is disabled
+func provideEventStreamPublisher(cfg *config.Config, rdb *redis.Client) *eventstream.Publisher {
+	return eventstream.NewPublisher(cfg.EventStream, rdb.GetClient())
+}
+
+// provideEventStreamHub provides the hub fanning the stream out to the SSE clients, stopped on cleanup; nil when the stream is disabled
+func provideEventStreamHub(cfg *config.Config, rdb *redis.Client, log logger.Logger) (*eventstream.Hub, func()) {
+	hub := eventstream.NewHub(cfg.EventStream, rdb.GetClient(), logger.Component(log, "eventstream"))
+	if hub == nil {
+		return nil, func() {}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		hub.Run(ctx)
+	}()
+	return hub, func() {
+		cancel()
+		<-done
+	}
+}
+
+// provideEventStreamHandler provides the SSE event stream handler; nil when the stream is disabled
+func provideEventStreamHandler(cfg *config.Config, hub *eventstream.Hub, log logger.Logger) *handler.EventStreamHandler {
+	if hub == nil {
+		return nil
+	}
+	return handler.NewEventStreamHandler(hub, cfg.EventStream.Heartbeat, log)
+}
+
+// provideWorker registers the job handlers on the queue
+func provideWorker(queue *asynq.Queue, jobHandler *handler.UserJobHandler, m *metrics.Metrics) (*Worker, error) {
+	if err := jobHandler.Register(queue); err != nil {
+		return nil, err
+	}
+	return &Worker{Queue: queue, Metrics: m}, nil
 }
@@ -15,11 +15,14 @@ const (
 	spanIDKey        key = "span_id"
 	parentSpanIDKey  key = "parent_span_id"
 	userIDKey        key = "user_id"
+	tenantIDKey      key = "tenant_id"
 	requestIDKey     key = "request_id"
 	clientIPKey      key = "client_ip"
 	userAgentKey     key = "user_agent"
 	serviceNameKey   key = "service_name"
 	operationNameKey key = "operation_name"
+	localeKey        key = "locale"
+	clientIDKey      key = "client_identity"
 )
 
 // TraceContext contains tracing information.
@@ -81,6 +84,11 @@ func WithUserID(ctx context.Context, userID string) context.Context {
 	return context.WithValue(ctx, userIDKey, userID)
 }
 
+// WithTenantID sets tenant ID in context.
+func WithTenantID(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantIDKey, tenantID)
+}
+
 // WithRequestID sets request ID in context.
 func WithRequestID(ctx context.Context, requestID string) context.Context {
 	return context.WithValue(ctx, requestIDKey, requestID)
@@ -106,6 +114,11 @@ func WithOperationName(ctx context.Context, operationName string) context.Contex
 	return context.WithValue(ctx, operationNameKey, operationName)
 }
 
+// WithLocale sets the negotiated message locale in context.
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey, locale)
+}
+
 // WithTraceContext sets all trace context values at once.
 func WithTraceContext(ctx context.Context, tc *TraceContext) context.Context {
 	if tc.TraceID != "" {
@@ -138,6 +151,11 @@ func WithTraceContext(ctx context.Context, tc *TraceContext) context.Context {
 	return ctx
 }
 
+// WithClientIdentity sets the identity of the verified mTLS client certificate in context.
+func WithClientIdentity(ctx context.Context, identity string) context.Context {
+	return context.WithValue(ctx, clientIDKey, identity)
+}
+
 // --- Context Getters ---
 
 // GetTraceID retrieves trace ID from context.
@@ -172,6 +190,14 @@ func GetUserID(ctx context.Context) string {
 	return ""
 }
 
+// GetTenantID retrieves tenant ID from context.
+func GetTenantID(ctx context.Context) string {
+	if v, ok := ctx.Value(tenantIDKey).(string); ok {
+		return v
+	}
+	return ""
+}
+
 // GetRequestID retrieves request ID from context.
 func GetRequestID(ctx context.Context) string {
 	if v, ok := ctx.Value(requestIDKey).(string); ok {
@@ -212,6 +238,23 @@ func GetOperationName(ctx context.Context) string {
 	return ""
 }
 
+// GetLocale retrieves the negotiated message locale from context.
+func GetLocale(ctx context.Context) string {
+	if v, ok := ctx.Value(localeKey).(string); ok {
+		return v
+	}
+	return ""
+}
+
+// GetClientIdentity retrieves the identity of the verified mTLS client certificate
+// (SPIFFE ID or other URI SAN, else the subject common name) from context.
+func GetClientIdentity(ctx context.Context) string {
+	if v, ok := ctx.Value(clientIDKey).(string); ok {
+		return v
+	}
+	return ""
+}
+
 // GetTraceContext retrieves all trace context from context.
 func GetTraceContext(ctx context.Context) *TraceContext {
 	return &TraceContext{
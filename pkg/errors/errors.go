@@ -20,19 +20,42 @@ const (
 	ErrCodeNotFound       ErrorCode = 404
 	ErrCodeConflict       ErrorCode = 409
 	ErrCodeTooManyRequest ErrorCode = 429
+	// ErrCodeUnprocessableEntity 引用的资源不存在或仍被引用（外键约束）
+	ErrCodeUnprocessableEntity ErrorCode = 422
+	// ErrCodeServiceUnavailable 暂时性故障（死锁、超时、连接中断），可重试
+	ErrCodeServiceUnavailable ErrorCode = 503
+	// ErrCodeRequestTooLarge 请求体超过路由的上限
+	ErrCodeRequestTooLarge ErrorCode = 413
+	// ErrCodeRequestTimeout 请求处理超过路由的时限
+	ErrCodeRequestTimeout ErrorCode = 504
+	// ErrCodeUnsupportedMediaType 请求体的 Content-Type 不受支持
+	ErrCodeUnsupportedMediaType ErrorCode = 415
 
 	// 业务错误码 (1000-9999)
 	ErrCodeUserNotFound      ErrorCode = 1001
 	ErrCodeUserAlreadyExists ErrorCode = 1002
 	ErrCodeInvalidPassword   ErrorCode = 1003
 	ErrCodeInvalidEmail      ErrorCode = 1004
+
+	ErrCodeTenantNotFound      ErrorCode = 1101
+	ErrCodeTenantAlreadyExists ErrorCode = 1102
+	ErrCodeTenantRequired      ErrorCode = 1103
+	ErrCodeTenantInactive      ErrorCode = 1104
 )
 
 // Error 业务错误结构
 type Error struct {
-	Code    ErrorCode `json:"code"`
-	Message string    `json:"message"`
-	Err     error     `json:"-"`
+	Code    ErrorCode    `json:"code"`
+	Message string       `json:"message"`
+	Fields  []FieldError `json:"fields,omitempty"` // 字段级校验明细
+	Err     error        `json:"-"`
+}
+
+// FieldError 字段级校验错误；Code 与 binding 校验标签对齐 (required、email、min、max 等)
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
 }
 
 func (e *Error) Error() string {
@@ -46,6 +69,13 @@ func (e *Error) Unwrap() error {
 	return e.Err
 }
 
+// WithFields 返回附带字段明细的副本，预定义错误可安全使用
+func (e *Error) WithFields(fields ...FieldError) *Error {
+	clone := *e
+	clone.Fields = append(append([]FieldError(nil), e.Fields...), fields...)
+	return &clone
+}
+
 // New 创建新的业务错误
 func New(code ErrorCode, message string) *Error {
 	return &Error{
@@ -106,18 +136,27 @@ func Cause(err error) error {
 
 // 预定义错误
 var (
-	ErrInternalError  = New(ErrCodeInternalError, "internal server error")
-	ErrInvalidParam   = New(ErrCodeInvalidParam, "invalid parameter")
-	ErrUnauthorized   = New(ErrCodeUnauthorized, "unauthorized")
-	ErrForbidden      = New(ErrCodeForbidden, "forbidden")
-	ErrNotFound       = New(ErrCodeNotFound, "resource not found")
-	ErrConflict       = New(ErrCodeConflict, "resource conflict")
-	ErrTooManyRequest = New(ErrCodeTooManyRequest, "too many requests")
+	ErrInternalError   = New(ErrCodeInternalError, "internal server error")
+	ErrInvalidParam    = New(ErrCodeInvalidParam, "invalid parameter")
+	ErrUnauthorized    = New(ErrCodeUnauthorized, "unauthorized")
+	ErrForbidden       = New(ErrCodeForbidden, "forbidden")
+	ErrNotFound        = New(ErrCodeNotFound, "resource not found")
+	ErrConflict        = New(ErrCodeConflict, "resource conflict")
+	ErrTooManyRequest  = New(ErrCodeTooManyRequest, "too many requests")
+	ErrRequestTooLarge = New(ErrCodeRequestTooLarge, "request body too large")
+	ErrRequestTimeout  = New(ErrCodeRequestTimeout, "request timed out")
+
+	ErrUnsupportedMediaType = New(ErrCodeUnsupportedMediaType, "unsupported media type")
 
 	ErrUserNotFound      = New(ErrCodeUserNotFound, "user not found")
 	ErrUserAlreadyExists = New(ErrCodeUserAlreadyExists, "user already exists")
 	ErrInvalidPassword   = New(ErrCodeInvalidPassword, "invalid password")
 	ErrInvalidEmail      = New(ErrCodeInvalidEmail, "invalid email")
+
+	ErrTenantNotFound      = New(ErrCodeTenantNotFound, "tenant not found")
+	ErrTenantAlreadyExists = New(ErrCodeTenantAlreadyExists, "tenant already exists")
+	ErrTenantRequired      = New(ErrCodeTenantRequired, "tenant is required")
+	ErrTenantInactive      = New(ErrCodeTenantInactive, "tenant is not active")
 )
 
 // 工具函数
@@ -133,6 +172,12 @@ func WrapNotFound(err error, message string) *Error {
 	return Wrap(err, ErrCodeNotFound, message)
 }
 
+// IsRetryable 是否为可重试的暂时性错误
+func IsRetryable(err error) bool {
+	var bizErr *Error
+	return As(err, &bizErr) && bizErr.Code == ErrCodeServiceUnavailable
+}
+
 // 获取调用者信息
 func GetCallerInfo(skip int) (string, int) {
 	_, file, line, ok := runtime.Caller(skip + 1)
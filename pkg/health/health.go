@@ -0,0 +1,203 @@
+// Package health provides a registry of liveness and readiness checks.
+// Results are cached per check, so frequent probes from several load balancers
+// do not turn into a ping storm against the dependencies.
+package health
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Check reports whether a dependency is usable; it should honour ctx
+type Check func(ctx context.Context) error
+
+// Status of a check or a report
+type Status string
+
+const (
+	StatusUp   Status = "up"
+	StatusDown Status = "down"
+)
+
+// shutdownCheck name of the pseudo check reported while draining
+const shutdownCheck = "shutdown"
+
+// CheckResult outcome of a single check
+type CheckResult struct {
+	Status    Status    `json:"status"`
+	LatencyMS float64   `json:"latency_ms"`
+	Error     string    `json:"error,omitempty"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// Report outcome of all the checks of a probe; Status is down if any check is down
+type Report struct {
+	Status Status                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks,omitempty"`
+}
+
+// Up reports whether the probe passed
+func (r Report) Up() bool {
+	return r.Status == StatusUp
+}
+
+// Summary outcome of a probe with only the name and status of each check.
+// Check errors can reveal hosts, addresses and driver details, so unauthenticated
+// probes serve a Summary and the full Report stays behind the admin token.
+type Summary struct {
+	Status Status            `json:"status"`
+	Checks map[string]Status `json:"checks,omitempty"`
+}
+
+// Summary drops the latency, error and time of each check
+func (r Report) Summary() Summary {
+	summary := Summary{Status: r.Status, Checks: make(map[string]Status, len(r.Checks))}
+	for name, result := range r.Checks {
+		summary.Checks[name] = result.Status
+	}
+	return summary
+}
+
+// Options configures the registry
+type Options struct {
+	// Timeout per check; a check still running after Timeout is reported down
+	Timeout time.Duration
+	// CacheTTL how long a result is reused; 0 runs the check on every probe
+	CacheTTL time.Duration
+}
+
+// Registry holds the checks of a process
+type Registry struct {
+	opts      Options
+	mu        sync.RWMutex
+	liveness  map[string]*entry
+	readiness map[string]*entry
+	draining  atomic.Bool
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry(opts Options) *Registry {
+	if opts.Timeout <= 0 {
+		opts.Timeout = 2 * time.Second
+	}
+	return &Registry{
+		opts:      opts,
+		liveness:  make(map[string]*entry),
+		readiness: make(map[string]*entry),
+	}
+}
+
+// AddLivenessCheck registers a check of the process itself; a failing liveness probe gets the process restarted,
+// so dependencies belong in readiness checks
+func (r *Registry) AddLivenessCheck(name string, check Check) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.liveness[name] = &entry{check: check}
+}
+
+// AddReadinessCheck registers a dependency required to serve traffic
+func (r *Registry) AddReadinessCheck(name string, check Check) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.readiness[name] = &entry{check: check}
+}
+
+// Liveness runs the liveness checks
+func (r *Registry) Liveness(ctx context.Context) Report {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.run(ctx, r.liveness)
+}
+
+// Readiness runs the readiness checks; it is down once Shutdown was called
+func (r *Registry) Readiness(ctx context.Context) Report {
+	r.mu.RLock()
+	report := r.run(ctx, r.readiness)
+	r.mu.RUnlock()
+
+	if r.draining.Load() {
+		report.Status = StatusDown
+		report.Checks[shutdownCheck] = CheckResult{Status: StatusDown, Error: "server is shutting down", CheckedAt: time.Now()}
+	}
+	return report
+}
+
+// Shutdown makes the readiness probe fail so that load balancers stop sending traffic
+func (r *Registry) Shutdown() {
+	r.draining.Store(true)
+}
+
+// Draining reports whether Shutdown was called
+func (r *Registry) Draining() bool {
+	return r.draining.Load()
+}
+
+// run executes the checks concurrently
+func (r *Registry) run(ctx context.Context, entries map[string]*entry) Report {
+	report := Report{Status: StatusUp, Checks: make(map[string]CheckResult, len(entries))}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, e := range entries {
+		wg.Add(1)
+		go func(name string, e *entry) {
+			defer wg.Done()
+			result := e.result(ctx, r.opts)
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[name] = result
+			if result.Status != StatusUp {
+				report.Status = StatusDown
+			}
+		}(name, e)
+	}
+	wg.Wait()
+	return report
+}
+
+// entry a check with its last result
+type entry struct {
+	check Check
+	mu    sync.Mutex
+	last  CheckResult
+}
+
+// result returns the cached result or runs the check. Concurrent probes wait for the running check
+// instead of starting their own.
+func (e *entry) result(ctx context.Context, opts Options) CheckResult {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if opts.CacheTTL > 0 && !e.last.CheckedAt.IsZero() && time.Since(e.last.CheckedAt) < opts.CacheTTL {
+		return e.last
+	}
+
+	// The result is shared, so it must not depend on the probe request being cancelled
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), opts.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() { done <- e.check(ctx) }()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		// The check ignores its context; report it down without waiting
+		err = ctx.Err()
+	}
+
+	e.last = CheckResult{
+		Status:    StatusUp,
+		LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+		CheckedAt: time.Now(),
+	}
+	if err != nil {
+		e.last.Status = StatusDown
+		e.last.Error = err.Error()
+	}
+	return e.last
+}
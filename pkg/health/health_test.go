@@ -0,0 +1,97 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry_Readiness(t *testing.T) {
+	registry := NewRegistry(Options{Timeout: time.Second})
+	registry.AddReadinessCheck("database", func(ctx context.Context) error { return nil })
+	registry.AddReadinessCheck("redis", func(ctx context.Context) error { return errors.New("connection refused") })
+
+	report := registry.Readiness(context.Background())
+	assert.False(t, report.Up())
+	assert.Equal(t, StatusUp, report.Checks["database"].Status)
+	assert.Equal(t, StatusDown, report.Checks["redis"].Status)
+	assert.Equal(t, "connection refused", report.Checks["redis"].Error)
+
+	// Dependencies do not affect liveness
+	assert.True(t, registry.Liveness(context.Background()).Up())
+}
+
+func TestRegistry_Timeout(t *testing.T) {
+	registry := NewRegistry(Options{Timeout: 20 * time.Millisecond})
+	release := make(chan struct{})
+	defer close(release)
+	// Ignores its context, like a client without context support
+	registry.AddReadinessCheck("broker", func(ctx context.Context) error {
+		<-release
+		return nil
+	})
+
+	start := time.Now()
+	report := registry.Readiness(context.Background())
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, StatusDown, report.Checks["broker"].Status)
+	assert.Equal(t, context.DeadlineExceeded.Error(), report.Checks["broker"].Error)
+}
+
+func TestRegistry_Cache(t *testing.T) {
+	registry := NewRegistry(Options{Timeout: time.Second, CacheTTL: time.Hour})
+	var calls atomic.Int32
+	registry.AddReadinessCheck("database", func(ctx context.Context) error {
+		calls.Add(1)
+		time.Sleep(10 * time.Millisecond)
+		return nil
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.True(t, registry.Readiness(context.Background()).Up())
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), calls.Load())
+
+	// A cancelled probe does not poison the shared result
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	uncached := NewRegistry(Options{Timeout: time.Second})
+	uncached.AddReadinessCheck("database", func(ctx context.Context) error { return ctx.Err() })
+	assert.True(t, uncached.Readiness(ctx).Up())
+}
+
+func TestRegistry_Shutdown(t *testing.T) {
+	registry := NewRegistry(Options{})
+	registry.AddReadinessCheck("database", func(ctx context.Context) error { return nil })
+	assert.True(t, registry.Readiness(context.Background()).Up())
+
+	registry.Shutdown()
+	report := registry.Readiness(context.Background())
+	assert.False(t, report.Up())
+	assert.Equal(t, StatusUp, report.Checks["database"].Status)
+	assert.Equal(t, StatusDown, report.Checks["shutdown"].Status)
+	assert.True(t, registry.Liveness(context.Background()).Up())
+}
+
+func TestReport_Summary(t *testing.T) {
+	registry := NewRegistry(Options{Timeout: time.Second})
+	registry.AddReadinessCheck("database", func(ctx context.Context) error { return nil })
+	registry.AddReadinessCheck("redis", func(ctx context.Context) error { return errors.New("dial tcp 10.0.0.5:6379: connection refused") })
+
+	summary := registry.Readiness(context.Background()).Summary()
+	assert.Equal(t, Summary{
+		Status: StatusDown,
+		Checks: map[string]Status{"database": StatusUp, "redis": StatusDown},
+	}, summary)
+}
@@ -0,0 +1,249 @@
+// Package i18n 提供面向客户端的消息目录：错误消息、字段校验说明和通知邮件模板。
+// 消息按语言存放在 messages/<locale>.json，值为 text/template 模板。
+package i18n
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"example.com/classic/pkg/contextx"
+	"example.com/classic/pkg/errors"
+)
+
+const (
+	English           = "en"
+	SimplifiedChinese = "zh-CN"
+
+	// SourceLocale 代码中硬编码消息所用的语言，也是翻译缺失时的最终回退
+	SourceLocale = English
+)
+
+//go:embed messages/*.json
+var messagesFS embed.FS
+
+var defaultCatalog = mustLoad()
+
+// Catalog 消息目录
+type Catalog struct {
+	locales  []string
+	messages map[string]map[string]*template.Template
+}
+
+// Load 从目录中的 <locale>.json 文件加载消息目录，必须包含 SourceLocale
+func Load(fsys fs.FS) (*Catalog, error) {
+	files, err := fs.Glob(fsys, "*.json")
+	if err != nil {
+		return nil, err
+	}
+	c := &Catalog{messages: make(map[string]map[string]*template.Template)}
+	for _, file := range files {
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+		var raw map[string]string
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return nil, fmt.Errorf("parse %s: %w", file, err)
+		}
+		locale := strings.TrimSuffix(path.Base(file), ".json")
+		messages := make(map[string]*template.Template, len(raw))
+		for key, text := range raw {
+			tmpl, err := template.New(key).Option("missingkey=zero").Parse(text)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", file, err)
+			}
+			messages[key] = tmpl
+		}
+		c.messages[locale] = messages
+		c.locales = append(c.locales, locale)
+	}
+	if _, ok := c.messages[SourceLocale]; !ok {
+		return nil, fmt.Errorf("catalog has no %s messages", SourceLocale)
+	}
+	sort.Strings(c.locales)
+	return c, nil
+}
+
+func mustLoad() *Catalog {
+	sub, err := fs.Sub(messagesFS, "messages")
+	if err != nil {
+		panic(err)
+	}
+	c, err := Load(sub)
+	if err != nil {
+		panic(fmt.Sprintf("i18n: %v", err))
+	}
+	return c
+}
+
+// Default 内置的消息目录 (en、zh-CN)
+func Default() *Catalog {
+	return defaultCatalog
+}
+
+// Locales 目录支持的语言
+func (c *Catalog) Locales() []string {
+	return c.locales
+}
+
+// Supported 返回目录中与 tag 大小写无关匹配的语言
+func (c *Catalog) Supported(tag string) (string, bool) {
+	for _, locale := range c.locales {
+		if strings.EqualFold(locale, tag) {
+			return locale, true
+		}
+	}
+	return "", false
+}
+
+// Match 按 Accept-Language (含 q 值) 协商语言：依次尝试完全匹配和同一基础语言 (zh-TW → zh-CN)，
+// 都不匹配时返回 fallback；fallback 不受支持时返回 SourceLocale
+func (c *Catalog) Match(acceptLanguage, fallback string) string {
+	if locale, ok := c.Supported(fallback); ok {
+		fallback = locale
+	} else {
+		fallback = SourceLocale
+	}
+
+	for _, tag := range parseAcceptLanguage(acceptLanguage) {
+		if tag == "*" {
+			return fallback
+		}
+		if locale, ok := c.Supported(tag); ok {
+			return locale
+		}
+		for _, locale := range c.locales {
+			if strings.EqualFold(baseLanguage(locale), baseLanguage(tag)) {
+				return locale
+			}
+		}
+	}
+	return fallback
+}
+
+// Render 渲染 key 对应的消息。查找顺序：locale、同一基础语言的其他语言、SourceLocale
+func (c *Catalog) Render(locale, key string, data any) (string, bool) {
+	for _, candidate := range c.chain(locale) {
+		tmpl, ok := c.messages[candidate][key]
+		if !ok {
+			continue
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return "", false
+		}
+		return buf.String(), true
+	}
+	return "", false
+}
+
+// chain 翻译缺失时的回退链
+func (c *Catalog) chain(locale string) []string {
+	chain := make([]string, 0, 3)
+	supported, _ := c.Supported(locale)
+	if supported != "" {
+		chain = append(chain, supported)
+	}
+	for _, candidate := range c.locales {
+		if candidate != supported && strings.EqualFold(baseLanguage(candidate), baseLanguage(locale)) {
+			chain = append(chain, candidate)
+		}
+	}
+	return append(chain, SourceLocale)
+}
+
+// Locale 返回请求上下文中协商出的语言，未协商时为 SourceLocale
+func Locale(ctx context.Context) string {
+	if locale := contextx.GetLocale(ctx); locale != "" {
+		return locale
+	}
+	return SourceLocale
+}
+
+// Translate 按请求上下文的语言渲染消息
+func Translate(ctx context.Context, key string, data any) (string, bool) {
+	return defaultCatalog.Render(Locale(ctx), key, data)
+}
+
+// T 同 Translate，目录中没有该 key 时返回 key 本身
+func T(ctx context.Context, key string, data any) string {
+	if msg, ok := Translate(ctx, key, data); ok {
+		return msg
+	}
+	return key
+}
+
+// ErrorMessage 返回业务错误面向客户端的消息。SourceLocale 下保持原消息；其他语言使用错误码对应的
+// 翻译，并附上明细：带字段明细时为第一条字段说明，否则为调用方写的原消息 (与错误码的通用消息相同时不附加)。
+// 没有该错误码的翻译时保持原消息
+func ErrorMessage(ctx context.Context, err *errors.Error) string {
+	locale := Locale(ctx)
+	if strings.EqualFold(baseLanguage(locale), baseLanguage(SourceLocale)) {
+		return err.Message
+	}
+	key := "errors." + strconv.Itoa(int(err.Code))
+	msg, ok := defaultCatalog.Render(locale, key, nil)
+	if !ok {
+		return err.Message
+	}
+	var detail string
+	if len(err.Fields) > 0 {
+		detail = err.Fields[0].Message
+	} else if generic, _ := defaultCatalog.Render(SourceLocale, key, nil); err.Message != generic {
+		detail = err.Message
+	}
+	if detail != "" {
+		msg = T(ctx, "errors.with_field", map[string]string{"Message": msg, "Field": detail})
+	}
+	return msg
+}
+
+// parseAcceptLanguage 按 q 值从高到低返回语言标签，q=0 的标签被忽略
+func parseAcceptLanguage(header string) []string {
+	type weighted struct {
+		tag string
+		q   float64
+	}
+	var tags []weighted
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q <= 0 {
+			continue
+		}
+		tags = append(tags, weighted{tag: tag, q: q})
+	}
+	sort.SliceStable(tags, func(i, j int) bool { return tags[i].q > tags[j].q })
+
+	out := make([]string, len(tags))
+	for i, t := range tags {
+		out[i] = t.tag
+	}
+	return out
+}
+
+// baseLanguage 语言标签的主语言部分 (zh-CN → zh)
+func baseLanguage(tag string) string {
+	base, _, _ := strings.Cut(tag, "-")
+	return strings.ToLower(base)
+}
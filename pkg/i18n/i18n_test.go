@@ -0,0 +1,92 @@
+package i18n
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"example.com/classic/pkg/contextx"
+	"example.com/classic/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCatalog_Match(t *testing.T) {
+	catalog := Default()
+
+	tests := []struct {
+		header   string
+		fallback string
+		want     string
+	}{
+		{"", English, English},
+		{"", SimplifiedChinese, SimplifiedChinese},
+		{"zh-cn", English, SimplifiedChinese},
+		{"zh-TW", English, SimplifiedChinese},
+		{"en-US,en;q=0.9", SimplifiedChinese, English},
+		{"fr-FR, zh;q=0.8, en;q=0.5", English, SimplifiedChinese},
+		{"zh;q=0, en", SimplifiedChinese, English},
+		{"fr, *;q=0.1", SimplifiedChinese, SimplifiedChinese},
+		{"de", "xx", English},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, catalog.Match(tt.header, tt.fallback), "Accept-Language %q", tt.header)
+	}
+}
+
+func TestCatalog_RenderFallback(t *testing.T) {
+	catalog, err := Load(fstest.MapFS{
+		"en.json":    {Data: []byte(`{"greeting": "Hello, {{.Name}}", "farewell": "Bye"}`)},
+		"zh-CN.json": {Data: []byte(`{"greeting": "你好，{{.Name}}"}`)},
+	})
+	require.NoError(t, err)
+
+	msg, ok := catalog.Render("zh-CN", "greeting", map[string]string{"Name": "Alice"})
+	assert.True(t, ok)
+	assert.Equal(t, "你好，Alice", msg)
+
+	// Other regions of a language use its translations, missing keys fall back to the source locale
+	msg, _ = catalog.Render("zh-HK", "greeting", map[string]string{"Name": "Alice"})
+	assert.Equal(t, "你好，Alice", msg)
+	msg, _ = catalog.Render("zh-CN", "farewell", nil)
+	assert.Equal(t, "Bye", msg)
+
+	_, ok = catalog.Render("zh-CN", "missing", nil)
+	assert.False(t, ok)
+
+	_, err = Load(fstest.MapFS{"zh-CN.json": {Data: []byte(`{}`)}})
+	assert.Error(t, err)
+}
+
+func TestDefault_Complete(t *testing.T) {
+	catalog := Default()
+	assert.Equal(t, []string{English, SimplifiedChinese}, catalog.Locales())
+
+	// Every translation covers the keys of the source locale
+	for _, locale := range catalog.Locales() {
+		for key := range catalog.messages[SourceLocale] {
+			assert.Contains(t, catalog.messages[locale], key, "%s is missing %s", locale, key)
+		}
+	}
+}
+
+func TestErrorMessage(t *testing.T) {
+	en := contextx.WithLocale(context.Background(), English)
+	zh := contextx.WithLocale(context.Background(), SimplifiedChinese)
+
+	// The source locale keeps the message written by the caller
+	detailed := errors.New(errors.ErrCodeInvalidParam, "invalid user id")
+	assert.Equal(t, "invalid user id", ErrorMessage(en, detailed))
+	assert.Equal(t, "invalid user id", ErrorMessage(context.Background(), detailed))
+
+	// Other locales translate the code and keep the caller's detail
+	assert.Equal(t, "请求参数错误：invalid user id", ErrorMessage(zh, detailed))
+	assert.Equal(t, "用户已存在", ErrorMessage(zh, errors.ErrUserAlreadyExists))
+	assert.Equal(t, "请求参数错误", ErrorMessage(zh, errors.ErrInvalidParam))
+
+	withField := detailed.WithFields(errors.FieldError{Field: "name", Code: "required", Message: "name 不能为空"})
+	assert.Equal(t, "请求参数错误：name 不能为空", ErrorMessage(zh, withField))
+
+	// Codes without a translation keep the original message
+	assert.Equal(t, "teapot", ErrorMessage(zh, errors.New(errors.ErrorCode(418), "teapot")))
+}
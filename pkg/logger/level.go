@@ -0,0 +1,105 @@
+package logger
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/rs/zerolog"
+)
+
+// levels 运行时日志级别：全局级别与按组件的覆盖，修改后立即对所有日志实例生效
+var levels = struct {
+	sync.RWMutex
+	global     zerolog.Level
+	components map[string]zerolog.Level
+}{
+	global:     zerolog.InfoLevel,
+	components: make(map[string]zerolog.Level),
+}
+
+// Levels 当前日志级别
+type Levels struct {
+	Level      string            `json:"level"`
+	Components map[string]string `json:"components"` // 组件 -> 覆盖的级别
+}
+
+// parseLevel 解析级别名称
+func parseLevel(level string) (zerolog.Level, error) {
+	switch level {
+	case "debug":
+		return zerolog.DebugLevel, nil
+	case "info":
+		return zerolog.InfoLevel, nil
+	case "warn":
+		return zerolog.WarnLevel, nil
+	case "error":
+		return zerolog.ErrorLevel, nil
+	case "fatal":
+		return zerolog.FatalLevel, nil
+	case "panic":
+		return zerolog.PanicLevel, nil
+	}
+	return zerolog.NoLevel, fmt.Errorf("invalid log level: %q (want debug, info, warn, error, fatal or panic)", level)
+}
+
+// SetLevel 设置全局日志级别，未单独设置级别的组件随之变化
+func SetLevel(level string) error {
+	lvl, err := parseLevel(level)
+	if err != nil {
+		return err
+	}
+	levels.Lock()
+	defer levels.Unlock()
+	levels.global = lvl
+	return nil
+}
+
+// SetComponentLevel 设置组件的日志级别 (见 Component)；level 为空时取消覆盖，恢复使用全局级别
+func SetComponentLevel(component, level string) error {
+	if component == "" {
+		return fmt.Errorf("log component is required")
+	}
+	levels.Lock()
+	defer levels.Unlock()
+	if level == "" {
+		delete(levels.components, component)
+		return nil
+	}
+	lvl, err := parseLevel(level)
+	if err != nil {
+		return err
+	}
+	levels.components[component] = lvl
+	return nil
+}
+
+// GetLevels 返回全局级别与各组件的覆盖
+func GetLevels() Levels {
+	levels.RLock()
+	defer levels.RUnlock()
+	out := Levels{Level: levels.global.String(), Components: make(map[string]string, len(levels.components))}
+	for component, lvl := range levels.components {
+		out.Components[component] = lvl.String()
+	}
+	return out
+}
+
+// enabled 组件在该级别是否输出日志
+func enabled(component string, level zerolog.Level) bool {
+	levels.RLock()
+	defer levels.RUnlock()
+	min := levels.global
+	if lvl, ok := levels.components[component]; ok && component != "" {
+		min = lvl
+	}
+	return level >= min
+}
+
+// Component 返回属于组件 name 的日志实例：日志带 component 字段，级别可通过 SetComponentLevel 单独调整
+func Component(l Logger, name string) Logger {
+	base, ok := l.(*logger)
+	if !ok {
+		return l.With(String("component", name))
+	}
+	return &logger{log: base.log.With().Str("component", name).Logger(), component: name}
+}
@@ -101,12 +101,18 @@ func (h traceHook) Run(e *zerolog.Event, level zerolog.Level, msg string) {
 	if userID := contextx.GetUserID(ctx); userID != "" {
 		e.Str("user_id", userID)
 	}
+	if tenantID := contextx.GetTenantID(ctx); tenantID != "" {
+		e.Str("tenant_id", tenantID)
+	}
 	if requestID := contextx.GetRequestID(ctx); requestID != "" {
 		e.Str("request_id", requestID)
 	}
 	if clientIP := contextx.GetClientIP(ctx); clientIP != "" {
 		e.Str("client_ip", clientIP)
 	}
+	if identity := contextx.GetClientIdentity(ctx); identity != "" {
+		e.Str("client_identity", identity)
+	}
 	if operation := contextx.GetOperationName(ctx); operation != "" {
 		e.Str("operation", operation)
 	}
@@ -114,32 +120,21 @@ func (h traceHook) Run(e *zerolog.Event, level zerolog.Level, msg string) {
 
 // logger 日志实现
 type logger struct {
-	log zerolog.Logger
+	log       zerolog.Logger
+	component string // 见 Component
 }
 
 // New creates a new logger instance.
 // If logDir is provided, logs will be written to both console and file.
 func New(service string, level string, isDevelopment bool, logDir ...string) Logger {
-	// Set log level
-	logLevel := zerolog.InfoLevel
-	switch level {
-	case "debug":
-		logLevel = zerolog.DebugLevel
-	case "info":
-		logLevel = zerolog.InfoLevel
-	case "warn":
-		logLevel = zerolog.WarnLevel
-	case "error":
-		logLevel = zerolog.ErrorLevel
-	case "fatal":
-		logLevel = zerolog.FatalLevel
-	case "panic":
-		logLevel = zerolog.PanicLevel
-	}
-
-	// Configure zerolog
+	// Set log level (unknown levels fall back to info; it can be changed at runtime with SetLevel)
+	if SetLevel(level) != nil {
+		_ = SetLevel("info")
+	}
+
+	// Configure zerolog; levels are checked by the logger itself so that they can differ per component
 	zerolog.TimeFieldFormat = time.RFC3339Nano
-	zerolog.SetGlobalLevel(logLevel)
+	zerolog.SetGlobalLevel(zerolog.TraceLevel)
 
 	// Determine output writer
 	var writer io.Writer
@@ -180,6 +175,9 @@ func New(service string, level string, isDevelopment bool, logDir ...string) Log
 
 // Debug 调试日志
 func (l *logger) Debug(ctx context.Context, msg string, fields ...Field) {
+	if !enabled(l.component, zerolog.DebugLevel) {
+		return
+	}
 	event := l.log.Debug()
 	if ctx != nil {
 		event = event.Ctx(ctx)
@@ -192,6 +190,9 @@ func (l *logger) Debug(ctx context.Context, msg string, fields ...Field) {
 
 // Info 信息日志
 func (l *logger) Info(ctx context.Context, msg string, fields ...Field) {
+	if !enabled(l.component, zerolog.InfoLevel) {
+		return
+	}
 	event := l.log.Info()
 	if ctx != nil {
 		event = event.Ctx(ctx)
@@ -204,6 +205,9 @@ func (l *logger) Info(ctx context.Context, msg string, fields ...Field) {
 
 // Warn 警告日志
 func (l *logger) Warn(ctx context.Context, msg string, fields ...Field) {
+	if !enabled(l.component, zerolog.WarnLevel) {
+		return
+	}
 	event := l.log.Warn()
 	if ctx != nil {
 		event = event.Ctx(ctx)
@@ -216,6 +220,9 @@ func (l *logger) Warn(ctx context.Context, msg string, fields ...Field) {
 
 // Error 错误日志
 func (l *logger) Error(ctx context.Context, msg string, fields ...Field) {
+	if !enabled(l.component, zerolog.ErrorLevel) {
+		return
+	}
 	event := l.log.Error()
 	if ctx != nil {
 		event = event.Ctx(ctx)
@@ -252,7 +259,7 @@ func (l *logger) Panic(ctx context.Context, msg string, fields ...Field) {
 
 // WithContext creates a logger instance bound to context
 func (l *logger) WithContext(ctx context.Context) Logger {
-	return &logger{log: l.log.With().Ctx(ctx).Logger()}
+	return &logger{log: l.log.With().Ctx(ctx).Logger(), component: l.component}
 }
 
 // With 添加固定字段到日志实例
@@ -261,7 +268,7 @@ func (l *logger) With(fields ...Field) Logger {
 	for _, field := range fields {
 		ctx = ctx.Interface(field.Key, field.Value)
 	}
-	return &logger{log: ctx.Logger()}
+	return &logger{log: ctx.Logger(), component: l.component}
 }
 
 // Sync 同步日志
@@ -0,0 +1,186 @@
+// Package metrics exposes Prometheus RED metrics (rate, errors, duration) for HTTP, gRPC,
+// the task queue and the database pool, plus Go runtime metrics.
+// A nil *Metrics is valid and records nothing, so callers need no checks when metrics are disabled.
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Result label values
+const (
+	ResultSuccess = "success"
+	ResultError   = "error"
+)
+
+// Options configures the collectors
+type Options struct {
+	// Namespace prefixes the application metrics (not the Go runtime ones)
+	Namespace string
+	// Buckets latency histogram buckets in seconds; prometheus.DefBuckets when empty
+	Buckets []float64
+	// GoRuntime registers the Go runtime and process collectors
+	GoRuntime bool
+	// DBStats reports the pools passed to ObserveDB
+	DBStats bool
+}
+
+// Metrics holds the collectors of a process
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpRequests *prometheus.CounterVec
+	httpDuration *prometheus.HistogramVec
+
+	grpcRequests *prometheus.CounterVec
+	grpcDuration *prometheus.HistogramVec
+
+	tasksEnqueued  *prometheus.CounterVec
+	tasksProcessed *prometheus.CounterVec
+	taskDuration   *prometheus.HistogramVec
+
+	dbStats *dbStatsCollector
+}
+
+// New creates the collectors on a dedicated registry
+func New(opts Options) *Metrics {
+	buckets := opts.Buckets
+	if len(buckets) == 0 {
+		buckets = prometheus.DefBuckets
+	}
+
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: opts.Namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests by method, route and status.",
+		}, []string{"method", "route", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: opts.Namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency by method, route and status.",
+			Buckets:   buckets,
+		}, []string{"method", "route", "status"}),
+		grpcRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: opts.Namespace,
+			Name:      "grpc_server_handled_total",
+			Help:      "gRPC calls by method and status code.",
+		}, []string{"method", "code"}),
+		grpcDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: opts.Namespace,
+			Name:      "grpc_server_handling_seconds",
+			Help:      "gRPC call latency by method and status code.",
+			Buckets:   buckets,
+		}, []string{"method", "code"}),
+		tasksEnqueued: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: opts.Namespace,
+			Name:      "tasks_enqueued_total",
+			Help:      "Tasks enqueued by type, queue and result.",
+		}, []string{"type", "queue", "result"}),
+		tasksProcessed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: opts.Namespace,
+			Name:      "tasks_processed_total",
+			Help:      "Tasks processed by type and result.",
+		}, []string{"type", "result"}),
+		taskDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: opts.Namespace,
+			Name:      "task_processing_seconds",
+			Help:      "Task processing time by type and result.",
+			Buckets:   buckets,
+		}, []string{"type", "result"}),
+	}
+	m.registry.MustRegister(
+		m.httpRequests, m.httpDuration,
+		m.grpcRequests, m.grpcDuration,
+		m.tasksEnqueued, m.tasksProcessed, m.taskDuration,
+	)
+
+	if opts.GoRuntime {
+		m.registry.MustRegister(
+			collectors.NewGoCollector(),
+			collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		)
+	}
+	if opts.DBStats {
+		m.dbStats = newDBStatsCollector(opts.Namespace)
+		m.registry.MustRegister(m.dbStats)
+	}
+	return m
+}
+
+// Handler serves the metrics in the Prometheus exposition format
+func (m *Metrics) Handler() http.Handler {
+	if m == nil {
+		return http.NotFoundHandler()
+	}
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// Registry returns the registry for additional collectors
+func (m *Metrics) Registry() *prometheus.Registry {
+	if m == nil {
+		return nil
+	}
+	return m.registry
+}
+
+// ObserveHTTP records a finished HTTP request; route is the route template, not the raw path
+func (m *Metrics) ObserveHTTP(method, route string, status int, duration time.Duration) {
+	if m == nil {
+		return
+	}
+	code := strconv.Itoa(status)
+	m.httpRequests.WithLabelValues(method, route, code).Inc()
+	m.httpDuration.WithLabelValues(method, route, code).Observe(duration.Seconds())
+}
+
+// ObserveGRPC records a finished gRPC call; code is the gRPC status code name (OK, NotFound ...)
+func (m *Metrics) ObserveGRPC(method, code string, duration time.Duration) {
+	if m == nil {
+		return
+	}
+	m.grpcRequests.WithLabelValues(method, code).Inc()
+	m.grpcDuration.WithLabelValues(method, code).Observe(duration.Seconds())
+}
+
+// ObserveEnqueue records an enqueue attempt
+func (m *Metrics) ObserveEnqueue(taskType, queue string, err error) {
+	if m == nil {
+		return
+	}
+	m.tasksEnqueued.WithLabelValues(taskType, queue, result(err)).Inc()
+}
+
+// ObserveTask records a processed task
+func (m *Metrics) ObserveTask(taskType string, duration time.Duration, err error) {
+	if m == nil {
+		return
+	}
+	res := result(err)
+	m.tasksProcessed.WithLabelValues(taskType, res).Inc()
+	m.taskDuration.WithLabelValues(taskType, res).Observe(duration.Seconds())
+}
+
+// ObserveDB adds a connection pool to the pool gauges.
+// Stats of several pools are summed: they are the connections of this process.
+func (m *Metrics) ObserveDB(db *sql.DB) {
+	if m == nil || m.dbStats == nil {
+		return
+	}
+	m.dbStats.add(db)
+}
+
+func result(err error) string {
+	if err != nil {
+		return ResultError
+	}
+	return ResultSuccess
+}
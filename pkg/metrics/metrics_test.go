@@ -0,0 +1,71 @@
+package metrics
+
+import (
+	"database/sql"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+)
+
+func TestMetrics_Observe(t *testing.T) {
+	m := New(Options{Namespace: "app"})
+
+	m.ObserveHTTP("GET", "/api/v1/users/:id", 200, 10*time.Millisecond)
+	m.ObserveHTTP("GET", "/api/v1/users/:id", 200, 20*time.Millisecond)
+	m.ObserveHTTP("GET", "/api/v1/users/:id", 404, time.Millisecond)
+	m.ObserveGRPC("/user.UserService/GetByID", "OK", time.Millisecond)
+	m.ObserveEnqueue("user:welcome_email", "default", nil)
+	m.ObserveEnqueue("user:welcome_email", "default", errors.New("redis down"))
+	m.ObserveTask("user:welcome_email", time.Second, nil)
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(m.httpRequests.WithLabelValues("GET", "/api/v1/users/:id", "200")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.httpRequests.WithLabelValues("GET", "/api/v1/users/:id", "404")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.grpcRequests.WithLabelValues("/user.UserService/GetByID", "OK")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.tasksEnqueued.WithLabelValues("user:welcome_email", "default", ResultError)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.tasksProcessed.WithLabelValues("user:welcome_email", ResultSuccess)))
+}
+
+func TestMetrics_Handler(t *testing.T) {
+	sqldb, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "metrics.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = sqldb.Close() })
+	sqldb.SetMaxOpenConns(3)
+
+	m := New(Options{GoRuntime: true, DBStats: true})
+	m.ObserveDB(sqldb)
+	m.ObserveDB(sqldb) // registered once
+	m.ObserveHTTP("POST", "/api/v1/users", 201, time.Millisecond)
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	body, _ := io.ReadAll(rec.Body)
+
+	assert.Contains(t, string(body), `http_requests_total{method="POST",route="/api/v1/users",status="201"} 1`)
+	assert.Contains(t, string(body), "db_max_open_connections 3")
+	assert.Contains(t, string(body), "go_goroutines")
+}
+
+func TestMetrics_Nil(t *testing.T) {
+	var m *Metrics
+	assert.NotPanics(t, func() {
+		m.ObserveHTTP("GET", "/", 200, time.Millisecond)
+		m.ObserveGRPC("/svc/Method", "OK", time.Millisecond)
+		m.ObserveEnqueue("task", "default", nil)
+		m.ObserveTask("task", time.Millisecond, nil)
+		m.ObserveDB(nil)
+	})
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
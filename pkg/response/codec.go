@@ -0,0 +1,199 @@
+package response
+
+import (
+	"bytes"
+	"encoding/json"
+	stderrors "errors"
+	"mime"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	ugorji "github.com/ugorji/go/codec"
+	"google.golang.org/protobuf/proto"
+)
+
+// 内置编解码器的媒体类型
+const (
+	JSONContentType     = "application/json"
+	ProtobufContentType = "application/x-protobuf"
+	MsgpackContentType  = "application/msgpack"
+)
+
+// ErrUnsupportedValue 编解码器无法表示该值，响应回退为 JSON
+var ErrUnsupportedValue = stderrors.New("value not supported by codec")
+
+// Codec 请求/响应体编解码器
+type Codec interface {
+	// ContentType 写入响应 Content-Type 头的值
+	ContentType() string
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+// JSONCodec application/json
+type JSONCodec struct{}
+
+func (JSONCodec) ContentType() string { return "application/json; charset=utf-8" }
+
+func (JSONCodec) Marshal(v any) ([]byte, error) { return json.Marshal(v) }
+
+func (JSONCodec) Unmarshal(data []byte, v any) error { return json.Unmarshal(data, v) }
+
+// ProtobufCodec application/x-protobuf，只能编码 proto.Message；
+// 统一响应格式中的 data 为 proto.Message 时直接编码 data，不带外层包装
+type ProtobufCodec struct{}
+
+func (ProtobufCodec) ContentType() string { return ProtobufContentType }
+
+func (ProtobufCodec) Marshal(v any) ([]byte, error) {
+	if resp, ok := v.(Response); ok {
+		v = resp.Data
+	}
+	msg, ok := v.(proto.Message)
+	if !ok {
+		return nil, ErrUnsupportedValue
+	}
+	return proto.Marshal(msg)
+}
+
+func (ProtobufCodec) Unmarshal(data []byte, v any) error {
+	msg, ok := v.(proto.Message)
+	if !ok {
+		return ErrUnsupportedValue
+	}
+	return proto.Unmarshal(data, msg)
+}
+
+// MsgpackCodec application/msgpack，字段名沿用 json tag，时间使用 msgpack timestamp 扩展类型
+type MsgpackCodec struct{}
+
+// msgpackHandle 解码到 interface{} 时与 JSON 保持一致：map[string]any、有符号整数、字符串
+var msgpackHandle = func() *ugorji.MsgpackHandle {
+	h := &ugorji.MsgpackHandle{WriteExt: true}
+	h.MapType = reflect.TypeOf(map[string]interface{}(nil))
+	h.SignedInteger = true
+	h.RawToString = true
+	return h
+}()
+
+func (MsgpackCodec) ContentType() string { return MsgpackContentType }
+
+func (MsgpackCodec) Marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := ugorji.NewEncoder(&buf, msgpackHandle).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (MsgpackCodec) Unmarshal(data []byte, v any) error {
+	return ugorji.NewDecoderBytes(data, msgpackHandle).Decode(v)
+}
+
+var (
+	codecsMu sync.RWMutex
+	// codecs 媒体类型 -> 编解码器
+	codecs = map[string]Codec{
+		JSONContentType:           JSONCodec{},
+		ProtobufContentType:       ProtobufCodec{},
+		"application/protobuf":    ProtobufCodec{},
+		MsgpackContentType:        MsgpackCodec{},
+		"application/x-msgpack":   MsgpackCodec{},
+		"application/vnd.msgpack": MsgpackCodec{},
+	}
+)
+
+// RegisterCodec 注册（或替换）媒体类型对应的编解码器
+func RegisterCodec(mediaType string, c Codec) {
+	codecsMu.Lock()
+	defer codecsMu.Unlock()
+	codecs[strings.ToLower(mediaType)] = c
+}
+
+// lookupCodec 按媒体类型查找编解码器
+func lookupCodec(mediaType string) (Codec, bool) {
+	codecsMu.RLock()
+	defer codecsMu.RUnlock()
+	c, ok := codecs[strings.ToLower(mediaType)]
+	return c, ok
+}
+
+// Negotiate 按 Accept 头选择响应编解码器：取 q 值最高且已注册的媒体类型，
+// 未声明、通配或均不支持时使用 JSON
+func Negotiate(c *gin.Context) Codec {
+	type candidate struct {
+		mediaType string
+		q         float64
+	}
+	var candidates []candidate
+	for _, accept := range c.Request.Header.Values("Accept") {
+		for _, mediaRange := range strings.Split(accept, ",") {
+			mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(mediaRange))
+			if err != nil {
+				continue
+			}
+			q := 1.0
+			if raw, ok := params["q"]; ok {
+				if q, err = strconv.ParseFloat(raw, 64); err != nil {
+					continue
+				}
+			}
+			if q > 0 {
+				candidates = append(candidates, candidate{mediaType: mediaType, q: q})
+			}
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+
+	for _, cand := range candidates {
+		if cand.mediaType == "*/*" || cand.mediaType == "application/*" {
+			break
+		}
+		if codec, ok := lookupCodec(cand.mediaType); ok {
+			return codec
+		}
+	}
+	return JSONCodec{}
+}
+
+// RequestCodec 按 Content-Type 选择请求体编解码器；未声明时按 JSON 处理，不支持时返回 false
+func RequestCodec(c *gin.Context) (Codec, bool) {
+	contentType := c.GetHeader("Content-Type")
+	if contentType == "" {
+		return JSONCodec{}, true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, false
+	}
+	return lookupCodec(mediaType)
+}
+
+// Negotiated 响应是否协商为指定媒体类型
+func Negotiated(c *gin.Context, mediaType string) bool {
+	codec, ok := lookupCodec(mediaType)
+	return ok && Negotiate(c).ContentType() == codec.ContentType()
+}
+
+// render 以协商出的格式写出响应体；编解码器无法表示时回退为 JSON
+func render(c *gin.Context, httpStatus int, body any) {
+	c.Writer.Header().Add("Vary", "Accept")
+
+	codec := Negotiate(c)
+	data, err := codec.Marshal(body)
+	if stderrors.Is(err, ErrUnsupportedValue) {
+		codec = JSONCodec{}
+		data, err = codec.Marshal(body)
+	}
+	if err != nil {
+		_ = c.Error(err)
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+	c.Data(httpStatus, codec.ContentType(), data)
+}
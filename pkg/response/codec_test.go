@@ -0,0 +1,71 @@
+package response
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"example.com/classic/pkg/contextx"
+	"example.com/classic/pkg/i18n"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		accept string
+		want   Codec
+	}{
+		{"", JSONCodec{}},
+		{"*/*", JSONCodec{}},
+		{"application/msgpack", MsgpackCodec{}},
+		{"application/x-msgpack", MsgpackCodec{}},
+		{"application/json;q=0.9, application/x-protobuf", ProtobufCodec{}},
+		{"application/x-protobuf;q=0.1, application/msgpack;q=0.5", MsgpackCodec{}},
+		// A wildcard ranked above a concrete type keeps the default
+		{"*/*, application/msgpack;q=0.5", JSONCodec{}},
+		{"application/x-protobuf;q=0, text/html", JSONCodec{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.accept, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest("GET", "/", nil)
+			c.Request.Header.Set("Accept", tt.accept)
+			assert.Equal(t, tt.want, Negotiate(c))
+		})
+	}
+}
+
+func TestRender_FallsBackToJSON(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/", nil)
+	c.Request.Header.Set("Accept", ProtobufContentType)
+
+	// No protobuf representation for a plain map
+	Success(c, map[string]int{"n": 1})
+
+	assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"code":0,"msg":"success","data":{"n":1}}`, w.Body.String())
+}
+
+func TestSuccessWithMsg_Localized(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	for locale, want := range map[string]string{
+		i18n.English:           "user deleted successfully",
+		i18n.SimplifiedChinese: "用户删除成功",
+	} {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("DELETE", "/", nil)
+		c.Request = c.Request.WithContext(contextx.WithLocale(c.Request.Context(), locale))
+
+		SuccessWithMsg(c, "success.user_deleted", nil)
+
+		assert.JSONEq(t, `{"code":0,"msg":"`+want+`"}`, w.Body.String(), locale)
+	}
+}
@@ -0,0 +1,91 @@
+package response
+
+import (
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"example.com/classic/pkg/contextx"
+	"example.com/classic/pkg/errors"
+	"example.com/classic/pkg/i18n"
+	"github.com/gin-gonic/gin"
+)
+
+// ProblemContentType RFC 7807 问题详情媒体类型
+const ProblemContentType = "application/problem+json"
+
+// problemOptionsKey gin 上下文中保存问题详情选项的键
+const problemOptionsKey = "response.problem_options"
+
+// Problem RFC 7807 问题详情，Code 与 TraceID 为扩展成员
+type Problem struct {
+	Type     string              `json:"type"`               // 问题类型 URI
+	Title    string              `json:"title"`              // HTTP 状态对应的简短标题
+	Status   int                 `json:"status"`             // HTTP 状态码
+	Detail   string              `json:"detail,omitempty"`   // 本次错误的说明
+	Instance string              `json:"instance,omitempty"` // 出错的请求路径
+	Code     int                 `json:"code"`               // 业务错误码
+	TraceID  string              `json:"trace_id,omitempty"` // 链路追踪 ID
+	Errors   []errors.FieldError `json:"errors,omitempty"`   // 字段级校验明细
+}
+
+// ProblemOptions 问题详情选项
+type ProblemOptions struct {
+	// Enabled 所有错误响应均使用 problem+json；关闭时客户端仍可通过 Accept 头单独选择
+	Enabled bool
+	// TypeBase 问题类型 URI 前缀，拼接业务错误码；为空时使用 about:blank
+	TypeBase string
+}
+
+// ProblemDetails 将问题详情选项写入请求上下文，供错误响应使用
+func ProblemDetails(opts ProblemOptions) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(problemOptionsKey, opts)
+		c.Next()
+	}
+}
+
+// WriteProblem 以 application/problem+json 写出错误响应
+func WriteProblem(c *gin.Context, httpStatus int, err *errors.Error) {
+	opts := problemOptions(c)
+
+	problemType := "about:blank"
+	if opts.TypeBase != "" {
+		problemType = opts.TypeBase + strconv.Itoa(int(err.Code))
+	}
+
+	c.Header("Content-Type", ProblemContentType)
+	c.JSON(httpStatus, Problem{
+		Type:     problemType,
+		Title:    http.StatusText(httpStatus),
+		Status:   httpStatus,
+		Detail:   i18n.ErrorMessage(c.Request.Context(), err),
+		Instance: c.Request.URL.Path,
+		Code:     int(err.Code),
+		TraceID:  contextx.GetTraceID(c.Request.Context()),
+		Errors:   err.Fields,
+	})
+}
+
+// problemOptions 读取请求上下文中的选项，未挂载中间件时为零值
+func problemOptions(c *gin.Context) ProblemOptions {
+	value, _ := c.Get(problemOptionsKey)
+	opts, _ := value.(ProblemOptions)
+	return opts
+}
+
+// wantsProblem 配置开启或 Accept 头声明 application/problem+json 时使用问题详情格式
+func wantsProblem(c *gin.Context) bool {
+	if problemOptions(c).Enabled {
+		return true
+	}
+	for _, accept := range c.Request.Header.Values("Accept") {
+		for _, mediaRange := range strings.Split(accept, ",") {
+			if mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(mediaRange)); err == nil && mediaType == ProblemContentType {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"example.com/classic/pkg/errors"
+	"example.com/classic/pkg/i18n"
 	"github.com/gin-gonic/gin"
 )
 
@@ -16,35 +17,52 @@ type Response struct {
 
 // Success 成功响应
 func Success(c *gin.Context, data interface{}) {
-	c.JSON(http.StatusOK, Response{
+	render(c, http.StatusOK, Response{
 		Code: int(errors.ErrCodeSuccess),
 		Msg:  "success",
 		Data: data,
 	})
 }
 
-// SuccessWithMsg 带消息的成功响应
-func SuccessWithMsg(c *gin.Context, msg string, data interface{}) {
-	c.JSON(http.StatusOK, Response{
+// SuccessWithMsg 带消息的成功响应；msgKey 为消息目录中的 key，按请求语言渲染
+func SuccessWithMsg(c *gin.Context, msgKey string, data interface{}) {
+	render(c, http.StatusOK, Response{
 		Code: int(errors.ErrCodeSuccess),
-		Msg:  msg,
+		Msg:  i18n.T(c.Request.Context(), msgKey, nil),
 		Data: data,
 	})
 }
 
-// Error 错误响应
+// Accepted 异步任务已受理响应；msgKey 同 SuccessWithMsg
+func Accepted(c *gin.Context, msgKey string, data interface{}) {
+	render(c, http.StatusAccepted, Response{
+		Code: int(errors.ErrCodeSuccess),
+		Msg:  i18n.T(c.Request.Context(), msgKey, nil),
+		Data: data,
+	})
+}
+
+// Error 错误响应；消息按请求语言本地化，按配置或 Accept 头切换为 problem+json
 func Error(c *gin.Context, httpStatus int, err *errors.Error) {
-	c.JSON(httpStatus, Response{
+	if wantsProblem(c) {
+		WriteProblem(c, httpStatus, err)
+		return
+	}
+	render(c, httpStatus, Response{
 		Code: int(err.Code),
-		Msg:  err.Message,
+		Msg:  i18n.ErrorMessage(c.Request.Context(), err),
 	})
 }
 
-// ErrorWithData 带数据的错误响应
+// ErrorWithData 带数据的错误响应；problem+json 格式下不携带数据
 func ErrorWithData(c *gin.Context, httpStatus int, err *errors.Error, data interface{}) {
-	c.JSON(httpStatus, Response{
+	if wantsProblem(c) {
+		WriteProblem(c, httpStatus, err)
+		return
+	}
+	render(c, httpStatus, Response{
 		Code: int(err.Code),
-		Msg:  err.Message,
+		Msg:  i18n.ErrorMessage(c.Request.Context(), err),
 		Data: data,
 	})
 }
@@ -74,6 +92,31 @@ func TooManyRequests(c *gin.Context, err *errors.Error) {
 	Error(c, http.StatusTooManyRequests, err)
 }
 
+func UnprocessableEntity(c *gin.Context, err *errors.Error) {
+	Error(c, http.StatusUnprocessableEntity, err)
+}
+
+// ServiceUnavailable 暂时性故障，客户端可稍后重试
+func ServiceUnavailable(c *gin.Context, err *errors.Error) {
+	c.Header("Retry-After", "1")
+	Error(c, http.StatusServiceUnavailable, err)
+}
+
+// RequestEntityTooLarge 请求体超过上限
+func RequestEntityTooLarge(c *gin.Context, err *errors.Error) {
+	Error(c, http.StatusRequestEntityTooLarge, err)
+}
+
+// UnsupportedMediaType 请求体格式不受支持
+func UnsupportedMediaType(c *gin.Context, err *errors.Error) {
+	Error(c, http.StatusUnsupportedMediaType, err)
+}
+
+// GatewayTimeout 请求处理超时
+func GatewayTimeout(c *gin.Context, err *errors.Error) {
+	Error(c, http.StatusGatewayTimeout, err)
+}
+
 func InternalServerError(c *gin.Context, err *errors.Error) {
 	Error(c, http.StatusInternalServerError, err)
 }